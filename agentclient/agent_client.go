@@ -1,18 +1,33 @@
-package http
+package agentclient
 
 import (
 	"fmt"
 	"time"
 
-	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
-	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/settings"
+	boshsettings "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/settings"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	boshretry "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/retrystrategy"
 )
 
+// AgentClient is the http agent client, with the agent messages that the vendored agent client interface does not have.
+// It replaces the vendored http agent client, which cannot send them at its pinned revision.
+type AgentClient interface {
+	boshagentclient.AgentClient
+	AddPersistentDisk(diskCID string, diskHint interface{}) error
+	FetchLogs(logType string, filters []string) (logsBlobRef boshagentclient.BlobRef, err error)
+	RunErrand() (ErrandResult, error)
+}
+
+type ErrandResult struct {
+	ExitStatus int
+	Stdout     string
+	Stderr     string
+}
+
 type agentClient struct {
 	agentRequest        agentRequest
 	getTaskDelay        time.Duration
@@ -28,20 +43,18 @@ func NewAgentClient(
 	toleratedErrorCount int,
 	httpClient httpclient.HTTPClient,
 	logger boshlog.Logger,
-) agentclient.AgentClient {
+) AgentClient {
 	// if this were NATS, we would need the agentID, but since it's http, the endpoint is unique to the agent
-	agentEndpoint := fmt.Sprintf("%s/agent", endpoint)
-	agentRequest := agentRequest{
-		directorID: directorID,
-		endpoint:   agentEndpoint,
-		httpClient: httpClient,
-	}
 	return &agentClient{
-		agentRequest:        agentRequest,
+		agentRequest: agentRequest{
+			directorID: directorID,
+			endpoint:   fmt.Sprintf("%s/agent", endpoint),
+			httpClient: httpClient,
+		},
 		getTaskDelay:        getTaskDelay,
 		toleratedErrorCount: toleratedErrorCount,
 		logger:              logger,
-		logTag:              "httpAgentClient",
+		logTag:              "agentClient",
 	}
 }
 
@@ -73,13 +86,13 @@ func (c *agentClient) Start() error {
 	}
 
 	if response.Value != "started" {
-		return bosherr.Errorf("Failed to start agent services with response: '%s'", response)
+		return bosherr.Errorf("Failed to start agent services with response: '%s'", response.Value)
 	}
 
 	return nil
 }
 
-func (c *agentClient) GetState() (agentclient.AgentState, error) {
+func (c *agentClient) GetState() (boshagentclient.AgentState, error) {
 	var response StateResponse
 
 	getStateRetryable := boshretry.NewRetryable(func() (bool, error) {
@@ -93,10 +106,10 @@ func (c *agentClient) GetState() (agentclient.AgentState, error) {
 	attemptRetryStrategy := boshretry.NewAttemptRetryStrategy(c.toleratedErrorCount+1, c.getTaskDelay, getStateRetryable, c.logger)
 	err := attemptRetryStrategy.Try()
 	if err != nil {
-		return agentclient.AgentState{}, bosherr.WrapError(err, "Sending get_state to the agent")
+		return boshagentclient.AgentState{}, bosherr.WrapError(err, "Sending get_state to the agent")
 	}
 
-	agentState := agentclient.AgentState{
+	agentState := boshagentclient.AgentState{
 		JobState: response.Value.JobState,
 	}
 
@@ -128,11 +141,118 @@ func (c *agentClient) MigrateDisk() error {
 	return err
 }
 
-func (c *agentClient) UpdateSettings(settings settings.Settings) error {
+func (c *agentClient) UpdateSettings(settings boshsettings.Settings) error {
 	_, err := c.sendAsyncTaskMessage("update_settings", []interface{}{settings})
 	return err
 }
 
+func (c *agentClient) CompilePackage(packageSource boshagentclient.BlobRef, compiledPackageDependencies []boshagentclient.BlobRef) (compiledPackageRef boshagentclient.BlobRef, err error) {
+	dependencies := make(map[string]BlobRef, len(compiledPackageDependencies))
+	for _, dependency := range compiledPackageDependencies {
+		dependencies[dependency.Name] = BlobRef{
+			Name:        dependency.Name,
+			Version:     dependency.Version,
+			SHA1:        dependency.SHA1,
+			BlobstoreID: dependency.BlobstoreID,
+		}
+	}
+
+	args := []interface{}{
+		packageSource.BlobstoreID,
+		packageSource.SHA1,
+		packageSource.Name,
+		packageSource.Version,
+		dependencies,
+	}
+
+	responseValue, err := c.sendAsyncTaskMessage("compile_package", args)
+	if err != nil {
+		return boshagentclient.BlobRef{}, bosherr.WrapError(err, "Sending 'compile_package' to the agent")
+	}
+
+	result, ok := responseValue["result"].(map[string]interface{})
+	if !ok {
+		return boshagentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
+	}
+
+	sha1, ok := result["sha1"].(string)
+	if !ok {
+		return boshagentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
+	}
+
+	blobstoreID, ok := result["blobstore_id"].(string)
+	if !ok {
+		return boshagentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
+	}
+
+	compiledPackageRef = boshagentclient.BlobRef{
+		Name:        packageSource.Name,
+		Version:     packageSource.Version,
+		SHA1:        sha1,
+		BlobstoreID: blobstoreID,
+	}
+
+	return compiledPackageRef, nil
+}
+
+// AddPersistentDisk tells the agent where the disk is attached, with the disk hint returned by the CPI.
+// Agents that do not implement it return an UnsupportedMessageError, and find the disk through the registry instead.
+func (c *agentClient) AddPersistentDisk(diskCID string, diskHint interface{}) error {
+	method := "add_persistent_disk"
+	var response ExceptionResponse
+	err := c.agentRequest.Send(method, []interface{}{diskCID, diskHint}, &response)
+	if err != nil {
+		if response.IsUnknownMessage() {
+			return NewUnsupportedMessageError(method)
+		}
+		return bosherr.WrapErrorf(err, "Sending '%s' to the agent", method)
+	}
+
+	return nil
+}
+
+func (c *agentClient) FetchLogs(logType string, filters []string) (boshagentclient.BlobRef, error) {
+	responseValue, err := c.sendAsyncTaskMessage("fetch_logs", []interface{}{logType, filters})
+	if err != nil {
+		return boshagentclient.BlobRef{}, err
+	}
+
+	blobstoreID, ok := responseValue["blobstore_id"].(string)
+	if !ok {
+		return boshagentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'fetch_logs' response from the agent: %#v", responseValue)
+	}
+
+	// older agents do not report the sha1 of the logs tarball
+	sha1, _ := responseValue["sha1"].(string)
+
+	return boshagentclient.BlobRef{
+		BlobstoreID: blobstoreID,
+		SHA1:        sha1,
+	}, nil
+}
+
+func (c *agentClient) RunErrand() (ErrandResult, error) {
+	responseValue, err := c.sendAsyncTaskMessage("run_errand", []interface{}{})
+	if err != nil {
+		return ErrandResult{}, err
+	}
+
+	exitStatus, ok := responseValue["exit_code"].(float64)
+	if !ok {
+		return ErrandResult{}, bosherr.Errorf("Unable to parse 'run_errand' response from the agent: %#v", responseValue)
+	}
+
+	stdout, _ := responseValue["stdout"].(string)
+	stderr, _ := responseValue["stderr"].(string)
+
+	return ErrandResult{
+		ExitStatus: int(exitStatus),
+		Stdout:     stdout,
+		Stderr:     stderr,
+	}, nil
+}
+
+// sendAsyncTaskMessage sends the message and polls the agent task it starts until the task is done
 func (c *agentClient) sendAsyncTaskMessage(method string, arguments []interface{}) (value map[string]interface{}, err error) {
 	var response TaskResponse
 	err = c.agentRequest.Send(method, arguments, &response)
@@ -153,14 +273,11 @@ func (c *agentClient) sendAsyncTaskMessage(method string, arguments []interface{
 			sendErrors++
 			shouldRetry := sendErrors <= c.toleratedErrorCount
 			err = bosherr.WrapError(err, "Sending 'get_task' to the agent")
-			msg := fmt.Sprintf("Error occured sending get_task. Error retry %d of %d", sendErrors, c.toleratedErrorCount)
-			c.logger.Debug(c.logTag, msg, err)
+			c.logger.Debug(c.logTag, "Error occured sending get_task. Error retry %d of %d: %s", sendErrors, c.toleratedErrorCount, err.Error())
 			return shouldRetry, err
 		}
 		sendErrors = 0
 
-		c.logger.Debug(c.logTag, "get_task response value: %#v", response.Value)
-
 		taskState, err := response.TaskState()
 		if err != nil {
 			return false, bosherr.WrapError(err, "Getting task state")
@@ -184,52 +301,3 @@ func (c *agentClient) sendAsyncTaskMessage(method string, arguments []interface{
 	err = getTaskRetryStrategy.Try()
 	return value, err
 }
-
-func (c *agentClient) CompilePackage(packageSource agentclient.BlobRef, compiledPackageDependencies []agentclient.BlobRef) (compiledPackageRef agentclient.BlobRef, err error) {
-	dependencies := make(map[string]BlobRef, len(compiledPackageDependencies))
-	for _, dependency := range compiledPackageDependencies {
-		dependencies[dependency.Name] = BlobRef{
-			Name:        dependency.Name,
-			Version:     dependency.Version,
-			SHA1:        dependency.SHA1,
-			BlobstoreID: dependency.BlobstoreID,
-		}
-	}
-
-	args := []interface{}{
-		packageSource.BlobstoreID,
-		packageSource.SHA1,
-		packageSource.Name,
-		packageSource.Version,
-		dependencies,
-	}
-
-	responseValue, err := c.sendAsyncTaskMessage("compile_package", args)
-	if err != nil {
-		return agentclient.BlobRef{}, bosherr.WrapError(err, "Sending 'compile_package' to the agent")
-	}
-
-	result, ok := responseValue["result"].(map[string]interface{})
-	if !ok {
-		return agentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
-	}
-
-	sha1, ok := result["sha1"].(string)
-	if !ok {
-		return agentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
-	}
-
-	blobstoreID, ok := result["blobstore_id"].(string)
-	if !ok {
-		return agentclient.BlobRef{}, bosherr.Errorf("Unable to parse 'compile_package' response from the agent: %#v", responseValue)
-	}
-
-	compiledPackageRef = agentclient.BlobRef{
-		Name:        packageSource.Name,
-		Version:     packageSource.Version,
-		SHA1:        sha1,
-		BlobstoreID: blobstoreID,
-	}
-
-	return compiledPackageRef, nil
-}
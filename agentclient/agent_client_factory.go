@@ -1,17 +1,14 @@
-package http
-
-//go:generate mockgen -source=agent_client_factory.go -package=mocks -destination=mocks/mocks.go
+package agentclient
 
 import (
 	"time"
 
-	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 )
 
 type AgentClientFactory interface {
-	NewAgentClient(directorID, mbusURL string) agentclient.AgentClient
+	NewAgentClient(directorID, mbusURL string) AgentClient
 }
 
 type agentClientFactory struct {
@@ -29,7 +26,7 @@ func NewAgentClientFactory(
 	}
 }
 
-func (f *agentClientFactory) NewAgentClient(directorID, mbusURL string) agentclient.AgentClient {
+func (f *agentClientFactory) NewAgentClient(directorID, mbusURL string) AgentClient {
 	httpClient := httpclient.NewHTTPClient(httpclient.DefaultClient, f.logger)
 	return NewAgentClient(mbusURL, directorID, f.getTaskDelay, 10, httpClient, f.logger)
 }
@@ -1,24 +1,24 @@
-package http_test
+package agentclient_test
 
 import (
 	"encoding/json"
 	"errors"
 	"net/http"
 
-	. "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
+	. "github.com/cloudfoundry/bosh-init/agentclient"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
-	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
 	fakehttpclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient/fakes"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
-	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
-	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 )
 
 var _ = Describe("AgentClient", func() {
 	var (
 		fakeHTTPClient *fakehttpclient.FakeHTTPClient
-		agentClient    agentclient.AgentClient
+		agentClient    AgentClient
 	)
 
 	BeforeEach(func() {
@@ -344,7 +344,7 @@ var _ = Describe("AgentClient", func() {
 			It("makes a POST request to the endpoint", func() {
 				stateResponse, err := agentClient.GetState()
 				Expect(err).ToNot(HaveOccurred())
-				Expect(stateResponse).To(Equal(agentclient.AgentState{JobState: "running"}))
+				Expect(stateResponse).To(Equal(boshagentclient.AgentState{JobState: "running"}))
 
 				Expect(fakeHTTPClient.PostInputs).To(HaveLen(1))
 				Expect(fakeHTTPClient.PostInputs[0].Endpoint).To(Equal("http://localhost:6305/agent"))
@@ -372,7 +372,7 @@ var _ = Describe("AgentClient", func() {
 				stateResponse, err := agentClient.GetState()
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("status code: 500"))
-				Expect(stateResponse).To(Equal(agentclient.AgentState{}))
+				Expect(stateResponse).To(Equal(boshagentclient.AgentState{}))
 			})
 		})
 
@@ -387,7 +387,7 @@ var _ = Describe("AgentClient", func() {
 				stateResponse, err := agentClient.GetState()
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("bad request"))
-				Expect(stateResponse).To(Equal(agentclient.AgentState{}))
+				Expect(stateResponse).To(Equal(boshagentclient.AgentState{}))
 			})
 		})
 
@@ -401,7 +401,7 @@ var _ = Describe("AgentClient", func() {
 			It("retries the up to error count specified", func() {
 				stateResponse, err := agentClient.GetState()
 				Expect(err).ToNot(HaveOccurred())
-				Expect(stateResponse).To(Equal(agentclient.AgentState{JobState: "running"}))
+				Expect(stateResponse).To(Equal(boshagentclient.AgentState{JobState: "running"}))
 			})
 		})
 
@@ -658,13 +658,13 @@ var _ = Describe("AgentClient", func() {
 		})
 
 		It("makes a compile_package request and waits for the task to be done", func() {
-			packageSource := agentclient.BlobRef{
+			packageSource := boshagentclient.BlobRef{
 				Name:        "fake-package-name",
 				Version:     "fake-package-version",
 				SHA1:        "fake-package-sha1",
 				BlobstoreID: "fake-package-blobstore-id",
 			}
-			dependencies := []agentclient.BlobRef{
+			dependencies := []boshagentclient.BlobRef{
 				{
 					Name:        "fake-compiled-package-dep-name",
 					Version:     "fake-compiled-package-dep-version",
@@ -702,4 +702,243 @@ var _ = Describe("AgentClient", func() {
 			}))
 		})
 	})
+
+	Describe("AddPersistentDisk", func() {
+		Context("when agent responds with a value", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{}}`, 200, nil)
+			})
+
+			It("makes an add_persistent_disk request with the disk hint", func() {
+				err := agentClient.AddPersistentDisk("fake-disk-cid", map[string]interface{}{"path": "/dev/sdc"})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeHTTPClient.PostInputs).To(HaveLen(1))
+				Expect(fakeHTTPClient.PostInputs[0].Endpoint).To(Equal("http://localhost:6305/agent"))
+
+				var request AgentRequestMessage
+				err = json.Unmarshal(fakeHTTPClient.PostInputs[0].Payload, &request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(request).To(Equal(AgentRequestMessage{
+					Method:    "add_persistent_disk",
+					Arguments: []interface{}{"fake-disk-cid", map[string]interface{}{"path": "/dev/sdc"}},
+					ReplyTo:   "fake-uuid",
+				}))
+			})
+		})
+
+		Context("when the agent does not implement add_persistent_disk", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"exception":{"message":"unknown message add_persistent_disk"}}`, 200, nil)
+			})
+
+			It("returns an unsupported message error", func() {
+				err := agentClient.AddPersistentDisk("fake-disk-cid", "/dev/sdc")
+				Expect(err).To(Equal(NewUnsupportedMessageError("add_persistent_disk")))
+			})
+		})
+
+		Context("when agent responds with an exception", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"exception":{"message":"fake-exception"}}`, 200, nil)
+			})
+
+			It("returns an error", func() {
+				err := agentClient.AddPersistentDisk("fake-disk-cid", "/dev/sdc")
+				Expect(err).To(HaveOccurred())
+				Expect(err).ToNot(BeAssignableToTypeOf(UnsupportedMessageError{}))
+				Expect(err.Error()).To(ContainSubstring("Sending 'add_persistent_disk' to the agent"))
+				Expect(err.Error()).To(ContainSubstring("fake-exception"))
+			})
+		})
+	})
+
+	Describe("FetchLogs", func() {
+		Context("when agent responds with a value", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"blobstore_id":"fake-logs-blobstore-id","sha1":"fake-logs-sha1"}}`, 200, nil)
+			})
+
+			It("makes a fetch_logs request and waits for the task to be done", func() {
+				logsBlobRef, err := agentClient.FetchLogs("job", []string{"fake-job-name/**/*"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(logsBlobRef).To(Equal(boshagentclient.BlobRef{
+					BlobstoreID: "fake-logs-blobstore-id",
+					SHA1:        "fake-logs-sha1",
+				}))
+
+				Expect(fakeHTTPClient.PostInputs).To(HaveLen(3))
+				Expect(fakeHTTPClient.PostInputs[0].Endpoint).To(Equal("http://localhost:6305/agent"))
+
+				var request AgentRequestMessage
+				err = json.Unmarshal(fakeHTTPClient.PostInputs[0].Payload, &request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(request).To(Equal(AgentRequestMessage{
+					Method:    "fetch_logs",
+					Arguments: []interface{}{"job", []interface{}{"fake-job-name/**/*"}},
+					ReplyTo:   "fake-uuid",
+				}))
+
+				err = json.Unmarshal(fakeHTTPClient.PostInputs[1].Payload, &request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(request).To(Equal(AgentRequestMessage{
+					Method:    "get_task",
+					Arguments: []interface{}{"fake-agent-task-id"},
+					ReplyTo:   "fake-uuid",
+				}))
+			})
+		})
+
+		Context("when the agent does not report the sha1 of the logs", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"blobstore_id":"fake-logs-blobstore-id"}}`, 200, nil)
+			})
+
+			It("returns a blob ref without sha1", func() {
+				logsBlobRef, err := agentClient.FetchLogs("job", nil)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(logsBlobRef).To(Equal(boshagentclient.BlobRef{BlobstoreID: "fake-logs-blobstore-id"}))
+			})
+		})
+
+		Context("when agent responds without a blobstore id", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{}}`, 200, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := agentClient.FetchLogs("job", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Unable to parse 'fetch_logs' response"))
+			})
+		})
+
+		Context("when agent responds with an exception", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"exception":{"message":"bad request"}}`, 200, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := agentClient.FetchLogs("job", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Sending 'fetch_logs' to the agent"))
+				Expect(err.Error()).To(ContainSubstring("bad request"))
+			})
+		})
+
+		Context("when agent responds with a non-successful status code", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior("", 500, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := agentClient.FetchLogs("job", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Agent responded with non-successful status code: 500"))
+			})
+		})
+
+		Context("when the http client errors more times than the error retry count while getting the task", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 1"))
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 2"))
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 3"))
+			})
+
+			It("returns the error", func() {
+				_, err := agentClient.FetchLogs("job", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("connection reset by peer 3"))
+			})
+		})
+	})
+
+	Describe("RunErrand", func() {
+		Context("when agent responds with a value", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"exit_code":1,"stdout":"fake-stdout","stderr":"fake-stderr"}}`, 200, nil)
+			})
+
+			It("makes a run_errand request and waits for the task to be done", func() {
+				result, err := agentClient.RunErrand()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(Equal(ErrandResult{
+					ExitStatus: 1,
+					Stdout:     "fake-stdout",
+					Stderr:     "fake-stderr",
+				}))
+
+				Expect(fakeHTTPClient.PostInputs).To(HaveLen(3))
+
+				var request AgentRequestMessage
+				err = json.Unmarshal(fakeHTTPClient.PostInputs[0].Payload, &request)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(request).To(Equal(AgentRequestMessage{
+					Method:    "run_errand",
+					Arguments: []interface{}{},
+					ReplyTo:   "fake-uuid",
+				}))
+			})
+		})
+
+		Context("when sending get_task fails", func() {
+			It("retries like the other async task messages", func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer"))
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer"))
+				fakeHTTPClient.SetPostBehavior(`{"value":{"exit_code":0,"stdout":"fake-stdout","stderr":""}}`, 200, nil)
+
+				result, err := agentClient.RunErrand()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Stdout).To(Equal("fake-stdout"))
+			})
+
+			It("returns the error once the tolerated error count is exceeded", func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 1"))
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 2"))
+				fakeHTTPClient.SetPostBehavior("", 0, errors.New("connection reset by peer 3"))
+
+				_, err := agentClient.RunErrand()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("connection reset by peer 3"))
+			})
+		})
+
+		Context("when agent responds without an exit code", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"value":{"agent_task_id":"fake-agent-task-id","state":"running"}}`, 200, nil)
+				fakeHTTPClient.SetPostBehavior(`{"value":{"stdout":"fake-stdout"}}`, 200, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := agentClient.RunErrand()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Unable to parse 'run_errand' response"))
+			})
+		})
+
+		Context("when agent responds with an exception", func() {
+			BeforeEach(func() {
+				fakeHTTPClient.SetPostBehavior(`{"exception":{"message":"bad request"}}`, 200, nil)
+			})
+
+			It("returns an error", func() {
+				_, err := agentClient.RunErrand()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Sending 'run_errand' to the agent"))
+			})
+		})
+	})
 })
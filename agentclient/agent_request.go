@@ -1,4 +1,4 @@
-package http
+package agentclient
 
 import (
 	"encoding/json"
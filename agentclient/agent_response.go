@@ -1,10 +1,11 @@
-package http
+package agentclient
 
 import (
 	"encoding/json"
+	"runtime/debug"
+	"strings"
 
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
-	"runtime/debug"
 )
 
 type Response interface {
@@ -143,3 +144,25 @@ func (r *TaskResponse) TaskState() (string, error) {
 
 	return "finished", nil
 }
+
+// ExceptionResponse is the response of messages whose value is not used
+type ExceptionResponse struct {
+	Value     interface{}
+	Exception *exception
+}
+
+func (r *ExceptionResponse) ServerError() error {
+	if r.Exception != nil {
+		return bosherr.Errorf("Agent responded with error: %s", r.Exception.Message)
+	}
+	return nil
+}
+
+func (r *ExceptionResponse) Unmarshal(message []byte) error {
+	return json.Unmarshal(message, r)
+}
+
+// IsUnknownMessage is true when the agent does not implement the message it was sent
+func (r *ExceptionResponse) IsUnknownMessage() bool {
+	return r.Exception != nil && strings.HasPrefix(r.Exception.Message, "unknown message")
+}
@@ -1,7 +1,7 @@
-package http_test
+package agentclient_test
 
 import (
-	. "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
+	. "github.com/cloudfoundry/bosh-init/agentclient"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 )
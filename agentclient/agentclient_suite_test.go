@@ -1,12 +1,13 @@
-package http_test
+package agentclient_test
 
 import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
 	"testing"
 )
 
 func TestAgentclient(t *testing.T) {
 	RegisterFailHandler(Fail)
-	RunSpecs(t, "HTTP AgentClient Suite")
+	RunSpecs(t, "Agentclient Suite")
 }
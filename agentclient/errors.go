@@ -0,0 +1,22 @@
+package agentclient
+
+import (
+	"fmt"
+)
+
+// UnsupportedMessageError is returned for messages the agent does not implement, e.g. because its stemcell is too old
+type UnsupportedMessageError struct {
+	method string
+}
+
+func NewUnsupportedMessageError(method string) UnsupportedMessageError {
+	return UnsupportedMessageError{method: method}
+}
+
+func (e UnsupportedMessageError) Error() string {
+	return fmt.Sprintf("Agent does not support '%s'", e.method)
+}
+
+func (e UnsupportedMessageError) Method() string {
+	return e.method
+}
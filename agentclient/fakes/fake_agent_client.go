@@ -0,0 +1,73 @@
+package fakes
+
+import (
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	fakeboshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/fakes"
+)
+
+type FakeAgentClient struct {
+	*fakeboshagentclient.FakeAgentClient
+
+	AddPersistentDiskInputs []AddPersistentDiskInput
+	addPersistentDiskErr    error
+
+	FetchLogsInputs  []FetchLogsInput
+	fetchLogsBlobRef boshagentclient.BlobRef
+	fetchLogsErr     error
+
+	RunErrandCalledTimes int
+	runErrandResult      biagentclient.ErrandResult
+	runErrandErr         error
+}
+
+type AddPersistentDiskInput struct {
+	DiskCID  string
+	DiskHint interface{}
+}
+
+type FetchLogsInput struct {
+	LogType string
+	Filters []string
+}
+
+func NewFakeAgentClient() *FakeAgentClient {
+	return &FakeAgentClient{
+		FakeAgentClient: fakeboshagentclient.NewFakeAgentClient(),
+	}
+}
+
+func (c *FakeAgentClient) AddPersistentDisk(diskCID string, diskHint interface{}) error {
+	c.AddPersistentDiskInputs = append(c.AddPersistentDiskInputs, AddPersistentDiskInput{
+		DiskCID:  diskCID,
+		DiskHint: diskHint,
+	})
+	return c.addPersistentDiskErr
+}
+
+func (c *FakeAgentClient) FetchLogs(logType string, filters []string) (boshagentclient.BlobRef, error) {
+	c.FetchLogsInputs = append(c.FetchLogsInputs, FetchLogsInput{
+		LogType: logType,
+		Filters: filters,
+	})
+	return c.fetchLogsBlobRef, c.fetchLogsErr
+}
+
+func (c *FakeAgentClient) RunErrand() (biagentclient.ErrandResult, error) {
+	c.RunErrandCalledTimes++
+	return c.runErrandResult, c.runErrandErr
+}
+
+func (c *FakeAgentClient) SetAddPersistentDiskBehavior(err error) {
+	c.addPersistentDiskErr = err
+}
+
+func (c *FakeAgentClient) SetFetchLogsBehavior(logsBlobRef boshagentclient.BlobRef, err error) {
+	c.fetchLogsBlobRef = logsBlobRef
+	c.fetchLogsErr = err
+}
+
+func (c *FakeAgentClient) SetRunErrandBehavior(result biagentclient.ErrandResult, err error) {
+	c.runErrandResult = result
+	c.runErrandErr = err
+}
@@ -4,7 +4,6 @@ import (
 	"io"
 	"os"
 
-	boshdavcli "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/client"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
@@ -14,6 +13,7 @@ import (
 type Blobstore interface {
 	Get(blobID string) (LocalBlob, error)
 	Add(sourcePath string) (blobID string, err error)
+	Delete(blobID string) error
 }
 
 type Config struct {
@@ -23,14 +23,14 @@ type Config struct {
 }
 
 type blobstore struct {
-	davClient     boshdavcli.Client
+	davClient     DavClient
 	uuidGenerator boshuuid.Generator
 	fs            boshsys.FileSystem
 	logger        boshlog.Logger
 	logTag        string
 }
 
-func NewBlobstore(davClient boshdavcli.Client, uuidGenerator boshuuid.Generator, fs boshsys.FileSystem, logger boshlog.Logger) Blobstore {
+func NewBlobstore(davClient DavClient, uuidGenerator boshuuid.Generator, fs boshsys.FileSystem, logger boshlog.Logger) Blobstore {
 	return &blobstore{
 		davClient:     davClient,
 		uuidGenerator: uuidGenerator,
@@ -103,3 +103,14 @@ func (b *blobstore) Add(sourcePath string) (string, error) {
 
 	return blobID, nil
 }
+
+func (b *blobstore) Delete(blobID string) error {
+	b.logger.Debug(b.logTag, "Deleting blob %s", blobID)
+
+	err := b.davClient.Delete(blobID)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deleting blob %s from blobstore", blobID)
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net/url"
 
-	boshdavcli "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/client"
 	boshdavcliconf "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/config"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	bihttpclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient"
@@ -40,7 +39,7 @@ func (f blobstoreFactory) Create(blobstoreURL string) (Blobstore, error) {
 
 	httpClient := bihttpclient.DefaultClient
 
-	davClient := boshdavcli.NewClient(boshdavcliconf.Config{
+	davClient := NewDavClient(boshdavcliconf.Config{
 		Endpoint: fmt.Sprintf("%s/blobs", blobstoreConfig.Endpoint),
 		User:     blobstoreConfig.Username,
 		Password: blobstoreConfig.Password,
@@ -6,7 +6,7 @@ import (
 	"strings"
 
 	. "github.com/cloudfoundry/bosh-init/blobstore"
-	fakeboshdavcli "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/client/fakes"
+	fakeblobstore "github.com/cloudfoundry/bosh-init/blobstore/fakes"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
 	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
@@ -16,14 +16,14 @@ import (
 
 var _ = Describe("Blobstore", func() {
 	var (
-		fakeDavClient     *fakeboshdavcli.FakeClient
+		fakeDavClient     *fakeblobstore.FakeDavClient
 		fakeUUIDGenerator *fakeuuid.FakeGenerator
 		fs                *fakesys.FakeFileSystem
 		blobstore         Blobstore
 	)
 
 	BeforeEach(func() {
-		fakeDavClient = fakeboshdavcli.NewFakeClient()
+		fakeDavClient = fakeblobstore.NewFakeDavClient()
 		fakeUUIDGenerator = fakeuuid.NewFakeGenerator()
 		fs = fakesys.NewFakeFileSystem()
 		logger := boshlog.NewLogger(boshlog.LevelNone)
@@ -92,4 +92,22 @@ var _ = Describe("Blobstore", func() {
 			Expect(fakeDavClient.PutContents).To(Equal("fake-contents"))
 		})
 	})
+
+	Describe("Delete", func() {
+		It("deletes the blob from the blobstore", func() {
+			err := blobstore.Delete("fake-blob-id")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeDavClient.DeleteBlobID).To(Equal("fake-blob-id"))
+		})
+
+		Context("when deleting from blobstore fails", func() {
+			It("returns an error", func() {
+				fakeDavClient.DeleteErr = errors.New("fake-delete-error")
+
+				err := blobstore.Delete("fake-blob-id")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-delete-error"))
+			})
+		})
+	})
 })
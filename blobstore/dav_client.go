@@ -0,0 +1,70 @@
+package blobstore
+
+import (
+	"net/http"
+
+	boshdavcli "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/client"
+	boshdavcliconf "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/config"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshhttp "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/http"
+)
+
+// DavClient is the dav client of the agent blobstore.
+// The vendored dav client cannot delete blobs at its pinned revision, so Delete sends the request it builds for the blob with the DELETE method.
+type DavClient interface {
+	boshdavcli.Client
+	Delete(blobID string) error
+}
+
+type davClient struct {
+	boshdavcli.Client
+	config     boshdavcliconf.Config
+	httpClient boshhttp.Client
+}
+
+func NewDavClient(config boshdavcliconf.Config, httpClient boshhttp.Client) DavClient {
+	return davClient{
+		Client:     boshdavcli.NewClient(config, httpClient),
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// Delete deletes the blob, succeeding if the blob does not exist
+func (c davClient) Delete(blobID string) error {
+	// the vendored dav client builds the request of the blob, with its blob path & credentials
+	recorder := &requestRecorder{}
+	_, err := boshdavcli.NewClient(c.config, recorder).Get(blobID)
+	if recorder.request == nil {
+		return bosherr.WrapErrorf(err, "Creating request to delete dav blob %s", blobID)
+	}
+
+	req := recorder.request
+	req.Method = "DELETE"
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Deleting dav blob %s", blobID)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return bosherr.Errorf("Deleting dav blob %s: Wrong response code: %d", blobID, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// requestRecorder records the request of the vendored dav client instead of sending it
+type requestRecorder struct {
+	request *http.Request
+}
+
+func (r *requestRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.request = req
+	return nil, bosherr.Error("Request recorded")
+}
@@ -0,0 +1,90 @@
+package blobstore_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry/bosh-init/blobstore"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	boshdavcliconf "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/config"
+	fakehttp "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/http/fakes"
+)
+
+var _ = Describe("DavClient", func() {
+	var (
+		fakeHTTPClient *fakehttp.FakeClient
+		davClient      DavClient
+	)
+
+	BeforeEach(func() {
+		fakeHTTPClient = fakehttp.NewFakeClient()
+		davClient = NewDavClient(boshdavcliconf.Config{
+			Endpoint: "https://fake-host:1234/blobs",
+			User:     "fake-user",
+			Password: "fake-password",
+		}, fakeHTTPClient)
+	})
+
+	Describe("Delete", func() {
+		It("deletes the blob at the path the dav client stores it under", func() {
+			fakeHTTPClient.StatusCode = 200
+			_, err := davClient.Get("fake-blob-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeHTTPClient.StatusCode = 204
+			err = davClient.Delete("fake-blob-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeHTTPClient.Requests).To(HaveLen(2))
+			request := fakeHTTPClient.Requests[1]
+			Expect(request.Method).To(Equal("DELETE"))
+			Expect(request.URL.String()).To(Equal("https://fake-host:1234/blobs/80/fake-blob-id"))
+			Expect(request.URL).To(Equal(fakeHTTPClient.Requests[0].URL))
+
+			user, password, ok := request.BasicAuth()
+			Expect(ok).To(BeTrue())
+			Expect(user).To(Equal("fake-user"))
+			Expect(password).To(Equal("fake-password"))
+		})
+
+		It("does not return an error if the blob does not exist", func() {
+			fakeHTTPClient.StatusCode = 404
+
+			err := davClient.Delete("fake-blob-id")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("when the request cannot be built", func() {
+			It("returns an error", func() {
+				davClient = NewDavClient(boshdavcliconf.Config{Endpoint: "%"}, fakeHTTPClient)
+
+				err := davClient.Delete("fake-blob-id")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Creating request to delete dav blob fake-blob-id"))
+				Expect(fakeHTTPClient.Requests).To(BeEmpty())
+			})
+		})
+
+		Context("when the http request fails", func() {
+			It("returns an error", func() {
+				fakeHTTPClient.Error = errors.New("fake-http-error")
+
+				err := davClient.Delete("fake-blob-id")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Deleting dav blob fake-blob-id"))
+				Expect(err.Error()).To(ContainSubstring("fake-http-error"))
+			})
+		})
+
+		Context("when the http response code is not 200 or 204", func() {
+			It("returns an error", func() {
+				fakeHTTPClient.StatusCode = 500
+
+				err := davClient.Delete("fake-blob-id")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deleting dav blob fake-blob-id: Wrong response code: 500"))
+			})
+		})
+	})
+})
@@ -7,6 +7,9 @@ type FakeBlobstore struct {
 	AddInputs []AddInput
 	AddBlobID string
 	AddErr    error
+
+	DeleteInputs []DeleteInput
+	DeleteErr    error
 }
 
 type GetInput struct {
@@ -18,6 +21,10 @@ type AddInput struct {
 	SourcePath string
 }
 
+type DeleteInput struct {
+	BlobID string
+}
+
 func NewFakeBlobstore() *FakeBlobstore {
 	return &FakeBlobstore{}
 }
@@ -38,3 +45,11 @@ func (b *FakeBlobstore) Add(sourcePath string) (blobID string, err error) {
 
 	return b.AddBlobID, b.AddErr
 }
+
+func (b *FakeBlobstore) Delete(blobID string) error {
+	b.DeleteInputs = append(b.DeleteInputs, DeleteInput{
+		BlobID: blobID,
+	})
+
+	return b.DeleteErr
+}
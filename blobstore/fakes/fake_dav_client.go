@@ -0,0 +1,24 @@
+package fakes
+
+import (
+	fakeboshdavcli "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-davcli/client/fakes"
+)
+
+type FakeDavClient struct {
+	*fakeboshdavcli.FakeClient
+
+	DeleteBlobID string
+	DeleteErr    error
+}
+
+func NewFakeDavClient() *FakeDavClient {
+	return &FakeDavClient{
+		FakeClient: fakeboshdavcli.NewFakeClient(),
+	}
+}
+
+func (c *FakeDavClient) Delete(blobID string) error {
+	c.DeleteBlobID = blobID
+
+	return c.DeleteErr
+}
@@ -18,18 +18,20 @@ type Cloud interface {
 		cloudProperties biproperty.Map,
 		networksInterfaces map[string]biproperty.Map,
 		env biproperty.Map,
-	) (vmCID string, err error)
+	) (vmCID string, networks map[string]biproperty.Map, err error)
 	SetVMMetadata(cmCID string, metadata VMMetadata) error
 	DeleteVM(vmCID string) error
 	CreateDisk(size int, cloudProperties biproperty.Map, vmCID string) (diskCID string, err error)
-	AttachDisk(vmCID, diskCID string) error
+	AttachDisk(vmCID, diskCID string) (diskHint interface{}, err error)
 	DetachDisk(vmCID, diskCID string) error
 	DeleteDisk(diskCID string) error
+	Info() CPIInfo
 	fmt.Stringer
 }
 
 type cloud struct {
 	cpiCmdRunner CPICmdRunner
+	info         CPIInfo
 	context      CmdContext
 	logger       boshlog.Logger
 	logTag       string
@@ -42,14 +44,17 @@ type VMMetadata struct {
 	Index      string `json:"index"`
 }
 
+// NewCloud returns a Cloud that calls the CPI with the API version negotiated from the info of the CPI
 func NewCloud(
 	cpiCmdRunner CPICmdRunner,
 	directorID string,
+	info CPIInfo,
 	logger boshlog.Logger,
 ) Cloud {
 	return cloud{
 		cpiCmdRunner: cpiCmdRunner,
-		context:      CmdContext{DirectorID: directorID},
+		info:         info,
+		context:      CmdContext{DirectorID: directorID, APIVersion: info.NegotiatedAPIVersion()},
 		logger:       logger,
 		logTag:       "cloud",
 	}
@@ -110,13 +115,14 @@ func (c cloud) HasVM(vmCID string) (bool, error) {
 	return found, nil
 }
 
+// CreateVM returns the networks of the vm reported by the CPI with API version 2, or else nil
 func (c cloud) CreateVM(
 	agentID string,
 	stemcellCID string,
 	cloudProperties biproperty.Map,
 	networksInterfaces map[string]biproperty.Map,
 	env biproperty.Map,
-) (string, error) {
+) (string, map[string]biproperty.Map, error) {
 	method := "create_vm"
 	diskLocality := []interface{}{} // not used with bosh-init
 	cmdOutput, err := c.cpiCmdRunner.Run(
@@ -130,19 +136,59 @@ func (c cloud) CreateVM(
 		env,
 	)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
 	if cmdOutput.Error != nil {
-		return "", NewCPIError(method, *cmdOutput.Error)
+		return "", nil, NewCPIError(method, *cmdOutput.Error)
 	}
 
-	// for create_vm, the result is a string of the vm cid
-	cidString, ok := cmdOutput.Result.(string)
+	// for create_vm, the result is a string of the vm cid,
+	// or with API version 2, an array of the vm cid and of the networks of the vm
+	result := cmdOutput.Result
+	var networks map[string]biproperty.Map
+	if resultArray, ok := result.([]interface{}); ok && len(resultArray) > 0 && c.context.APIVersion >= 2 {
+		result = resultArray[0]
+		if len(resultArray) > 1 {
+			networks, ok = vmNetworks(resultArray[1])
+			if !ok {
+				return "", nil, bosherr.Errorf("Unexpected external CPI command result: '%#v'", cmdOutput.Result)
+			}
+		}
+	}
+
+	cidString, ok := result.(string)
 	if !ok {
-		return "", bosherr.Errorf("Unexpected external CPI command result: '%#v'", cmdOutput.Result)
+		return "", nil, bosherr.Errorf("Unexpected external CPI command result: '%#v'", cmdOutput.Result)
 	}
-	return cidString, nil
+	return cidString, networks, nil
+}
+
+// vmNetworks converts the networks returned by create_vm, a hash of network settings by network name
+func vmNetworks(rawNetworks interface{}) (map[string]biproperty.Map, bool) {
+	if rawNetworks == nil {
+		return nil, true
+	}
+
+	rawNetworksMap, ok := rawNetworks.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	networks := map[string]biproperty.Map{}
+	for networkName, rawNetwork := range rawNetworksMap {
+		rawNetworkMap, ok := rawNetwork.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		network := biproperty.Map{}
+		for key, value := range rawNetworkMap {
+			network[key] = value
+		}
+		networks[networkName] = network
+	}
+	return networks, true
 }
 
 func (c cloud) SetVMMetadata(vmCID string, metadata VMMetadata) error {
@@ -194,7 +240,8 @@ func (c cloud) CreateDisk(size int, cloudProperties biproperty.Map, vmCID string
 	return cidString, nil
 }
 
-func (c cloud) AttachDisk(vmCID, diskCID string) error {
+// AttachDisk returns the disk hint of the CPI with API version 2, which tells the agent how to find the disk, or else nil
+func (c cloud) AttachDisk(vmCID, diskCID string) (interface{}, error) {
 	c.logger.Debug(c.logTag, "Attaching disk '%s' to vm '%s'", diskCID, vmCID)
 	method := "attach_disk"
 	cmdOutput, err := c.cpiCmdRunner.Run(
@@ -204,14 +251,18 @@ func (c cloud) AttachDisk(vmCID, diskCID string) error {
 		diskCID,
 	)
 	if err != nil {
-		return bosherr.WrapError(err, "Calling CPI 'attach_disk' method")
+		return nil, bosherr.WrapError(err, "Calling CPI 'attach_disk' method")
 	}
 
 	if cmdOutput.Error != nil {
-		return NewCPIError(method, *cmdOutput.Error)
+		return nil, NewCPIError(method, *cmdOutput.Error)
 	}
 
-	return nil
+	if c.context.APIVersion < 2 {
+		return nil, nil
+	}
+
+	return cmdOutput.Result, nil
 }
 
 func (c cloud) DetachDisk(vmCID, diskCID string) error {
@@ -264,6 +315,10 @@ func (c cloud) DeleteDisk(diskCID string) error {
 	return nil
 }
 
+func (c cloud) Info() CPIInfo {
+	return c.info
+}
+
 func (c cloud) String() string {
 	return fmt.Sprintf("Cloud{Context=%s}", c.context)
 }
@@ -23,8 +23,8 @@ var _ = Describe("Cloud", func() {
 	BeforeEach(func() {
 		fakeCPICmdRunner = fakebicloud.NewFakeCPICmdRunner()
 		logger := boshlog.NewLogger(boshlog.LevelNone)
-		cloud = NewCloud(fakeCPICmdRunner, "fake-director-id", logger)
-		context = CmdContext{DirectorID: "fake-director-id"}
+		cloud = NewCloud(fakeCPICmdRunner, "fake-director-id", CPIInfo{}, logger)
+		context = CmdContext{DirectorID: "fake-director-id", APIVersion: 1}
 	})
 
 	var itHandlesCPIErrors = func(method string, exec func() error) {
@@ -236,7 +236,7 @@ var _ = Describe("Cloud", func() {
 			})
 
 			It("executes the cpi job script with the director UUID and stemcell CID", func() {
-				_, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeCPICmdRunner.RunInputs).To(HaveLen(1))
 				Expect(fakeCPICmdRunner.RunInputs[0]).To(Equal(fakebicloud.RunInput{
@@ -254,12 +254,60 @@ var _ = Describe("Cloud", func() {
 			})
 
 			It("returns the cid returned from executing the cpi script", func() {
-				cid, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				cid, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(cid).To(Equal("fake-vm-cid"))
 			})
 		})
 
+		Context("when the cpi supports API version 2", func() {
+			BeforeEach(func() {
+				cloud = NewCloud(fakeCPICmdRunner, "fake-director-id", CPIInfo{APIVersion: 2}, boshlog.NewLogger(boshlog.LevelNone))
+				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+					Result: []interface{}{
+						"fake-vm-cid",
+						map[string]interface{}{"bosh": map[string]interface{}{"ip": "10.0.0.5"}},
+					},
+				}
+			})
+
+			It("sends the API version to the cpi", func() {
+				_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeCPICmdRunner.RunInputs[0].Context).To(Equal(CmdContext{DirectorID: "fake-director-id", APIVersion: 2}))
+			})
+
+			It("returns the vm cid and networks returned by the cpi", func() {
+				cid, networks, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cid).To(Equal("fake-vm-cid"))
+				Expect(networks).To(Equal(map[string]biproperty.Map{
+					"bosh": biproperty.Map{"ip": "10.0.0.5"},
+				}))
+			})
+
+			It("returns no networks when the cpi only returns the vm cid", func() {
+				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+					Result: "fake-vm-cid",
+				}
+
+				cid, networks, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cid).To(Equal("fake-vm-cid"))
+				Expect(networks).To(BeNil())
+			})
+
+			It("returns an error when the networks are of an unexpected type", func() {
+				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+					Result: []interface{}{"fake-vm-cid", "fake-networks"},
+				}
+
+				_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Unexpected external CPI command result"))
+			})
+		})
+
 		Context("when the result is of an unexpected type", func() {
 			BeforeEach(func() {
 				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
@@ -268,7 +316,7 @@ var _ = Describe("Cloud", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("Unexpected external CPI command result: '1'"))
 			})
@@ -280,14 +328,14 @@ var _ = Describe("Cloud", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+				_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-run-error"))
 			})
 		})
 
 		itHandlesCPIErrors("create_vm", func() error {
-			_, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
+			_, _, err := cloud.CreateVM(agentID, stemcellCID, cloudProperties, networkInterfaces, env)
 			return err
 		})
 	})
@@ -421,7 +469,7 @@ var _ = Describe("Cloud", func() {
 	Describe("AttachDisk", func() {
 		Context("when the cpi successfully attaches the disk", func() {
 			It("executes the cpi job script with the correct arguments", func() {
-				err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+				_, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(fakeCPICmdRunner.RunInputs).To(HaveLen(1))
 				Expect(fakeCPICmdRunner.RunInputs[0]).To(Equal(fakebicloud.RunInput{
@@ -433,6 +481,31 @@ var _ = Describe("Cloud", func() {
 					},
 				}))
 			})
+
+			It("returns no disk hint", func() {
+				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+					Result: "fake-disk-hint",
+				}
+
+				diskHint, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diskHint).To(BeNil())
+			})
+		})
+
+		Context("when the cpi supports API version 2", func() {
+			BeforeEach(func() {
+				cloud = NewCloud(fakeCPICmdRunner, "fake-director-id", CPIInfo{APIVersion: 2}, boshlog.NewLogger(boshlog.LevelNone))
+				fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+					Result: map[string]interface{}{"path": "/dev/sdc"},
+				}
+			})
+
+			It("returns the disk hint returned by the cpi", func() {
+				diskHint, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(diskHint).To(Equal(map[string]interface{}{"path": "/dev/sdc"}))
+			})
 		})
 
 		Context("when the cpi command execution fails", func() {
@@ -441,14 +514,15 @@ var _ = Describe("Cloud", func() {
 			})
 
 			It("returns an error", func() {
-				err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+				_, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-run-error"))
 			})
 		})
 
 		itHandlesCPIErrors("attach_disk", func() error {
-			return cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+			_, err := cloud.AttachDisk("fake-vm-cid", "fake-disk-cid")
+			return err
 		})
 	})
 
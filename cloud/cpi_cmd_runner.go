@@ -4,20 +4,37 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 )
 
+// cpiKillGracePeriod is how long a CPI process group may take to exit after SIGTERM, before it gets SIGKILL
+const cpiKillGracePeriod = 10 * time.Second
+
 type CmdInput struct {
-	Method    string        `json:"method"`
-	Arguments []interface{} `json:"arguments"`
-	Context   CmdContext    `json:"context"`
+	Method     string        `json:"method"`
+	Arguments  []interface{} `json:"arguments"`
+	Context    CmdContext    `json:"context"`
+	APIVersion int           `json:"api_version,omitempty"`
 }
 
 type CmdContext struct {
 	DirectorID string `json:"director_uuid"`
+
+	// RequestID identifies the call in the logs of bosh-init and of the CPI. It is generated for each call.
+	RequestID string `json:"request_id,omitempty"`
+
+	// APIVersion is the CPI API version of the call. It is sent as the 'api_version' of the request, not in its context.
+	APIVersion int `json:"-"`
 }
 
 func (c CmdContext) String() string {
@@ -53,30 +70,59 @@ type CPICmdRunner interface {
 }
 
 type cpiCmdRunner struct {
-	cmdRunner boshsys.CmdRunner
-	cpi       CPI
-	logger    boshlog.Logger
-	logTag    string
+	cmdRunner     boshsys.CmdRunner
+	cpi           CPI
+	timeouts      Timeouts
+	interrupter   Interrupter
+	uuidGenerator boshuuid.Generator
+	timeService   clock.Clock
+	redactor      bilog.Redactor
+	cpiLogger     boshlog.Logger
+	logger        boshlog.Logger
+	logTag        string
 }
 
+// NewCPICmdRunner returns a CPICmdRunner that kills the CPI process group when a call takes longer than the timeout of its method,
+// or when bosh-init is interrupted.
+// The stderr of the CPI is logged line by line while it runs. All the traffic with the CPI is also logged to cpiLogger.
+// Secrets are masked with the redactor before anything is logged.
 func NewCPICmdRunner(
 	cmdRunner boshsys.CmdRunner,
 	cpi CPI,
+	timeouts Timeouts,
+	interrupter Interrupter,
+	uuidGenerator boshuuid.Generator,
+	timeService clock.Clock,
+	redactor bilog.Redactor,
+	cpiLogger boshlog.Logger,
 	logger boshlog.Logger,
 ) CPICmdRunner {
 	return &cpiCmdRunner{
-		cmdRunner: cmdRunner,
-		cpi:       cpi,
-		logger:    logger,
-		logTag:    "cpiCmdRunner",
+		cmdRunner:     cmdRunner,
+		cpi:           cpi,
+		timeouts:      timeouts,
+		interrupter:   interrupter,
+		uuidGenerator: uuidGenerator,
+		timeService:   timeService,
+		redactor:      redactor,
+		cpiLogger:     cpiLogger,
+		logger:        logger,
+		logTag:        "cpiCmdRunner",
 	}
 }
 
 func (r *cpiCmdRunner) Run(context CmdContext, method string, args ...interface{}) (CmdOutput, error) {
+	requestID, err := r.uuidGenerator.Generate()
+	if err != nil {
+		return CmdOutput{}, bosherr.WrapError(err, "Generating CPI request id")
+	}
+	context.RequestID = "cpi-" + requestID
+
 	cmdInput := CmdInput{
-		Method:    method,
-		Arguments: args,
-		Context:   context,
+		Method:     method,
+		Arguments:  args,
+		Context:    context,
+		APIVersion: context.APIVersion,
 	}
 	inputBytes, err := json.Marshal(cmdInput)
 	if err != nil {
@@ -94,19 +140,92 @@ func (r *cpiCmdRunner) Run(context CmdContext, method string, args ...interface{
 		UseIsolatedEnv: true,
 		Stdin:          bytes.NewReader(inputBytes),
 	}
-	stdout, stderr, exitCode, err := r.cmdRunner.RunComplexCommand(cmd)
-	r.logger.Debug(r.logTag, "Exit Code %d when executing external CPI command '%s'\nSTDIN: '%s'\nSTDOUT: '%s'\nSTDERR: '%s'", exitCode, cmdPath, string(inputBytes), stdout, stderr)
+
+	r.logCall(context.RequestID, method, "request", r.redactor.RedactJSON(inputBytes))
+
+	stderrBuffer := bytes.NewBufferString("")
+	stderrWriter := newLineWriter(func(line string) {
+		r.logCall(context.RequestID, method, "stderr", r.redactor.RedactText(line))
+	})
+	cmd.Stderr = io.MultiWriter(stderrBuffer, stderrWriter)
+
+	// listen for interrupts before starting the process, so that it is never left running
+	interrupts := make(chan os.Signal, 1)
+	r.interrupter.Notify(interrupts)
+	defer r.interrupter.Stop(interrupts)
+
+	process, err := r.cmdRunner.RunComplexCommandAsync(cmd)
 	if err != nil {
 		return CmdOutput{}, bosherr.WrapErrorf(err, "Executing external CPI command: '%s'", cmdPath)
 	}
 
+	timeout := r.timeouts.For(method)
+	timer := r.timeService.NewTimer(timeout)
+	defer timer.Stop()
+
+	resultCh := process.Wait()
+
+	var result boshsys.Result
+	select {
+	case result = <-resultCh:
+	case <-timer.C():
+		r.logger.Error(r.logTag, "External CPI command '%s' method '%s' timed out after %s, killing it", cmdPath, method, timeout)
+		result = r.terminate(process, resultCh)
+		r.logResult(context.RequestID, method, cmdPath, result, stderrWriter)
+		return CmdOutput{
+			Error: &CmdError{
+				Type:    TimeoutError,
+				Message: fmt.Sprintf("Timed out after %s", timeout),
+			},
+		}, nil
+	case sig := <-interrupts:
+		r.logger.Error(r.logTag, "External CPI command '%s' method '%s' interrupted by signal '%s', killing it", cmdPath, method, sig)
+		result = r.terminate(process, resultCh)
+		r.logResult(context.RequestID, method, cmdPath, result, stderrWriter)
+		return CmdOutput{}, bosherr.Errorf("Interrupted external CPI command '%s' method '%s'", cmdPath, method)
+	}
+
+	r.logResult(context.RequestID, method, cmdPath, result, stderrWriter)
+	stdout, stderr := result.Stdout, stderrBuffer.String()
+	if result.Error != nil {
+		// the error contains the stdout of the CPI
+		redactedErr := bosherr.Error(r.redactor.RedactText(result.Error.Error()))
+		return CmdOutput{}, bosherr.WrapErrorf(redactedErr, "Executing external CPI command: '%s'", cmdPath)
+	}
+
 	cmdOutput := CmdOutput{}
 	err = json.Unmarshal([]byte(stdout), &cmdOutput)
 	if err != nil {
-		return CmdOutput{}, bosherr.WrapErrorf(err, "Unmarshalling external CPI command output: STDOUT: '%s', STDERR: '%s'", stdout, stderr)
+		return CmdOutput{}, bosherr.WrapErrorf(err, "Unmarshalling external CPI command output: STDOUT: '%s', STDERR: '%s'", r.redactor.RedactText(stdout), r.redactor.RedactText(stderr))
 	}
 
-	r.logger.Debug(r.logTag, cmdOutput.Log)
+	for _, line := range strings.Split(strings.TrimRight(cmdOutput.Log, "\n"), "\n") {
+		if line != "" {
+			r.logCall(context.RequestID, method, "log", r.redactor.RedactText(line))
+		}
+	}
 
 	return cmdOutput, err
 }
+
+// terminate sends SIGTERM to the process group of the CPI, then SIGKILL after cpiKillGracePeriod,
+// and waits for the result of the process
+func (r *cpiCmdRunner) terminate(process boshsys.Process, resultCh <-chan boshsys.Result) boshsys.Result {
+	err := process.TerminateNicely(cpiKillGracePeriod)
+	if err != nil {
+		r.logger.Warn(r.logTag, "Failed to terminate external CPI command: %s", err.Error())
+	}
+	return <-resultCh
+}
+
+// logResult logs the end of the stderr of the CPI and its stdout, which is only complete once it exits
+func (r *cpiCmdRunner) logResult(requestID, method, cmdPath string, result boshsys.Result, stderrWriter *lineWriter) {
+	stderrWriter.Flush()
+	r.logCall(requestID, method, fmt.Sprintf("response from '%s' with exit code %d", cmdPath, result.ExitStatus), r.redactor.RedactJSON([]byte(result.Stdout)))
+}
+
+// logCall logs the traffic with the CPI, prefixed with the method and the request id of the call
+func (r *cpiCmdRunner) logCall(requestID, method, kind, line string) {
+	r.logger.Debug(r.logTag, "[%s %s] %s: %s", method, requestID, kind, line)
+	r.cpiLogger.Debug("cpi", "[%s %s] %s: %s", method, requestID, kind, line)
+}
@@ -1,15 +1,22 @@
 package cloud_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"time"
 
 	. "github.com/cloudfoundry/bosh-init/cloud"
+	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock/fakeclock"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 )
 
 var _ = Describe("CpiCmdRunner", func() {
@@ -18,6 +25,10 @@ var _ = Describe("CpiCmdRunner", func() {
 		context      CmdContext
 		cmdRunner    *fakesys.FakeCmdRunner
 		cpi          CPI
+		interrupter  *fakebicloud.FakeInterrupter
+		fakeClock    *fakeclock.FakeClock
+		logBuffer    *bytes.Buffer
+		cpiLogBuffer *bytes.Buffer
 	)
 
 	BeforeEach(func() {
@@ -32,8 +43,16 @@ var _ = Describe("CpiCmdRunner", func() {
 		}
 
 		cmdRunner = fakesys.NewFakeCmdRunner()
-		logger := boshlog.NewLogger(boshlog.LevelNone)
-		cpiCmdRunner = NewCPICmdRunner(cmdRunner, cpi, logger)
+		logBuffer = bytes.NewBufferString("")
+		logger := boshlog.NewWriterLogger(boshlog.LevelDebug, logBuffer, logBuffer)
+		cpiLogBuffer = bytes.NewBufferString("")
+		cpiLogger := boshlog.NewWriterLogger(boshlog.LevelDebug, cpiLogBuffer, cpiLogBuffer)
+		interrupter = fakebicloud.NewFakeInterrupter()
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		timeouts := Timeouts{"default": time.Minute, "create_vm": time.Hour}
+		redactor, err := bilog.NewRedactor(nil)
+		Expect(err).ToNot(HaveOccurred())
+		cpiCmdRunner = NewCPICmdRunner(cmdRunner, cpi, timeouts, interrupter, fakeuuid.NewFakeGenerator(), fakeClock, redactor, cpiLogger, logger)
 	})
 
 	Describe("Run", func() {
@@ -42,11 +61,11 @@ var _ = Describe("CpiCmdRunner", func() {
 			outputBytes, err := json.Marshal(cmdOutput)
 			Expect(err).NotTo(HaveOccurred())
 
-			result := fakesys.FakeCmdResult{
+			result := boshsys.Result{
 				Stdout:     string(outputBytes),
 				ExitStatus: 0,
 			}
-			cmdRunner.AddCmdResult("/jobs/cpi/bin/cpi", result)
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: result})
 
 			_, err = cpiCmdRunner.Run(context, "fake-method", "fake-argument-1", "fake-argument-2")
 			Expect(err).NotTo(HaveOccurred())
@@ -67,11 +86,81 @@ var _ = Describe("CpiCmdRunner", func() {
 				`{` +
 					`"method":"fake-method",` +
 					`"arguments":["fake-argument-1","fake-argument-2"],` +
-					`"context":{"director_uuid":"fake-director-id"}` +
+					`"context":{"director_uuid":"fake-director-id","request_id":"cpi-fake-uuid-0"}` +
 					`}`,
 			))
 		})
 
+		It("sends the API version of the context", func() {
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: boshsys.Result{Stdout: `{}`}})
+
+			context.APIVersion = 2
+			_, err := cpiCmdRunner.Run(context, "fake-method", "fake-argument")
+			Expect(err).NotTo(HaveOccurred())
+
+			bytes, err := ioutil.ReadAll(cmdRunner.RunComplexCommands[0].Stdin)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(bytes)).To(Equal(
+				`{` +
+					`"method":"fake-method",` +
+					`"arguments":["fake-argument"],` +
+					`"context":{"director_uuid":"fake-director-id","request_id":"cpi-fake-uuid-0"},` +
+					`"api_version":2` +
+					`}`,
+			))
+		})
+
+		It("logs the stderr of the command line by line while it runs", func() {
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: boshsys.Result{Stdout: `{}`}})
+
+			_, err := cpiCmdRunner.Run(context, "fake-method", "fake-argument")
+			Expect(err).NotTo(HaveOccurred())
+
+			stderr := cmdRunner.RunComplexCommands[0].Stderr
+			stderr.Write([]byte("fake-stderr-line-1\nfake-stderr-"))
+			Expect(logBuffer.String()).To(ContainSubstring("[fake-method cpi-fake-uuid-0] stderr: fake-stderr-line-1\n"))
+			Expect(logBuffer.String()).ToNot(ContainSubstring("fake-stderr-line-2"))
+
+			stderr.Write([]byte("line-2\n"))
+			Expect(logBuffer.String()).To(ContainSubstring("[fake-method cpi-fake-uuid-0] stderr: fake-stderr-line-2\n"))
+			Expect(cpiLogBuffer.String()).To(ContainSubstring("[fake-method cpi-fake-uuid-0] stderr: fake-stderr-line-2\n"))
+		})
+
+		It("logs the request, the response and the log of the command to the cpi log", func() {
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{
+				WaitResult: boshsys.Result{Stdout: `{"result":"fake-cid","log":"fake-log-line-1\nfake-log-line-2\n"}`},
+			})
+
+			_, err := cpiCmdRunner.Run(context, "fake-method", "fake-argument")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cpiLogBuffer.String()).To(ContainSubstring(`[fake-method cpi-fake-uuid-0] request: {"arguments":["fake-argument"],"context":{"director_uuid":"fake-director-id","request_id":"cpi-fake-uuid-0"},"method":"fake-method"}`))
+			Expect(cpiLogBuffer.String()).To(ContainSubstring(`[fake-method cpi-fake-uuid-0] response from '/jobs/cpi/bin/cpi' with exit code 0: {"log":"fake-log-line-1\nfake-log-line-2\n","result":"fake-cid"}`))
+			Expect(cpiLogBuffer.String()).To(ContainSubstring("[fake-method cpi-fake-uuid-0] log: fake-log-line-1\n"))
+			Expect(cpiLogBuffer.String()).To(ContainSubstring("[fake-method cpi-fake-uuid-0] log: fake-log-line-2\n"))
+		})
+
+		It("masks the secrets of the request, the stderr and the response before logging them", func() {
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{
+				WaitResult: boshsys.Result{Stdout: `{"result":{"password":"fake-response-password"},"log":"secret: fake-log-secret"}`},
+			})
+
+			_, err := cpiCmdRunner.Run(context, "fake-method", map[string]interface{}{"env": map[string]interface{}{"bosh": map[string]interface{}{"password": "fake-request-password"}}})
+			Expect(err).NotTo(HaveOccurred())
+			cmdRunner.RunComplexCommands[0].Stderr.Write([]byte("fake-stderr with password=fake-stderr-password\n"))
+
+			for _, log := range []string{logBuffer.String(), cpiLogBuffer.String()} {
+				Expect(log).To(ContainSubstring(`request: {"arguments":[{"env":{"bosh":{"password":"<redacted>"}}}],"context"`))
+				Expect(log).To(ContainSubstring(`stderr: fake-stderr with password=<redacted>`))
+				Expect(log).To(ContainSubstring(`with exit code 0: {"log":"secret: <redacted>","result":{"password":"<redacted>"}}`))
+				Expect(log).To(ContainSubstring(`log: secret: <redacted>`))
+				Expect(log).ToNot(ContainSubstring("fake-request-password"))
+				Expect(log).ToNot(ContainSubstring("fake-stderr-password"))
+				Expect(log).ToNot(ContainSubstring("fake-response-password"))
+				Expect(log).ToNot(ContainSubstring("fake-log-secret"))
+			}
+		})
+
 		Context("when the command succeeds", func() {
 			BeforeEach(func() {
 				cmdOutput := CmdOutput{
@@ -80,11 +169,11 @@ var _ = Describe("CpiCmdRunner", func() {
 				outputBytes, err := json.Marshal(cmdOutput)
 				Expect(err).NotTo(HaveOccurred())
 
-				result := fakesys.FakeCmdResult{
+				result := boshsys.Result{
 					Stdout:     string(outputBytes),
 					ExitStatus: 0,
 				}
-				cmdRunner.AddCmdResult("/jobs/cpi/bin/cpi", result)
+				cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: result})
 			})
 
 			It("returns the result", func() {
@@ -100,10 +189,10 @@ var _ = Describe("CpiCmdRunner", func() {
 
 		Context("when running the command fails", func() {
 			BeforeEach(func() {
-				result := fakesys.FakeCmdResult{
+				result := boshsys.Result{
 					Error: errors.New("fake-error-trying-to-run-command"),
 				}
-				cmdRunner.AddCmdResult("/jobs/cpi/bin/cpi", result)
+				cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: result})
 			})
 
 			It("returns an error", func() {
@@ -124,11 +213,11 @@ var _ = Describe("CpiCmdRunner", func() {
 				outputBytes, err := json.Marshal(cmdOutput)
 				Expect(err).NotTo(HaveOccurred())
 
-				result := fakesys.FakeCmdResult{
+				result := boshsys.Result{
 					Stdout:     string(outputBytes),
 					ExitStatus: 0,
 				}
-				cmdRunner.AddCmdResult("/jobs/cpi/bin/cpi", result)
+				cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: result})
 			})
 
 			It("returns the command output and no error", func() {
@@ -137,5 +226,94 @@ var _ = Describe("CpiCmdRunner", func() {
 				Expect(cmdOutput.Error.Message).To(ContainSubstring("fake-run-error"))
 			})
 		})
+
+		Context("when the command takes longer than the timeout of the method", func() {
+			var process *fakesys.FakeProcess
+
+			BeforeEach(func() {
+				process = &fakesys.FakeProcess{
+					TerminatedNicelyCallBack: func(p *fakesys.FakeProcess) {
+						p.WaitCh <- boshsys.Result{ExitStatus: 143, Error: errors.New("fake-terminated-error")}
+					},
+				}
+				cmdRunner.AddProcess("/jobs/cpi/bin/cpi", process)
+			})
+
+			runUntilTimeout := func(method string, timeout time.Duration) (CmdOutput, error) {
+				var (
+					cmdOutput CmdOutput
+					err       error
+				)
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					cmdOutput, err = cpiCmdRunner.Run(context, method, "fake-argument")
+				}()
+
+				Eventually(fakeClock.WatcherCount).Should(Equal(1))
+				fakeClock.Increment(timeout - time.Millisecond)
+				Consistently(done).ShouldNot(BeClosed())
+				fakeClock.Increment(time.Millisecond)
+				Eventually(done).Should(BeClosed())
+
+				return cmdOutput, err
+			}
+
+			It("kills the process group and returns a timeout error", func() {
+				cmdOutput, err := runUntilTimeout("fake-method", time.Minute)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmdOutput.Error).To(Equal(&CmdError{
+					Type:    TimeoutError,
+					Message: "Timed out after 1m0s",
+				}))
+
+				Expect(process.TerminatedNicely).To(BeTrue())
+				Expect(process.TerminateNicelyKillGracePeriod).To(Equal(10 * time.Second))
+			})
+
+			It("uses the timeout of the method", func() {
+				cmdOutput, err := runUntilTimeout("create_vm", time.Hour)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmdOutput.Error.Message).To(Equal("Timed out after 1h0m0s"))
+			})
+		})
+
+		Context("when interrupted", func() {
+			var process *fakesys.FakeProcess
+
+			BeforeEach(func() {
+				process = &fakesys.FakeProcess{
+					TerminatedNicelyCallBack: func(p *fakesys.FakeProcess) {
+						p.WaitCh <- boshsys.Result{ExitStatus: 143, Error: errors.New("fake-terminated-error")}
+					},
+				}
+				cmdRunner.AddProcess("/jobs/cpi/bin/cpi", process)
+			})
+
+			It("terminates the process group and returns an error", func() {
+				var err error
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					_, err = cpiCmdRunner.Run(context, "fake-method", "fake-argument")
+				}()
+
+				Eventually(fakeClock.WatcherCount).Should(Equal(1))
+				interrupter.Interrupt()
+				Eventually(done).Should(BeClosed())
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Interrupted external CPI command '/jobs/cpi/bin/cpi' method 'fake-method'"))
+				Expect(process.TerminatedNicely).To(BeTrue())
+			})
+		})
+
+		It("stops listening for interrupts once the command exits", func() {
+			cmdRunner.AddProcess("/jobs/cpi/bin/cpi", &fakesys.FakeProcess{WaitResult: boshsys.Result{Stdout: `{}`}})
+
+			_, err := cpiCmdRunner.Run(context, "fake-method", "fake-argument")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(interrupter.NotifiedCount()).To(Equal(0))
+		})
 	})
 })
@@ -0,0 +1,71 @@
+package cloud
+
+import (
+	"encoding/json"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+// MaxSupportedAPIVersion is the highest CPI API version bosh-init can use
+const MaxSupportedAPIVersion = 2
+
+// CPIInfo is what a CPI reports about itself with its 'info' method
+type CPIInfo struct {
+	APIVersion int `json:"api_version"`
+}
+
+// NegotiatedAPIVersion returns the API version used to call the CPI:
+// the highest version supported by both the CPI and bosh-init.
+func (i CPIInfo) NegotiatedAPIVersion() int {
+	if i.APIVersion < 1 {
+		return 1
+	}
+	if i.APIVersion > MaxSupportedAPIVersion {
+		return MaxSupportedAPIVersion
+	}
+	return i.APIVersion
+}
+
+// FetchCPIInfo calls the 'info' method of the CPI.
+// CPIs that do not implement it only support API version 1, so API version 1 is assumed when the CPI reports an error
+// or a result that cannot be parsed. Errors running the CPI, e.g. when it is interrupted, are returned.
+func FetchCPIInfo(cpiCmdRunner CPICmdRunner, directorID string, logger boshlog.Logger) (CPIInfo, error) {
+	method := "info"
+	cmdOutput, err := cpiCmdRunner.Run(CmdContext{DirectorID: directorID}, method)
+	if err != nil {
+		return CPIInfo{}, bosherr.WrapError(err, "Calling CPI 'info' method")
+	}
+
+	if cmdOutput.Error != nil {
+		logger.Warn("cloud", "Assuming CPI API version 1, since the CPI 'info' method failed: %s", NewCPIError(method, *cmdOutput.Error).Error())
+		return CPIInfo{APIVersion: 1}, nil
+	}
+
+	info, err := parseCPIInfo(cmdOutput.Result)
+	if err != nil {
+		logger.Warn("cloud", "Assuming CPI API version 1, since the result of the CPI 'info' method cannot be parsed: %s", err.Error())
+		return CPIInfo{APIVersion: 1}, nil
+	}
+
+	logger.Info("cloud", "CPI supports API version %d", info.APIVersion)
+	return info, nil
+}
+
+func parseCPIInfo(result interface{}) (CPIInfo, error) {
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return CPIInfo{}, bosherr.WrapErrorf(err, "Marshalling external CPI command result: '%#v'", result)
+	}
+
+	info := CPIInfo{}
+	err = json.Unmarshal(resultBytes, &info)
+	if err != nil {
+		return CPIInfo{}, bosherr.Errorf("Unexpected external CPI command result: '%#v'", result)
+	}
+	if info.APIVersion < 1 {
+		info.APIVersion = 1
+	}
+
+	return info, nil
+}
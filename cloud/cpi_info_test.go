@@ -0,0 +1,108 @@
+package cloud_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry/bosh-init/cloud"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+var _ = Describe("FetchCPIInfo", func() {
+	var (
+		fakeCPICmdRunner *fakebicloud.FakeCPICmdRunner
+		logger           boshlog.Logger
+	)
+
+	BeforeEach(func() {
+		fakeCPICmdRunner = fakebicloud.NewFakeCPICmdRunner()
+		logger = boshlog.NewLogger(boshlog.LevelNone)
+	})
+
+	It("calls the info method of the cpi", func() {
+		fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+			Result: map[string]interface{}{
+				"api_version":      2,
+				"stemcell_formats": []interface{}{"aws-raw", "aws-light"},
+			},
+		}
+
+		info, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(CPIInfo{APIVersion: 2}))
+
+		Expect(fakeCPICmdRunner.RunInputs).To(Equal([]fakebicloud.RunInput{
+			{
+				Context: CmdContext{DirectorID: "fake-director-id"},
+				Method:  "info",
+			},
+		}))
+	})
+
+	It("assumes API version 1 when the cpi does not report its API version", func() {
+		fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+			Result: map[string]interface{}{"stemcell_formats": []interface{}{"aws-raw"}},
+		}
+
+		info, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.APIVersion).To(Equal(1))
+	})
+
+	It("assumes API version 1 when the cpi does not implement info", func() {
+		fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+			Error: &CmdError{Type: "Bosh::Clouds::NotImplemented", Message: "Must call implemented method"},
+		}
+
+		info, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(CPIInfo{APIVersion: 1}))
+	})
+
+	It("assumes API version 1 when the result is of an unexpected type", func() {
+		fakeCPICmdRunner.RunCmdOutput = CmdOutput{
+			Result: "fake-result",
+		}
+
+		info, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info).To(Equal(CPIInfo{APIVersion: 1}))
+	})
+
+	It("returns an error when the cpi command execution fails", func() {
+		fakeCPICmdRunner.RunErr = errors.New("fake-run-error")
+
+		_, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Calling CPI 'info' method: fake-run-error"))
+	})
+
+	It("returns an error when the cpi command is interrupted", func() {
+		fakeCPICmdRunner.RunErr = bosherr.Error("Interrupted external CPI command '/jobs/cpi/bin/cpi' method 'info'")
+
+		_, err := FetchCPIInfo(fakeCPICmdRunner, "fake-director-id", logger)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Interrupted external CPI command"))
+	})
+})
+
+var _ = Describe("CPIInfo", func() {
+	Describe("NegotiatedAPIVersion", func() {
+		It("uses the API version of the cpi when bosh-init supports it", func() {
+			Expect(CPIInfo{APIVersion: 1}.NegotiatedAPIVersion()).To(Equal(1))
+			Expect(CPIInfo{APIVersion: 2}.NegotiatedAPIVersion()).To(Equal(2))
+		})
+
+		It("uses the highest API version supported by bosh-init when the cpi supports a higher one", func() {
+			Expect(CPIInfo{APIVersion: 3}.NegotiatedAPIVersion()).To(Equal(MaxSupportedAPIVersion))
+		})
+
+		It("uses API version 1 when the cpi reports none", func() {
+			Expect(CPIInfo{}.NegotiatedAPIVersion()).To(Equal(1))
+		})
+	})
+})
@@ -9,6 +9,9 @@ const (
 	DiskNotFoundError     = "Bosh::Clouds::DiskNotFound"
 	StemcellNotFoundError = "Bosh::Clouds::StemcellNotFound"
 	NotImplementedError   = "Bosh::Clouds::NotImplemented"
+
+	// TimeoutError is the type of the error of CPI calls that bosh-init killed because they took longer than their timeout
+	TimeoutError = "Bosh::Init::CPITimeout"
 )
 
 type Error interface {
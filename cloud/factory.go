@@ -1,10 +1,17 @@
 package cloud
 
 import (
+	"os"
+
 	biinstall "github.com/cloudfoundry/bosh-init/installation"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshlogfile "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger/file"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
+	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
 type Factory interface {
@@ -12,20 +19,41 @@ type Factory interface {
 }
 
 type factory struct {
-	fs        boshsys.FileSystem
-	cmdRunner boshsys.CmdRunner
-	logger    boshlog.Logger
+	fs            boshsys.FileSystem
+	cmdRunner     boshsys.CmdRunner
+	interrupter   Interrupter
+	uuidGenerator boshuuid.Generator
+	ui            biui.UI
+	timeService   clock.Clock
+	redactor      bilog.Redactor
+	cpiLogPath    string
+	cpiLogger     boshlog.Logger
+	logger        boshlog.Logger
 }
 
+// NewFactory returns a Factory of clouds calling the installed CPI.
+// With a cpiLogPath, all the traffic with the CPI is appended to that file.
 func NewFactory(
 	fs boshsys.FileSystem,
 	cmdRunner boshsys.CmdRunner,
+	interrupter Interrupter,
+	uuidGenerator boshuuid.Generator,
+	ui biui.UI,
+	timeService clock.Clock,
+	redactor bilog.Redactor,
+	cpiLogPath string,
 	logger boshlog.Logger,
 ) Factory {
 	return &factory{
-		fs:        fs,
-		cmdRunner: cmdRunner,
-		logger:    logger,
+		fs:            fs,
+		cmdRunner:     cmdRunner,
+		interrupter:   interrupter,
+		uuidGenerator: uuidGenerator,
+		ui:            ui,
+		timeService:   timeService,
+		redactor:      redactor,
+		cpiLogPath:    cpiLogPath,
+		logger:        logger,
 	}
 }
 
@@ -43,6 +71,40 @@ func (f *factory) NewCloud(installation biinstall.Installation, directorID strin
 		return nil, bosherr.Errorf("Installed CPI job '%s' does not contain the required executable '%s'", cpiJob.Name, cmdPath)
 	}
 
-	cpiCmdRunner := NewCPICmdRunner(f.cmdRunner, cpi, f.logger)
-	return NewCloud(cpiCmdRunner, directorID, f.logger), nil
+	cpiLogger, err := f.loadCPILogger()
+	if err != nil {
+		return nil, err
+	}
+
+	timeouts := NewTimeouts(installation.Manifest().Timeouts)
+	cpiCmdRunner := NewCPICmdRunner(f.cmdRunner, cpi, timeouts, f.interrupter, f.uuidGenerator, f.timeService, f.redactor, cpiLogger, f.logger)
+	info, err := FetchCPIInfo(cpiCmdRunner, directorID, f.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cloud := NewCloud(cpiCmdRunner, directorID, info, f.logger)
+	retryPolicies := NewRetryPolicies(installation.Manifest().Retries)
+	return NewRetryingCloud(cloud, retryPolicies, f.ui, f.timeService, f.logger), nil
+}
+
+// loadCPILogger opens the CPI log file once, and keeps it open until bosh-init exits
+func (f *factory) loadCPILogger() (boshlog.Logger, error) {
+	if f.cpiLogger != nil {
+		return f.cpiLogger, nil
+	}
+
+	if f.cpiLogPath == "" {
+		f.cpiLogger = boshlog.NewLogger(boshlog.LevelNone)
+		return f.cpiLogger, nil
+	}
+
+	// the CPI log contains the cloud properties sent to the CPI, so it is only readable by the current user
+	cpiLogger, _, err := boshlogfile.New(boshlog.LevelDebug, f.cpiLogPath, os.FileMode(0600), f.fs)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Opening CPI log")
+	}
+
+	f.cpiLogger = cpiLogger
+	return f.cpiLogger, nil
 }
@@ -14,16 +14,18 @@ type FakeCloud struct {
 	HasVMFound bool
 	HasVMErr   error
 
-	CreateVMInput CreateVMInput
-	CreateVMCID   string
-	CreateVMErr   error
+	CreateVMInput    CreateVMInput
+	CreateVMCID      string
+	CreateVMNetworks map[string]biproperty.Map
+	CreateVMErr      error
 
 	CreateDiskInput CreateDiskInput
 	CreateDiskCID   string
 	CreateDiskErr   error
 
-	AttachDiskInput AttachDiskInput
-	AttachDiskErr   error
+	AttachDiskInput    AttachDiskInput
+	AttachDiskDiskHint interface{}
+	AttachDiskErr      error
 
 	DetachDiskInput DetachDiskInput
 	DetachDiskErr   error
@@ -40,6 +42,8 @@ type FakeCloud struct {
 	SetVMMetadataCid      string
 	SetVMMetadataMetadata cloud.VMMetadata
 	SetVMMetadataError    error
+
+	CPIInfo cloud.CPIInfo
 }
 
 type CreateStemcellInput struct {
@@ -123,7 +127,7 @@ func (c *FakeCloud) CreateVM(
 	cloudProperties biproperty.Map,
 	networksInterfaces map[string]biproperty.Map,
 	env biproperty.Map,
-) (string, error) {
+) (string, map[string]biproperty.Map, error) {
 	c.CreateVMInput = CreateVMInput{
 		AgentID:            agentID,
 		StemcellCID:        stemcellCID,
@@ -132,7 +136,7 @@ func (c *FakeCloud) CreateVM(
 		Env:                env,
 	}
 
-	return c.CreateVMCID, c.CreateVMErr
+	return c.CreateVMCID, c.CreateVMNetworks, c.CreateVMErr
 }
 
 func (c *FakeCloud) SetVMMetadata(cid string, metadata cloud.VMMetadata) error {
@@ -155,12 +159,12 @@ func (c *FakeCloud) CreateDisk(
 	return c.CreateDiskCID, c.CreateDiskErr
 }
 
-func (c *FakeCloud) AttachDisk(vmCID, diskCID string) error {
+func (c *FakeCloud) AttachDisk(vmCID, diskCID string) (interface{}, error) {
 	c.AttachDiskInput = AttachDiskInput{
 		VMCID:   vmCID,
 		DiskCID: diskCID,
 	}
-	return c.AttachDiskErr
+	return c.AttachDiskDiskHint, c.AttachDiskErr
 }
 
 func (c *FakeCloud) DetachDisk(vmCID, diskCID string) error {
@@ -185,6 +189,10 @@ func (c *FakeCloud) DeleteDisk(diskCID string) error {
 	return c.DeleteDiskErr
 }
 
+func (c *FakeCloud) Info() cloud.CPIInfo {
+	return c.CPIInfo
+}
+
 func (c *FakeCloud) String() string {
 	return "FakeCloud{}"
 }
@@ -0,0 +1,53 @@
+package fakes
+
+import (
+	"os"
+	"sync"
+)
+
+type FakeInterrupter struct {
+	lock     sync.Mutex
+	channels []chan<- os.Signal
+}
+
+func NewFakeInterrupter() *FakeInterrupter {
+	return &FakeInterrupter{}
+}
+
+func (i *FakeInterrupter) Notify(c chan<- os.Signal) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.channels = append(i.channels, c)
+}
+
+func (i *FakeInterrupter) Stop(c chan<- os.Signal) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	channels := []chan<- os.Signal{}
+	for _, channel := range i.channels {
+		if channel != c {
+			channels = append(channels, channel)
+		}
+	}
+	i.channels = channels
+}
+
+// NotifiedCount returns how many channels are notified of interrupts
+func (i *FakeInterrupter) NotifiedCount() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return len(i.channels)
+}
+
+// Interrupt sends os.Interrupt to the notified channels, like Ctrl-C
+func (i *FakeInterrupter) Interrupt() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	for _, channel := range i.channels {
+		channel <- os.Interrupt
+	}
+}
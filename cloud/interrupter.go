@@ -0,0 +1,29 @@
+package cloud
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Interrupter notifies the running CPI command of interrupts (e.g. Ctrl-C).
+// CPI processes run in their own process group, so they do not get the signals sent to bosh-init,
+// and would be left running when bosh-init exits unless they are terminated.
+type Interrupter interface {
+	Notify(c chan<- os.Signal)
+	Stop(c chan<- os.Signal)
+}
+
+type signalInterrupter struct{}
+
+func NewSignalInterrupter() Interrupter {
+	return signalInterrupter{}
+}
+
+func (i signalInterrupter) Notify(c chan<- os.Signal) {
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+}
+
+func (i signalInterrupter) Stop(c chan<- os.Signal) {
+	signal.Stop(c)
+}
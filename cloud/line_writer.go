@@ -0,0 +1,45 @@
+package cloud
+
+import (
+	"bytes"
+	"sync"
+)
+
+// lineWriter calls writeLine with each line written to it, as soon as the line is complete
+type lineWriter struct {
+	writeLine func(line string)
+	buffer    []byte
+	lock      sync.Mutex
+}
+
+func newLineWriter(writeLine func(line string)) *lineWriter {
+	return &lineWriter{writeLine: writeLine}
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+		w.writeLine(string(w.buffer[:i]))
+		w.buffer = w.buffer[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the last line, when it does not end with a newline
+func (w *lineWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.buffer) > 0 {
+		w.writeLine(string(w.buffer))
+		w.buffer = nil
+	}
+}
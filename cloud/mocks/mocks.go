@@ -31,10 +31,11 @@ func (_m *MockCloud) EXPECT() *_MockCloudRecorder {
 	return _m.recorder
 }
 
-func (_m *MockCloud) AttachDisk(_param0 string, _param1 string) error {
+func (_m *MockCloud) AttachDisk(_param0 string, _param1 string) (interface{}, error) {
 	ret := _m.ctrl.Call(_m, "AttachDisk", _param0, _param1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 func (_mr *_MockCloudRecorder) AttachDisk(arg0, arg1 interface{}) *gomock.Call {
@@ -63,11 +64,12 @@ func (_mr *_MockCloudRecorder) CreateStemcell(arg0, arg1 interface{}) *gomock.Ca
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "CreateStemcell", arg0, arg1)
 }
 
-func (_m *MockCloud) CreateVM(_param0 string, _param1 string, _param2 property.Map, _param3 map[string]property.Map, _param4 property.Map) (string, error) {
+func (_m *MockCloud) CreateVM(_param0 string, _param1 string, _param2 property.Map, _param3 map[string]property.Map, _param4 property.Map) (string, map[string]property.Map, error) {
 	ret := _m.ctrl.Call(_m, "CreateVM", _param0, _param1, _param2, _param3, _param4)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(map[string]property.Map)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 func (_mr *_MockCloudRecorder) CreateVM(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
@@ -125,6 +127,16 @@ func (_mr *_MockCloudRecorder) HasVM(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "HasVM", arg0)
 }
 
+func (_m *MockCloud) Info() cloud.CPIInfo {
+	ret := _m.ctrl.Call(_m, "Info")
+	ret0, _ := ret[0].(cloud.CPIInfo)
+	return ret0
+}
+
+func (_mr *_MockCloudRecorder) Info() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Info")
+}
+
 func (_m *MockCloud) SetVMMetadata(_param0 string, _param1 cloud.VMMetadata) error {
 	ret := _m.ctrl.Call(_m, "SetVMMetadata", _param0, _param1)
 	ret0, _ := ret[0].(error)
@@ -0,0 +1,222 @@
+package cloud
+
+import (
+	"fmt"
+	"time"
+
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+// RetryPolicy is how calls to a CPI method that fail with a retryable error are retried:
+// up to MaxAttempts attempts, waiting InitialDelay after the first one and doubling the delay up to MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 2 * time.Second,
+	MaxDelay:     30 * time.Second,
+}
+
+// idempotentMethods can be called again after a failure without leaking IaaS resources, so they are retried by default.
+// The other methods are only retried when a retry policy is given for them in the installation manifest.
+var idempotentMethods = []string{
+	"delete_stemcell",
+	"delete_vm",
+	"has_vm",
+	"set_vm_metadata",
+	"attach_disk",
+	"detach_disk",
+	"delete_disk",
+}
+
+// Delay returns how long to wait after the failed attempt with the given number (starting at 1)
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+func (p RetryPolicy) merge(override biinstallmanifest.RetryPolicy) RetryPolicy {
+	if override.MaxAttempts > 0 {
+		p.MaxAttempts = override.MaxAttempts
+	}
+	if override.InitialDelay > 0 {
+		p.InitialDelay = override.InitialDelay
+	}
+	if override.MaxDelay > 0 {
+		p.MaxDelay = override.MaxDelay
+	}
+	return p
+}
+
+// NewRetryPolicies returns the retry policy of each retried CPI method, given the retry policies of the installation manifest.
+// The policy of a method overrides the 'default' policy, which overrides DefaultRetryPolicy.
+func NewRetryPolicies(retries map[string]biinstallmanifest.RetryPolicy) map[string]RetryPolicy {
+	defaultPolicy := DefaultRetryPolicy.merge(retries["default"])
+
+	policies := map[string]RetryPolicy{}
+	for _, method := range idempotentMethods {
+		policies[method] = defaultPolicy.merge(retries[method])
+	}
+	for method, policy := range retries {
+		if method != "default" {
+			policies[method] = defaultPolicy.merge(policy)
+		}
+	}
+
+	return policies
+}
+
+type retryingCloud struct {
+	cloud       Cloud
+	policies    map[string]RetryPolicy
+	ui          biui.UI
+	timeService clock.Clock
+	logger      boshlog.Logger
+	logTag      string
+}
+
+// NewRetryingCloud returns a Cloud that retries the calls to the CPI methods of cloud that fail with an error that is ok to retry,
+// according to the policy of the method. Methods without policy are not retried.
+// Retries are shown on the current line of ui, which is the line of the stage calling the CPI.
+func NewRetryingCloud(
+	cloud Cloud,
+	policies map[string]RetryPolicy,
+	ui biui.UI,
+	timeService clock.Clock,
+	logger boshlog.Logger,
+) Cloud {
+	return retryingCloud{
+		cloud:       cloud,
+		policies:    policies,
+		ui:          ui,
+		timeService: timeService,
+		logger:      logger,
+		logTag:      "retryingCloud",
+	}
+}
+
+func (c retryingCloud) CreateStemcell(imagePath string, cloudProperties biproperty.Map) (stemcellCID string, err error) {
+	err = c.retry("create_stemcell", func() error {
+		stemcellCID, err = c.cloud.CreateStemcell(imagePath, cloudProperties)
+		return err
+	})
+	return stemcellCID, err
+}
+
+func (c retryingCloud) DeleteStemcell(stemcellCID string) error {
+	return c.retry("delete_stemcell", func() error {
+		return c.cloud.DeleteStemcell(stemcellCID)
+	})
+}
+
+func (c retryingCloud) HasVM(vmCID string) (found bool, err error) {
+	err = c.retry("has_vm", func() error {
+		found, err = c.cloud.HasVM(vmCID)
+		return err
+	})
+	return found, err
+}
+
+func (c retryingCloud) CreateVM(
+	agentID string,
+	stemcellCID string,
+	cloudProperties biproperty.Map,
+	networksInterfaces map[string]biproperty.Map,
+	env biproperty.Map,
+) (vmCID string, networks map[string]biproperty.Map, err error) {
+	err = c.retry("create_vm", func() error {
+		vmCID, networks, err = c.cloud.CreateVM(agentID, stemcellCID, cloudProperties, networksInterfaces, env)
+		return err
+	})
+	return vmCID, networks, err
+}
+
+func (c retryingCloud) SetVMMetadata(vmCID string, metadata VMMetadata) error {
+	return c.retry("set_vm_metadata", func() error {
+		return c.cloud.SetVMMetadata(vmCID, metadata)
+	})
+}
+
+func (c retryingCloud) DeleteVM(vmCID string) error {
+	return c.retry("delete_vm", func() error {
+		return c.cloud.DeleteVM(vmCID)
+	})
+}
+
+func (c retryingCloud) CreateDisk(size int, cloudProperties biproperty.Map, vmCID string) (diskCID string, err error) {
+	err = c.retry("create_disk", func() error {
+		diskCID, err = c.cloud.CreateDisk(size, cloudProperties, vmCID)
+		return err
+	})
+	return diskCID, err
+}
+
+func (c retryingCloud) AttachDisk(vmCID, diskCID string) (diskHint interface{}, err error) {
+	err = c.retry("attach_disk", func() error {
+		diskHint, err = c.cloud.AttachDisk(vmCID, diskCID)
+		return err
+	})
+	return diskHint, err
+}
+
+func (c retryingCloud) DetachDisk(vmCID, diskCID string) error {
+	return c.retry("detach_disk", func() error {
+		return c.cloud.DetachDisk(vmCID, diskCID)
+	})
+}
+
+func (c retryingCloud) DeleteDisk(diskCID string) error {
+	return c.retry("delete_disk", func() error {
+		return c.cloud.DeleteDisk(diskCID)
+	})
+}
+
+func (c retryingCloud) Info() CPIInfo {
+	return c.cloud.Info()
+}
+
+func (c retryingCloud) String() string {
+	return fmt.Sprintf("RetryingCloud{%s}", c.cloud)
+}
+
+// retry calls the CPI method until it succeeds, fails with an error that is not ok to retry, or runs out of attempts.
+// The error of the last attempt is returned unchanged, so that callers can still check its type.
+func (c retryingCloud) retry(method string, call func() error) error {
+	policy, found := c.policies[method]
+	if !found || policy.MaxAttempts < 1 {
+		policy = RetryPolicy{MaxAttempts: 1}
+	}
+
+	for attempt := 1; ; attempt++ {
+		c.logger.Debug(c.logTag, "Calling CPI '%s' method (attempt %d of %d)", method, attempt, policy.MaxAttempts)
+
+		err := call()
+		if err == nil {
+			return nil
+		}
+
+		cloudErr, ok := err.(Error)
+		if !ok || !cloudErr.OkToRetry() || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		delay := policy.Delay(attempt)
+		c.logger.Warn(c.logTag, "CPI '%s' method failed with a retryable error (attempt %d of %d), retrying in %s: %s", method, attempt, policy.MaxAttempts, delay, err)
+		c.ui.BeginLinef(" Retrying '%s' in %s (attempt %d of %d)...", method, delay, attempt+1, policy.MaxAttempts)
+		c.timeService.Sleep(delay)
+	}
+}
@@ -0,0 +1,161 @@
+package cloud_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/cloudfoundry/bosh-init/cloud"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock/fakeclock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+// sleepRecordingClock returns from Sleep immediately, recording the duration
+type sleepRecordingClock struct {
+	*fakeclock.FakeClock
+	sleeps []time.Duration
+}
+
+func (c *sleepRecordingClock) Sleep(d time.Duration) {
+	c.sleeps = append(c.sleeps, d)
+}
+
+var _ = Describe("RetryingCloud", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockCloud *mock_cloud.MockCloud
+		fakeUI    *fakebiui.FakeUI
+		fakeClock *sleepRecordingClock
+		policies  map[string]RetryPolicy
+
+		retryableErr    error
+		nonRetryableErr error
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCloud = mock_cloud.NewMockCloud(mockCtrl)
+		fakeUI = &fakebiui.FakeUI{}
+		fakeClock = &sleepRecordingClock{FakeClock: fakeclock.NewFakeClock(time.Now())}
+
+		policies = map[string]RetryPolicy{
+			"delete_vm": {MaxAttempts: 4, InitialDelay: time.Second, MaxDelay: 3 * time.Second},
+			"create_vm": {MaxAttempts: 2, InitialDelay: time.Second, MaxDelay: time.Second},
+		}
+
+		retryableErr = NewCPIError("fake-method", CmdError{Type: "Bosh::Clouds::CloudError", Message: "fake-rate-limit", OkToRetry: true})
+		nonRetryableErr = NewCPIError("fake-method", CmdError{Type: "Bosh::Clouds::CloudError", Message: "fake-error"})
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	newRetryingCloud := func() Cloud {
+		return NewRetryingCloud(mockCloud, policies, fakeUI, fakeClock, boshlog.NewLogger(boshlog.LevelNone))
+	}
+
+	It("retries calls that fail with a retryable error, with exponential backoff", func() {
+		gomock.InOrder(
+			mockCloud.EXPECT().DeleteVM("fake-vm-cid").Return(retryableErr).Times(3),
+			mockCloud.EXPECT().DeleteVM("fake-vm-cid").Return(nil),
+		)
+
+		err := newRetryingCloud().DeleteVM("fake-vm-cid")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(fakeClock.sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}))
+		Expect(fakeUI.Said).To(Equal([]string{
+			" Retrying 'delete_vm' in 1s (attempt 2 of 4)...",
+			" Retrying 'delete_vm' in 2s (attempt 3 of 4)...",
+			" Retrying 'delete_vm' in 3s (attempt 4 of 4)...",
+		}))
+	})
+
+	It("returns the results of the successful attempt", func() {
+		gomock.InOrder(
+			mockCloud.EXPECT().CreateVM("fake-agent-id", "fake-stemcell-cid", gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil, retryableErr),
+			mockCloud.EXPECT().CreateVM("fake-agent-id", "fake-stemcell-cid", gomock.Any(), gomock.Any(), gomock.Any()).Return("fake-vm-cid", map[string]biproperty.Map{"bosh": biproperty.Map{"ip": "10.0.0.5"}}, nil),
+		)
+
+		vmCID, networks, err := newRetryingCloud().CreateVM("fake-agent-id", "fake-stemcell-cid", nil, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vmCID).To(Equal("fake-vm-cid"))
+		Expect(networks).To(Equal(map[string]biproperty.Map{"bosh": biproperty.Map{"ip": "10.0.0.5"}}))
+	})
+
+	It("returns the error of the last attempt when out of attempts", func() {
+		mockCloud.EXPECT().DeleteVM("fake-vm-cid").Return(retryableErr).Times(4)
+
+		err := newRetryingCloud().DeleteVM("fake-vm-cid")
+		Expect(err).To(Equal(retryableErr))
+		Expect(fakeClock.sleeps).To(HaveLen(3))
+	})
+
+	It("does not retry errors that are not ok to retry", func() {
+		mockCloud.EXPECT().DeleteVM("fake-vm-cid").Return(nonRetryableErr)
+
+		err := newRetryingCloud().DeleteVM("fake-vm-cid")
+		Expect(err).To(Equal(nonRetryableErr))
+		Expect(fakeClock.sleeps).To(BeEmpty())
+	})
+
+	It("does not retry errors that are not CPI errors", func() {
+		execErr := errors.New("fake-exec-error")
+		mockCloud.EXPECT().DeleteVM("fake-vm-cid").Return(execErr)
+
+		err := newRetryingCloud().DeleteVM("fake-vm-cid")
+		Expect(err).To(Equal(execErr))
+	})
+
+	It("does not retry methods without policy", func() {
+		mockCloud.EXPECT().CreateDisk(1024, gomock.Any(), "fake-vm-cid").Return("", retryableErr)
+
+		_, err := newRetryingCloud().CreateDisk(1024, nil, "fake-vm-cid")
+		Expect(err).To(Equal(retryableErr))
+		Expect(fakeUI.Said).To(BeEmpty())
+	})
+})
+
+var _ = Describe("RetryPolicy", func() {
+	Describe("Delay", func() {
+		It("doubles the initial delay after each attempt, up to the max delay", func() {
+			policy := RetryPolicy{InitialDelay: 2 * time.Second, MaxDelay: 10 * time.Second}
+			Expect(policy.Delay(1)).To(Equal(2 * time.Second))
+			Expect(policy.Delay(2)).To(Equal(4 * time.Second))
+			Expect(policy.Delay(3)).To(Equal(8 * time.Second))
+			Expect(policy.Delay(4)).To(Equal(10 * time.Second))
+			Expect(policy.Delay(100)).To(Equal(10 * time.Second))
+		})
+	})
+})
+
+var _ = Describe("NewRetryPolicies", func() {
+	It("retries the idempotent methods with the default policy", func() {
+		policies := NewRetryPolicies(nil)
+		Expect(policies["delete_vm"]).To(Equal(DefaultRetryPolicy))
+		Expect(policies["attach_disk"]).To(Equal(DefaultRetryPolicy))
+		Expect(policies).ToNot(HaveKey("create_vm"))
+	})
+
+	It("overrides the default policy with the policies of the installation manifest", func() {
+		policies := NewRetryPolicies(map[string]biinstallmanifest.RetryPolicy{
+			"default":   {MaxAttempts: 5},
+			"delete_vm": {InitialDelay: time.Second},
+			"create_vm": {MaxDelay: time.Minute},
+		})
+
+		Expect(policies["has_vm"]).To(Equal(RetryPolicy{MaxAttempts: 5, InitialDelay: DefaultRetryPolicy.InitialDelay, MaxDelay: DefaultRetryPolicy.MaxDelay}))
+		Expect(policies["delete_vm"]).To(Equal(RetryPolicy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: DefaultRetryPolicy.MaxDelay}))
+		Expect(policies["create_vm"]).To(Equal(RetryPolicy{MaxAttempts: 5, InitialDelay: DefaultRetryPolicy.InitialDelay, MaxDelay: time.Minute}))
+		Expect(policies).ToNot(HaveKey("default"))
+	})
+})
@@ -0,0 +1,41 @@
+package cloud
+
+import (
+	"time"
+)
+
+// DefaultTimeouts are how long calls to the CPI methods may take, by method name or 'default'.
+// Uploading a stemcell and booting a VM take longer than the other methods.
+var DefaultTimeouts = Timeouts{
+	"default":         10 * time.Minute,
+	"create_stemcell": time.Hour,
+	"create_vm":       30 * time.Minute,
+}
+
+// Timeouts are how long calls to the CPI methods may take, by method name or 'default'
+type Timeouts map[string]time.Duration
+
+// NewTimeouts returns the timeouts of the CPI methods, given the timeouts of the installation manifest.
+// A 'default' timeout in the installation manifest replaces all of the DefaultTimeouts.
+func NewTimeouts(manifestTimeouts map[string]time.Duration) Timeouts {
+	timeouts := Timeouts{}
+	if _, found := manifestTimeouts["default"]; !found {
+		for method, timeout := range DefaultTimeouts {
+			timeouts[method] = timeout
+		}
+	}
+
+	for method, timeout := range manifestTimeouts {
+		timeouts[method] = timeout
+	}
+
+	return timeouts
+}
+
+// For returns the timeout of the CPI method
+func (t Timeouts) For(method string) time.Duration {
+	if timeout, found := t[method]; found {
+		return timeout
+	}
+	return t["default"]
+}
@@ -0,0 +1,32 @@
+package cloud_test
+
+import (
+	"time"
+
+	. "github.com/cloudfoundry/bosh-init/cloud"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeouts", func() {
+	Describe("NewTimeouts", func() {
+		It("uses the default timeouts when the installation manifest has none", func() {
+			timeouts := NewTimeouts(nil)
+			Expect(timeouts).To(Equal(DefaultTimeouts))
+		})
+
+		It("overrides the default timeouts with the timeouts of the installation manifest", func() {
+			timeouts := NewTimeouts(map[string]time.Duration{"create_vm": time.Hour})
+			Expect(timeouts.For("create_vm")).To(Equal(time.Hour))
+			Expect(timeouts.For("create_stemcell")).To(Equal(DefaultTimeouts["create_stemcell"]))
+			Expect(timeouts.For("delete_vm")).To(Equal(DefaultTimeouts["default"]))
+		})
+
+		It("uses the default timeout of the installation manifest for all the other methods", func() {
+			timeouts := NewTimeouts(map[string]time.Duration{"default": 2 * time.Hour, "delete_vm": time.Minute})
+			Expect(timeouts.For("create_stemcell")).To(Equal(2 * time.Hour))
+			Expect(timeouts.For("create_vm")).To(Equal(2 * time.Hour))
+			Expect(timeouts.For("delete_vm")).To(Equal(time.Minute))
+		})
+	})
+})
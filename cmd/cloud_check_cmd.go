@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bicloudcheck "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type cloudCheckCmd struct {
+	cloudCheckerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (CloudChecker, error)
+	ui                   biui.UI
+	fs                   boshsys.FileSystem
+	logger               boshlog.Logger
+	logTag               string
+}
+
+func NewCloudCheckCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	cloudCheckerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (CloudChecker, error),
+) Cmd {
+	return &cloudCheckCmd{
+		ui:                   ui,
+		fs:                   fs,
+		cloudCheckerProvider: cloudCheckerProvider,
+		logger:               logger,
+		logTag:               "cloudCheckCmd",
+	}
+}
+
+func (c *cloudCheckCmd) Name() string {
+	return "cloud-check"
+}
+
+func (c *cloudCheckCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Reconcile deployment state with the IaaS",
+		Usage:    "[--auto] [--resolve <problem>=<resolution>]... " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *cloudCheckCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, resolutions, auto, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	cloudChecker, err := c.cloudCheckerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return cloudChecker.CheckCloud(stage, resolutions, auto)
+}
+
+type resolutionsFlag map[bicloudcheck.ProblemType]bicloudcheck.Resolution
+
+func (f resolutionsFlag) String() string {
+	pairs := []string{}
+	for problemType, resolution := range f {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", problemType, resolution))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f resolutionsFlag) Set(value string) error {
+	pieces := strings.SplitN(value, "=", 2)
+	if len(pieces) != 2 || pieces[0] == "" || pieces[1] == "" {
+		return bosherr.Errorf("Expected resolution in the form <problem>=<resolution>, got '%s'", value)
+	}
+
+	f[bicloudcheck.ProblemType(pieces[0])] = bicloudcheck.Resolution(pieces[1])
+	return nil
+}
+
+func (c *cloudCheckCmd) parseCmdInputs(args []string) (string, map[bicloudcheck.ProblemType]bicloudcheck.Resolution, bool, manifestFlags, error) {
+	var auto bool
+	resolutions := resolutionsFlag{}
+	flags := manifestFlags{saveVarsStore: true}
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.BoolVar(&auto, "auto", false, "")
+	flags.register(flagSet)
+	flagSet.Var(resolutions, "resolve", "")
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", nil, false, flags, err
+	}
+
+	return positionalArgs[0], resolutions, auto, flags, nil
+}
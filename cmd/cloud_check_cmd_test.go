@@ -0,0 +1,132 @@
+package cmd_test
+
+import (
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bicloudcheck "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("CloudCheckCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockCloudChecker *mock_cmd.MockCloudChecker
+			fs               *fakesys.FakeFileSystem
+			logger           boshlog.Logger
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+			noResolutions          = map[bicloudcheck.ProblemType]bicloudcheck.Resolution{}
+		)
+
+		var newCloudCheckCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.CloudChecker, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				return mockCloudChecker, nil
+			}
+
+			return bicmd.NewCloudCheckCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockCloudChecker = mock_cmd.NewMockCloudChecker(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'cloud-check'", func() {
+			Expect(newCloudCheckCmd().Name()).To(Equal("cloud-check"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newCloudCheckCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("checks the cloud without resolving problems", func() {
+				mockCloudChecker.EXPECT().CheckCloud(fakeStage, noResolutions, false).Return(nil)
+
+				err := newCloudCheckCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("passes --auto to the cloud checker", func() {
+				mockCloudChecker.EXPECT().CheckCloud(fakeStage, noResolutions, true).Return(nil)
+
+				err := newCloudCheckCmd().Run(fakeStage, []string{deploymentManifestPath, "--auto"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("passes the --resolve flags to the cloud checker", func() {
+				mockCloudChecker.EXPECT().CheckCloud(fakeStage, map[bicloudcheck.ProblemType]bicloudcheck.Resolution{
+					bicloudcheck.MissingVM:   bicloudcheck.ForgetVM,
+					bicloudcheck.MissingDisk: bicloudcheck.ReattachDisk,
+				}, false).Return(nil)
+
+				err := newCloudCheckCmd().Run(fakeStage, []string{
+					"--resolve", "missing_vm=forget_vm",
+					deploymentManifestPath,
+					"--resolve", "missing_disk=reattach_disk",
+				})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("when checking the cloud fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockCloudChecker.EXPECT().CheckCloud(fakeStage, noResolutions, false).Return(err)
+
+					returnedErr := newCloudCheckCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err when a --resolve flag is malformed", func() {
+			err := newCloudCheckCmd().Run(fakeStage, []string{"--resolve", "missing_vm", deploymentManifestPath})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newCloudCheckCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
+	bicloudcheck "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
+	biinstall "github.com/cloudfoundry/bosh-init/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type CloudChecker interface {
+	// CheckCloud reports problems and applies the given resolutions.
+	// Problems without a given resolution are skipped, unless auto is set, in which case the recommended resolution is applied.
+	CheckCloud(stage biui.Stage, resolutions map[bicloudcheck.ProblemType]bicloudcheck.Resolution, auto bool) error
+}
+
+func NewCloudChecker(
+	ui biui.UI,
+	logTag string,
+	logger boshlog.Logger,
+	deploymentStateService biconfig.DeploymentStateService,
+	releaseManager birel.Manager,
+	cloudFactory bicloud.Factory,
+	checkerFactory bicloudcheck.CheckerFactory,
+	deploymentManifestPath string,
+	cpiInstaller bicpirel.CpiInstaller,
+	releaseFetcher birel.Fetcher,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+	tempRootConfigurator TempRootConfigurator,
+	targetProvider biinstall.TargetProvider,
+	deploymentRecreator DeploymentRecreator,
+) CloudChecker {
+	return &cloudChecker{
+		ui:                  ui,
+		logTag:              logTag,
+		logger:              logger,
+		cloudFactory:        cloudFactory,
+		checkerFactory:      checkerFactory,
+		deploymentRecreator: deploymentRecreator,
+		installedCpiRunner: installedCpiRunner{
+			ui:                                      ui,
+			logTag:                                  logTag,
+			logger:                                  logger,
+			deploymentStateService:                  deploymentStateService,
+			releaseManager:                          releaseManager,
+			deploymentManifestPath:                  deploymentManifestPath,
+			cpiInstaller:                            cpiInstaller,
+			releaseFetcher:                          releaseFetcher,
+			releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+			tempRootConfigurator:                    tempRootConfigurator,
+			targetProvider:                          targetProvider,
+		},
+	}
+}
+
+type cloudChecker struct {
+	ui                  biui.UI
+	logTag              string
+	logger              boshlog.Logger
+	cloudFactory        bicloud.Factory
+	checkerFactory      bicloudcheck.CheckerFactory
+	installedCpiRunner  installedCpiRunner
+	deploymentRecreator DeploymentRecreator
+}
+
+func (c *cloudChecker) CheckCloud(stage biui.Stage, resolutions map[bicloudcheck.ProblemType]bicloudcheck.Resolution, auto bool) error {
+	recreate := false
+	err := c.installedCpiRunner.Run(stage, func(localCpiInstallation biinstall.Installation, installationManifest biinstallmanifest.Manifest, deploymentState biconfig.DeploymentState) error {
+		c.logger.Debug(c.logTag, "Creating cloud client...")
+		cloud, err := c.cloudFactory.NewCloud(localCpiInstallation, deploymentState.DirectorID)
+		if err != nil {
+			return bosherr.WrapError(err, "Creating CPI client from CPI installation")
+		}
+
+		checkers, err := c.checkerFactory.NewCheckers(cloud, deploymentState.DirectorID, installationManifest.Mbus)
+		if err != nil {
+			return bosherr.WrapError(err, "Creating checkers")
+		}
+
+		// each problem is resolved by the checker of the instance it was found on
+		var problems []bicloudcheck.Problem
+		var problemCheckers []bicloudcheck.Checker
+		err = stage.Perform("Scanning VMs and disks", func() error {
+			for _, checker := range checkers {
+				instanceProblems, err := checker.Check()
+				if err != nil {
+					return err
+				}
+
+				for _, problem := range instanceProblems {
+					problems = append(problems, problem)
+					problemCheckers = append(problemCheckers, checker)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			c.ui.PrintLinef("No problems found")
+			return nil
+		}
+
+		c.printProblems(problems)
+
+		if len(resolutions) == 0 && !auto {
+			c.ui.PrintLinef("Re-run with --auto or --resolve <problem>=<resolution> to resolve problems")
+			return nil
+		}
+
+		err = stage.PerformComplex("resolving problems", func(resolveStage biui.Stage) error {
+			for i, problem := range problems {
+				checker := problemCheckers[i]
+				resolution, found := resolutions[problem.Type]
+				if !found {
+					resolution = bicloudcheck.Skip
+					if auto {
+						resolution = problem.Resolutions[0]
+					}
+				}
+
+				stepName := fmt.Sprintf("Resolving '%s' with '%s'", problem.Description, resolution)
+				err := resolveStage.Perform(stepName, func() error {
+					if resolution == bicloudcheck.Skip {
+						return biui.NewSkipStageError(bosherr.Error("No resolution chosen"), "No resolution chosen")
+					}
+					return checker.Resolve(problem, resolution)
+				})
+				if err != nil {
+					return err
+				}
+
+				if resolution == bicloudcheck.RecreateVM {
+					recreate = true
+				}
+			}
+
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if !recreate {
+		return nil
+	}
+
+	// the deployer deletes every vm, tolerating missing vms & unresponsive agents, and creates them again with their disks
+	err = c.deploymentRecreator.RecreateDeployment(stage)
+	if err != nil {
+		return bosherr.WrapError(err, "Recreating deployment")
+	}
+
+	return nil
+}
+
+func (c *cloudChecker) printProblems(problems []bicloudcheck.Problem) {
+	c.ui.PrintLinef("Found %d problem(s):", len(problems))
+	for _, problem := range problems {
+		resolutionNames := []string{}
+		for _, resolution := range problem.Resolutions {
+			resolutionNames = append(resolutionNames, string(resolution))
+		}
+
+		c.ui.PrintLinef("  - %s (%s)", problem.Description, problem.Type)
+		c.ui.PrintLinef("      resolutions: %s", strings.Join(resolutionNames, ", "))
+	}
+}
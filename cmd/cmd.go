@@ -36,4 +36,9 @@ var genericEnv = map[string]MetaEnv{
 		Default:     "standard out/err",
 		Description: "The path where logs will be written",
 	},
+	"BOSH_INIT_LOG_REDACT_KEYS": MetaEnv{
+		Example:     "vcenter_user,^ssh_key$",
+		Default:     "none",
+		Description: "Comma separated regular expressions of additional keys whose values are masked in logs",
+	},
 }
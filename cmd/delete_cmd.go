@@ -1,17 +1,14 @@
 package cmd
 
 import (
-	"errors"
-	"path/filepath"
-
-	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
 type deleteCmd struct {
-	deploymentDeleterProvider func(deploymentManifestString string) (DeploymentDeleter, error)
+	deploymentDeleterProvider func(deploymentManifestString string, manifestInterpolator biinterpolation.Interpolator) (DeploymentDeleter, error)
 	ui                        biui.UI
 	fs                        boshsys.FileSystem
 	logger                    boshlog.Logger
@@ -22,7 +19,7 @@ func NewDeleteCmd(
 	ui biui.UI,
 	fs boshsys.FileSystem,
 	logger boshlog.Logger,
-	deploymentDeleterProvider func(deploymentManifestString string) (DeploymentDeleter, error),
+	deploymentDeleterProvider func(deploymentManifestString string, manifestInterpolator biinterpolation.Interpolator) (DeploymentDeleter, error),
 ) Cmd {
 	return &deleteCmd{
 		ui: ui,
@@ -40,31 +37,23 @@ func (c *deleteCmd) Name() string {
 func (c *deleteCmd) Meta() Meta {
 	return Meta{
 		Synopsis: "Delete existing deployment",
-		Usage:    "<deployment_manifest_path>",
+		Usage:    manifestFlagsUsage + " <deployment_manifest_path>",
 		Env:      genericEnv,
 	}
 }
 
 func (c *deleteCmd) Run(stage biui.Stage, args []string) error {
-	deploymentManifestPath, err := c.parseCmdInputs(args)
+	deploymentManifestPath, flags, err := c.parseCmdInputs(args)
 	if err != nil {
 		return err
 	}
 
-	manifestAbsFilePath, err := filepath.Abs(deploymentManifestPath)
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
 	if err != nil {
-		c.ui.ErrorLinef("Failed getting absolute path to deployment file '%s'", deploymentManifestPath)
-		return bosherr.WrapErrorf(err, "Getting absolute path to deployment file '%s'", deploymentManifestPath)
-	}
-
-	if !c.fs.FileExists(manifestAbsFilePath) {
-		c.ui.ErrorLinef("Deployment '%s' does not exist", manifestAbsFilePath)
-		return bosherr.Errorf("Deployment manifest does not exist at '%s'", manifestAbsFilePath)
+		return err
 	}
 
-	c.ui.PrintLinef("Deployment manifest: '%s'", manifestAbsFilePath)
-
-	deploymentDeleter, err := c.deploymentDeleterProvider(manifestAbsFilePath)
+	deploymentDeleter, err := c.deploymentDeleterProvider(manifestAbsFilePath, manifestInterpolator)
 	if err != nil {
 		return err
 	}
@@ -72,10 +61,16 @@ func (c *deleteCmd) Run(stage biui.Stage, args []string) error {
 	return deploymentDeleter.DeleteDeployment(stage)
 }
 
-func (c *deleteCmd) parseCmdInputs(args []string) (string, error) {
-	if len(args) != 1 {
-		c.logger.Error(c.logTag, "Invalid arguments: %#v", args)
-		return "", errors.New("Invalid usage - delete command requires exactly 1 argument")
+func (c *deleteCmd) parseCmdInputs(args []string) (string, manifestFlags, error) {
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", flags, err
 	}
-	return args[0], nil
+
+	return positionalArgs[0], flags, nil
 }
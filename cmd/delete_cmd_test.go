@@ -2,6 +2,7 @@ package cmd_test
 
 import (
 	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
@@ -38,7 +39,7 @@ var _ = Describe("DeleteCmd", func() {
 		)
 
 		var newDeleteCmd = func() bicmd.Cmd {
-			doGetFunc := func(deploymentManifestPath string) (bicmd.DeploymentDeleter, error) {
+			doGetFunc := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentDeleter, error) {
 				Expect(deploymentManifestPath).To(Equal(deploymentManifestPath))
 				return mockDeploymentDeleter, nil
 			}
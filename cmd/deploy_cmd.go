@@ -1,18 +1,16 @@
 package cmd
 
 import (
-	"errors"
-	"path/filepath"
 	"strings"
 
-	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
 type deployCmd struct {
-	deploymentPreparerProvider func(deploymentManifestPath string) (DeploymentPreparer, error)
+	deploymentPreparerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPreparer, error)
 	ui                         biui.UI
 	fs                         boshsys.FileSystem
 	eventLogger                biui.Stage
@@ -24,7 +22,7 @@ func NewDeployCmd(
 	ui biui.UI,
 	fs boshsys.FileSystem,
 	logger boshlog.Logger,
-	deploymentPreparerProvider func(deploymentManifestPath string) (DeploymentPreparer, error),
+	deploymentPreparerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPreparer, error),
 ) Cmd {
 	return &deployCmd{
 		ui: ui,
@@ -42,31 +40,23 @@ func (c *deployCmd) Name() string {
 func (c *deployCmd) Meta() Meta {
 	return Meta{
 		Synopsis: "Create or update a deployment",
-		Usage:    "<deployment_manifest_path>",
+		Usage:    manifestFlagsUsage + " <deployment_manifest_path>",
 		Env:      genericEnv,
 	}
 }
 
 func (c *deployCmd) Run(stage biui.Stage, args []string) error {
-	deploymentManifestPath, err := c.parseCmdInputs(args)
+	deploymentManifestPath, flags, err := c.parseCmdInputs(args)
 	if err != nil {
 		return err
 	}
 
-	manifestAbsFilePath, err := filepath.Abs(deploymentManifestPath)
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
 	if err != nil {
-		c.ui.ErrorLinef("Failed getting absolute path to deployment file '%s'", deploymentManifestPath)
-		return bosherr.WrapErrorf(err, "Getting absolute path to deployment file '%s'", deploymentManifestPath)
-	}
-
-	if !c.fs.FileExists(manifestAbsFilePath) {
-		c.ui.ErrorLinef("Deployment '%s' does not exist", manifestAbsFilePath)
-		return bosherr.Errorf("Deployment manifest does not exist at '%s'", manifestAbsFilePath)
+		return err
 	}
 
-	c.ui.PrintLinef("Deployment manifest: '%s'", manifestAbsFilePath)
-
-	deploymentPreparer, err := c.deploymentPreparerProvider(manifestAbsFilePath)
+	deploymentPreparer, err := c.deploymentPreparerProvider(manifestAbsFilePath, manifestInterpolator)
 	if err != nil {
 		return err
 	}
@@ -74,12 +64,18 @@ func (c *deployCmd) Run(stage biui.Stage, args []string) error {
 	return deploymentPreparer.PrepareDeployment(stage)
 }
 
-func (c *deployCmd) parseCmdInputs(args []string) (string, error) {
-	if len(args) != 1 {
-		c.logger.Error(c.logTag, "Invalid arguments: %#v", args)
-		return "", errors.New("Invalid usage - deploy command requires exactly 1 argument")
+func (c *deployCmd) parseCmdInputs(args []string) (string, manifestFlags, error) {
+	flags := manifestFlags{saveVarsStore: true}
+
+	flagSet := newFlagSet(c.Name())
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", flags, err
 	}
-	return args[0], nil
+
+	return positionalArgs[0], flags, nil
 }
 
 func (c *deployCmd) isBlank(str string) bool {
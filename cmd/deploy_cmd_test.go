@@ -3,9 +3,11 @@ package cmd_test
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
@@ -14,14 +16,11 @@ import (
 	"github.com/cloudfoundry/bosh-init/deployment"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega/gbytes"
 
-	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
 	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
 	mock_config "github.com/cloudfoundry/bosh-init/config/mocks"
 	mock_deployment "github.com/cloudfoundry/bosh-init/deployment/mocks"
-	mock_vm "github.com/cloudfoundry/bosh-init/deployment/vm/mocks"
+	mock_instance "github.com/cloudfoundry/bosh-init/deployment/instance/mocks"
 	mock_install "github.com/cloudfoundry/bosh-init/installation/mocks"
-	mock_httpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	mock_registry "github.com/cloudfoundry/bosh-init/registry/mocks"
 	mock_release "github.com/cloudfoundry/bosh-init/release/mocks"
 	mock_stemcell "github.com/cloudfoundry/bosh-init/stemcell/mocks"
@@ -47,7 +46,6 @@ import (
 	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
 	fakebideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest/fakes"
 	fakebideplval "github.com/cloudfoundry/bosh-init/deployment/manifest/fakes"
-	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
 	fakebiinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest/fakes"
 	fakebihttpclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient/fakes"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -73,13 +71,16 @@ func rootDesc() {
 
 	Describe("Run", func() {
 		var (
-			command        bicmd.Cmd
-			fakeFs         *fakesys.FakeFileSystem
-			stdOut         *gbytes.Buffer
-			stdErr         *gbytes.Buffer
-			userInterface  biui.UI
-			sha1Calculator crypto.SHA1Calculator
-			manifestSHA1   string
+			command         bicmd.Cmd
+			planCommand     bicmd.Cmd
+			recreateCommand bicmd.Cmd
+			validateCommand bicmd.Cmd
+			fakeFs          *fakesys.FakeFileSystem
+			stdOut          *gbytes.Buffer
+			stdErr          *gbytes.Buffer
+			userInterface   biui.UI
+			sha1Calculator  crypto.SHA1Calculator
+			manifestSHA1    string
 
 			mockDeployer              *mock_deployment.MockDeployer
 			mockInstaller             *mock_install.MockInstaller
@@ -88,18 +89,14 @@ func rootDesc() {
 			releaseManager            birel.Manager
 			mockRegistryServerManager *mock_registry.MockServerManager
 			mockRegistryServer        *mock_registry.MockServer
-			mockAgentClient           *mock_agentclient.MockAgentClient
-			mockAgentClientFactory    *mock_httpagent.MockAgentClientFactory
 			mockCloudFactory          *mock_cloud.MockFactory
 
 			fakeCPIRelease *fakebirel.FakeRelease
 			logger         boshlog.Logger
 
-			mockBlobstoreFactory *mock_blobstore.MockFactory
-			mockBlobstore        *mock_blobstore.MockBlobstore
+			mockInstanceManagerProviderFactory *mock_instance.MockManagerProviderFactory
+			mockInstanceManagerProvider        *mock_instance.MockManagerProvider
 
-			mockVMManagerFactory       *mock_vm.MockManagerFactory
-			fakeVMManager              *fakebivm.FakeManager
 			fakeStemcellExtractor      *fakebistemcell.FakeExtractor
 			mockStemcellManager        *mock_stemcell.MockManager
 			fakeStemcellManagerFactory *fakebistemcell.FakeManagerFactory
@@ -167,19 +164,11 @@ func rootDesc() {
 			mockRegistryServerManager = mock_registry.NewMockServerManager(mockCtrl)
 			mockRegistryServer = mock_registry.NewMockServer(mockCtrl)
 
-			mockAgentClientFactory = mock_httpagent.NewMockAgentClientFactory(mockCtrl)
-			mockAgentClient = mock_agentclient.NewMockAgentClient(mockCtrl)
-			mockAgentClientFactory.EXPECT().NewAgentClient(gomock.Any(), gomock.Any()).Return(mockAgentClient).AnyTimes()
-
 			mockCloudFactory = mock_cloud.NewMockFactory(mockCtrl)
 
-			mockBlobstoreFactory = mock_blobstore.NewMockFactory(mockCtrl)
-			mockBlobstore = mock_blobstore.NewMockBlobstore(mockCtrl)
-			mockBlobstoreFactory.EXPECT().Create(mbusURL).Return(mockBlobstore, nil).AnyTimes()
-
-			mockVMManagerFactory = mock_vm.NewMockManagerFactory(mockCtrl)
-			fakeVMManager = fakebivm.NewFakeManager()
-			mockVMManagerFactory.EXPECT().NewManager(gomock.Any(), mockAgentClient).Return(fakeVMManager).AnyTimes()
+			mockInstanceManagerProviderFactory = mock_instance.NewMockManagerProviderFactory(mockCtrl)
+			mockInstanceManagerProvider = mock_instance.NewMockManagerProvider(mockCtrl)
+			mockInstanceManagerProviderFactory.EXPECT().NewManagerProvider(gomock.Any(), gomock.Any(), mbusURL).Return(mockInstanceManagerProvider).AnyTimes()
 
 			fakeStemcellExtractor = fakebistemcell.NewFakeExtractor()
 			mockStemcellManager = mock_stemcell.NewMockManager(mockCtrl)
@@ -263,7 +252,8 @@ func rootDesc() {
 				Name: "fake-deployment-name",
 				Jobs: []bideplmanifest.Job{
 					{
-						Name: "fake-job-name",
+						Name:      "fake-job-name",
+						Instances: 1,
 					},
 				},
 				ResourcePools: []bideplmanifest.ResourcePool{
@@ -290,19 +280,22 @@ func rootDesc() {
 				},
 			}
 
-			cloud = bicloud.NewCloud(fakebicloud.NewFakeCPICmdRunner(), "fake-director-id", logger)
+			cloud = bicloud.NewCloud(fakebicloud.NewFakeCPICmdRunner(), "fake-director-id", bicloud.CPIInfo{}, logger)
 
 			cloudStemcell = fakebistemcell.NewFakeCloudStemcell("fake-stemcell-cid", "fake-stemcell-name", "fake-stemcell-version")
 		})
 
 		JustBeforeEach(func() {
 
-			doGet := func(deploymentManifestPath string) (bicmd.DeploymentPreparer, error) {
+			doGet := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentPreparer, error) {
 				deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fakeFs, configUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath))
 				deploymentRepo := biconfig.NewDeploymentRepo(deploymentStateService)
 				releaseRepo := biconfig.NewReleaseRepo(deploymentStateService, fakeUUIDGenerator)
 				stemcellRepo := biconfig.NewStemcellRepo(deploymentStateService, fakeUUIDGenerator)
 				deploymentRecord := deployment.NewRecord(deploymentRepo, releaseRepo, stemcellRepo, sha1Calculator)
+				instanceRepo := biconfig.NewInstanceRepo(deploymentStateService)
+				diskRepo := biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeUUIDGenerator)
+				deploymentPlanner := deployment.NewPlanner(deploymentRepo, releaseRepo, stemcellRepo, instanceRepo, diskRepo, sha1Calculator)
 
 				fakeHTTPClient := fakebihttpclient.NewFakeHTTPClient()
 				tarballCache := bitarball.NewCache("fake-base-path", fakeFs, logger)
@@ -327,6 +320,7 @@ func rootDesc() {
 					DeploymentParser:    fakeDeploymentParser,
 					DeploymentValidator: fakeDeploymentValidator,
 					ReleaseManager:      releaseManager,
+					UI:                  userInterface,
 				}
 
 				fakeInstallationUUIDGenerator := &fakeuuid.FakeGenerator{}
@@ -348,11 +342,11 @@ func rootDesc() {
 					deploymentRecord,
 					mockCloudFactory,
 					fakeStemcellManagerFactory,
-					mockAgentClientFactory,
-					mockVMManagerFactory,
-					mockBlobstoreFactory,
+					mockInstanceManagerProviderFactory,
 					mockDeployer,
 					deploymentManifestPath,
+					manifestInterpolator,
+					fakeFs,
 					cpiInstaller,
 					releaseFetcher,
 					stemcellFetcher,
@@ -360,11 +354,28 @@ func rootDesc() {
 					deploymentManifestParser,
 					tempRootConfigurator,
 					targetProvider,
+					deploymentPlanner,
+					deployment.NewIPAllocator(biconfig.NewIPAllocationRepo(deploymentStateService), logger),
 				), nil
 			}
 
 			command = bicmd.NewDeployCmd(userInterface, fakeFs, logger, doGet)
 
+			planCommand = bicmd.NewPlanCmd(userInterface, fakeFs, logger, func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentPlanner, error) {
+				deploymentPreparer, err := doGet(deploymentManifestPath, manifestInterpolator)
+				return &deploymentPreparer, err
+			})
+
+			recreateCommand = bicmd.NewRecreateCmd(userInterface, fakeFs, logger, func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentRecreator, error) {
+				deploymentPreparer, err := doGet(deploymentManifestPath, manifestInterpolator)
+				return &deploymentPreparer, err
+			})
+
+			validateCommand = bicmd.NewValidateCmd(userInterface, fakeFs, logger, func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentValidator, error) {
+				deploymentPreparer, err := doGet(deploymentManifestPath, manifestInterpolator)
+				return &deploymentPreparer, err
+			})
+
 			expectLegacyMigrate = mockLegacyDeploymentStateMigrator.EXPECT().MigrateIfExists("/path/to/bosh-deployments.yml").AnyTimes()
 
 			fakeStemcellExtractor.SetExtractBehavior(stemcellTarballPath, extractedStemcell, nil)
@@ -405,10 +416,9 @@ func rootDesc() {
 				boshDeploymentManifest,
 				cloudStemcell,
 				installationManifest.Registry,
-				fakeVMManager,
-				mockBlobstore,
+				mockInstanceManagerProvider,
 				gomock.Any(),
-			).Do(func(_, _, _, _, _, _ interface{}, stage biui.Stage) {
+			).Do(func(_, _, _, _, _ interface{}, stage biui.Stage) {
 				Expect(fakeStage.SubStages).To(ContainElement(stage))
 			}).Return(mockDeployment, nil).AnyTimes()
 
@@ -656,6 +666,227 @@ func rootDesc() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(stdOut).To(gbytes.Say("No deployment, stemcell or release changes. Skipping deploy."))
 			})
+
+			Context("when the deployment manifest uses variables", func() {
+				BeforeEach(func() {
+					err := fakeFs.WriteFileString(deploymentManifestPath, "---\nname: ((name))\n")
+					Expect(err).ToNot(HaveOccurred())
+
+					interpolatedManifest, err := biinterpolation.NewInterpolator(biinterpolation.Variables{"name": "fake-old-name"}, nil).Interpolate([]byte("---\nname: ((name))\n"))
+					Expect(err).ToNot(HaveOccurred())
+					manifestSHA1 = sha1Calculator.CalculateContents(interpolatedManifest)
+				})
+
+				It("skips deploy when the variables have not changed", func() {
+					expectDeploy.Times(0)
+
+					err := command.Run(fakeStage, []string{"--var", "name=fake-old-name", deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(stdOut).To(gbytes.Say("No deployment, stemcell or release changes. Skipping deploy."))
+				})
+
+				It("deploys when only a variable has changed", func() {
+					expectDeploy.Times(1)
+
+					err := command.Run(fakeStage, []string{"--var", "name=fake-new-name", deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+
+		Context("when planning", func() {
+			It("prints the changes that deploy would make", func() {
+				err := planCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stdOut).To(gbytes.Say("Deployment manifest: '/path/to/manifest.yml'"))
+				Expect(stdOut).To(gbytes.Say("Deployment state: '/path/to/manifest-state.json'"))
+				Expect(stdOut).To(gbytes.Say("Deploy would make the following changes:"))
+				Expect(stdOut).To(gbytes.Say("  - Upload stemcell 'fake-stemcell-name/fake-stemcell-version'"))
+				Expect(stdOut).To(gbytes.Say("  - Create vm for instance 'fake-job-name/0'"))
+				Expect(stdOut).To(gbytes.Say("      deployment manifest changed"))
+			})
+
+			It("validates without installing the CPI or deploying", func() {
+				expectInstall.Times(0)
+				expectNewCloud.Times(0)
+				expectStemcellUpload.Times(0)
+				expectDeploy.Times(0)
+
+				err := planCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStage.PerformCalls[0].Name).To(Equal("validating"))
+				Expect(fakeStage.PerformCalls).To(HaveLen(1))
+			})
+
+			It("does not update the deployment record", func() {
+				err := planCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				deploymentState, err := setupDeploymentStateService.Load()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploymentState.CurrentManifestSHA1).To(BeEmpty())
+				Expect(deploymentState.Releases).To(BeEmpty())
+			})
+
+			Context("when deployment has not changed", func() {
+				JustBeforeEach(func() {
+					previousDeploymentState := biconfig.DeploymentState{
+						DirectorID:        directorID,
+						CurrentReleaseIDs: []string{"my-release-id-1"},
+						Releases: []biconfig.ReleaseRecord{{
+							ID:      "my-release-id-1",
+							Name:    fakeCPIRelease.Name(),
+							Version: fakeCPIRelease.Version(),
+						}},
+						CurrentStemcellID: "my-stemcellRecordID",
+						Stemcells: []biconfig.StemcellRecord{{
+							ID:      "my-stemcellRecordID",
+							Name:    cloudStemcell.Name(),
+							Version: cloudStemcell.Version(),
+						}},
+						CurrentManifestSHA1: manifestSHA1,
+					}
+
+					err := setupDeploymentStateService.Save(previousDeploymentState)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("prints that deploy would be skipped", func() {
+					err := planCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(stdOut).To(gbytes.Say("No deployment, stemcell or release changes. Deploy would be skipped."))
+				})
+			})
+		})
+
+		Context("when recreating", func() {
+			Context("when deployment has not changed", func() {
+				JustBeforeEach(func() {
+					previousDeploymentState := biconfig.DeploymentState{
+						DirectorID:        directorID,
+						CurrentReleaseIDs: []string{"my-release-id-1"},
+						Releases: []biconfig.ReleaseRecord{{
+							ID:      "my-release-id-1",
+							Name:    fakeCPIRelease.Name(),
+							Version: fakeCPIRelease.Version(),
+						}},
+						CurrentStemcellID: "my-stemcellRecordID",
+						Stemcells: []biconfig.StemcellRecord{{
+							ID:      "my-stemcellRecordID",
+							Name:    cloudStemcell.Name(),
+							Version: cloudStemcell.Version(),
+						}},
+						CurrentManifestSHA1: manifestSHA1,
+					}
+
+					err := setupDeploymentStateService.Save(previousDeploymentState)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("deploys even though nothing changed", func() {
+					expectDeploy.Times(1)
+
+					err := recreateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("keeps the deployment record up to date", func() {
+					err := recreateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+
+					deploymentState, err := setupDeploymentStateService.Load()
+					Expect(err).ToNot(HaveOccurred())
+					Expect(deploymentState.CurrentManifestSHA1).To(Equal(manifestSHA1))
+				})
+			})
+
+			Context("when deployment has changed", func() {
+				It("returns an error without deploying", func() {
+					expectDeploy.Times(0)
+
+					err := recreateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Deployment has changed, run 'bosh-init deploy' instead of recreating"))
+					Expect(stdErr).To(gbytes.Say("Deployment manifest, stemcell or releases have changed since the last deploy"))
+				})
+			})
+		})
+
+		Context("when validating", func() {
+			It("validates without installing the CPI or deploying", func() {
+				expectInstall.Times(0)
+				expectNewCloud.Times(0)
+				expectStemcellUpload.Times(0)
+				expectDeploy.Times(0)
+
+				err := validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStage.PerformCalls[0].Name).To(Equal("validating"))
+				Expect(fakeStage.PerformCalls).To(HaveLen(1))
+				Expect(stdOut).To(gbytes.Say("Deployment manifest, releases and stemcell are valid"))
+			})
+
+			It("does not touch the deployment state", func() {
+				expectLegacyMigrate.Times(0)
+
+				err := fakeFs.RemoveAll(deploymentStatePath)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeFs.FileExists(deploymentStatePath)).To(BeFalse())
+			})
+
+			Context("when the deployment state exists", func() {
+				BeforeEach(func() {
+					err := fakeFs.WriteFileString(deploymentStatePath, "{}")
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("sets the temp root to the tmp dir of the installation", func() {
+					err := validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeFs.TempRootPath).To(Equal("fake-install-dir/fake-installation-id/tmp"))
+				})
+
+				It("returns an error when setting the temp root fails", func() {
+					fakeFs.ChangeTempRootErr = errors.New("fake ChangeTempRootErr")
+					err := validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Setting temp root: fake ChangeTempRootErr"))
+				})
+			})
+
+			It("cleans up the extracted stemcell", func() {
+				err := fakeFs.MkdirAll("fake-extracted-path", os.ModePerm)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeFs.FileExists("fake-extracted-path")).To(BeFalse())
+			})
+
+			Context("when the release and the stemcell are both invalid", func() {
+				BeforeEach(func() {
+					mockReleaseExtractor.EXPECT().Extract(cpiReleaseTarballPath).Return(nil, errors.New("not there"))
+				})
+
+				JustBeforeEach(func() {
+					fakeStemcellExtractor.SetExtractBehavior(stemcellTarballPath, extractedStemcell, errors.New("no-stemcell-there"))
+				})
+
+				It("reports all the errors at once", func() {
+					err := validateCommand.Run(fakeStage, []string{deploymentManifestPath})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("not there"))
+					Expect(err.Error()).To(ContainSubstring("no-stemcell-there"))
+				})
+			})
 		})
 
 		Context("when parsing the cpi deployment manifest fails", func() {
@@ -1002,8 +1233,7 @@ func rootDesc() {
 					boshDeploymentManifest,
 					cloudStemcell,
 					installationManifest.Registry,
-					fakeVMManager,
-					mockBlobstore,
+					mockInstanceManagerProvider,
 					gomock.Any(),
 				).Return(nil, errors.New("fake-deploy-error")).AnyTimes()
 
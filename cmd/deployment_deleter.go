@@ -1,18 +1,20 @@
 package cmd
 
 import (
+	"time"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
 	bidepl "github.com/cloudfoundry/bosh-init/deployment"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	biinstall "github.com/cloudfoundry/bosh-init/installation"
 	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
-	bihttpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	birel "github.com/cloudfoundry/bosh-init/release"
-	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
@@ -27,9 +29,11 @@ func NewDeploymentDeleter(
 	deploymentStateService biconfig.DeploymentStateService,
 	releaseManager birel.Manager,
 	cloudFactory bicloud.Factory,
-	agentClientFactory bihttpagent.AgentClientFactory,
+	agentClientFactory biagentclient.AgentClientFactory,
 	blobstoreFactory biblobstore.Factory,
 	deploymentManagerFactory bidepl.ManagerFactory,
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory,
+	instanceRepo biconfig.InstanceRepo,
 	deploymentManifestPath string,
 	cpiInstaller bicpirel.CpiInstaller,
 	cpiUninstaller biinstall.Uninstaller,
@@ -39,135 +43,94 @@ func NewDeploymentDeleter(
 	targetProvider biinstall.TargetProvider,
 ) DeploymentDeleter {
 	return &deploymentDeleter{
-		ui:                                      ui,
-		logTag:                                  logTag,
-		logger:                                  logger,
-		deploymentStateService:                  deploymentStateService,
-		releaseManager:                          releaseManager,
-		cloudFactory:                            cloudFactory,
-		agentClientFactory:                      agentClientFactory,
-		blobstoreFactory:                        blobstoreFactory,
-		deploymentManagerFactory:                deploymentManagerFactory,
-		deploymentManifestPath:                  deploymentManifestPath,
-		cpiInstaller:                            cpiInstaller,
-		cpiUninstaller:                          cpiUninstaller,
-		releaseFetcher:                          releaseFetcher,
-		releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
-		tempRootConfigurator:                    tempRootConfigurator,
-		targetProvider:                          targetProvider,
+		ui:                             ui,
+		logTag:                         logTag,
+		logger:                         logger,
+		deploymentStateService:         deploymentStateService,
+		cloudFactory:                   cloudFactory,
+		agentClientFactory:             agentClientFactory,
+		blobstoreFactory:               blobstoreFactory,
+		deploymentManagerFactory:       deploymentManagerFactory,
+		instanceManagerProviderFactory: instanceManagerProviderFactory,
+		instanceRepo:                   instanceRepo,
+		cpiUninstaller:                 cpiUninstaller,
+		installedCpiRunner: installedCpiRunner{
+			ui:                                      ui,
+			logTag:                                  logTag,
+			logger:                                  logger,
+			deploymentStateService:                  deploymentStateService,
+			releaseManager:                          releaseManager,
+			deploymentManifestPath:                  deploymentManifestPath,
+			cpiInstaller:                            cpiInstaller,
+			releaseFetcher:                          releaseFetcher,
+			releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+			tempRootConfigurator:                    tempRootConfigurator,
+			targetProvider:                          targetProvider,
+		},
 	}
 }
 
 type deploymentDeleter struct {
-	ui                                      biui.UI
-	logTag                                  string
-	logger                                  boshlog.Logger
-	deploymentStateService                  biconfig.DeploymentStateService
-	releaseManager                          birel.Manager
-	cloudFactory                            bicloud.Factory
-	agentClientFactory                      bihttpagent.AgentClientFactory
-	blobstoreFactory                        biblobstore.Factory
-	deploymentManagerFactory                bidepl.ManagerFactory
-	deploymentManifestPath                  string
-	cpiInstaller                            bicpirel.CpiInstaller
-	cpiUninstaller                          biinstall.Uninstaller
-	releaseFetcher                          birel.Fetcher
-	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
-	tempRootConfigurator                    TempRootConfigurator
-	targetProvider                          biinstall.TargetProvider
+	ui                             biui.UI
+	logTag                         string
+	logger                         boshlog.Logger
+	deploymentStateService         biconfig.DeploymentStateService
+	cloudFactory                   bicloud.Factory
+	agentClientFactory             biagentclient.AgentClientFactory
+	blobstoreFactory               biblobstore.Factory
+	deploymentManagerFactory       bidepl.ManagerFactory
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory
+	instanceRepo                   biconfig.InstanceRepo
+	cpiUninstaller                 biinstall.Uninstaller
+	installedCpiRunner             installedCpiRunner
 }
 
 func (c *deploymentDeleter) DeleteDeployment(stage biui.Stage) (err error) {
-	c.ui.PrintLinef("Deployment state: '%s'", c.deploymentStateService.Path())
-
-	if !c.deploymentStateService.Exists() {
-		c.ui.PrintLinef("No deployment state file found.")
-		return nil
-	}
-
-	deploymentState, err := c.deploymentStateService.Load()
-	if err != nil {
-		return bosherr.WrapError(err, "Loading deployment state")
-	}
-
-	target, err := c.targetProvider.NewTarget()
-	if err != nil {
-		return bosherr.WrapError(err, "Determining installation target")
-	}
-
-	err = c.tempRootConfigurator.PrepareAndSetTempRoot(target.TmpPath(), c.logger)
-	if err != nil {
-		return bosherr.WrapError(err, "Setting temp root")
-	}
-
-	defer func() {
-		err := c.releaseManager.DeleteAll()
-		if err != nil {
-			c.logger.Warn(c.logTag, "Deleting all extracted releases: %s", err.Error())
-		}
-	}()
-
-	var installationManifest biinstallmanifest.Manifest
-	err = stage.PerformComplex("validating", func(stage biui.Stage) error {
-		var releaseSetManifest birelsetmanifest.Manifest
-		releaseSetManifest, installationManifest, err = c.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(c.deploymentManifestPath)
+	return c.installedCpiRunner.Run(stage, func(localCpiInstallation biinstall.Installation, installationManifest biinstallmanifest.Manifest, deploymentState biconfig.DeploymentState) error {
+		err := c.findAndDeleteDeployment(stage, localCpiInstallation, deploymentState.DirectorID, installationManifest.Mbus)
 		if err != nil {
 			return err
 		}
 
-		cpiReleaseName := installationManifest.Template.Release
-		cpiReleaseRef, found := releaseSetManifest.FindByName(cpiReleaseName)
-		if !found {
-			return bosherr.Errorf("installation release '%s' must refer to a release in releases", cpiReleaseName)
-		}
-
-		err = c.releaseFetcher.DownloadAndExtract(cpiReleaseRef, stage)
-		if err != nil {
-			return err
-		}
-
-		err = c.cpiInstaller.ValidateCpiRelease(installationManifest, stage)
-		return err
-	})
-	if err != nil {
-		return err
-	}
-
-	err = c.cpiInstaller.WithInstalledCpiRelease(installationManifest, target, stage, func(localCpiInstallation biinstall.Installation) error {
-		return localCpiInstallation.WithRunningRegistry(c.logger, stage, func() error {
-			err = c.findAndDeleteDeployment(stage, localCpiInstallation, deploymentState.DirectorID, installationManifest.Mbus)
-
+		return stage.Perform("Uninstalling local artifacts for CPI and deployment", func() error {
+			err := c.cpiUninstaller.Uninstall(localCpiInstallation.Target())
 			if err != nil {
 				return err
 			}
 
-			return stage.Perform("Uninstalling local artifacts for CPI and deployment", func() error {
-				err := c.cpiUninstaller.Uninstall(localCpiInstallation.Target())
-				if err != nil {
-					return err
-				}
-
-				return c.deploymentStateService.Cleanup()
-			})
+			return c.deploymentStateService.Cleanup()
 		})
 	})
-
-	return err
 }
 
 func (c *deploymentDeleter) findAndDeleteDeployment(stage biui.Stage, installation biinstall.Installation, directorID, installationMbus string) error {
-	deploymentManager, err := c.deploymentManager(installation, directorID, installationMbus)
+	c.logger.Debug(c.logTag, "Creating cloud client...")
+	cloud, err := c.cloudFactory.NewCloud(installation, directorID)
+	if err != nil {
+		return bosherr.WrapError(err, "Creating CPI client from CPI installation")
+	}
+
+	deploymentManager, err := c.deploymentManager(cloud, directorID, installationMbus)
 	if err != nil {
 		return err
 	}
-	err = c.findCurrentDeploymentAndDelete(stage, deploymentManager)
+
+	instanceManagerProvider := c.instanceManagerProviderFactory.NewManagerProvider(cloud, directorID, installationMbus)
+
+	err = c.findCurrentDeploymentAndDelete(stage, deploymentManager, instanceManagerProvider)
 	if err != nil {
 		return bosherr.WrapError(err, "Deleting deployment")
 	}
 	return deploymentManager.Cleanup(stage)
 }
 
-func (c *deploymentDeleter) findCurrentDeploymentAndDelete(stage biui.Stage, deploymentManager bidepl.Manager) error {
+func (c *deploymentDeleter) findCurrentDeploymentAndDelete(stage biui.Stage, deploymentManager bidepl.Manager, instanceManagerProvider biinstance.ManagerProvider) error {
+	c.logger.Debug(c.logTag, "Finding current instances...")
+	instanceManagers, err := instanceManagerProvider.FindAll()
+	if err != nil {
+		return bosherr.WrapError(err, "Finding current instances")
+	}
+
 	c.logger.Debug(c.logTag, "Finding current deployment...")
 	deployment, found, err := deploymentManager.FindCurrent()
 	if err != nil {
@@ -175,6 +138,20 @@ func (c *deploymentDeleter) findCurrentDeploymentAndDelete(stage biui.Stage, dep
 	}
 
 	return stage.PerformComplex("deleting deployment", func(deleteStage biui.Stage) error {
+		// The bootstrap instance is deleted with the deployment, after every other instance.
+		// The disks of the other instances become unused once their records are cleared, and are deleted by the cleanup.
+		pingTimeout := 10 * time.Second
+		pingDelay := 500 * time.Millisecond
+		for _, instanceManager := range instanceManagers[1:] {
+			if err := instanceManager.DeleteAll(pingTimeout, pingDelay, deleteStage); err != nil {
+				return err
+			}
+		}
+
+		if err := c.instanceRepo.ClearAll(); err != nil {
+			return bosherr.WrapError(err, "Clearing instance records")
+		}
+
 		if !found {
 			//TODO: skip? would require adding skip support to PerformComplex
 			c.logger.Debug(c.logTag, "No current deployment found...")
@@ -185,13 +162,7 @@ func (c *deploymentDeleter) findCurrentDeploymentAndDelete(stage biui.Stage, dep
 	})
 }
 
-func (c *deploymentDeleter) deploymentManager(installation biinstall.Installation, directorID, installationMbus string) (bidepl.Manager, error) {
-	c.logger.Debug(c.logTag, "Creating cloud client...")
-	cloud, err := c.cloudFactory.NewCloud(installation, directorID)
-	if err != nil {
-		return nil, bosherr.WrapError(err, "Creating CPI client from CPI installation")
-	}
-
+func (c *deploymentDeleter) deploymentManager(cloud bicloud.Cloud, directorID, installationMbus string) (bidepl.Manager, error) {
 	c.logger.Debug(c.logTag, "Creating agent client...")
 	agentClient := c.agentClientFactory.NewAgentClient(directorID, installationMbus)
 
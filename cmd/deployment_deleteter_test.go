@@ -8,18 +8,20 @@ import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
 	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 
+	mock_agentclient "github.com/cloudfoundry/bosh-init/agentclient/mocks"
 	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
 	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
+	mock_instance "github.com/cloudfoundry/bosh-init/deployment/instance/mocks"
 	mock_deployment "github.com/cloudfoundry/bosh-init/deployment/mocks"
 	mock_install "github.com/cloudfoundry/bosh-init/installation/mocks"
-	mock_httpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	mock_release "github.com/cloudfoundry/bosh-init/release/mocks"
 
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	biinstall "github.com/cloudfoundry/bosh-init/installation"
 	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
 	bitarball "github.com/cloudfoundry/bosh-init/installation/tarball"
@@ -76,8 +78,12 @@ var _ = Describe("DeploymentDeleter", func() {
 			mockDeploymentManager        *mock_deployment.MockManager
 			mockDeployment               *mock_deployment.MockDeployment
 
+			mockInstanceManagerProviderFactory *mock_instance.MockManagerProviderFactory
+			mockInstanceManagerProvider        *mock_instance.MockManagerProvider
+			mockBootstrapInstanceManager       *mock_instance.MockManager
+
 			mockAgentClient        *mock_agentclient.MockAgentClient
-			mockAgentClientFactory *mock_httpagent.MockAgentClientFactory
+			mockAgentClientFactory *mock_agentclient.MockAgentClientFactory
 			mockCloud              *mock_cloud.MockCloud
 
 			fakeStage *fakebiui.FakeStage
@@ -162,9 +168,9 @@ cloud_provider:
 
 		var newDeploymentDeleter = func() bicmd.DeploymentDeleter {
 			releaseSetValidator := birelsetmanifest.NewValidator(logger)
-			releaseSetParser := birelsetmanifest.NewParser(fs, logger, releaseSetValidator)
+			releaseSetParser := birelsetmanifest.NewParser(fs, biinterpolation.NewInterpolator(biinterpolation.Variables{}, biinterpolation.Ops{}), logger, releaseSetValidator)
 			installationValidator := biinstallmanifest.NewValidator(logger)
-			installationParser := biinstallmanifest.NewParser(fs, fakeUUIDGenerator, logger, installationValidator)
+			installationParser := biinstallmanifest.NewParser(fs, biinterpolation.NewInterpolator(biinterpolation.Variables{}, biinterpolation.Ops{}), fakeUUIDGenerator, logger, installationValidator)
 			fakeHTTPClient := fakebihttpclient.NewFakeHTTPClient()
 			tarballCache := bitarball.NewCache("fake-base-path", fs, logger)
 			fakeSHA1Calculator := fakebicrypto.NewFakeSha1Calculator()
@@ -201,6 +207,8 @@ cloud_provider:
 				mockAgentClientFactory,
 				mockBlobstoreFactory,
 				mockDeploymentManagerFactory,
+				mockInstanceManagerProviderFactory,
+				biconfig.NewInstanceRepo(deploymentStateService),
 				deploymentManifestPath,
 				cpiInstaller,
 				mockCpiUninstaller,
@@ -214,6 +222,7 @@ cloud_provider:
 		var expectDeleteAndCleanup = func(defaultUninstallerUsed bool) {
 			mockDeploymentManagerFactory.EXPECT().NewManager(mockCloud, mockAgentClient, mockBlobstore).Return(mockDeploymentManager)
 			mockDeploymentManager.EXPECT().FindCurrent().Return(mockDeployment, true, nil)
+			mockInstanceManagerProvider.EXPECT().FindAll().Return([]biinstance.Manager{mockBootstrapInstanceManager}, nil).AnyTimes()
 
 			gomock.InOrder(
 				mockDeployment.EXPECT().Delete(gomock.Any()).Do(func(stage biui.Stage) {
@@ -230,6 +239,7 @@ cloud_provider:
 		var expectCleanup = func() {
 			mockDeploymentManagerFactory.EXPECT().NewManager(mockCloud, mockAgentClient, mockBlobstore).Return(mockDeploymentManager).AnyTimes()
 			mockDeploymentManager.EXPECT().FindCurrent().Return(nil, false, nil).AnyTimes()
+			mockInstanceManagerProvider.EXPECT().FindAll().Return([]biinstance.Manager{mockBootstrapInstanceManager}, nil).AnyTimes()
 
 			mockDeploymentManager.EXPECT().Cleanup(fakeStage)
 			mockCpiUninstaller.EXPECT().Uninstall(gomock.Any()).Return(nil)
@@ -302,10 +312,15 @@ cloud_provider:
 			mockDeploymentManager = mock_deployment.NewMockManager(mockCtrl)
 			mockDeployment = mock_deployment.NewMockDeployment(mockCtrl)
 
+			mockInstanceManagerProviderFactory = mock_instance.NewMockManagerProviderFactory(mockCtrl)
+			mockInstanceManagerProvider = mock_instance.NewMockManagerProvider(mockCtrl)
+			mockBootstrapInstanceManager = mock_instance.NewMockManager(mockCtrl)
+			mockInstanceManagerProviderFactory.EXPECT().NewManagerProvider(mockCloud, gomock.Any(), mbusURL).Return(mockInstanceManagerProvider).AnyTimes()
+
 			mockReleaseExtractor = mock_release.NewMockExtractor(mockCtrl)
 			releaseManager = birel.NewManager(logger)
 
-			mockAgentClientFactory = mock_httpagent.NewMockAgentClientFactory(mockCtrl)
+			mockAgentClientFactory = mock_agentclient.NewMockAgentClientFactory(mockCtrl)
 			mockAgentClient = mock_agentclient.NewMockAgentClient(mockCtrl)
 
 			mockAgentClientFactory.EXPECT().NewAgentClient(gomock.Any(), gomock.Any()).Return(mockAgentClient).AnyTimes()
@@ -423,6 +438,38 @@ cloud_provider:
 					Expect(fs.FileExists(deploymentStatePath)).To(BeFalse())
 				})
 
+				Context("when the deployment has instances other than the bootstrap instance", func() {
+					BeforeEach(func() {
+						setupDeploymentStateService.Save(biconfig.DeploymentState{
+							DirectorID: directorID,
+							Instances: []biconfig.InstanceRecord{
+								{Job: "fake-job", Index: 1, Host: "10.0.0.7", VMCID: "fake-vm-cid", DiskID: "fake-disk-id"},
+							},
+						})
+					})
+
+					It("deletes the other instances first and clears their records, so that their disks are cleaned up", func() {
+						mockOtherInstanceManager := mock_instance.NewMockManager(mockCtrl)
+
+						mockDeploymentManagerFactory.EXPECT().NewManager(mockCloud, mockAgentClient, mockBlobstore).Return(mockDeploymentManager)
+						mockDeploymentManager.EXPECT().FindCurrent().Return(mockDeployment, true, nil)
+						mockInstanceManagerProvider.EXPECT().FindAll().Return([]biinstance.Manager{mockBootstrapInstanceManager, mockOtherInstanceManager}, nil)
+						mockCpiUninstaller.EXPECT().Uninstall(gomock.Any()).Return(nil)
+
+						gomock.InOrder(
+							mockOtherInstanceManager.EXPECT().DeleteAll(gomock.Any(), gomock.Any(), gomock.Any()),
+							mockDeployment.EXPECT().Delete(gomock.Any()),
+							mockDeploymentManager.EXPECT().Cleanup(fakeStage).Do(func(_ biui.Stage) {
+								deploymentState, err := setupDeploymentStateService.Load()
+								Expect(err).ToNot(HaveOccurred())
+								Expect(deploymentState.Instances).To(BeEmpty())
+							}),
+						)
+
+						err := newDeploymentDeleter().DeleteDeployment(fakeStage)
+						Expect(err).ToNot(HaveOccurred())
+					})
+				})
 			})
 
 			Context("when nothing has been deployed", func() {
@@ -469,6 +516,7 @@ cloud_provider:
 				It("returns the error", func() {
 					mockDeploymentManagerFactory.EXPECT().NewManager(mockCloud, mockAgentClient, mockBlobstore).Return(mockDeploymentManager)
 					mockDeploymentManager.EXPECT().FindCurrent().Return(mockDeployment, true, nil)
+					mockInstanceManagerProvider.EXPECT().FindAll().Return([]biinstance.Manager{mockBootstrapInstanceManager}, nil)
 
 					deleteError := bosherr.Error("delete error")
 
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	biposition "github.com/cloudfoundry/bosh-init/common/position"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	birel "github.com/cloudfoundry/bosh-init/release"
 	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
 	biui "github.com/cloudfoundry/bosh-init/ui"
@@ -12,10 +15,17 @@ type DeploymentManifestParser struct {
 	DeploymentParser    bideplmanifest.Parser
 	DeploymentValidator bideplmanifest.Validator
 	ReleaseManager      birel.Manager
+	// FS is used to read the manifest again to find the position of the nodes with validation errors.
+	// Validation errors do not have positions when it is not set.
+	FS                   boshsys.FileSystem
+	ManifestInterpolator biinterpolation.Interpolator
+	// UI prints the warnings of the validation, which do not prevent a deploy
+	UI biui.UI
 }
 
 func (y DeploymentManifestParser) GetDeploymentManifest(deploymentManifestPath string, releaseSetManifest birelsetmanifest.Manifest, stage biui.Stage) (bideplmanifest.Manifest, error) {
 	var deploymentManifest bideplmanifest.Manifest
+	var warnings []string
 	err := stage.Perform("Validating deployment manifest", func() error {
 		var err error
 		deploymentManifest, err = y.DeploymentParser.Parse(deploymentManifestPath)
@@ -25,19 +35,50 @@ func (y DeploymentManifestParser) GetDeploymentManifest(deploymentManifestPath s
 
 		err = y.DeploymentValidator.Validate(deploymentManifest, releaseSetManifest)
 		if err != nil {
-			return bosherr.WrapError(err, "Validating deployment manifest")
+			return bosherr.WrapError(y.annotate(deploymentManifestPath, err), "Validating deployment manifest")
 		}
 
-		err = y.DeploymentValidator.ValidateReleaseJobs(deploymentManifest, y.ReleaseManager)
+		warnings, err = y.DeploymentValidator.ValidateReleaseJobs(deploymentManifest, y.ReleaseManager)
 		if err != nil {
-			return bosherr.WrapError(err, "Validating deployment jobs refer to jobs in release")
+			return bosherr.WrapError(y.annotate(deploymentManifestPath, err), "Validating deployment jobs refer to jobs in release")
 		}
 
 		return nil
 	})
+
+	// printed once the stage line is ended
+	for _, warning := range warnings {
+		y.UI.PrintLinef("Warning: %s", warning)
+	}
+
 	if err != nil {
 		return bideplmanifest.Manifest{}, err
 	}
 
 	return deploymentManifest, nil
 }
+
+// annotate adds the position in the manifest file of the nodes with validation errors.
+// When ops files are applied, the paths of the errors are mapped back from the interpolated manifest to the file.
+func (y DeploymentManifestParser) annotate(deploymentManifestPath string, err error) error {
+	if y.FS == nil {
+		return err
+	}
+
+	contents, readErr := y.FS.ReadFile(deploymentManifestPath)
+	if readErr != nil {
+		return err
+	}
+
+	positions := biposition.NewIndex(deploymentManifestPath, contents)
+	if y.ManifestInterpolator != nil && y.ManifestInterpolator.AppliesOps() {
+		// the interpolator keeps its result, so the manifest is not interpolated again
+		interpolatedContents, interpolateErr := y.ManifestInterpolator.Interpolate(contents)
+		if interpolateErr != nil {
+			return err
+		}
+		positions = positions.Map(interpolatedContents)
+	}
+
+	return positions.Annotate(err)
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"errors"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
+
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	fakebideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest/fakes"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("DeploymentManifestParser", func() {
+	var (
+		fakeFs                  *fakesys.FakeFileSystem
+		fakeDeploymentValidator *fakebideplmanifest.FakeValidator
+		fakeUI                  *fakebiui.FakeUI
+		deploymentManifestPath  string
+		manifestParser          bicmd.DeploymentManifestParser
+	)
+
+	BeforeEach(func() {
+		fakeFs = fakesys.NewFakeFileSystem()
+		deploymentManifestPath = "/path/to/manifest.yml"
+		fakeFs.WriteFileString(deploymentManifestPath, "---\nname: fake-deployment-name\njobs:\n- name: fake-job\n  instances: 0\n")
+
+		fakeDeploymentValidator = fakebideplmanifest.NewFakeValidator()
+		fakeUI = &fakebiui.FakeUI{}
+		fakeDeploymentValidator.SetValidateBehavior([]fakebideplmanifest.ValidateOutput{
+			{Err: bosherr.NewMultiError(errors.New("jobs[0].instances must be greater than 0"))},
+		})
+
+		manifestParser = bicmd.DeploymentManifestParser{
+			DeploymentParser:     fakebideplmanifest.NewFakeParser(),
+			DeploymentValidator:  fakeDeploymentValidator,
+			FS:                   fakeFs,
+			ManifestInterpolator: biinterpolation.NewInterpolator(biinterpolation.Variables{}, biinterpolation.Ops{}),
+			UI:                   fakeUI,
+		}
+	})
+
+	Describe("GetDeploymentManifest", func() {
+		It("adds the position in the manifest of the nodes with validation errors", func() {
+			_, err := manifestParser.GetDeploymentManifest(deploymentManifestPath, birelsetmanifest.Manifest{}, fakebiui.NewFakeStage())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`Validating deployment manifest: /path/to/manifest.yml:5:3: jobs[0].instances must be greater than 0
+  5 |   instances: 0`))
+		})
+
+		It("prints the warnings of the release job validation after the validation stage", func() {
+			fakeDeploymentValidator.SetValidateBehavior([]fakebideplmanifest.ValidateOutput{{Err: nil}})
+			fakeDeploymentValidator.SetValidateReleaseJobsBehavior([]fakebideplmanifest.ValidateReleaseJobsOutput{
+				{Warnings: []string{"jobs[0].templates[0] job 'fake-job' property 'fake-key' is not set and has no default"}},
+			})
+			fakeStage := fakebiui.NewFakeStage()
+
+			_, err := manifestParser.GetDeploymentManifest(deploymentManifestPath, birelsetmanifest.Manifest{}, fakeStage)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeStage.PerformCalls).To(HaveLen(1))
+			Expect(fakeStage.PerformCalls[0].Name).To(Equal("Validating deployment manifest"))
+			Expect(fakeUI.Said).To(Equal([]string{
+				"Warning: jobs[0].templates[0] job 'fake-job' property 'fake-key' is not set and has no default",
+			}))
+		})
+
+		Context("when ops files are applied", func() {
+			BeforeEach(func() {
+				fakeFs.WriteFileString(deploymentManifestPath, "---\nname: fake-deployment-name\njobs:\n- name: fake-job\n  instances: 0\n- name: fake-other-job\n  instances: 1\n")
+				manifestParser.ManifestInterpolator = biinterpolation.NewInterpolator(biinterpolation.Variables{}, biinterpolation.Ops{
+					{Type: "remove", Path: "/jobs/0"},
+					{Type: "replace", Path: "/jobs/-", Value: map[interface{}]interface{}{"name": "fake-added-job"}},
+				})
+			})
+
+			It("adds the position in the manifest file of the nodes of the ops-applied manifest with validation errors", func() {
+				fakeDeploymentValidator.SetValidateBehavior([]fakebideplmanifest.ValidateOutput{
+					{Err: bosherr.NewMultiError(errors.New("jobs[0].instances must be greater than 0"))},
+				})
+
+				_, err := manifestParser.GetDeploymentManifest(deploymentManifestPath, birelsetmanifest.Manifest{}, fakebiui.NewFakeStage())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(`Validating deployment manifest: /path/to/manifest.yml:7:3: jobs[0].instances must be greater than 0
+  7 |   instances: 1`))
+			})
+
+			It("says when the nodes with validation errors are set by the ops files", func() {
+				fakeDeploymentValidator.SetValidateBehavior([]fakebideplmanifest.ValidateOutput{
+					{Err: bosherr.NewMultiError(errors.New("jobs[1].instances must be greater than 0"))},
+				})
+
+				_, err := manifestParser.GetDeploymentManifest(deploymentManifestPath, birelsetmanifest.Manifest{}, fakebiui.NewFakeStage())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal(`Validating deployment manifest: /path/to/manifest.yml:3:1: jobs[1].instances must be greater than 0 (jobs[1] is not in the file as is, it is set by ops files or variables)
+  3 | jobs:`))
+			})
+		})
+	})
+})
@@ -3,18 +3,18 @@ package cmd
 import (
 	"strings"
 
-	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
 	bidepl "github.com/cloudfoundry/bosh-init/deployment"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
 	biinstall "github.com/cloudfoundry/bosh-init/installation"
 	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
-	bihttpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	birel "github.com/cloudfoundry/bosh-init/release"
 	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
 	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
@@ -31,11 +31,11 @@ func NewDeploymentPreparer(
 	deploymentRecord bidepl.Record,
 	cloudFactory bicloud.Factory,
 	stemcellManagerFactory bistemcell.ManagerFactory,
-	agentClientFactory bihttpagent.AgentClientFactory,
-	vmManagerFactory bivm.ManagerFactory,
-	blobstoreFactory biblobstore.Factory,
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory,
 	deployer bidepl.Deployer,
 	deploymentManifestPath string,
+	manifestInterpolator biinterpolation.Interpolator,
+	fs boshsys.FileSystem,
 	cpiInstaller bicpirel.CpiInstaller,
 	releaseFetcher birel.Fetcher,
 	stemcellFetcher bistemcell.Fetcher,
@@ -43,6 +43,8 @@ func NewDeploymentPreparer(
 	deploymentManifestParser DeploymentManifestParser,
 	tempRootConfigurator TempRootConfigurator,
 	targetProvider biinstall.TargetProvider,
+	deploymentPlanner bidepl.Planner,
+	ipAllocator bidepl.IPAllocator,
 ) DeploymentPreparer {
 	return DeploymentPreparer{
 		ui:                                      ui,
@@ -54,11 +56,11 @@ func NewDeploymentPreparer(
 		deploymentRecord:                        deploymentRecord,
 		cloudFactory:                            cloudFactory,
 		stemcellManagerFactory:                  stemcellManagerFactory,
-		agentClientFactory:                      agentClientFactory,
-		vmManagerFactory:                        vmManagerFactory,
-		blobstoreFactory:                        blobstoreFactory,
+		instanceManagerProviderFactory:          instanceManagerProviderFactory,
 		deployer:                                deployer,
 		deploymentManifestPath:                  deploymentManifestPath,
+		manifestInterpolator:                    manifestInterpolator,
+		fs:                                      fs,
 		cpiInstaller:                            cpiInstaller,
 		releaseFetcher:                          releaseFetcher,
 		stemcellFetcher:                         stemcellFetcher,
@@ -66,6 +68,8 @@ func NewDeploymentPreparer(
 		deploymentManifestParser:                deploymentManifestParser,
 		tempRootConfigurator:                    tempRootConfigurator,
 		targetProvider:                          targetProvider,
+		deploymentPlanner:                       deploymentPlanner,
+		ipAllocator:                             ipAllocator,
 	}
 }
 
@@ -79,11 +83,11 @@ type DeploymentPreparer struct {
 	deploymentRecord                        bidepl.Record
 	cloudFactory                            bicloud.Factory
 	stemcellManagerFactory                  bistemcell.ManagerFactory
-	agentClientFactory                      bihttpagent.AgentClientFactory
-	vmManagerFactory                        bivm.ManagerFactory
-	blobstoreFactory                        biblobstore.Factory
+	instanceManagerProviderFactory          biinstance.ManagerProviderFactory
 	deployer                                bidepl.Deployer
 	deploymentManifestPath                  string
+	manifestInterpolator                    biinterpolation.Interpolator
+	fs                                      boshsys.FileSystem
 	cpiInstaller                            bicpirel.CpiInstaller
 	releaseFetcher                          birel.Fetcher
 	stemcellFetcher                         bistemcell.Fetcher
@@ -91,24 +95,105 @@ type DeploymentPreparer struct {
 	deploymentManifestParser                DeploymentManifestParser
 	tempRootConfigurator                    TempRootConfigurator
 	targetProvider                          biinstall.TargetProvider
+	deploymentPlanner                       bidepl.Planner
+	ipAllocator                             bidepl.IPAllocator
 }
 
 func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage) (err error) {
+	return c.prepareDeployment(stage, false)
+}
+
+// RecreateDeployment deletes and recreates the deployed VM, keeping its persistent disk.
+// It refuses to run if the manifest, stemcell or releases changed since the last deploy.
+func (c *DeploymentPreparer) RecreateDeployment(stage biui.Stage) (err error) {
+	return c.prepareDeployment(stage, true)
+}
+
+func (c *DeploymentPreparer) prepareDeployment(stage biui.Stage, recreate bool) (err error) {
 	c.ui.PrintLinef("Deployment state: '%s'", c.deploymentStateService.Path())
 
-	if !c.deploymentStateService.Exists() {
-		migrated, err := c.legacyDeploymentStateMigrator.MigrateIfExists(biconfig.LegacyDeploymentStatePath(c.deploymentManifestPath))
+	err = c.migrateLegacyDeploymentState()
+	if err != nil {
+		return err
+	}
+
+	deploymentState, err := c.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading deployment state")
+	}
+
+	target, err := c.targetProvider.NewTarget()
+	if err != nil {
+		return bosherr.WrapError(err, "Determining installation target")
+	}
+
+	err = c.tempRootConfigurator.PrepareAndSetTempRoot(target.TmpPath(), c.logger)
+	if err != nil {
+		return bosherr.WrapError(err, "Setting temp root")
+	}
+
+	defer func() {
+		err := c.releaseManager.DeleteAll()
 		if err != nil {
-			return bosherr.WrapError(err, "Migrating legacy deployment state file")
+			c.logger.Warn(c.logTag, "Deleting all extracted releases: %s", err.Error())
 		}
-		if migrated {
-			c.ui.PrintLinef("Migrated legacy deployments file: '%s'", biconfig.LegacyDeploymentStatePath(c.deploymentManifestPath))
+	}()
+
+	installationManifest, deploymentManifest, extractedStemcell, err := c.validate(stage)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		deleteErr := extractedStemcell.Delete()
+		if deleteErr != nil {
+			c.logger.Warn(c.logTag, "Failed to delete extracted stemcell: %s", deleteErr.Error())
 		}
+	}()
+
+	manifestContents, err := c.interpolatedManifest()
+	if err != nil {
+		return err
 	}
 
-	deploymentState, err := c.deploymentStateService.Load()
+	isDeployed, err := c.deploymentRecord.IsDeployed(manifestContents, c.releaseManager.List(), extractedStemcell)
 	if err != nil {
-		return bosherr.WrapError(err, "Loading deployment state")
+		return bosherr.WrapError(err, "Checking if deployment has changed")
+	}
+
+	if recreate {
+		if !isDeployed {
+			c.ui.ErrorLinef("Deployment manifest, stemcell or releases have changed since the last deploy")
+			return bosherr.Error("Deployment has changed, run 'bosh-init deploy' instead of recreating")
+		}
+	} else if isDeployed {
+		c.ui.PrintLinef("No deployment, stemcell or release changes. Skipping deploy.")
+		return nil
+	}
+
+	err = c.cpiInstaller.WithInstalledCpiRelease(installationManifest, target, stage, func(installation biinstall.Installation) error {
+		return installation.WithRunningRegistry(c.logger, stage, func() error {
+			return c.deploy(
+				installation,
+				deploymentState,
+				extractedStemcell,
+				installationManifest,
+				deploymentManifest,
+				manifestContents,
+				stage)
+		})
+	})
+
+	return err
+
+}
+
+// PlanDeployment prints the changes that PrepareDeployment would make, without installing the CPI
+func (c *DeploymentPreparer) PlanDeployment(stage biui.Stage) (err error) {
+	c.ui.PrintLinef("Deployment state: '%s'", c.deploymentStateService.Path())
+
+	err = c.migrateLegacyDeploymentState()
+	if err != nil {
+		return err
 	}
 
 	target, err := c.targetProvider.NewTarget()
@@ -128,11 +213,114 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage) (err error) {
 		}
 	}()
 
-	var (
-		extractedStemcell    bistemcell.ExtractedStemcell
-		deploymentManifest   bideplmanifest.Manifest
-		installationManifest biinstallmanifest.Manifest
-	)
+	_, deploymentManifest, extractedStemcell, err := c.validate(stage)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		deleteErr := extractedStemcell.Delete()
+		if deleteErr != nil {
+			c.logger.Warn(c.logTag, "Failed to delete extracted stemcell: %s", deleteErr.Error())
+		}
+	}()
+
+	manifestContents, err := c.interpolatedManifest()
+	if err != nil {
+		return err
+	}
+
+	plan, err := c.deploymentPlanner.Plan(manifestContents, deploymentManifest, c.releaseManager.List(), extractedStemcell)
+	if err != nil {
+		return bosherr.WrapError(err, "Planning deployment")
+	}
+
+	if !plan.HasChanges() {
+		c.ui.PrintLinef("No deployment, stemcell or release changes. Deploy would be skipped.")
+		return nil
+	}
+
+	c.ui.PrintLinef("Deploy would make the following changes:")
+	for _, change := range plan.Changes {
+		c.ui.PrintLinef("  - %s", change.Description)
+		for _, reason := range change.Reasons {
+			c.ui.PrintLinef("      %s", reason)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDeployment runs the validating stage of PrepareDeployment, without installing the CPI or touching the deployment state
+func (c *DeploymentPreparer) ValidateDeployment(stage biui.Stage) (err error) {
+	// without a deployment state there is no installation yet, and creating one would touch the deployment state
+	if c.deploymentStateService.Exists() {
+		target, err := c.targetProvider.NewTarget()
+		if err != nil {
+			return bosherr.WrapError(err, "Determining installation target")
+		}
+
+		err = c.tempRootConfigurator.PrepareAndSetTempRoot(target.TmpPath(), c.logger)
+		if err != nil {
+			return bosherr.WrapError(err, "Setting temp root")
+		}
+	}
+
+	defer func() {
+		err := c.releaseManager.DeleteAll()
+		if err != nil {
+			c.logger.Warn(c.logTag, "Deleting all extracted releases: %s", err.Error())
+		}
+	}()
+
+	_, _, extractedStemcell, err := c.validate(stage)
+	if err != nil {
+		return err
+	}
+
+	deleteErr := extractedStemcell.Delete()
+	if deleteErr != nil {
+		c.logger.Warn(c.logTag, "Failed to delete extracted stemcell: %s", deleteErr.Error())
+	}
+
+	c.ui.PrintLinef("Deployment manifest, releases and stemcell are valid")
+	return nil
+}
+
+// interpolatedManifest returns the deployment manifest as deployed, after ops files and variables have been applied,
+// so that changing only a variable is detected as a change to the deployment.
+func (c *DeploymentPreparer) interpolatedManifest() ([]byte, error) {
+	contents, err := c.fs.ReadFile(c.deploymentManifestPath)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Reading deployment manifest '%s'", c.deploymentManifestPath)
+	}
+
+	return c.manifestInterpolator.Interpolate(contents)
+}
+
+func (c *DeploymentPreparer) migrateLegacyDeploymentState() error {
+	if c.deploymentStateService.Exists() {
+		return nil
+	}
+
+	migrated, err := c.legacyDeploymentStateMigrator.MigrateIfExists(biconfig.LegacyDeploymentStatePath(c.deploymentManifestPath))
+	if err != nil {
+		return bosherr.WrapError(err, "Migrating legacy deployment state file")
+	}
+	if migrated {
+		c.ui.PrintLinef("Migrated legacy deployments file: '%s'", biconfig.LegacyDeploymentStatePath(c.deploymentManifestPath))
+	}
+
+	return nil
+}
+
+// validate parses the manifests, fetches the releases and the stemcell and checks them against each other.
+// Validation continues past failures where possible, so that all errors are reported at once.
+func (c *DeploymentPreparer) validate(stage biui.Stage) (
+	installationManifest biinstallmanifest.Manifest,
+	deploymentManifest bideplmanifest.Manifest,
+	extractedStemcell bistemcell.ExtractedStemcell,
+	err error,
+) {
 	err = stage.PerformComplex("validating", func(stage biui.Stage) error {
 		var releaseSetManifest birelsetmanifest.Manifest
 		releaseSetManifest, installationManifest, err = c.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(c.deploymentManifestPath)
@@ -140,24 +328,31 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage) (err error) {
 			return err
 		}
 
+		validationErrors := []error{}
+
 		for _, releaseRef := range releaseSetManifest.Releases {
 			err = c.releaseFetcher.DownloadAndExtract(releaseRef, stage)
 			if err != nil {
-				return err
+				validationErrors = append(validationErrors, err)
 			}
 		}
 
-		err := c.cpiInstaller.ValidateCpiRelease(installationManifest, stage)
+		err = c.cpiInstaller.ValidateCpiRelease(installationManifest, stage)
 		if err != nil {
-			return err
+			validationErrors = append(validationErrors, err)
 		}
 
 		deploymentManifest, err = c.deploymentManifestParser.GetDeploymentManifest(c.deploymentManifestPath, releaseSetManifest, stage)
 		if err != nil {
-			return err
+			validationErrors = append(validationErrors, err)
+			return c.validationError(validationErrors)
 		}
 
 		extractedStemcell, err = c.stemcellFetcher.GetStemcell(deploymentManifest, stage)
+		if err != nil {
+			validationErrors = append(validationErrors, err)
+			return c.validationError(validationErrors)
+		}
 
 		nonCpiReleasesMap, _ := deploymentManifest.GetListOfTemplateReleases()
 		delete(nonCpiReleasesMap, installationManifest.Template.Release) // remove CPI release from nonCpiReleasesMap
@@ -167,53 +362,40 @@ func (c *DeploymentPreparer) PrepareDeployment(stage biui.Stage) (err error) {
 				if release.IsCompiled() {
 					compilationOsAndVersion := release.Packages()[0].Stemcell
 					if strings.ToLower(compilationOsAndVersion) != strings.ToLower(extractedStemcell.OsAndVersion()) {
-						return bosherr.Errorf("OS/Version mismatch between deployment stemcell and compiled package stemcell for release '%s'", release.Name())
+						validationErrors = append(validationErrors, bosherr.Errorf("OS/Version mismatch between deployment stemcell and compiled package stemcell for release '%s'", release.Name()))
 					}
 				}
 			} else {
 				// It is a CPI release, check if it is compiled
 				if release.IsCompiled() {
-					return bosherr.Errorf("CPI is not allowed to be a compiled release. The provided CPI release '%s' is compiled", release.Name())
+					validationErrors = append(validationErrors, bosherr.Errorf("CPI is not allowed to be a compiled release. The provided CPI release '%s' is compiled", release.Name()))
 				}
 			}
 		}
 
-		return err
+		return c.validationError(validationErrors)
 	})
-	if err != nil {
-		return err
-	}
-	defer func() {
+
+	if err != nil && extractedStemcell != nil {
 		deleteErr := extractedStemcell.Delete()
 		if deleteErr != nil {
 			c.logger.Warn(c.logTag, "Failed to delete extracted stemcell: %s", deleteErr.Error())
 		}
-	}()
-
-	isDeployed, err := c.deploymentRecord.IsDeployed(c.deploymentManifestPath, c.releaseManager.List(), extractedStemcell)
-	if err != nil {
-		return bosherr.WrapError(err, "Checking if deployment has changed")
+		extractedStemcell = nil
 	}
 
-	if isDeployed {
-		c.ui.PrintLinef("No deployment, stemcell or release changes. Skipping deploy.")
+	return installationManifest, deploymentManifest, extractedStemcell, err
+}
+
+func (c *DeploymentPreparer) validationError(validationErrors []error) error {
+	switch len(validationErrors) {
+	case 0:
 		return nil
+	case 1:
+		return validationErrors[0]
+	default:
+		return bosherr.NewMultiError(validationErrors...)
 	}
-
-	err = c.cpiInstaller.WithInstalledCpiRelease(installationManifest, target, stage, func(installation biinstall.Installation) error {
-		return installation.WithRunningRegistry(c.logger, stage, func() error {
-			return c.deploy(
-				installation,
-				deploymentState,
-				extractedStemcell,
-				installationManifest,
-				deploymentManifest,
-				stage)
-		})
-	})
-
-	return err
-
 }
 
 func (c *DeploymentPreparer) deploy(
@@ -222,6 +404,7 @@ func (c *DeploymentPreparer) deploy(
 	extractedStemcell bistemcell.ExtractedStemcell,
 	installationManifest biinstallmanifest.Manifest,
 	deploymentManifest bideplmanifest.Manifest,
+	manifestContents []byte,
 	stage biui.Stage,
 ) (err error) {
 	cloud, err := c.cloudFactory.NewCloud(installation, deploymentState.DirectorID)
@@ -236,14 +419,13 @@ func (c *DeploymentPreparer) deploy(
 		return err
 	}
 
-	agentClient := c.agentClientFactory.NewAgentClient(deploymentState.DirectorID, installationManifest.Mbus)
-	vmManager := c.vmManagerFactory.NewManager(cloud, agentClient)
-
-	blobstore, err := c.blobstoreFactory.Create(installationManifest.Mbus)
+	deploymentManifest, err = c.ipAllocator.Allocate(deploymentManifest)
 	if err != nil {
-		return bosherr.WrapError(err, "Creating blobstore client")
+		return bosherr.WrapError(err, "Allocating IPs")
 	}
 
+	instanceManagerProvider := c.instanceManagerProviderFactory.NewManagerProvider(cloud, deploymentState.DirectorID, installationManifest.Mbus)
+
 	err = stage.PerformComplex("deploying", func(deployStage biui.Stage) error {
 		err = c.deploymentRecord.Clear()
 		if err != nil {
@@ -255,15 +437,14 @@ func (c *DeploymentPreparer) deploy(
 			deploymentManifest,
 			cloudStemcell,
 			installationManifest.Registry,
-			vmManager,
-			blobstore,
+			instanceManagerProvider,
 			deployStage,
 		)
 		if err != nil {
 			return bosherr.WrapError(err, "Deploying")
 		}
 
-		err = c.deploymentRecord.Update(c.deploymentManifestPath, c.releaseManager.List())
+		err = c.deploymentRecord.Update(manifestContents, c.releaseManager.List())
 		if err != nil {
 			return bosherr.WrapError(err, "Updating deployment record")
 		}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
+	bidepl "github.com/cloudfoundry/bosh-init/deployment"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	biinstall "github.com/cloudfoundry/bosh-init/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type ErrandRunner interface {
+	// RunErrand creates the VM of the errand job, runs the errand on it and prints its output.
+	// The VM is deleted afterwards, unless keepAlive is set.
+	RunErrand(stage biui.Stage, errandName string, keepAlive bool) error
+}
+
+func NewErrandRunner(
+	ui biui.UI,
+	logTag string,
+	logger boshlog.Logger,
+	deploymentStateService biconfig.DeploymentStateService,
+	releaseManager birel.Manager,
+	cloudFactory bicloud.Factory,
+	stemcellManagerFactory bistemcell.ManagerFactory,
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory,
+	deploymentManifestPath string,
+	cpiInstaller bicpirel.CpiInstaller,
+	releaseFetcher birel.Fetcher,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+	deploymentManifestParser DeploymentManifestParser,
+	tempRootConfigurator TempRootConfigurator,
+	targetProvider biinstall.TargetProvider,
+	ipAllocator bidepl.IPAllocator,
+) ErrandRunner {
+	return &errandRunner{
+		ui:                                      ui,
+		logTag:                                  logTag,
+		logger:                                  logger,
+		releaseManager:                          releaseManager,
+		cloudFactory:                            cloudFactory,
+		stemcellManagerFactory:                  stemcellManagerFactory,
+		instanceManagerProviderFactory:          instanceManagerProviderFactory,
+		deploymentManifestPath:                  deploymentManifestPath,
+		releaseFetcher:                          releaseFetcher,
+		releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+		deploymentManifestParser:                deploymentManifestParser,
+		ipAllocator:                             ipAllocator,
+		installedCpiRunner: installedCpiRunner{
+			ui:                                      ui,
+			logTag:                                  logTag,
+			logger:                                  logger,
+			deploymentStateService:                  deploymentStateService,
+			releaseManager:                          releaseManager,
+			deploymentManifestPath:                  deploymentManifestPath,
+			cpiInstaller:                            cpiInstaller,
+			releaseFetcher:                          releaseFetcher,
+			releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+			tempRootConfigurator:                    tempRootConfigurator,
+			targetProvider:                          targetProvider,
+		},
+	}
+}
+
+type errandRunner struct {
+	ui                                      biui.UI
+	logTag                                  string
+	logger                                  boshlog.Logger
+	releaseManager                          birel.Manager
+	cloudFactory                            bicloud.Factory
+	stemcellManagerFactory                  bistemcell.ManagerFactory
+	instanceManagerProviderFactory          biinstance.ManagerProviderFactory
+	deploymentManifestPath                  string
+	releaseFetcher                          birel.Fetcher
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
+	deploymentManifestParser                DeploymentManifestParser
+	ipAllocator                             bidepl.IPAllocator
+	installedCpiRunner                      installedCpiRunner
+}
+
+func (r *errandRunner) RunErrand(stage biui.Stage, errandName string, keepAlive bool) error {
+	return r.installedCpiRunner.Run(stage, func(localCpiInstallation biinstall.Installation, installationManifest biinstallmanifest.Manifest, deploymentState biconfig.DeploymentState) error {
+		deploymentManifest, err := r.validateErrand(stage, errandName)
+		if err != nil {
+			return err
+		}
+
+		r.logger.Debug(r.logTag, "Creating cloud client...")
+		cloud, err := r.cloudFactory.NewCloud(localCpiInstallation, deploymentState.DirectorID)
+		if err != nil {
+			return bosherr.WrapError(err, "Creating CPI client from CPI installation")
+		}
+
+		cloudStemcells, err := r.stemcellManagerFactory.NewManager(cloud).FindCurrent()
+		if err != nil {
+			return bosherr.WrapError(err, "Finding deployed stemcell")
+		}
+		if len(cloudStemcells) == 0 {
+			r.ui.ErrorLinef("No deployed stemcell found, run 'bosh-init deploy' first")
+			return bosherr.Error("No deployed stemcell found")
+		}
+
+		deploymentManifest, err = r.ipAllocator.Allocate(deploymentManifest)
+		if err != nil {
+			return bosherr.WrapError(err, "Allocating IPs")
+		}
+
+		instanceManagerProvider := r.instanceManagerProviderFactory.NewManagerProvider(cloud, deploymentState.DirectorID, installationManifest.Mbus)
+		instanceManager, err := instanceManagerProvider.Get(deploymentManifest, errandName, 0)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Finding instance of errand '%s'", errandName)
+		}
+
+		var result biagentclient.ErrandResult
+		err = stage.PerformComplex(fmt.Sprintf("running errand '%s'", errandName), func(errandStage biui.Stage) error {
+			result, err = r.runErrand(instanceManager, errandName, deploymentManifest, cloudStemcells[0], installationManifest.Registry, keepAlive, errandStage)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		r.printResult(errandName, result)
+
+		if result.ExitStatus != 0 {
+			return bosherr.Errorf("Errand '%s' completed with exit code %d", errandName, result.ExitStatus)
+		}
+
+		return nil
+	})
+}
+
+// validateErrand fetches the releases not fetched while installing the CPI,
+// parses the deployment manifest and checks that errandName is an errand job.
+func (r *errandRunner) validateErrand(stage biui.Stage, errandName string) (bideplmanifest.Manifest, error) {
+	var deploymentManifest bideplmanifest.Manifest
+	err := stage.PerformComplex("validating errand", func(stage biui.Stage) error {
+		releaseSetManifest, _, err := r.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(r.deploymentManifestPath)
+		if err != nil {
+			return err
+		}
+
+		for _, releaseRef := range releaseSetManifest.Releases {
+			if _, found := r.releaseManager.Find(releaseRef.Name); found {
+				continue
+			}
+
+			err = r.releaseFetcher.DownloadAndExtract(releaseRef, stage)
+			if err != nil {
+				return err
+			}
+		}
+
+		deploymentManifest, err = r.deploymentManifestParser.GetDeploymentManifest(r.deploymentManifestPath, releaseSetManifest, stage)
+		if err != nil {
+			return err
+		}
+
+		job, found := deploymentManifest.FindJobByName(errandName)
+		if !found {
+			return bosherr.Errorf("Errand '%s' not found in deployment manifest", errandName)
+		}
+
+		if job.Lifecycle != bideplmanifest.JobLifecycleErrand {
+			return bosherr.Errorf("Job '%s' is not an errand (lifecycle must be 'errand')", errandName)
+		}
+
+		return nil
+	})
+
+	return deploymentManifest, err
+}
+
+func (r *errandRunner) runErrand(
+	instanceManager biinstance.Manager,
+	errandName string,
+	deploymentManifest bideplmanifest.Manifest,
+	cloudStemcell bistemcell.CloudStemcell,
+	registryConfig biinstallmanifest.Registry,
+	keepAlive bool,
+	errandStage biui.Stage,
+) (biagentclient.ErrandResult, error) {
+	pingTimeout := 10 * time.Second
+	pingDelay := 500 * time.Millisecond
+
+	// delete the VM left behind by a previous run with keepAlive
+	err := instanceManager.DeleteAll(pingTimeout, pingDelay, errandStage)
+	if err != nil {
+		return biagentclient.ErrandResult{}, err
+	}
+
+	instance, _, err := instanceManager.Create(errandName, 0, deploymentManifest, cloudStemcell, registryConfig, errandStage)
+	if err != nil {
+		return biagentclient.ErrandResult{}, bosherr.WrapErrorf(err, "Creating instance '%s/0'", errandName)
+	}
+
+	err = instance.UpdateJobs(deploymentManifest, errandStage)
+	if err != nil {
+		return biagentclient.ErrandResult{}, err
+	}
+
+	result, err := instance.RunErrand(errandStage)
+
+	if !keepAlive {
+		deleteErr := instance.Delete(pingTimeout, pingDelay, errandStage)
+		if deleteErr != nil {
+			if err != nil {
+				r.logger.Warn(r.logTag, "Deleting errand instance '%s/0': %s", errandName, deleteErr.Error())
+			} else {
+				err = deleteErr
+			}
+		}
+	}
+
+	return result, err
+}
+
+func (r *errandRunner) printResult(errandName string, result biagentclient.ErrandResult) {
+	r.ui.PrintLinef("Errand '%s' completed with exit code %d", errandName, result.ExitStatus)
+
+	if result.Stdout != "" {
+		r.ui.PrintLinef("[stdout]")
+		r.ui.PrintLinef("%s", result.Stdout)
+	}
+
+	if result.Stderr != "" {
+		r.ui.PrintLinef("[stderr]")
+		r.ui.PrintLinef("%s", result.Stderr)
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"path/filepath"
 	"time"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
 	bicrypto "github.com/cloudfoundry/bosh-init/crypto"
 	bidepl "github.com/cloudfoundry/bosh-init/deployment"
+	bicloudcheck "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	biinstancestate "github.com/cloudfoundry/bosh-init/deployment/instance/state"
@@ -21,7 +24,6 @@ import (
 	biinstall "github.com/cloudfoundry/bosh-init/installation"
 	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
 	bitarball "github.com/cloudfoundry/bosh-init/installation/tarball"
-	bihttpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshcmd "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/fileutil"
 	bihttpclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/httpclient"
@@ -29,6 +31,7 @@ import (
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 	biregistry "github.com/cloudfoundry/bosh-init/registry"
 	birel "github.com/cloudfoundry/bosh-init/release"
 	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
@@ -51,9 +54,11 @@ type factory struct {
 	logger                 boshlog.Logger
 	uuidGenerator          boshuuid.Generator
 	workspaceRootPath      string
+	redactor               bilog.Redactor
+	cpiLogPath             string
 	runner                 boshsys.CmdRunner
 	compressor             boshcmd.Compressor
-	agentClientFactory     bihttpagent.AgentClientFactory
+	agentClientFactory     biagentclient.AgentClientFactory
 	registryServerManager  biregistry.ServerManager
 	sshTunnelFactory       bisshtunnel.Factory
 	instanceFactory        biinstance.Factory
@@ -63,10 +68,7 @@ type factory struct {
 	eventLogger            biui.Stage
 	releaseExtractor       birel.Extractor
 	releaseManager         birel.Manager
-	releaseSetParser       birelsetmanifest.Parser
 	releaseJobResolver     bideplrel.JobResolver
-	installationParser     biinstallmanifest.Parser
-	deploymentParser       bideplmanifest.Parser
 	releaseSetValidator    birelsetmanifest.Validator
 	installationValidator  biinstallmanifest.Validator
 	deploymentValidator    bideplmanifest.Validator
@@ -75,6 +77,7 @@ type factory struct {
 	compiledPackageRepo    bistatepkg.CompiledPackageRepo
 	tarballProvider        bitarball.Provider
 	cpiReleaseValidator    *bicpirel.Validator
+	jobListRenderer        bitemplate.JobListRenderer
 }
 
 func NewFactory(
@@ -84,6 +87,8 @@ func NewFactory(
 	logger boshlog.Logger,
 	uuidGenerator boshuuid.Generator,
 	workspaceRootPath string,
+	redactor bilog.Redactor,
+	cpiLogPath string,
 ) Factory {
 	f := &factory{
 		fs:                fs,
@@ -92,12 +97,25 @@ func NewFactory(
 		logger:            logger,
 		uuidGenerator:     uuidGenerator,
 		workspaceRootPath: workspaceRootPath,
+		redactor:          redactor,
+		cpiLogPath:        cpiLogPath,
 	}
 	f.commands = CommandList{
-		"deploy":  f.createDeployCmd,
-		"delete":  f.createDeleteCmd,
-		"help":    f.createHelpCmd,
-		"version": f.createVersionCmd,
+		"deploy":      f.createDeployCmd,
+		"delete":      f.createDeleteCmd,
+		"plan":        f.createPlanCmd,
+		"recreate":    f.createRecreateCmd,
+		"state":       f.createStateCmd,
+		"cloud-check": f.createCloudCheckCmd,
+		"stop":        f.createStopCmd,
+		"start":       f.createStartCmd,
+		"logs":        f.createLogsCmd,
+		"ssh":         f.createSSHCmd,
+		"validate":    f.createValidateCmd,
+		"render":      f.createRenderCmd,
+		"run-errand":  f.createRunErrandCmd,
+		"help":        f.createHelpCmd,
+		"version":     f.createVersionCmd,
 	}
 	return f
 }
@@ -117,8 +135,8 @@ func (f *factory) CreateCommand(name string) (Cmd, error) {
 }
 
 func (f *factory) createDeployCmd() (Cmd, error) {
-	getter := func(deploymentManifestPath string) (DeploymentPreparer, error) {
-		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath}
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPreparer, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
 		deploymentPreparer, err := f.loadDeploymentPreparer()
 		if err != nil {
 			return deploymentPreparer, err
@@ -129,9 +147,43 @@ func (f *factory) createDeployCmd() (Cmd, error) {
 	return NewDeployCmd(f.ui, f.fs, f.logger, getter), nil
 }
 
-func (f *factory) createDeleteCmd() (Cmd, error) {
-	getter := func(deploymentManifestPath string) (DeploymentDeleter, error) {
+func (f *factory) createPlanCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPlanner, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		deploymentPreparer, err := f.loadDeploymentPreparer()
+		if err != nil {
+			return nil, err
+		}
+
+		return &deploymentPreparer, nil
+	}
+	return NewPlanCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createRecreateCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentRecreator, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		deploymentPreparer, err := f.loadDeploymentPreparer()
+		if err != nil {
+			return nil, err
+		}
+
+		return &deploymentPreparer, nil
+	}
+	return NewRecreateCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createStateCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string) biconfig.DeploymentStateService {
 		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath}
+		return f.loadDeploymentStateService()
+	}
+	return NewStateCmd(f.ui, getter, biconfig.NewDeploymentStateValidator(), f.logger), nil
+}
+
+func (f *factory) createDeleteCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentDeleter, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
 		deploymentDeleter, err := f.loadDeploymentDeleter()
 		if err != nil {
 			return deploymentDeleter, err
@@ -142,6 +194,83 @@ func (f *factory) createDeleteCmd() (Cmd, error) {
 	return NewDeleteCmd(f.ui, f.fs, f.logger, getter), nil
 }
 
+func (f *factory) createCloudCheckCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (CloudChecker, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadCloudChecker()
+	}
+
+	return NewCloudCheckCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createStopCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadJobController()
+	}
+
+	return NewStopCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createStartCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadJobController()
+	}
+
+	return NewStartCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createLogsCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (LogsFetcher, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadLogsFetcher(), nil
+	}
+
+	return NewLogsCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createSSHCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (SSHRunner, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadSSHRunner(), nil
+	}
+
+	return NewSSHCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createValidateCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentValidator, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		deploymentPreparer, err := f.loadDeploymentPreparer()
+		if err != nil {
+			return nil, err
+		}
+
+		return &deploymentPreparer, nil
+	}
+
+	return NewValidateCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createRenderCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobTemplatesRenderer, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadJobTemplatesRenderer(), nil
+	}
+
+	return NewRenderCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
+func (f *factory) createRunErrandCmd() (Cmd, error) {
+	getter := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (ErrandRunner, error) {
+		f := &deploymentManagerFactory2{f: f, deploymentManifestPath: deploymentManifestPath, manifestInterpolator: manifestInterpolator}
+		return f.loadErrandRunner()
+	}
+
+	return NewRunErrandCmd(f.ui, f.fs, f.logger, getter), nil
+}
+
 func (f *factory) createHelpCmd() (Cmd, error) {
 	return NewHelpCmd(f.ui, f.commands), nil
 }
@@ -181,7 +310,7 @@ func (f *factory) loadRegistryServerManager() biregistry.ServerManager {
 		return f.registryServerManager
 	}
 
-	f.registryServerManager = biregistry.NewServerManager(f.logger)
+	f.registryServerManager = biregistry.NewServerManager(f.redactor, f.logger)
 	return f.registryServerManager
 }
 
@@ -227,14 +356,21 @@ func (f *factory) loadReleaseJobResolver() bideplrel.JobResolver {
 	return f.releaseJobResolver
 }
 
-func (f *factory) loadBuilderFactory() biinstancestate.BuilderFactory {
-	if f.stateBuilderFactory != nil {
-		return f.stateBuilderFactory
+func (f *factory) loadJobListRenderer() bitemplate.JobListRenderer {
+	if f.jobListRenderer != nil {
+		return f.jobListRenderer
 	}
 
 	erbRenderer := bitemplateerb.NewERBRenderer(f.fs, f.loadCMDRunner(), f.logger)
 	jobRenderer := bitemplate.NewJobRenderer(erbRenderer, f.fs, f.logger)
-	jobListRenderer := bitemplate.NewJobListRenderer(jobRenderer, f.logger)
+	f.jobListRenderer = bitemplate.NewJobListRenderer(jobRenderer, f.logger)
+	return f.jobListRenderer
+}
+
+func (f *factory) loadBuilderFactory() biinstancestate.BuilderFactory {
+	if f.stateBuilderFactory != nil {
+		return f.stateBuilderFactory
+	}
 
 	sha1Calculator := bicrypto.NewSha1Calculator(f.fs)
 
@@ -248,7 +384,8 @@ func (f *factory) loadBuilderFactory() biinstancestate.BuilderFactory {
 	f.stateBuilderFactory = biinstancestate.NewBuilderFactory(
 		f.loadCompiledPackageRepo(),
 		f.loadReleaseJobResolver(),
-		jobListRenderer,
+		f.loadReleaseManager(),
+		f.loadJobListRenderer(),
 		renderedJobListCompressor,
 		f.logger,
 	)
@@ -270,12 +407,12 @@ func (f *factory) loadDeploymentFactory() bidepl.Factory {
 	return f.deploymentFactory
 }
 
-func (f *factory) loadAgentClientFactory() bihttpagent.AgentClientFactory {
+func (f *factory) loadAgentClientFactory() biagentclient.AgentClientFactory {
 	if f.agentClientFactory != nil {
 		return f.agentClientFactory
 	}
 
-	f.agentClientFactory = bihttpagent.NewAgentClientFactory(1*time.Second, f.logger)
+	f.agentClientFactory = biagentclient.NewAgentClientFactory(1*time.Second, bilog.NewRedactingLogger(f.logger, f.redactor))
 	return f.agentClientFactory
 }
 
@@ -328,34 +465,6 @@ func (f *factory) loadReleaseManager() birel.Manager {
 	return f.releaseManager
 }
 
-func (f *factory) loadReleaseSetParser() birelsetmanifest.Parser {
-	if f.releaseSetParser != nil {
-		return f.releaseSetParser
-	}
-
-	f.releaseSetParser = birelsetmanifest.NewParser(f.fs, f.logger, f.loadReleaseSetValidator())
-	return f.releaseSetParser
-}
-
-func (f *factory) loadInstallationParser() biinstallmanifest.Parser {
-	if f.installationParser != nil {
-		return f.installationParser
-	}
-
-	uuidGenerator := boshuuid.NewGenerator()
-	f.installationParser = biinstallmanifest.NewParser(f.fs, uuidGenerator, f.logger, f.loadInstallationValidator())
-	return f.installationParser
-}
-
-func (f *factory) loadDeploymentParser() bideplmanifest.Parser {
-	if f.deploymentParser != nil {
-		return f.deploymentParser
-	}
-
-	f.deploymentParser = bideplmanifest.NewParser(f.fs, f.logger)
-	return f.deploymentParser
-}
-
 func (f *factory) loadInstallationValidator() biinstallmanifest.Validator {
 	if f.installationValidator != nil {
 		return f.installationValidator
@@ -388,25 +497,42 @@ func (f *factory) loadCloudFactory() bicloud.Factory {
 		return f.cloudFactory
 	}
 
-	f.cloudFactory = bicloud.NewFactory(f.fs, f.loadCMDRunner(), f.logger)
+	f.cloudFactory = bicloud.NewFactory(
+		f.fs,
+		f.loadCMDRunner(),
+		bicloud.NewSignalInterrupter(),
+		f.uuidGenerator,
+		f.ui,
+		f.timeService,
+		f.redactor,
+		f.cpiLogPath,
+		f.logger,
+	)
 	return f.cloudFactory
 }
 
 type deploymentManagerFactory2 struct {
-	f                             *factory
-	deploymentManifestPath        string
-	deploymentStateService        biconfig.DeploymentStateService
-	legacyDeploymentStateMigrator biconfig.LegacyDeploymentStateMigrator
-	vmRepo                        biconfig.VMRepo
-	stemcellRepo                  biconfig.StemcellRepo
-	diskRepo                      biconfig.DiskRepo
-	diskDeployer                  bivm.DiskDeployer
-	diskManagerFactory            bidisk.ManagerFactory
-	deploymentManagerFactory      bidepl.ManagerFactory
-	vmManagerFactory              bivm.ManagerFactory
-	stemcellManagerFactory        bistemcell.ManagerFactory
-	installerFactory              biinstall.InstallerFactory
-	deployer                      bidepl.Deployer
+	f                              *factory
+	deploymentManifestPath         string
+	manifestInterpolator           biinterpolation.Interpolator
+	releaseSetParser               birelsetmanifest.Parser
+	installationParser             biinstallmanifest.Parser
+	deploymentParser               bideplmanifest.Parser
+	deploymentStateService         biconfig.DeploymentStateService
+	legacyDeploymentStateMigrator  biconfig.LegacyDeploymentStateMigrator
+	vmRepo                         biconfig.VMRepo
+	stemcellRepo                   biconfig.StemcellRepo
+	diskRepo                       biconfig.DiskRepo
+	instanceRepo                   biconfig.InstanceRepo
+	instanceIDRepo                 biconfig.InstanceIDRepo
+	diskDeployer                   bivm.DiskDeployer
+	diskManagerFactory             bidisk.ManagerFactory
+	deploymentManagerFactory       bidepl.ManagerFactory
+	vmManagerFactory               bivm.ManagerFactory
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory
+	stemcellManagerFactory         bistemcell.ManagerFactory
+	installerFactory               biinstall.InstallerFactory
+	deployer                       bidepl.Deployer
 }
 
 func (d *deploymentManagerFactory2) loadDeploymentPreparer() (DeploymentPreparer, error) {
@@ -414,6 +540,7 @@ func (d *deploymentManagerFactory2) loadDeploymentPreparer() (DeploymentPreparer
 	releaseRepo := biconfig.NewReleaseRepo(d.loadDeploymentStateService(), d.f.uuidGenerator)
 	sha1Calculator := bicrypto.NewSha1Calculator(d.f.fs)
 	deploymentRecord := bidepl.NewRecord(deploymentRepo, releaseRepo, d.loadStemcellRepo(), sha1Calculator)
+	deploymentPlanner := bidepl.NewPlanner(deploymentRepo, releaseRepo, d.loadStemcellRepo(), d.loadInstanceRepo(), d.loadDiskRepo(), sha1Calculator)
 	cpiInstaller, err := d.loadCpiInstaller()
 	if err != nil {
 		return DeploymentPreparer{}, err
@@ -429,11 +556,11 @@ func (d *deploymentManagerFactory2) loadDeploymentPreparer() (DeploymentPreparer
 		deploymentRecord,
 		d.f.loadCloudFactory(),
 		d.loadStemcellManagerFactory(),
-		d.f.loadAgentClientFactory(),
-		d.loadVMManagerFactory(),
-		d.f.loadBlobstoreFactory(),
+		d.loadInstanceManagerProviderFactory(),
 		d.loadDeployer(),
 		d.deploymentManifestPath,
+		d.manifestInterpolator,
+		d.f.fs,
 		cpiInstaller,
 		d.loadReleaseFetcher(),
 		d.loadStemcellFetcher(),
@@ -441,6 +568,8 @@ func (d *deploymentManagerFactory2) loadDeploymentPreparer() (DeploymentPreparer
 		d.loadDeploymentManifestParser(),
 		NewTempRootConfigurator(d.f.fs),
 		d.loadTargetProvider(),
+		deploymentPlanner,
+		d.loadIPAllocator(),
 	), nil
 }
 
@@ -459,6 +588,8 @@ func (d *deploymentManagerFactory2) loadDeploymentDeleter() (DeploymentDeleter,
 		d.f.loadAgentClientFactory(),
 		d.f.loadBlobstoreFactory(),
 		d.loadDeploymentManagerFactory(),
+		d.loadInstanceManagerProviderFactory(),
+		biconfig.NewInstanceRepo(d.loadDeploymentStateService()),
 		d.deploymentManifestPath,
 		cpiInstaller,
 		d.loadCpiUninstaller(),
@@ -469,6 +600,142 @@ func (d *deploymentManagerFactory2) loadDeploymentDeleter() (DeploymentDeleter,
 	), nil
 }
 
+func (d *deploymentManagerFactory2) loadCloudChecker() (CloudChecker, error) {
+	cpiInstaller, err := d.loadCpiInstaller()
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentPreparer, err := d.loadDeploymentPreparer()
+	if err != nil {
+		return nil, err
+	}
+
+	checkerFactory := bicloudcheck.NewCheckerFactory(
+		d.loadDeploymentStateService(),
+		d.f.loadAgentClientFactory(),
+		d.loadStemcellRepo(),
+		d.f.uuidGenerator,
+		d.f.fs,
+		d.f.logger,
+	)
+
+	return NewCloudChecker(
+		d.f.ui,
+		"CloudChecker",
+		d.f.logger,
+		d.loadDeploymentStateService(),
+		d.f.loadReleaseManager(),
+		d.f.loadCloudFactory(),
+		checkerFactory,
+		d.deploymentManifestPath,
+		cpiInstaller,
+		d.loadReleaseFetcher(),
+		d.loadReleaseSetAndInstallationManifestParser(),
+		NewTempRootConfigurator(d.f.fs),
+		d.loadTargetProvider(),
+		&deploymentPreparer,
+	), nil
+}
+
+func (d *deploymentManagerFactory2) loadJobController() (JobController, error) {
+	cpiInstaller, err := d.loadCpiInstaller()
+	if err != nil {
+		return nil, err
+	}
+	return NewJobController(
+		d.f.ui,
+		"JobController",
+		d.f.logger,
+		d.loadDeploymentStateService(),
+		d.f.loadReleaseManager(),
+		d.f.loadCloudFactory(),
+		d.loadInstanceManagerProviderFactory(),
+		d.loadDeploymentParser(),
+		d.deploymentManifestPath,
+		cpiInstaller,
+		d.loadReleaseFetcher(),
+		d.loadReleaseSetAndInstallationManifestParser(),
+		NewTempRootConfigurator(d.f.fs),
+		d.loadTargetProvider(),
+	), nil
+}
+
+func (d *deploymentManagerFactory2) loadErrandRunner() (ErrandRunner, error) {
+	cpiInstaller, err := d.loadCpiInstaller()
+	if err != nil {
+		return nil, err
+	}
+	return NewErrandRunner(
+		d.f.ui,
+		"ErrandRunner",
+		d.f.logger,
+		d.loadDeploymentStateService(),
+		d.f.loadReleaseManager(),
+		d.f.loadCloudFactory(),
+		d.loadStemcellManagerFactory(),
+		d.loadInstanceManagerProviderFactory(),
+		d.deploymentManifestPath,
+		cpiInstaller,
+		d.loadReleaseFetcher(),
+		d.loadReleaseSetAndInstallationManifestParser(),
+		d.loadDeploymentManifestParser(),
+		NewTempRootConfigurator(d.f.fs),
+		d.loadTargetProvider(),
+		d.loadIPAllocator(),
+	), nil
+}
+
+func (d *deploymentManagerFactory2) loadIPAllocator() bidepl.IPAllocator {
+	return bidepl.NewIPAllocator(biconfig.NewIPAllocationRepo(d.loadDeploymentStateService()), d.f.logger)
+}
+
+func (d *deploymentManagerFactory2) loadLogsFetcher() LogsFetcher {
+	return NewLogsFetcher(
+		d.f.ui,
+		d.f.fs,
+		"LogsFetcher",
+		d.f.logger,
+		d.loadDeploymentStateService(),
+		d.loadInstanceRepo(),
+		d.f.loadAgentClientFactory(),
+		d.f.loadBlobstoreFactory(),
+		d.f.loadCompressor(),
+		bicrypto.NewSha1Calculator(d.f.fs),
+		d.deploymentManifestPath,
+		d.loadReleaseSetAndInstallationManifestParser(),
+	)
+}
+
+func (d *deploymentManagerFactory2) loadSSHRunner() SSHRunner {
+	return NewSSHRunner(
+		d.f.ui,
+		"SSHRunner",
+		d.f.logger,
+		d.loadDeploymentStateService(),
+		d.loadInstanceRepo(),
+		d.f.loadSSHTunnelFactory(),
+		d.deploymentManifestPath,
+		d.loadReleaseSetAndInstallationManifestParser(),
+	)
+}
+
+func (d *deploymentManagerFactory2) loadJobTemplatesRenderer() JobTemplatesRenderer {
+	return NewJobTemplatesRenderer(
+		d.f.ui,
+		d.f.fs,
+		"JobTemplatesRenderer",
+		d.f.logger,
+		d.f.loadReleaseManager(),
+		d.f.loadReleaseJobResolver(),
+		d.f.loadJobListRenderer(),
+		d.deploymentManifestPath,
+		d.loadReleaseFetcher(),
+		d.loadReleaseSetAndInstallationManifestParser(),
+		d.loadDeploymentManifestParser(),
+	)
+}
+
 func (d *deploymentManagerFactory2) loadDeploymentStateService() biconfig.DeploymentStateService {
 	if d.deploymentStateService != nil {
 		return d.deploymentStateService
@@ -509,7 +776,7 @@ func (d *deploymentManagerFactory2) loadVMRepo() biconfig.VMRepo {
 	if d.vmRepo != nil {
 		return d.vmRepo
 	}
-	d.vmRepo = biconfig.NewVMRepo(d.loadDeploymentStateService())
+	d.vmRepo = biconfig.NewVMRepo(d.loadDeploymentStateService(), biconfig.BootstrapInstanceKey())
 	return d.vmRepo
 }
 
@@ -517,10 +784,26 @@ func (d *deploymentManagerFactory2) loadDiskRepo() biconfig.DiskRepo {
 	if d.diskRepo != nil {
 		return d.diskRepo
 	}
-	d.diskRepo = biconfig.NewDiskRepo(d.loadDeploymentStateService(), d.f.uuidGenerator)
+	d.diskRepo = biconfig.NewDiskRepo(d.loadDeploymentStateService(), biconfig.BootstrapInstanceKey(), d.f.uuidGenerator)
 	return d.diskRepo
 }
 
+func (d *deploymentManagerFactory2) loadInstanceRepo() biconfig.InstanceRepo {
+	if d.instanceRepo != nil {
+		return d.instanceRepo
+	}
+	d.instanceRepo = biconfig.NewInstanceRepo(d.loadDeploymentStateService())
+	return d.instanceRepo
+}
+
+func (d *deploymentManagerFactory2) loadInstanceIDRepo() biconfig.InstanceIDRepo {
+	if d.instanceIDRepo != nil {
+		return d.instanceIDRepo
+	}
+	d.instanceIDRepo = biconfig.NewInstanceIDRepo(d.loadDeploymentStateService(), biconfig.BootstrapInstanceKey(), d.f.uuidGenerator)
+	return d.instanceIDRepo
+}
+
 func (d *deploymentManagerFactory2) loadDiskDeployer() bivm.DiskDeployer {
 	if d.diskDeployer != nil {
 		return d.diskDeployer
@@ -550,6 +833,8 @@ func (d *deploymentManagerFactory2) loadDeploymentManagerFactory() bidepl.Manage
 		d.loadDiskManagerFactory(),
 		d.loadStemcellManagerFactory(),
 		d.f.loadDeploymentFactory(),
+		d.loadInstanceRepo(),
+		d.loadInstanceIDRepo(),
 	)
 	return d.deploymentManagerFactory
 }
@@ -570,6 +855,25 @@ func (d *deploymentManagerFactory2) loadVMManagerFactory() bivm.ManagerFactory {
 	return d.vmManagerFactory
 }
 
+func (d *deploymentManagerFactory2) loadInstanceManagerProviderFactory() biinstance.ManagerProviderFactory {
+	if d.instanceManagerProviderFactory != nil {
+		return d.instanceManagerProviderFactory
+	}
+
+	d.instanceManagerProviderFactory = biinstance.NewManagerProviderFactory(
+		d.loadDeploymentStateService(),
+		d.loadVMManagerFactory(),
+		d.f.loadInstanceManagerFactory(),
+		d.f.loadAgentClientFactory(),
+		d.f.loadBlobstoreFactory(),
+		d.loadStemcellRepo(),
+		d.f.uuidGenerator,
+		d.f.fs,
+		d.f.logger,
+	)
+	return d.instanceManagerProviderFactory
+}
+
 func (d *deploymentManagerFactory2) loadStemcellManagerFactory() bistemcell.ManagerFactory {
 	if d.stemcellManagerFactory != nil {
 		return d.stemcellManagerFactory
@@ -649,17 +953,48 @@ func (d *deploymentManagerFactory2) loadStemcellFetcher() bistemcell.Fetcher {
 	}
 }
 
+func (d *deploymentManagerFactory2) loadReleaseSetParser() birelsetmanifest.Parser {
+	if d.releaseSetParser != nil {
+		return d.releaseSetParser
+	}
+
+	d.releaseSetParser = birelsetmanifest.NewParser(d.f.fs, d.manifestInterpolator, d.f.logger, d.f.loadReleaseSetValidator())
+	return d.releaseSetParser
+}
+
+func (d *deploymentManagerFactory2) loadInstallationParser() biinstallmanifest.Parser {
+	if d.installationParser != nil {
+		return d.installationParser
+	}
+
+	uuidGenerator := boshuuid.NewGenerator()
+	d.installationParser = biinstallmanifest.NewParser(d.f.fs, d.manifestInterpolator, uuidGenerator, d.f.logger, d.f.loadInstallationValidator())
+	return d.installationParser
+}
+
+func (d *deploymentManagerFactory2) loadDeploymentParser() bideplmanifest.Parser {
+	if d.deploymentParser != nil {
+		return d.deploymentParser
+	}
+
+	d.deploymentParser = bideplmanifest.NewParser(d.f.fs, d.manifestInterpolator, d.f.logger)
+	return d.deploymentParser
+}
+
 func (d *deploymentManagerFactory2) loadReleaseSetAndInstallationManifestParser() ReleaseSetAndInstallationManifestParser {
 	return ReleaseSetAndInstallationManifestParser{
-		ReleaseSetParser:   d.f.loadReleaseSetParser(),
-		InstallationParser: d.f.loadInstallationParser(),
+		ReleaseSetParser:   d.loadReleaseSetParser(),
+		InstallationParser: d.loadInstallationParser(),
 	}
 }
 
 func (d *deploymentManagerFactory2) loadDeploymentManifestParser() DeploymentManifestParser {
 	return DeploymentManifestParser{
-		DeploymentParser:    d.f.loadDeploymentParser(),
-		DeploymentValidator: d.f.loadDeploymentValidator(),
-		ReleaseManager:      d.f.loadReleaseManager(),
+		DeploymentParser:     d.loadDeploymentParser(),
+		DeploymentValidator:  d.f.loadDeploymentValidator(),
+		ReleaseManager:       d.f.loadReleaseManager(),
+		FS:                   d.f.fs,
+		ManifestInterpolator: d.manifestInterpolator,
+		UI:                   d.f.ui,
 	}
 }
@@ -10,6 +10,7 @@ import (
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
 	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 
 	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
@@ -29,6 +30,8 @@ var _ = Describe("cmd.Factory", func() {
 		fs = fakesys.NewFakeFileSystem()
 		ui = &fakebiui.FakeUI{}
 		uuidGenerator = &fakeuuid.FakeGenerator{}
+		redactor, err := bilog.NewRedactor(nil)
+		Expect(err).ToNot(HaveOccurred())
 
 		factory = NewFactory(
 			fs,
@@ -37,6 +40,8 @@ var _ = Describe("cmd.Factory", func() {
 			logger,
 			uuidGenerator,
 			"/fake-path",
+			redactor,
+			"",
 		)
 	})
 
@@ -60,6 +65,94 @@ var _ = Describe("cmd.Factory", func() {
 				Expect(cmd.Name()).To(Equal("delete"))
 			})
 		})
+
+		Describe("plan command", func() {
+			It("returns plan command", func() {
+				cmd, err := factory.CreateCommand("plan")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("plan"))
+			})
+		})
+
+		Describe("recreate command", func() {
+			It("returns recreate command", func() {
+				cmd, err := factory.CreateCommand("recreate")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("recreate"))
+			})
+		})
+
+		Describe("state command", func() {
+			It("returns state command", func() {
+				cmd, err := factory.CreateCommand("state")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("state"))
+			})
+		})
+
+		Describe("cloud-check command", func() {
+			It("returns cloud-check command", func() {
+				cmd, err := factory.CreateCommand("cloud-check")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("cloud-check"))
+			})
+		})
+
+		Describe("stop command", func() {
+			It("returns stop command", func() {
+				cmd, err := factory.CreateCommand("stop")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("stop"))
+			})
+		})
+
+		Describe("start command", func() {
+			It("returns start command", func() {
+				cmd, err := factory.CreateCommand("start")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("start"))
+			})
+		})
+
+		Describe("logs command", func() {
+			It("returns logs command", func() {
+				cmd, err := factory.CreateCommand("logs")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("logs"))
+			})
+		})
+
+		Describe("ssh command", func() {
+			It("returns ssh command", func() {
+				cmd, err := factory.CreateCommand("ssh")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("ssh"))
+			})
+		})
+
+		Describe("validate command", func() {
+			It("returns validate command", func() {
+				cmd, err := factory.CreateCommand("validate")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("validate"))
+			})
+		})
+
+		Describe("render command", func() {
+			It("returns render command", func() {
+				cmd, err := factory.CreateCommand("render")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("render"))
+			})
+		})
+
+		Describe("run-errand command", func() {
+			It("returns run-errand command", func() {
+				cmd, err := factory.CreateCommand("run-errand")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cmd.Name()).To(Equal("run-errand"))
+			})
+		})
 	})
 
 	Context("unknown command name", func() {
@@ -2,6 +2,7 @@ package fakes
 
 import (
 	biinstallation "github.com/cloudfoundry/bosh-init/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
@@ -17,6 +18,10 @@ func (f *FakeInstallation) Job() biinstallation.InstalledJob {
 	return biinstallation.InstalledJob{}
 }
 
+func (f *FakeInstallation) Manifest() biinstallmanifest.Manifest {
+	return biinstallmanifest.Manifest{}
+}
+
 func (f *FakeInstallation) WithRunningRegistry(logger boshlog.Logger, stage biui.Stage, fn func() error) error {
 	return fn()
 }
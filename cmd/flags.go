@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+func newFlagSet(name string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.SetOutput(ioutil.Discard)
+	return flagSet
+}
+
+// parseFlags allows flags to appear before, between or after positional arguments
+func parseFlags(flagSet *flag.FlagSet, args []string) ([]string, error) {
+	positionalArgs := []string{}
+
+	for {
+		err := flagSet.Parse(args)
+		if err != nil {
+			return positionalArgs, err
+		}
+
+		args = flagSet.Args()
+		if len(args) == 0 {
+			return positionalArgs, nil
+		}
+
+		positionalArgs = append(positionalArgs, args[0])
+		args = args[1:]
+	}
+}
+
+// parseCmdArgs parses the flags of a command and returns its positional arguments, of which there must be exactly numArgs
+func parseCmdArgs(cmdName string, flagSet *flag.FlagSet, args []string, numArgs int, logger boshlog.Logger, logTag string) ([]string, error) {
+	positionalArgs, err := parseFlags(flagSet, args)
+	if err != nil || len(positionalArgs) != numArgs {
+		logger.Error(logTag, "Invalid arguments: %#v", args)
+		if numArgs == 1 {
+			return nil, fmt.Errorf("Invalid usage - %s command requires exactly 1 argument", cmdName)
+		}
+		return nil, fmt.Errorf("Invalid usage - %s command requires exactly %d arguments", cmdName, numArgs)
+	}
+
+	return positionalArgs, nil
+}
+
+func absDeploymentManifestPath(deploymentManifestPath string, ui biui.UI) (string, error) {
+	manifestAbsFilePath, err := filepath.Abs(deploymentManifestPath)
+	if err != nil {
+		ui.ErrorLinef("Failed getting absolute path to deployment file '%s'", deploymentManifestPath)
+		return "", bosherr.WrapErrorf(err, "Getting absolute path to deployment file '%s'", deploymentManifestPath)
+	}
+
+	return manifestAbsFilePath, nil
+}
+
+// stringSliceFlag collects the values of a flag that may be given multiple times
+type stringSliceFlag []string
+
+func (f *stringSliceFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringSliceFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// manifestFlags are the flags of the commands that read the deployment manifest
+type manifestFlags struct {
+	vars      stringSliceFlag
+	varsFiles stringSliceFlag
+	varsEnvs  stringSliceFlag
+	opsFiles  stringSliceFlag
+	varsStore string
+
+	// saveVarsStore is set by the commands that deploy, which save the generated variables to the vars store
+	saveVarsStore bool
+}
+
+const manifestFlagsUsage = "[--var <name>=<value>]... [--vars-file <path>]... [--vars-env <prefix>]... [--vars-store <path>] [--ops-file <path>]..."
+
+func (f *manifestFlags) register(flagSet *flag.FlagSet) {
+	flagSet.Var(&f.vars, "var", "")
+	flagSet.Var(&f.varsFiles, "vars-file", "")
+	flagSet.Var(&f.varsEnvs, "vars-env", "")
+	flagSet.StringVar(&f.varsStore, "vars-store", "", "")
+	flagSet.Var(&f.opsFiles, "ops-file", "")
+}
+
+// resolveManifest returns the absolute path of the deployment manifest, which must exist, and the interpolator of the manifests
+func (f *manifestFlags) resolveManifest(deploymentManifestPath string, ui biui.UI, fs boshsys.FileSystem) (string, biinterpolation.Interpolator, error) {
+	manifestAbsFilePath, err := absDeploymentManifestPath(deploymentManifestPath, ui)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !fs.FileExists(manifestAbsFilePath) {
+		ui.ErrorLinef("Deployment '%s' does not exist", manifestAbsFilePath)
+		return "", nil, bosherr.Errorf("Deployment manifest does not exist at '%s'", manifestAbsFilePath)
+	}
+
+	ui.PrintLinef("Deployment manifest: '%s'", manifestAbsFilePath)
+
+	manifestInterpolator, err := f.interpolator(fs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return manifestAbsFilePath, manifestInterpolator, nil
+}
+
+// interpolator returns an interpolator with the operations from the ops files, in the order they were given,
+// and the variables from the environment, the vars files and the --var flags, with later sources taking precedence.
+// With a vars store, the variables declared in the manifest are generated when they are not given or stored,
+// and only stored by the commands that deploy.
+func (f *manifestFlags) interpolator(fs boshsys.FileSystem) (biinterpolation.Interpolator, error) {
+	variables := biinterpolation.Variables{}
+
+	for _, prefix := range f.varsEnvs {
+		variables = variables.Merge(biinterpolation.NewVariablesFromEnv(prefix, os.Environ()))
+	}
+
+	for _, path := range f.varsFiles {
+		fileVariables, err := biinterpolation.NewVariablesFromFile(path, fs)
+		if err != nil {
+			return nil, err
+		}
+		variables = variables.Merge(fileVariables)
+	}
+
+	for _, arg := range f.vars {
+		argVariables, err := biinterpolation.NewVariableFromArg(arg)
+		if err != nil {
+			return nil, err
+		}
+		variables = variables.Merge(argVariables)
+	}
+
+	ops := biinterpolation.Ops{}
+	for _, path := range f.opsFiles {
+		fileOps, err := biinterpolation.NewOpsFromFile(path, fs)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, fileOps...)
+	}
+
+	if f.varsStore != "" {
+		varsStore := biinterpolation.NewFileVarsStore(f.varsStore, fs)
+		if !f.saveVarsStore {
+			varsStore = biinterpolation.NewReadOnlyVarsStore(varsStore)
+		}
+		return biinterpolation.NewVarsStoreInterpolator(variables, ops, varsStore, biinterpolation.NewValueGenerator()), nil
+	}
+
+	return biinterpolation.NewInterpolator(variables, ops), nil
+}
@@ -147,8 +147,9 @@ ENVIRONMENT VARIABLES:{{ range .Envs }}
 {{ .Key }}{{ .Value }}{{ end }}{{ end }}
 
 GLOBAL OPTIONS:
-    --help, -h       Show help message
-    --version, -v    Show version`
+    --help, -h          Show help message
+    --version, -v       Show version
+    --cpi-log <path>    Append all CPI requests, responses and logs to the file`
 
 type helpContext struct {
 	Name         string
@@ -74,8 +74,9 @@ COMMANDS:
     simple     Simple command... sorted to the end of the list
 
 GLOBAL OPTIONS:
-    --help, -h       Show help message
-    --version, -v    Show version`
+    --help, -h          Show help message
+    --version, -v       Show version
+    --cpi-log <path>    Append all CPI requests, responses and logs to the file`
 
 				Expect(ui.Said).To(Equal([]string{expectedOutput}))
 			})
@@ -93,8 +94,9 @@ USAGE:
     bosh-init [global options] simple
 
 GLOBAL OPTIONS:
-    --help, -h       Show help message
-    --version, -v    Show version`
+    --help, -h          Show help message
+    --version, -v       Show version
+    --cpi-log <path>    Append all CPI requests, responses and logs to the file`
 
 					Expect(ui.Said).To(Equal([]string{expectedOutput}))
 				})
@@ -115,8 +117,9 @@ ENVIRONMENT VARIABLES:
     BOSH_ENV_VARIABLE2=something-else    Sets another environment variable
 
 GLOBAL OPTIONS:
-    --help, -h       Show help message
-    --version, -v    Show version`
+    --help, -h          Show help message
+    --version, -v       Show version
+    --cpi-log <path>    Append all CPI requests, responses and logs to the file`
 
 					Expect(ui.Said).To(Equal([]string{expectedOutput}))
 				})
@@ -142,8 +145,9 @@ USAGE:
     bosh-init [global options] help [command]
 
 GLOBAL OPTIONS:
-    --help, -h       Show help message
-    --version, -v    Show version`
+    --help, -h          Show help message
+    --version, -v       Show version
+    --cpi-log <path>    Append all CPI requests, responses and logs to the file`
 
 				Expect(ui.Said).To(Equal([]string{expectedOutput}))
 			})
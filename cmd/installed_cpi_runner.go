@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
+	biinstall "github.com/cloudfoundry/bosh-init/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+// installedCpiRunner installs the CPI release referenced by the deployment manifest
+// and runs commands against an existing deployment with the registry running.
+type installedCpiRunner struct {
+	ui                                      biui.UI
+	logTag                                  string
+	logger                                  boshlog.Logger
+	deploymentStateService                  biconfig.DeploymentStateService
+	releaseManager                          birel.Manager
+	deploymentManifestPath                  string
+	cpiInstaller                            bicpirel.CpiInstaller
+	releaseFetcher                          birel.Fetcher
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
+	tempRootConfigurator                    TempRootConfigurator
+	targetProvider                          biinstall.TargetProvider
+}
+
+type installedCpiFunc func(biinstall.Installation, biinstallmanifest.Manifest, biconfig.DeploymentState) error
+
+func (r installedCpiRunner) Run(stage biui.Stage, fn installedCpiFunc) (err error) {
+	r.ui.PrintLinef("Deployment state: '%s'", r.deploymentStateService.Path())
+
+	if !r.deploymentStateService.Exists() {
+		r.ui.PrintLinef("No deployment state file found.")
+		return nil
+	}
+
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading deployment state")
+	}
+
+	target, err := r.targetProvider.NewTarget()
+	if err != nil {
+		return bosherr.WrapError(err, "Determining installation target")
+	}
+
+	err = r.tempRootConfigurator.PrepareAndSetTempRoot(target.TmpPath(), r.logger)
+	if err != nil {
+		return bosherr.WrapError(err, "Setting temp root")
+	}
+
+	defer func() {
+		err := r.releaseManager.DeleteAll()
+		if err != nil {
+			r.logger.Warn(r.logTag, "Deleting all extracted releases: %s", err.Error())
+		}
+	}()
+
+	var installationManifest biinstallmanifest.Manifest
+	err = stage.PerformComplex("validating", func(stage biui.Stage) error {
+		var releaseSetManifest birelsetmanifest.Manifest
+		releaseSetManifest, installationManifest, err = r.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(r.deploymentManifestPath)
+		if err != nil {
+			return err
+		}
+
+		cpiReleaseName := installationManifest.Template.Release
+		cpiReleaseRef, found := releaseSetManifest.FindByName(cpiReleaseName)
+		if !found {
+			return bosherr.Errorf("installation release '%s' must refer to a release in releases", cpiReleaseName)
+		}
+
+		err = r.releaseFetcher.DownloadAndExtract(cpiReleaseRef, stage)
+		if err != nil {
+			return err
+		}
+
+		err = r.cpiInstaller.ValidateCpiRelease(installationManifest, stage)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cpiInstaller.WithInstalledCpiRelease(installationManifest, target, stage, func(localCpiInstallation biinstall.Installation) error {
+		return localCpiInstallation.WithRunningRegistry(r.logger, stage, func() error {
+			return fn(localCpiInstallation, installationManifest, deploymentState)
+		})
+	})
+}
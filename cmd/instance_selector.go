@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// InstanceSelector is the value of the --instance <job>/<index> flag.
+// The zero value selects no instance in particular.
+type InstanceSelector struct {
+	JobName string
+	Index   int
+}
+
+const instanceFlagUsage = "[--instance <job>/<index>]"
+
+func (s *InstanceSelector) String() string {
+	if s.IsEmpty() {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", s.JobName, s.Index)
+}
+
+func (s *InstanceSelector) Set(value string) error {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || parts[0] == "" {
+		return bosherr.Errorf("Instance '%s' must be given as <job>/<index>", value)
+	}
+
+	index, err := strconv.Atoi(parts[1])
+	if err != nil || index < 0 {
+		return bosherr.Errorf("Instance '%s' must be given as <job>/<index>", value)
+	}
+
+	s.JobName = parts[0]
+	s.Index = index
+	return nil
+}
+
+func (s InstanceSelector) IsEmpty() bool {
+	return s.JobName == ""
+}
+
+// Matches is true for every instance when no instance is selected
+func (s InstanceSelector) Matches(jobName string, index int) bool {
+	return s.IsEmpty() || (s.JobName == jobName && s.Index == index)
+}
+
+// find returns the record of the selected instance, or of the bootstrap instance when none is selected.
+// Only instances with a VM are found.
+func (s InstanceSelector) find(instanceRepo biconfig.InstanceRepo) (biconfig.InstanceRecord, bool, error) {
+	if s.IsEmpty() {
+		record, err := instanceRepo.FindBootstrap()
+		if err != nil {
+			return biconfig.InstanceRecord{}, false, bosherr.WrapError(err, "Finding bootstrap instance")
+		}
+		return record, record.VMCID != "", nil
+	}
+
+	record, found, err := instanceRepo.Find(s.JobName, s.Index)
+	if err != nil {
+		return biconfig.InstanceRecord{}, false, bosherr.WrapErrorf(err, "Finding instance '%s'", s.String())
+	}
+	return record, found && record.VMCID != "", nil
+}
+
+// instanceMbusURL returns the mbus URL of a found instance; the bootstrap instance has no host of its own
+func instanceMbusURL(record biconfig.InstanceRecord, mbusURL string) (string, error) {
+	if record.Host == "" {
+		return mbusURL, nil
+	}
+
+	instanceURL, err := biinstance.InstanceMbusURL(mbusURL, record.Host)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Building mbus URL for instance '%s/%d'", record.Job, record.Index)
+	}
+	return instanceURL, nil
+}
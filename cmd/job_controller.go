@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	biinstall "github.com/cloudfoundry/bosh-init/installation"
+	biinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type JobController interface {
+	// StopJobs stops the jobs of the selected instance, or of every instance if none is selected.
+	// If hard is set, the persistent disks are also detached.
+	StopJobs(stage biui.Stage, instance InstanceSelector, hard bool) error
+	// StartJobs re-attaches detached persistent disks, starts the jobs and waits for them to be running,
+	// on the selected instance or on every instance if none is selected.
+	StartJobs(stage biui.Stage, instance InstanceSelector) error
+}
+
+func NewJobController(
+	ui biui.UI,
+	logTag string,
+	logger boshlog.Logger,
+	deploymentStateService biconfig.DeploymentStateService,
+	releaseManager birel.Manager,
+	cloudFactory bicloud.Factory,
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory,
+	deploymentParser bideplmanifest.Parser,
+	deploymentManifestPath string,
+	cpiInstaller bicpirel.CpiInstaller,
+	releaseFetcher birel.Fetcher,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+	tempRootConfigurator TempRootConfigurator,
+	targetProvider biinstall.TargetProvider,
+) JobController {
+	return &jobController{
+		ui:                             ui,
+		logTag:                         logTag,
+		logger:                         logger,
+		cloudFactory:                   cloudFactory,
+		instanceManagerProviderFactory: instanceManagerProviderFactory,
+		deploymentParser:               deploymentParser,
+		deploymentManifestPath:         deploymentManifestPath,
+		installedCpiRunner: installedCpiRunner{
+			ui:                                      ui,
+			logTag:                                  logTag,
+			logger:                                  logger,
+			deploymentStateService:                  deploymentStateService,
+			releaseManager:                          releaseManager,
+			deploymentManifestPath:                  deploymentManifestPath,
+			cpiInstaller:                            cpiInstaller,
+			releaseFetcher:                          releaseFetcher,
+			releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+			tempRootConfigurator:                    tempRootConfigurator,
+			targetProvider:                          targetProvider,
+		},
+	}
+}
+
+type jobController struct {
+	ui                             biui.UI
+	logTag                         string
+	logger                         boshlog.Logger
+	cloudFactory                   bicloud.Factory
+	instanceManagerProviderFactory biinstance.ManagerProviderFactory
+	deploymentParser               bideplmanifest.Parser
+	deploymentManifestPath         string
+	installedCpiRunner             installedCpiRunner
+}
+
+// currentInstance is a deployed instance with the persistent disks recorded for it
+type currentInstance struct {
+	instance biinstance.Instance
+	disks    []bidisk.Disk
+}
+
+func (c *jobController) StopJobs(stage biui.Stage, instance InstanceSelector, hard bool) error {
+	return c.withCurrentInstances(stage, instance, func(currentInstances []currentInstance) error {
+		return stage.PerformComplex("stopping jobs", func(stopStage biui.Stage) error {
+			// stop in the reverse order of deploy, so that the bootstrap instance is stopped last
+			for i := len(currentInstances) - 1; i >= 0; i-- {
+				if err := currentInstances[i].instance.Stop(hard, stopStage); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (c *jobController) StartJobs(stage biui.Stage, instance InstanceSelector) error {
+	deploymentManifest, err := c.deploymentParser.Parse(c.deploymentManifestPath)
+	if err != nil {
+		return bosherr.WrapError(err, "Parsing deployment manifest")
+	}
+
+	return c.withCurrentInstances(stage, instance, func(currentInstances []currentInstance) error {
+		return stage.PerformComplex("starting jobs", func(startStage biui.Stage) error {
+			for _, current := range currentInstances {
+				if err := current.instance.Start(current.disks, deploymentManifest.Update.UpdateWatchTime, startStage); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (c *jobController) withCurrentInstances(stage biui.Stage, instance InstanceSelector, fn func([]currentInstance) error) error {
+	return c.installedCpiRunner.Run(stage, func(localCpiInstallation biinstall.Installation, installationManifest biinstallmanifest.Manifest, deploymentState biconfig.DeploymentState) error {
+		c.logger.Debug(c.logTag, "Creating cloud client...")
+		cloud, err := c.cloudFactory.NewCloud(localCpiInstallation, deploymentState.DirectorID)
+		if err != nil {
+			return bosherr.WrapError(err, "Creating CPI client from CPI installation")
+		}
+
+		c.logger.Debug(c.logTag, "Finding current instances...")
+		instanceManagerProvider := c.instanceManagerProviderFactory.NewManagerProvider(cloud, deploymentState.DirectorID, installationManifest.Mbus)
+		instanceManagers, err := instanceManagerProvider.FindAll()
+		if err != nil {
+			return bosherr.WrapError(err, "Finding current instances")
+		}
+
+		currentInstances := []currentInstance{}
+		for _, instanceManager := range instanceManagers {
+			instances, err := instanceManager.FindCurrent()
+			if err != nil {
+				return bosherr.WrapError(err, "Finding current instances")
+			}
+
+			for _, current := range instances {
+				if !instance.Matches(current.JobName(), current.ID()) {
+					continue
+				}
+
+				disks, err := instanceManager.FindCurrentDisks()
+				if err != nil {
+					return bosherr.WrapErrorf(err, "Finding current disks of instance '%s/%d'", current.JobName(), current.ID())
+				}
+
+				currentInstances = append(currentInstances, currentInstance{instance: current, disks: disks})
+			}
+		}
+
+		if len(currentInstances) == 0 {
+			c.ui.PrintLinef("No deployed VM found.")
+			return nil
+		}
+
+		return fn(currentInstances)
+	})
+}
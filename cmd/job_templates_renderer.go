@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	bideplrel "github.com/cloudfoundry/bosh-init/deployment/release"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+	bitemplate "github.com/cloudfoundry/bosh-init/templatescompiler"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type JobTemplatesRenderer interface {
+	// RenderJobTemplates renders the templates of the given deployment job (all jobs if empty)
+	// into outputDir, one sub-directory per deployment job and release job.
+	RenderJobTemplates(stage biui.Stage, jobName string, outputDir string) error
+}
+
+func NewJobTemplatesRenderer(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logTag string,
+	logger boshlog.Logger,
+	releaseManager birel.Manager,
+	releaseJobResolver bideplrel.JobResolver,
+	jobListRenderer bitemplate.JobListRenderer,
+	deploymentManifestPath string,
+	releaseFetcher birel.Fetcher,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+	deploymentManifestParser DeploymentManifestParser,
+) JobTemplatesRenderer {
+	return &jobTemplatesRenderer{
+		ui:                                      ui,
+		fs:                                      fs,
+		logTag:                                  logTag,
+		logger:                                  logger,
+		releaseManager:                          releaseManager,
+		releaseJobResolver:                      releaseJobResolver,
+		jobListRenderer:                         jobListRenderer,
+		deploymentManifestPath:                  deploymentManifestPath,
+		releaseFetcher:                          releaseFetcher,
+		releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+		deploymentManifestParser:                deploymentManifestParser,
+	}
+}
+
+type jobTemplatesRenderer struct {
+	ui                                      biui.UI
+	fs                                      boshsys.FileSystem
+	logTag                                  string
+	logger                                  boshlog.Logger
+	releaseManager                          birel.Manager
+	releaseJobResolver                      bideplrel.JobResolver
+	jobListRenderer                         bitemplate.JobListRenderer
+	deploymentManifestPath                  string
+	releaseFetcher                          birel.Fetcher
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
+	deploymentManifestParser                DeploymentManifestParser
+}
+
+func (r *jobTemplatesRenderer) RenderJobTemplates(stage biui.Stage, jobName string, outputDir string) error {
+	defer func() {
+		err := r.releaseManager.DeleteAll()
+		if err != nil {
+			r.logger.Warn(r.logTag, "Deleting all extracted releases: %s", err.Error())
+		}
+	}()
+
+	releaseSetManifest, _, err := r.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(r.deploymentManifestPath)
+	if err != nil {
+		return err
+	}
+
+	var deploymentManifest bideplmanifest.Manifest
+	err = stage.PerformComplex("validating", func(stage biui.Stage) error {
+		for _, releaseRef := range releaseSetManifest.Releases {
+			err := r.releaseFetcher.DownloadAndExtract(releaseRef, stage)
+			if err != nil {
+				return err
+			}
+		}
+
+		deploymentManifest, err = r.deploymentManifestParser.GetDeploymentManifest(r.deploymentManifestPath, releaseSetManifest, stage)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	deploymentJobs := deploymentManifest.Jobs
+	if jobName != "" {
+		deploymentJob, found := deploymentManifest.FindJobByName(jobName)
+		if !found {
+			return bosherr.Errorf("Job '%s' not found in deployment manifest", jobName)
+		}
+		deploymentJobs = []bideplmanifest.Job{deploymentJob}
+	}
+
+	err = stage.PerformComplex("rendering job templates", func(stage biui.Stage) error {
+		for _, deploymentJob := range deploymentJobs {
+			stepName := fmt.Sprintf("Rendering job templates for '%s'", deploymentJob.Name)
+			err := stage.Perform(stepName, func() error {
+				return r.renderDeploymentJob(deploymentJob, deploymentManifest, outputDir)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.ui.PrintLinef("Rendered job templates written to '%s'", outputDir)
+	return nil
+}
+
+func (r *jobTemplatesRenderer) renderDeploymentJob(deploymentJob bideplmanifest.Job, deploymentManifest bideplmanifest.Manifest, outputDir string) error {
+	releaseJobs := make([]bireljob.Job, len(deploymentJob.Templates), len(deploymentJob.Templates))
+	for i, jobRef := range deploymentJob.Templates {
+		releaseJob, err := r.releaseJobResolver.Resolve(jobRef.Name, jobRef.Release)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Resolving job '%s' in release '%s'", jobRef.Name, jobRef.Release)
+		}
+		releaseJobs[i] = releaseJob
+	}
+
+	// templates are rendered for the first instance of the job, without the ID that is recorded when it is deployed
+	instanceSpec, err := bitemplate.NewInstanceSpec(deploymentManifest, deploymentJob.Name, 0, "", releaseJobs, r.releaseManager)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Describing instance '%s/0'", deploymentJob.Name)
+	}
+
+	renderedJobList, err := r.jobListRenderer.Render(releaseJobs, deploymentJob.Properties, deploymentManifest.Properties, deploymentManifest.Name, instanceSpec)
+	if err != nil {
+		return err
+	}
+	defer renderedJobList.DeleteSilently()
+
+	for _, renderedJob := range renderedJobList.All() {
+		renderedJobDir := filepath.Join(outputDir, deploymentJob.Name, renderedJob.Job().Name)
+
+		// remove files rendered by a previous run, so that the output can be diffed
+		err = r.fs.RemoveAll(renderedJobDir)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Removing previously rendered job templates in '%s'", renderedJobDir)
+		}
+
+		err = r.fs.CopyDir(renderedJob.Path(), renderedJobDir)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Writing rendered job templates to '%s'", renderedJobDir)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package cmd_test
+
+import (
+	"errors"
+
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+
+	mock_deployment_release "github.com/cloudfoundry/bosh-init/deployment/release/mocks"
+	mock_tarball "github.com/cloudfoundry/bosh-init/installation/tarball/mocks"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+	mock_release "github.com/cloudfoundry/bosh-init/release/mocks"
+	mock_template "github.com/cloudfoundry/bosh-init/templatescompiler/mocks"
+
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+	birelmanifest "github.com/cloudfoundry/bosh-init/release/manifest"
+	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
+	bitemplate "github.com/cloudfoundry/bosh-init/templatescompiler"
+
+	fakebideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest/fakes"
+	fakebiinstallmanifest "github.com/cloudfoundry/bosh-init/installation/manifest/fakes"
+	fakebirel "github.com/cloudfoundry/bosh-init/release/fakes"
+	fakebirelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest/fakes"
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("JobTemplatesRenderer", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("RenderJobTemplates", func() {
+		var (
+			fs             *fakesys.FakeFileSystem
+			logger         boshlog.Logger
+			releaseManager birel.Manager
+
+			mockTarballProvider    *mock_tarball.MockProvider
+			mockReleaseExtractor   *mock_release.MockExtractor
+			mockReleaseJobResolver *mock_deployment_release.MockJobResolver
+			mockJobListRenderer    *mock_template.MockJobListRenderer
+
+			fakeReleaseSetParser    *fakebirelsetmanifest.FakeParser
+			fakeInstallationParser  *fakebiinstallmanifest.FakeParser
+			fakeDeploymentParser    *fakebideplmanifest.FakeParser
+			fakeDeploymentValidator *fakebideplmanifest.FakeValidator
+			fakeRelease             *fakebirel.FakeRelease
+
+			fakeUI    *fakebiui.FakeUI
+			fakeStage *fakebiui.FakeStage
+
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+			releaseTarballPath     = "/fake-release.tgz"
+			outputDir              = "/fake-output-dir"
+
+			releaseRef         birelmanifest.ReleaseRef
+			deploymentManifest bideplmanifest.Manifest
+			releaseJob         bireljob.Job
+		)
+
+		var newJobTemplatesRenderer = func() bicmd.JobTemplatesRenderer {
+			releaseFetcher := birel.NewFetcher(mockTarballProvider, mockReleaseExtractor, releaseManager)
+
+			releaseSetAndInstallationManifestParser := bicmd.ReleaseSetAndInstallationManifestParser{
+				ReleaseSetParser:   fakeReleaseSetParser,
+				InstallationParser: fakeInstallationParser,
+			}
+
+			deploymentManifestParser := bicmd.DeploymentManifestParser{
+				DeploymentParser:    fakeDeploymentParser,
+				DeploymentValidator: fakeDeploymentValidator,
+				ReleaseManager:      releaseManager,
+				UI:                  fakeUI,
+			}
+
+			return bicmd.NewJobTemplatesRenderer(
+				fakeUI,
+				fs,
+				"JobTemplatesRenderer",
+				logger,
+				releaseManager,
+				mockReleaseJobResolver,
+				mockJobListRenderer,
+				deploymentManifestPath,
+				releaseFetcher,
+				releaseSetAndInstallationManifestParser,
+				deploymentManifestParser,
+			)
+		}
+
+		var expectRender = func(renderedJobPath string) *gomock.Call {
+			return mockJobListRenderer.EXPECT().Render(
+				[]bireljob.Job{releaseJob},
+				biproperty.Map{"fake-job-property": "fake-job-value"},
+				biproperty.Map{"fake-global-property": "fake-global-value"},
+				"fake-deployment-name",
+				bitemplate.InstanceSpec{
+					Name:      "fake-job-name",
+					Index:     0,
+					Bootstrap: true,
+					Networks:  map[string]biproperty.Map{},
+					Links:     map[string]map[string]bideplmanifest.Link{"fake-release-job-name": {}},
+					Releases: map[string]bitemplate.ReleaseSpec{
+						"fake-release-job-name": {Name: "fake-release-name", Version: "fake-release-version"},
+					},
+				},
+			).Do(func(_, _, _, _, _ interface{}) {
+				fs.WriteFileString(renderedJobPath+"/bin/ctl", "rendered-ctl")
+			}).Return(bitemplate.NewRenderedJobList(), nil)
+		}
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			releaseManager = birel.NewManager(logger)
+
+			mockTarballProvider = mock_tarball.NewMockProvider(mockCtrl)
+			mockReleaseExtractor = mock_release.NewMockExtractor(mockCtrl)
+			mockReleaseJobResolver = mock_deployment_release.NewMockJobResolver(mockCtrl)
+			mockJobListRenderer = mock_template.NewMockJobListRenderer(mockCtrl)
+
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+
+			releaseRef = birelmanifest.ReleaseRef{
+				Name: "fake-release-name",
+				URL:  "file://" + releaseTarballPath,
+			}
+
+			fakeReleaseSetParser = fakebirelsetmanifest.NewFakeParser()
+			fakeReleaseSetParser.ParseManifest = birelsetmanifest.Manifest{
+				Releases: []birelmanifest.ReleaseRef{releaseRef},
+			}
+			fakeInstallationParser = fakebiinstallmanifest.NewFakeParser()
+
+			deploymentManifest = bideplmanifest.Manifest{
+				Name: "fake-deployment-name",
+				Jobs: []bideplmanifest.Job{
+					{
+						Name: "fake-job-name",
+						Templates: []bideplmanifest.ReleaseJobRef{
+							{Name: "fake-release-job-name", Release: "fake-release-name"},
+						},
+						Properties: biproperty.Map{"fake-job-property": "fake-job-value"},
+					},
+					{
+						Name: "other-job-name",
+					},
+				},
+				Properties: biproperty.Map{"fake-global-property": "fake-global-value"},
+			}
+			fakeDeploymentParser = fakebideplmanifest.NewFakeParser()
+			fakeDeploymentParser.ParseManifest = deploymentManifest
+
+			fakeDeploymentValidator = fakebideplmanifest.NewFakeValidator()
+			fakeDeploymentValidator.SetValidateBehavior([]fakebideplmanifest.ValidateOutput{{Err: nil}})
+			fakeDeploymentValidator.SetValidateReleaseJobsBehavior([]fakebideplmanifest.ValidateReleaseJobsOutput{{Err: nil}})
+
+			fakeRelease = fakebirel.NewFakeRelease()
+			fakeRelease.ReleaseName = "fake-release-name"
+			fakeRelease.ReleaseVersion = "fake-release-version"
+
+			releaseJob = bireljob.Job{Name: "fake-release-job-name"}
+
+			mockTarballProvider.EXPECT().Get(releaseRef, gomock.Any()).Return(releaseTarballPath, nil).AnyTimes()
+		})
+
+		Context("when the releases can be extracted", func() {
+			BeforeEach(func() {
+				mockReleaseExtractor.EXPECT().Extract(releaseTarballPath).Return(fakeRelease, nil)
+			})
+
+			It("renders the templates of the given job into the output directory", func() {
+				mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+
+				renderedJobList := bitemplate.NewRenderedJobList()
+				renderedJobList.Add(bitemplate.NewRenderedJob(releaseJob, "/fake-rendered-job-path", fs, logger))
+				expectRender("/fake-rendered-job-path").Return(renderedJobList, nil)
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				contents, err := fs.ReadFileString("/fake-output-dir/fake-job-name/fake-release-job-name/bin/ctl")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contents).To(Equal("rendered-ctl"))
+				Expect(fs.FileExists("/fake-rendered-job-path")).To(BeFalse())
+
+				Expect(fakeUI.Said).To(ContainElement("Rendered job templates written to '/fake-output-dir'"))
+			})
+
+			It("logs validating and rendering stages", func() {
+				mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+				expectRender("/fake-rendered-job-path")
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeStage.PerformCalls[0].Name).To(Equal("validating"))
+				Expect(fakeStage.PerformCalls[1].Name).To(Equal("rendering job templates"))
+				Expect(fakeStage.PerformCalls[1].Stage.PerformCalls[0].Name).To(Equal("Rendering job templates for 'fake-job-name'"))
+			})
+
+			It("removes templates rendered by a previous run", func() {
+				fs.WriteFileString("/fake-output-dir/fake-job-name/fake-release-job-name/bin/stale", "stale")
+
+				mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+
+				renderedJobList := bitemplate.NewRenderedJobList()
+				renderedJobList.Add(bitemplate.NewRenderedJob(releaseJob, "/fake-rendered-job-path", fs, logger))
+				expectRender("/fake-rendered-job-path").Return(renderedJobList, nil)
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fs.FileExists("/fake-output-dir/fake-job-name/fake-release-job-name/bin/stale")).To(BeFalse())
+			})
+
+			It("renders all jobs when no job is given", func() {
+				mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+				expectRender("/fake-rendered-job-path")
+				mockJobListRenderer.EXPECT().Render(
+					[]bireljob.Job{},
+					biproperty.Map(nil),
+					biproperty.Map{"fake-global-property": "fake-global-value"},
+					"fake-deployment-name",
+					bitemplate.InstanceSpec{
+						Name:      "other-job-name",
+						Index:     0,
+						Bootstrap: true,
+						Networks:  map[string]biproperty.Map{},
+						Links:     map[string]map[string]bideplmanifest.Link{},
+						Releases:  map[string]bitemplate.ReleaseSpec{},
+					},
+				).Return(bitemplate.NewRenderedJobList(), nil)
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "", outputDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				renderStage := fakeStage.PerformCalls[1].Stage
+				Expect(renderStage.PerformCalls).To(HaveLen(2))
+				Expect(renderStage.PerformCalls[1].Name).To(Equal("Rendering job templates for 'other-job-name'"))
+			})
+
+			It("deletes the extracted releases", func() {
+				mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+				expectRender("/fake-rendered-job-path")
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(fakeRelease.DeleteCalled).To(BeTrue())
+			})
+
+			Context("when the job is not in the deployment manifest", func() {
+				It("returns an error", func() {
+					err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "missing-job-name", outputDir)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("Job 'missing-job-name' not found in deployment manifest"))
+				})
+			})
+
+			Context("when resolving the release job fails", func() {
+				It("returns an error", func() {
+					mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(bireljob.Job{}, errors.New("fake-resolve-error"))
+
+					err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-resolve-error"))
+				})
+			})
+
+			Context("when rendering the templates fails", func() {
+				It("returns an error", func() {
+					mockReleaseJobResolver.EXPECT().Resolve("fake-release-job-name", "fake-release-name").Return(releaseJob, nil)
+					expectRender("/fake-rendered-job-path").Return(nil, errors.New("fake-render-error"))
+
+					err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-render-error"))
+
+					renderCall := fakeStage.PerformCalls[1].Stage.PerformCalls[0]
+					Expect(renderCall.Error.Error()).To(ContainSubstring("fake-render-error"))
+				})
+			})
+		})
+
+		Context("when extracting a release fails", func() {
+			It("returns an error without rendering", func() {
+				mockReleaseExtractor.EXPECT().Extract(releaseTarballPath).Return(nil, errors.New("fake-extract-error"))
+
+				err := newJobTemplatesRenderer().RenderJobTemplates(fakeStage, "fake-job-name", outputDir)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-extract-error"))
+				Expect(fakeStage.PerformCalls).To(HaveLen(1))
+			})
+		})
+	})
+})
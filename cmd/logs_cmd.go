@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type logsCmd struct {
+	logsFetcherProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (LogsFetcher, error)
+	ui                  biui.UI
+	fs                  boshsys.FileSystem
+	logger              boshlog.Logger
+	logTag              string
+}
+
+func NewLogsCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	logsFetcherProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (LogsFetcher, error),
+) Cmd {
+	return &logsCmd{
+		ui:                  ui,
+		fs:                  fs,
+		logsFetcherProvider: logsFetcherProvider,
+		logger:              logger,
+		logTag:              "logsCmd",
+	}
+}
+
+func (c *logsCmd) Name() string {
+	return "logs"
+}
+
+func (c *logsCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Download job logs from a deployed VM",
+		Usage:    instanceFlagUsage + " [--job <name>]... [--dir <path>] " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *logsCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, instance, jobNames, logsDir, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	logsAbsDir, err := filepath.Abs(logsDir)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting absolute path to logs directory '%s'", logsDir)
+	}
+
+	logsFetcher, err := c.logsFetcherProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return logsFetcher.FetchLogs(stage, instance, jobNames, logsAbsDir)
+}
+
+func (c *logsCmd) parseCmdInputs(args []string) (string, InstanceSelector, []string, string, manifestFlags, error) {
+	var instance InstanceSelector
+	var jobNames stringSliceFlag
+	var logsDir string
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.Var(&instance, "instance", "")
+	flagSet.Var(&jobNames, "job", "")
+	flagSet.StringVar(&logsDir, "dir", "logs", "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", instance, nil, "", flags, err
+	}
+
+	return positionalArgs[0], instance, jobNames, logsDir, flags, nil
+}
@@ -0,0 +1,127 @@
+package cmd_test
+
+import (
+	"path/filepath"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("LogsCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockLogsFetcher *mock_cmd.MockLogsFetcher
+			fs              *fakesys.FakeFileSystem
+			logger          boshlog.Logger
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newLogsCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.LogsFetcher, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				return mockLogsFetcher, nil
+			}
+
+			return bicmd.NewLogsCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockLogsFetcher = mock_cmd.NewMockLogsFetcher(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'logs'", func() {
+			Expect(newLogsCmd().Name()).To(Equal("logs"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newLogsCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("fetches the logs of all jobs into the logs directory", func() {
+				logsDir, err := filepath.Abs("logs")
+				Expect(err).ToNot(HaveOccurred())
+				mockLogsFetcher.EXPECT().FetchLogs(fakeStage, bicmd.InstanceSelector{}, []string(nil), logsDir).Return(nil)
+
+				err = newLogsCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("fetches the logs of the given jobs into the given directory", func() {
+				mockLogsFetcher.EXPECT().FetchLogs(fakeStage, bicmd.InstanceSelector{}, []string{"fake-job-1", "fake-job-2"}, "/fake-logs-dir").Return(nil)
+
+				err := newLogsCmd().Run(fakeStage, []string{"--job", "fake-job-1", deploymentManifestPath, "--job", "fake-job-2", "--dir", "/fake-logs-dir"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("fetches the logs of the given instance", func() {
+				mockLogsFetcher.EXPECT().FetchLogs(fakeStage, bicmd.InstanceSelector{JobName: "fake-job", Index: 1}, []string(nil), "/fake-logs-dir").Return(nil)
+
+				err := newLogsCmd().Run(fakeStage, []string{"--instance", "fake-job/1", "--dir", "/fake-logs-dir", deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns an error when the instance is not given as <job>/<index>", func() {
+				err := newLogsCmd().Run(fakeStage, []string{"--instance", "fake-job", deploymentManifestPath})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+			})
+
+			Context("when fetching the logs fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockLogsFetcher.EXPECT().FetchLogs(fakeStage, gomock.Any(), gomock.Any(), gomock.Any()).Return(err)
+
+					returnedErr := newLogsCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newLogsCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicrypto "github.com/cloudfoundry/bosh-init/crypto"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshcmd "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/fileutil"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type LogsFetcher interface {
+	// FetchLogs downloads the logs of the given jobs (all jobs if none are given) from the VM of the selected instance,
+	// the bootstrap instance if none is selected, and unpacks them into destinationDir.
+	FetchLogs(stage biui.Stage, instance InstanceSelector, jobNames []string, destinationDir string) error
+}
+
+func NewLogsFetcher(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logTag string,
+	logger boshlog.Logger,
+	deploymentStateService biconfig.DeploymentStateService,
+	instanceRepo biconfig.InstanceRepo,
+	agentClientFactory biagentclient.AgentClientFactory,
+	blobstoreFactory biblobstore.Factory,
+	compressor boshcmd.Compressor,
+	sha1Calculator bicrypto.SHA1Calculator,
+	deploymentManifestPath string,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+) LogsFetcher {
+	return &logsFetcher{
+		ui:                                      ui,
+		fs:                                      fs,
+		logTag:                                  logTag,
+		logger:                                  logger,
+		deploymentStateService:                  deploymentStateService,
+		instanceRepo:                            instanceRepo,
+		agentClientFactory:                      agentClientFactory,
+		blobstoreFactory:                        blobstoreFactory,
+		compressor:                              compressor,
+		sha1Calculator:                          sha1Calculator,
+		deploymentManifestPath:                  deploymentManifestPath,
+		releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+	}
+}
+
+type logsFetcher struct {
+	ui                                      biui.UI
+	fs                                      boshsys.FileSystem
+	logTag                                  string
+	logger                                  boshlog.Logger
+	deploymentStateService                  biconfig.DeploymentStateService
+	instanceRepo                            biconfig.InstanceRepo
+	agentClientFactory                      biagentclient.AgentClientFactory
+	blobstoreFactory                        biblobstore.Factory
+	compressor                              boshcmd.Compressor
+	sha1Calculator                          bicrypto.SHA1Calculator
+	deploymentManifestPath                  string
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
+}
+
+func (f *logsFetcher) FetchLogs(stage biui.Stage, instance InstanceSelector, jobNames []string, destinationDir string) error {
+	f.ui.PrintLinef("Deployment state: '%s'", f.deploymentStateService.Path())
+
+	if !f.deploymentStateService.Exists() {
+		f.ui.PrintLinef("No deployment state file found.")
+		return nil
+	}
+
+	deploymentState, err := f.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading deployment state")
+	}
+
+	instanceRecord, found, err := instance.find(f.instanceRepo)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		f.ui.PrintLinef("No deployed VM found.")
+		return nil
+	}
+
+	_, installationManifest, err := f.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(f.deploymentManifestPath)
+	if err != nil {
+		return err
+	}
+
+	mbusURL, err := instanceMbusURL(instanceRecord, installationManifest.Mbus)
+	if err != nil {
+		return err
+	}
+
+	f.logger.Debug(f.logTag, "Creating agent client...")
+	agentClient := f.agentClientFactory.NewAgentClient(deploymentState.DirectorID, mbusURL)
+
+	f.logger.Debug(f.logTag, "Creating blobstore client...")
+	blobstore, err := f.blobstoreFactory.Create(mbusURL)
+	if err != nil {
+		return bosherr.WrapError(err, "Creating blobstore client")
+	}
+
+	// job logs are filtered relative to the jobs log dir on the VM, one sub-directory per job
+	var filters []string
+	for _, jobName := range jobNames {
+		filters = append(filters, fmt.Sprintf("%s/**/*", jobName))
+	}
+
+	var logsBlobRef boshagentclient.BlobRef
+	stepName := fmt.Sprintf("Fetching logs from VM '%s'", instanceRecord.VMCID)
+	err = stage.Perform(stepName, func() error {
+		blobRef, err := agentClient.FetchLogs("job", filters)
+		if err != nil {
+			return bosherr.WrapError(err, "Fetching logs from the agent")
+		}
+		logsBlobRef = blobRef
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// the blob is left in the agent blobstore until deleted, whether or not the logs could be unpacked
+	defer func() {
+		deleteErr := blobstore.Delete(logsBlobRef.BlobstoreID)
+		if deleteErr != nil {
+			f.logger.Warn(f.logTag, "Deleting logs blob '%s': %s", logsBlobRef.BlobstoreID, deleteErr.Error())
+		}
+	}()
+
+	stepName = fmt.Sprintf("Downloading logs to '%s'", destinationDir)
+	return stage.Perform(stepName, func() error {
+		localBlob, err := blobstore.Get(logsBlobRef.BlobstoreID)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Downloading logs blob '%s'", logsBlobRef.BlobstoreID)
+		}
+		defer localBlob.DeleteSilently()
+
+		err = f.verifySHA1(localBlob.Path(), logsBlobRef)
+		if err != nil {
+			return err
+		}
+
+		err = f.fs.MkdirAll(destinationDir, os.ModePerm)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Creating logs directory '%s'", destinationDir)
+		}
+
+		err = f.compressor.DecompressFileToDir(localBlob.Path(), destinationDir, boshcmd.CompressorOptions{})
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Unpacking logs into '%s'", destinationDir)
+		}
+
+		return nil
+	})
+}
+
+func (f *logsFetcher) verifySHA1(path string, logsBlobRef boshagentclient.BlobRef) error {
+	if logsBlobRef.SHA1 == "" {
+		f.logger.Warn(f.logTag, "The agent did not report the sha1 of logs blob '%s', skipping verification", logsBlobRef.BlobstoreID)
+		return nil
+	}
+
+	sha1, err := f.sha1Calculator.Calculate(path)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Calculating sha1 of logs blob '%s'", logsBlobRef.BlobstoreID)
+	}
+
+	if sha1 != logsBlobRef.SHA1 {
+		return bosherr.Errorf("Logs blob '%s' has sha1 '%s', expected '%s'", logsBlobRef.BlobstoreID, sha1, logsBlobRef.SHA1)
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 // Automatically generated by MockGen. DO NOT EDIT!
-// Source: github.com/cloudfoundry/bosh-init/cmd (interfaces: DeploymentDeleter)
+// Source: github.com/cloudfoundry/bosh-init/cmd (interfaces: DeploymentDeleter,DeploymentPlanner,CloudChecker,DeploymentRecreator,JobController,LogsFetcher,SSHRunner,DeploymentValidator,JobTemplatesRenderer,ErrandRunner)
 
 package mocks
 
 import (
+	cmd "github.com/cloudfoundry/bosh-init/cmd"
+	cloudcheck "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
 	gomock "github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	ui "github.com/cloudfoundry/bosh-init/ui"
 )
@@ -38,3 +40,292 @@ func (_m *MockDeploymentDeleter) DeleteDeployment(_param0 ui.Stage) error {
 func (_mr *_MockDeploymentDeleterRecorder) DeleteDeployment(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "DeleteDeployment", arg0)
 }
+
+// Mock of DeploymentPlanner interface
+type MockDeploymentPlanner struct {
+	ctrl     *gomock.Controller
+	recorder *_MockDeploymentPlannerRecorder
+}
+
+// Recorder for MockDeploymentPlanner (not exported)
+type _MockDeploymentPlannerRecorder struct {
+	mock *MockDeploymentPlanner
+}
+
+func NewMockDeploymentPlanner(ctrl *gomock.Controller) *MockDeploymentPlanner {
+	mock := &MockDeploymentPlanner{ctrl: ctrl}
+	mock.recorder = &_MockDeploymentPlannerRecorder{mock}
+	return mock
+}
+
+func (_m *MockDeploymentPlanner) EXPECT() *_MockDeploymentPlannerRecorder {
+	return _m.recorder
+}
+
+func (_m *MockDeploymentPlanner) PlanDeployment(_param0 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "PlanDeployment", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDeploymentPlannerRecorder) PlanDeployment(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "PlanDeployment", arg0)
+}
+
+// Mock of CloudChecker interface
+type MockCloudChecker struct {
+	ctrl     *gomock.Controller
+	recorder *_MockCloudCheckerRecorder
+}
+
+// Recorder for MockCloudChecker (not exported)
+type _MockCloudCheckerRecorder struct {
+	mock *MockCloudChecker
+}
+
+func NewMockCloudChecker(ctrl *gomock.Controller) *MockCloudChecker {
+	mock := &MockCloudChecker{ctrl: ctrl}
+	mock.recorder = &_MockCloudCheckerRecorder{mock}
+	return mock
+}
+
+func (_m *MockCloudChecker) EXPECT() *_MockCloudCheckerRecorder {
+	return _m.recorder
+}
+
+func (_m *MockCloudChecker) CheckCloud(_param0 ui.Stage, _param1 map[cloudcheck.ProblemType]cloudcheck.Resolution, _param2 bool) error {
+	ret := _m.ctrl.Call(_m, "CheckCloud", _param0, _param1, _param2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockCloudCheckerRecorder) CheckCloud(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "CheckCloud", arg0, arg1, arg2)
+}
+
+// Mock of DeploymentRecreator interface
+type MockDeploymentRecreator struct {
+	ctrl     *gomock.Controller
+	recorder *_MockDeploymentRecreatorRecorder
+}
+
+// Recorder for MockDeploymentRecreator (not exported)
+type _MockDeploymentRecreatorRecorder struct {
+	mock *MockDeploymentRecreator
+}
+
+func NewMockDeploymentRecreator(ctrl *gomock.Controller) *MockDeploymentRecreator {
+	mock := &MockDeploymentRecreator{ctrl: ctrl}
+	mock.recorder = &_MockDeploymentRecreatorRecorder{mock}
+	return mock
+}
+
+func (_m *MockDeploymentRecreator) EXPECT() *_MockDeploymentRecreatorRecorder {
+	return _m.recorder
+}
+
+func (_m *MockDeploymentRecreator) RecreateDeployment(_param0 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "RecreateDeployment", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDeploymentRecreatorRecorder) RecreateDeployment(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RecreateDeployment", arg0)
+}
+
+// Mock of JobController interface
+type MockJobController struct {
+	ctrl     *gomock.Controller
+	recorder *_MockJobControllerRecorder
+}
+
+// Recorder for MockJobController (not exported)
+type _MockJobControllerRecorder struct {
+	mock *MockJobController
+}
+
+func NewMockJobController(ctrl *gomock.Controller) *MockJobController {
+	mock := &MockJobController{ctrl: ctrl}
+	mock.recorder = &_MockJobControllerRecorder{mock}
+	return mock
+}
+
+func (_m *MockJobController) EXPECT() *_MockJobControllerRecorder {
+	return _m.recorder
+}
+
+func (_m *MockJobController) StartJobs(_param0 ui.Stage, _param1 cmd.InstanceSelector) error {
+	ret := _m.ctrl.Call(_m, "StartJobs", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockJobControllerRecorder) StartJobs(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StartJobs", arg0, arg1)
+}
+
+func (_m *MockJobController) StopJobs(_param0 ui.Stage, _param1 cmd.InstanceSelector, _param2 bool) error {
+	ret := _m.ctrl.Call(_m, "StopJobs", _param0, _param1, _param2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockJobControllerRecorder) StopJobs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "StopJobs", arg0, arg1, arg2)
+}
+
+// Mock of LogsFetcher interface
+type MockLogsFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *_MockLogsFetcherRecorder
+}
+
+// Recorder for MockLogsFetcher (not exported)
+type _MockLogsFetcherRecorder struct {
+	mock *MockLogsFetcher
+}
+
+func NewMockLogsFetcher(ctrl *gomock.Controller) *MockLogsFetcher {
+	mock := &MockLogsFetcher{ctrl: ctrl}
+	mock.recorder = &_MockLogsFetcherRecorder{mock}
+	return mock
+}
+
+func (_m *MockLogsFetcher) EXPECT() *_MockLogsFetcherRecorder {
+	return _m.recorder
+}
+
+func (_m *MockLogsFetcher) FetchLogs(_param0 ui.Stage, _param1 cmd.InstanceSelector, _param2 []string, _param3 string) error {
+	ret := _m.ctrl.Call(_m, "FetchLogs", _param0, _param1, _param2, _param3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockLogsFetcherRecorder) FetchLogs(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FetchLogs", arg0, arg1, arg2, arg3)
+}
+
+// Mock of SSHRunner interface
+type MockSSHRunner struct {
+	ctrl     *gomock.Controller
+	recorder *_MockSSHRunnerRecorder
+}
+
+// Recorder for MockSSHRunner (not exported)
+type _MockSSHRunnerRecorder struct {
+	mock *MockSSHRunner
+}
+
+func NewMockSSHRunner(ctrl *gomock.Controller) *MockSSHRunner {
+	mock := &MockSSHRunner{ctrl: ctrl}
+	mock.recorder = &_MockSSHRunnerRecorder{mock}
+	return mock
+}
+
+func (_m *MockSSHRunner) EXPECT() *_MockSSHRunnerRecorder {
+	return _m.recorder
+}
+
+func (_m *MockSSHRunner) RunSSH(_param0 cmd.InstanceSelector, _param1 string) error {
+	ret := _m.ctrl.Call(_m, "RunSSH", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockSSHRunnerRecorder) RunSSH(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunSSH", arg0, arg1)
+}
+
+// Mock of DeploymentValidator interface
+type MockDeploymentValidator struct {
+	ctrl     *gomock.Controller
+	recorder *_MockDeploymentValidatorRecorder
+}
+
+// Recorder for MockDeploymentValidator (not exported)
+type _MockDeploymentValidatorRecorder struct {
+	mock *MockDeploymentValidator
+}
+
+func NewMockDeploymentValidator(ctrl *gomock.Controller) *MockDeploymentValidator {
+	mock := &MockDeploymentValidator{ctrl: ctrl}
+	mock.recorder = &_MockDeploymentValidatorRecorder{mock}
+	return mock
+}
+
+func (_m *MockDeploymentValidator) EXPECT() *_MockDeploymentValidatorRecorder {
+	return _m.recorder
+}
+
+func (_m *MockDeploymentValidator) ValidateDeployment(_param0 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "ValidateDeployment", _param0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDeploymentValidatorRecorder) ValidateDeployment(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "ValidateDeployment", arg0)
+}
+
+// Mock of JobTemplatesRenderer interface
+type MockJobTemplatesRenderer struct {
+	ctrl     *gomock.Controller
+	recorder *_MockJobTemplatesRendererRecorder
+}
+
+// Recorder for MockJobTemplatesRenderer (not exported)
+type _MockJobTemplatesRendererRecorder struct {
+	mock *MockJobTemplatesRenderer
+}
+
+func NewMockJobTemplatesRenderer(ctrl *gomock.Controller) *MockJobTemplatesRenderer {
+	mock := &MockJobTemplatesRenderer{ctrl: ctrl}
+	mock.recorder = &_MockJobTemplatesRendererRecorder{mock}
+	return mock
+}
+
+func (_m *MockJobTemplatesRenderer) EXPECT() *_MockJobTemplatesRendererRecorder {
+	return _m.recorder
+}
+
+func (_m *MockJobTemplatesRenderer) RenderJobTemplates(_param0 ui.Stage, _param1 string, _param2 string) error {
+	ret := _m.ctrl.Call(_m, "RenderJobTemplates", _param0, _param1, _param2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockJobTemplatesRendererRecorder) RenderJobTemplates(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RenderJobTemplates", arg0, arg1, arg2)
+}
+
+// Mock of ErrandRunner interface
+type MockErrandRunner struct {
+	ctrl     *gomock.Controller
+	recorder *_MockErrandRunnerRecorder
+}
+
+// Recorder for MockErrandRunner (not exported)
+type _MockErrandRunnerRecorder struct {
+	mock *MockErrandRunner
+}
+
+func NewMockErrandRunner(ctrl *gomock.Controller) *MockErrandRunner {
+	mock := &MockErrandRunner{ctrl: ctrl}
+	mock.recorder = &_MockErrandRunnerRecorder{mock}
+	return mock
+}
+
+func (_m *MockErrandRunner) EXPECT() *_MockErrandRunnerRecorder {
+	return _m.recorder
+}
+
+func (_m *MockErrandRunner) RunErrand(_param0 ui.Stage, _param1 string, _param2 bool) error {
+	ret := _m.ctrl.Call(_m, "RunErrand", _param0, _param1, _param2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockErrandRunnerRecorder) RunErrand(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunErrand", arg0, arg1, arg2)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type DeploymentPlanner interface {
+	PlanDeployment(stage biui.Stage) (err error)
+}
+
+type planCmd struct {
+	deploymentPlannerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPlanner, error)
+	ui                        biui.UI
+	fs                        boshsys.FileSystem
+	logger                    boshlog.Logger
+	logTag                    string
+}
+
+func NewPlanCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	deploymentPlannerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPlanner, error),
+) Cmd {
+	return &planCmd{
+		ui:                        ui,
+		fs:                        fs,
+		deploymentPlannerProvider: deploymentPlannerProvider,
+		logger:                    logger,
+		logTag:                    "planCmd",
+	}
+}
+
+func (c *planCmd) Name() string {
+	return "plan"
+}
+
+func (c *planCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Show the changes that deploy would make",
+		Usage:    manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *planCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	deploymentPlanner, err := c.deploymentPlannerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return deploymentPlanner.PlanDeployment(stage)
+}
+
+func (c *planCmd) parseCmdInputs(args []string) (string, manifestFlags, error) {
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", flags, err
+	}
+
+	return positionalArgs[0], flags, nil
+}
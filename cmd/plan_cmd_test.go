@@ -0,0 +1,122 @@
+package cmd_test
+
+import (
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("PlanCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockDeploymentPlanner *mock_cmd.MockDeploymentPlanner
+			fs                    *fakesys.FakeFileSystem
+			logger                boshlog.Logger
+			providedInterpolator  biinterpolation.Interpolator
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newPlanCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentPlanner, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				providedInterpolator = manifestInterpolator
+				return mockDeploymentPlanner, nil
+			}
+
+			return bicmd.NewPlanCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockDeploymentPlanner = mock_cmd.NewMockDeploymentPlanner(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'plan'", func() {
+			Expect(newPlanCmd().Name()).To(Equal("plan"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newPlanCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("plans the deployment", func() {
+				mockDeploymentPlanner.EXPECT().PlanDeployment(fakeStage).Return(nil)
+
+				err := newPlanCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("generates the manifest variables without saving them to the --vars-store", func() {
+				fs.WriteFileString("/creds.yml", "mbus_password: stored-mbus-password\n")
+				mockDeploymentPlanner.EXPECT().PlanDeployment(fakeStage).Return(nil)
+
+				err := newPlanCmd().Run(fakeStage, []string{"--vars-store", "/creds.yml", deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+
+				interpolatedContents, err := providedInterpolator.Interpolate([]byte("mbus: ((mbus_password))\nnats: ((nats_password))\nvariables:\n- {name: nats_password, type: password}\n"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(interpolatedContents)).To(ContainSubstring("mbus: stored-mbus-password\n"))
+				Expect(string(interpolatedContents)).To(MatchRegexp("nats: [a-z0-9]{20}\n"))
+
+				contents, err := fs.ReadFileString("/creds.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contents).To(Equal("mbus_password: stored-mbus-password\n"))
+			})
+
+			Context("when planning the deployment fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockDeploymentPlanner.EXPECT().PlanDeployment(fakeStage).Return(err)
+
+					returnedErr := newPlanCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newPlanCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
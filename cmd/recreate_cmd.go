@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type DeploymentRecreator interface {
+	RecreateDeployment(stage biui.Stage) (err error)
+}
+
+type recreateCmd struct {
+	deploymentRecreatorProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentRecreator, error)
+	ui                          biui.UI
+	fs                          boshsys.FileSystem
+	logger                      boshlog.Logger
+	logTag                      string
+}
+
+func NewRecreateCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	deploymentRecreatorProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentRecreator, error),
+) Cmd {
+	return &recreateCmd{
+		ui:                          ui,
+		fs:                          fs,
+		deploymentRecreatorProvider: deploymentRecreatorProvider,
+		logger:                      logger,
+		logTag:                      "recreateCmd",
+	}
+}
+
+func (c *recreateCmd) Name() string {
+	return "recreate"
+}
+
+func (c *recreateCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Recreate the deployed VM without changing the deployment",
+		Usage:    manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *recreateCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	deploymentRecreator, err := c.deploymentRecreatorProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return deploymentRecreator.RecreateDeployment(stage)
+}
+
+func (c *recreateCmd) parseCmdInputs(args []string) (string, manifestFlags, error) {
+	flags := manifestFlags{saveVarsStore: true}
+
+	flagSet := newFlagSet(c.Name())
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", flags, err
+	}
+
+	return positionalArgs[0], flags, nil
+}
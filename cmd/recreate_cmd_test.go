@@ -0,0 +1,121 @@
+package cmd_test
+
+import (
+	"fmt"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("RecreateCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockDeploymentRecreator *mock_cmd.MockDeploymentRecreator
+			fs                      *fakesys.FakeFileSystem
+			logger                  boshlog.Logger
+			providedInterpolator    biinterpolation.Interpolator
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newRecreateCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.DeploymentRecreator, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				providedInterpolator = manifestInterpolator
+				return mockDeploymentRecreator, nil
+			}
+
+			return bicmd.NewRecreateCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockDeploymentRecreator = mock_cmd.NewMockDeploymentRecreator(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'recreate'", func() {
+			Expect(newRecreateCmd().Name()).To(Equal("recreate"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newRecreateCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("recreates the deployment", func() {
+				mockDeploymentRecreator.EXPECT().RecreateDeployment(fakeStage).Return(nil)
+
+				err := newRecreateCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("saves the generated manifest variables to the --vars-store", func() {
+				mockDeploymentRecreator.EXPECT().RecreateDeployment(fakeStage).Return(nil)
+
+				err := newRecreateCmd().Run(fakeStage, []string{"--vars-store", "/creds.yml", deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+
+				interpolatedContents, err := providedInterpolator.Interpolate([]byte("nats: ((nats_password))\nvariables:\n- {name: nats_password, type: password}\n"))
+				Expect(err).ToNot(HaveOccurred())
+
+				storedVariables, err := biinterpolation.NewFileVarsStore("/creds.yml", fs).Load()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(interpolatedContents)).To(ContainSubstring(fmt.Sprintf("nats: %s\n", storedVariables["nats_password"])))
+			})
+
+			Context("when recreating the deployment fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockDeploymentRecreator.EXPECT().RecreateDeployment(fakeStage).Return(err)
+
+					returnedErr := newRecreateCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newRecreateCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
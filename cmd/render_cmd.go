@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type renderCmd struct {
+	jobTemplatesRendererProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobTemplatesRenderer, error)
+	ui                           biui.UI
+	fs                           boshsys.FileSystem
+	logger                       boshlog.Logger
+	logTag                       string
+}
+
+func NewRenderCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	jobTemplatesRendererProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobTemplatesRenderer, error),
+) Cmd {
+	return &renderCmd{
+		ui:                           ui,
+		fs:                           fs,
+		jobTemplatesRendererProvider: jobTemplatesRendererProvider,
+		logger:                       logger,
+		logTag:                       "renderCmd",
+	}
+}
+
+func (c *renderCmd) Name() string {
+	return "render"
+}
+
+func (c *renderCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Render job templates locally without deploying",
+		Usage:    "[--job <name>] [--out <path>] " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *renderCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, jobName, outputDir, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	outputAbsDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting absolute path to output directory '%s'", outputDir)
+	}
+
+	jobTemplatesRenderer, err := c.jobTemplatesRendererProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return jobTemplatesRenderer.RenderJobTemplates(stage, jobName, outputAbsDir)
+}
+
+func (c *renderCmd) parseCmdInputs(args []string) (string, string, string, manifestFlags, error) {
+	var jobName string
+	var outputDir string
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.StringVar(&jobName, "job", "", "")
+	flagSet.StringVar(&outputDir, "out", "rendered", "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", "", "", flags, err
+	}
+
+	return positionalArgs[0], jobName, outputDir, flags, nil
+}
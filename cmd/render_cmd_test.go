@@ -0,0 +1,114 @@
+package cmd_test
+
+import (
+	"path/filepath"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("RenderCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockJobTemplatesRenderer *mock_cmd.MockJobTemplatesRenderer
+			fs                       *fakesys.FakeFileSystem
+			logger                   boshlog.Logger
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newRenderCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.JobTemplatesRenderer, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				return mockJobTemplatesRenderer, nil
+			}
+
+			return bicmd.NewRenderCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockJobTemplatesRenderer = mock_cmd.NewMockJobTemplatesRenderer(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'render'", func() {
+			Expect(newRenderCmd().Name()).To(Equal("render"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newRenderCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("renders all jobs into the default output directory", func() {
+				outputDir, err := filepath.Abs("rendered")
+				Expect(err).ToNot(HaveOccurred())
+				mockJobTemplatesRenderer.EXPECT().RenderJobTemplates(fakeStage, "", outputDir).Return(nil)
+
+				err = newRenderCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("renders the given job into the given directory", func() {
+				mockJobTemplatesRenderer.EXPECT().RenderJobTemplates(fakeStage, "fake-job", "/fake-output-dir").Return(nil)
+
+				err := newRenderCmd().Run(fakeStage, []string{"--job", "fake-job", deploymentManifestPath, "--out", "/fake-output-dir"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("when rendering the job templates fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockJobTemplatesRenderer.EXPECT().RenderJobTemplates(fakeStage, gomock.Any(), gomock.Any()).Return(err)
+
+					returnedErr := newRenderCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newRenderCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type runErrandCmd struct {
+	errandRunnerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (ErrandRunner, error)
+	ui                   biui.UI
+	fs                   boshsys.FileSystem
+	logger               boshlog.Logger
+	logTag               string
+}
+
+func NewRunErrandCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	errandRunnerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (ErrandRunner, error),
+) Cmd {
+	return &runErrandCmd{
+		ui:                   ui,
+		fs:                   fs,
+		errandRunnerProvider: errandRunnerProvider,
+		logger:               logger,
+		logTag:               "runErrandCmd",
+	}
+}
+
+func (c *runErrandCmd) Name() string {
+	return "run-errand"
+}
+
+func (c *runErrandCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Run an errand job on a new VM and print its output",
+		Usage:    "[--keep-alive] " + manifestFlagsUsage + " <deployment_manifest_path> <errand_name>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *runErrandCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, errandName, keepAlive, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	errandRunner, err := c.errandRunnerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return errandRunner.RunErrand(stage, errandName, keepAlive)
+}
+
+func (c *runErrandCmd) parseCmdInputs(args []string) (string, string, bool, manifestFlags, error) {
+	var keepAlive bool
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.BoolVar(&keepAlive, "keep-alive", false, "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 2, c.logger, c.logTag)
+	if err != nil {
+		return "", "", false, flags, err
+	}
+
+	return positionalArgs[0], positionalArgs[1], keepAlive, flags, nil
+}
@@ -0,0 +1,110 @@
+package cmd_test
+
+import (
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("RunErrandCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockErrandRunner *mock_cmd.MockErrandRunner
+			fs               *fakesys.FakeFileSystem
+			logger           boshlog.Logger
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newRunErrandCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.ErrandRunner, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				return mockErrandRunner, nil
+			}
+
+			return bicmd.NewRunErrandCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockErrandRunner = mock_cmd.NewMockErrandRunner(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'run-errand'", func() {
+			Expect(newRunErrandCmd().Name()).To(Equal("run-errand"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newRunErrandCmd().Run(fakeStage, []string{"/garbage", "fake-errand"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("runs the errand", func() {
+				mockErrandRunner.EXPECT().RunErrand(fakeStage, "fake-errand", false).Return(nil)
+
+				err := newRunErrandCmd().Run(fakeStage, []string{deploymentManifestPath, "fake-errand"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("keeps the errand VM when --keep-alive is given", func() {
+				mockErrandRunner.EXPECT().RunErrand(fakeStage, "fake-errand", true).Return(nil)
+
+				err := newRunErrandCmd().Run(fakeStage, []string{"--keep-alive", deploymentManifestPath, "fake-errand"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("when running the errand fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockErrandRunner.EXPECT().RunErrand(fakeStage, "fake-errand", false).Return(err)
+
+					returnedErr := newRunErrandCmd().Run(fakeStage, []string{deploymentManifestPath, "fake-errand"})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 2 arguments are given", func() {
+			command := newRunErrandCmd()
+
+			err := command.Run(fakeStage, []string{deploymentManifestPath})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2", "3"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
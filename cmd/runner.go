@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"strings"
+
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
@@ -48,3 +50,29 @@ func (r *Runner) processArgs(args []string) []string {
 
 	return args
 }
+
+// ExtractCPILogPath removes the global --cpi-log option from the arguments of bosh-init, returning the path it was given.
+// Like the other global options, it is given before the command name, as '--cpi-log <path>' or '--cpi-log=<path>';
+// the arguments from the command name on are left to the command.
+func ExtractCPILogPath(args []string) (string, []string) {
+	cpiLogPath := ""
+	otherArgs := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return cpiLogPath, append(otherArgs, args[i:]...)
+		}
+
+		if arg == "--cpi-log" && i+1 < len(args) {
+			cpiLogPath = args[i+1]
+			i++
+		} else if strings.HasPrefix(arg, "--cpi-log=") {
+			cpiLogPath = strings.TrimPrefix(arg, "--cpi-log=")
+		} else {
+			otherArgs = append(otherArgs, arg)
+		}
+	}
+
+	return cpiLogPath, otherArgs
+}
@@ -91,3 +91,39 @@ var _ = Describe("Runner", func() {
 		})
 	})
 })
+
+var _ = Describe("ExtractCPILogPath", func() {
+	It("removes the --cpi-log option and its value from the arguments before the command", func() {
+		cpiLogPath, args := bicmd.ExtractCPILogPath([]string{"--cpi-log", "/fake/cpi.log", "deploy", "/fake/manifest_path"})
+		Expect(cpiLogPath).To(Equal("/fake/cpi.log"))
+		Expect(args).To(Equal([]string{"deploy", "/fake/manifest_path"}))
+	})
+
+	It("accepts the --cpi-log=<path> form", func() {
+		cpiLogPath, args := bicmd.ExtractCPILogPath([]string{"--cpi-log=/fake/cpi.log", "deploy", "/fake/manifest_path"})
+		Expect(cpiLogPath).To(Equal("/fake/cpi.log"))
+		Expect(args).To(Equal([]string{"deploy", "/fake/manifest_path"}))
+	})
+
+	It("keeps the other global options", func() {
+		cpiLogPath, args := bicmd.ExtractCPILogPath([]string{"--help", "--cpi-log", "/fake/cpi.log", "deploy"})
+		Expect(cpiLogPath).To(Equal("/fake/cpi.log"))
+		Expect(args).To(Equal([]string{"--help", "deploy"}))
+	})
+
+	It("leaves the arguments of the command unchanged", func() {
+		cpiLogPath, args := bicmd.ExtractCPILogPath([]string{"deploy", "/fake/manifest_path", "--cpi-log", "/fake/cpi.log"})
+		Expect(cpiLogPath).To(BeEmpty())
+		Expect(args).To(Equal([]string{"deploy", "/fake/manifest_path", "--cpi-log", "/fake/cpi.log"}))
+
+		cpiLogPath, args = bicmd.ExtractCPILogPath([]string{"deploy", "--", "--cpi-log=/fake/cpi.log"})
+		Expect(cpiLogPath).To(BeEmpty())
+		Expect(args).To(Equal([]string{"deploy", "--", "--cpi-log=/fake/cpi.log"}))
+	})
+
+	It("returns no path when the option is not given", func() {
+		cpiLogPath, args := bicmd.ExtractCPILogPath([]string{"deploy", "/fake/manifest_path"})
+		Expect(cpiLogPath).To(BeEmpty())
+		Expect(args).To(Equal([]string{"deploy", "/fake/manifest_path"}))
+	})
+})
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type sshCmd struct {
+	sshRunnerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (SSHRunner, error)
+	ui                biui.UI
+	fs                boshsys.FileSystem
+	logger            boshlog.Logger
+	logTag            string
+}
+
+func NewSSHCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	sshRunnerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (SSHRunner, error),
+) Cmd {
+	return &sshCmd{
+		ui:                ui,
+		fs:                fs,
+		sshRunnerProvider: sshRunnerProvider,
+		logger:            logger,
+		logTag:            "sshCmd",
+	}
+}
+
+func (c *sshCmd) Name() string {
+	return "ssh"
+}
+
+func (c *sshCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Open a shell or run a command on a deployed VM over SSH",
+		Usage:    instanceFlagUsage + " [-c <command>] " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *sshCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, instance, command, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	sshRunner, err := c.sshRunnerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return sshRunner.RunSSH(instance, command)
+}
+
+func (c *sshCmd) parseCmdInputs(args []string) (string, InstanceSelector, string, manifestFlags, error) {
+	var instance InstanceSelector
+	var command string
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.Var(&instance, "instance", "")
+	flagSet.StringVar(&command, "c", "", "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", instance, "", flags, err
+	}
+
+	return positionalArgs[0], instance, command, flags, nil
+}
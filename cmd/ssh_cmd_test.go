@@ -0,0 +1,117 @@
+package cmd_test
+
+import (
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	mock_cmd "github.com/cloudfoundry/bosh-init/cmd/mocks"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("SSHCmd", func() {
+	var mockCtrl *gomock.Controller
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Describe("Run", func() {
+		var (
+			mockSSHRunner *mock_cmd.MockSSHRunner
+			fs            *fakesys.FakeFileSystem
+			logger        boshlog.Logger
+
+			fakeUI                 *fakebiui.FakeUI
+			fakeStage              *fakebiui.FakeStage
+			deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+		)
+
+		var newSSHCmd = func() bicmd.Cmd {
+			doGetFunc := func(manifestPath string, manifestInterpolator biinterpolation.Interpolator) (bicmd.SSHRunner, error) {
+				Expect(manifestPath).To(Equal(deploymentManifestPath))
+				return mockSSHRunner, nil
+			}
+
+			return bicmd.NewSSHCmd(fakeUI, fs, logger, doGetFunc)
+		}
+
+		BeforeEach(func() {
+			mockSSHRunner = mock_cmd.NewMockSSHRunner(mockCtrl)
+			fs = fakesys.NewFakeFileSystem()
+			logger = boshlog.NewLogger(boshlog.LevelNone)
+			fakeUI = &fakebiui.FakeUI{}
+			fakeStage = fakebiui.NewFakeStage()
+			fs.WriteFileString(deploymentManifestPath, `---manifest-content`)
+		})
+
+		It("has the name 'ssh'", func() {
+			Expect(newSSHCmd().Name()).To(Equal("ssh"))
+		})
+
+		Context("when the deployment manifest does not exist", func() {
+			It("returns an error", func() {
+				err := newSSHCmd().Run(fakeStage, []string{"/garbage"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("Deployment manifest does not exist at '/garbage'"))
+				Expect(fakeUI.Errors).To(ContainElement("Deployment '/garbage' does not exist"))
+			})
+		})
+
+		Context("when the deployment manifest exists", func() {
+			It("opens an interactive shell", func() {
+				mockSSHRunner.EXPECT().RunSSH(bicmd.InstanceSelector{}, "").Return(nil)
+
+				err := newSSHCmd().Run(fakeStage, []string{deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeUI.Said).To(ContainElement("Deployment manifest: '/deployment-dir/fake-deployment-manifest.yml'"))
+			})
+
+			It("runs the given command", func() {
+				mockSSHRunner.EXPECT().RunSSH(bicmd.InstanceSelector{}, "sudo monit summary").Return(nil)
+
+				err := newSSHCmd().Run(fakeStage, []string{deploymentManifestPath, "-c", "sudo monit summary"})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("opens a shell on the given instance", func() {
+				mockSSHRunner.EXPECT().RunSSH(bicmd.InstanceSelector{JobName: "fake-job", Index: 1}, "").Return(nil)
+
+				err := newSSHCmd().Run(fakeStage, []string{"--instance", "fake-job/1", deploymentManifestPath})
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			Context("when running ssh fails", func() {
+				It("returns the error", func() {
+					err := bosherr.Error("boom")
+					mockSSHRunner.EXPECT().RunSSH(bicmd.InstanceSelector{}, "").Return(err)
+
+					returnedErr := newSSHCmd().Run(fakeStage, []string{deploymentManifestPath})
+					Expect(returnedErr).To(Equal(err))
+				})
+			})
+		})
+
+		It("returns err unless exactly 1 argument is given", func() {
+			command := newSSHCmd()
+
+			err := command.Run(fakeStage, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+			err = command.Run(fakeStage, []string{"1", "2"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+		})
+	})
+})
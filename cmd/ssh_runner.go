@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type SSHRunner interface {
+	// RunSSH opens an interactive shell on the VM of the selected instance, the bootstrap instance if none is selected,
+	// or runs the command if it is not empty. Every instance is reached with the credentials of cloud_provider.ssh_tunnel.
+	RunSSH(instance InstanceSelector, command string) error
+}
+
+func NewSSHRunner(
+	ui biui.UI,
+	logTag string,
+	logger boshlog.Logger,
+	deploymentStateService biconfig.DeploymentStateService,
+	instanceRepo biconfig.InstanceRepo,
+	sshTunnelFactory bisshtunnel.Factory,
+	deploymentManifestPath string,
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser,
+) SSHRunner {
+	return &sshRunner{
+		ui:                                      ui,
+		logTag:                                  logTag,
+		logger:                                  logger,
+		deploymentStateService:                  deploymentStateService,
+		instanceRepo:                            instanceRepo,
+		sshTunnelFactory:                        sshTunnelFactory,
+		deploymentManifestPath:                  deploymentManifestPath,
+		releaseSetAndInstallationManifestParser: releaseSetAndInstallationManifestParser,
+	}
+}
+
+type sshRunner struct {
+	ui                                      biui.UI
+	logTag                                  string
+	logger                                  boshlog.Logger
+	deploymentStateService                  biconfig.DeploymentStateService
+	instanceRepo                            biconfig.InstanceRepo
+	sshTunnelFactory                        bisshtunnel.Factory
+	deploymentManifestPath                  string
+	releaseSetAndInstallationManifestParser ReleaseSetAndInstallationManifestParser
+}
+
+func (r *sshRunner) RunSSH(instance InstanceSelector, command string) error {
+	r.ui.PrintLinef("Deployment state: '%s'", r.deploymentStateService.Path())
+
+	if !r.deploymentStateService.Exists() {
+		r.ui.PrintLinef("No deployment state file found.")
+		return nil
+	}
+
+	instanceRecord, found, err := instance.find(r.instanceRepo)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		r.ui.PrintLinef("No deployed VM found.")
+		return nil
+	}
+
+	_, installationManifest, err := r.releaseSetAndInstallationManifestParser.ReleaseSetAndInstallationManifest(r.deploymentManifestPath)
+	if err != nil {
+		return err
+	}
+
+	sshTunnelConfig := installationManifest.Registry.SSHTunnel
+	if sshTunnelConfig.Host == "" {
+		r.ui.ErrorLinef("SSH requires 'cloud_provider.ssh_tunnel' to be set in the deployment manifest")
+		return bosherr.Error("Missing cloud_provider.ssh_tunnel in deployment manifest")
+	}
+
+	// the bootstrap instance has no host of its own
+	host := instanceRecord.Host
+	if host == "" {
+		host = sshTunnelConfig.Host
+	}
+
+	r.logger.Debug(r.logTag, "Opening ssh session to VM '%s' at '%s'", instanceRecord.VMCID, host)
+	sshSession := r.sshTunnelFactory.NewSSHSession(bisshtunnel.Options{
+		Host:       host,
+		Port:       sshTunnelConfig.Port,
+		User:       sshTunnelConfig.User,
+		Password:   sshTunnelConfig.Password,
+		PrivateKey: sshTunnelConfig.PrivateKey,
+	})
+
+	return sshSession.Run(command)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type startCmd struct {
+	jobControllerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error)
+	ui                    biui.UI
+	fs                    boshsys.FileSystem
+	logger                boshlog.Logger
+	logTag                string
+}
+
+func NewStartCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	jobControllerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error),
+) Cmd {
+	return &startCmd{
+		ui:                    ui,
+		fs:                    fs,
+		jobControllerProvider: jobControllerProvider,
+		logger:                logger,
+		logTag:                "startCmd",
+	}
+}
+
+func (c *startCmd) Name() string {
+	return "start"
+}
+
+func (c *startCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Start the jobs on the deployed VMs",
+		Usage:    instanceFlagUsage + " " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *startCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, instance, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	jobController, err := c.jobControllerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return jobController.StartJobs(stage, instance)
+}
+
+func (c *startCmd) parseCmdInputs(args []string) (string, InstanceSelector, manifestFlags, error) {
+	var instance InstanceSelector
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.Var(&instance, "instance", "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", instance, flags, err
+	}
+
+	return positionalArgs[0], instance, flags, nil
+}
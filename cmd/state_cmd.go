@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type stateCmd struct {
+	ui                             biui.UI
+	deploymentStateServiceProvider func(deploymentManifestPath string) biconfig.DeploymentStateService
+	deploymentStateValidator       biconfig.DeploymentStateValidator
+	logger                         boshlog.Logger
+	logTag                         string
+}
+
+func NewStateCmd(
+	ui biui.UI,
+	deploymentStateServiceProvider func(deploymentManifestPath string) biconfig.DeploymentStateService,
+	deploymentStateValidator biconfig.DeploymentStateValidator,
+	logger boshlog.Logger,
+) Cmd {
+	return &stateCmd{
+		ui:                             ui,
+		deploymentStateServiceProvider: deploymentStateServiceProvider,
+		deploymentStateValidator:       deploymentStateValidator,
+		logger:                         logger,
+		logTag:                         "stateCmd",
+	}
+}
+
+func (c *stateCmd) Name() string {
+	return "state"
+}
+
+func (c *stateCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Show the deployment state",
+		Usage:    "[--json] <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+type stateOutput struct {
+	DirectorID      string                `json:"director_id"`
+	InstallationID  string                `json:"installation_id"`
+	ManifestSHA1    string                `json:"manifest_sha1"`
+	VMs             []stateVMOutput       `json:"vms"`
+	Disks           []stateDiskOutput     `json:"disks"`
+	Stemcells       []stateStemcellOutput `json:"stemcells"`
+	Releases        []stateReleaseOutput  `json:"releases"`
+	Inconsistencies []string              `json:"inconsistencies"`
+}
+
+type stateVMOutput struct {
+	Job     string `json:"job"`
+	Index   int    `json:"index"`
+	CID     string `json:"cid"`
+	Current bool   `json:"current"`
+}
+
+type stateDiskOutput struct {
+	ID      string `json:"id"`
+	CID     string `json:"cid"`
+	Size    int    `json:"size"`
+	Current bool   `json:"current"`
+}
+
+type stateStemcellOutput struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	CID     string `json:"cid"`
+	Current bool   `json:"current"`
+}
+
+type stateReleaseOutput struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Current bool   `json:"current"`
+}
+
+func (c *stateCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, printJSON, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, err := absDeploymentManifestPath(deploymentManifestPath, c.ui)
+	if err != nil {
+		return err
+	}
+
+	deploymentStateService := c.deploymentStateServiceProvider(manifestAbsFilePath)
+	if !deploymentStateService.Exists() {
+		c.ui.ErrorLinef("Deployment state '%s' does not exist", deploymentStateService.Path())
+		return bosherr.Errorf("Deployment state does not exist at '%s'", deploymentStateService.Path())
+	}
+
+	deploymentState, err := deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading deployment state")
+	}
+
+	output := c.buildOutput(deploymentState)
+
+	if printJSON {
+		jsonContent, err := json.MarshalIndent(output, "", "    ")
+		if err != nil {
+			return bosherr.WrapError(err, "Marshalling deployment state into JSON")
+		}
+		c.ui.PrintLinef("%s", jsonContent)
+		return nil
+	}
+
+	c.ui.PrintLinef("Deployment state: '%s'", deploymentStateService.Path())
+	c.printTable(output)
+
+	return nil
+}
+
+func (c *stateCmd) buildOutput(deploymentState biconfig.DeploymentState) stateOutput {
+	output := stateOutput{
+		DirectorID:      deploymentState.DirectorID,
+		InstallationID:  deploymentState.InstallationID,
+		ManifestSHA1:    deploymentState.CurrentManifestSHA1,
+		VMs:             []stateVMOutput{},
+		Disks:           []stateDiskOutput{},
+		Stemcells:       []stateStemcellOutput{},
+		Releases:        []stateReleaseOutput{},
+		Inconsistencies: []string{},
+	}
+
+	currentDiskIDs := map[string]bool{}
+
+	for _, instanceRecord := range deploymentState.Instances {
+		if instanceRecord.VMCID != "" {
+			output.VMs = append(output.VMs, stateVMOutput{
+				Job:     instanceRecord.Job,
+				Index:   instanceRecord.Index,
+				CID:     instanceRecord.VMCID,
+				Current: true,
+			})
+		}
+		if instanceRecord.DiskID != "" {
+			currentDiskIDs[instanceRecord.DiskID] = true
+		}
+	}
+
+	for _, diskRecord := range deploymentState.Disks {
+		output.Disks = append(output.Disks, stateDiskOutput{
+			ID:      diskRecord.ID,
+			CID:     diskRecord.CID,
+			Size:    diskRecord.Size,
+			Current: currentDiskIDs[diskRecord.ID],
+		})
+	}
+
+	for _, stemcellRecord := range deploymentState.Stemcells {
+		output.Stemcells = append(output.Stemcells, stateStemcellOutput{
+			ID:      stemcellRecord.ID,
+			Name:    stemcellRecord.Name,
+			Version: stemcellRecord.Version,
+			CID:     stemcellRecord.CID,
+			Current: stemcellRecord.ID == deploymentState.CurrentStemcellID,
+		})
+	}
+
+	currentReleaseIDs := map[string]bool{}
+	for _, releaseID := range deploymentState.CurrentReleaseIDs {
+		currentReleaseIDs[releaseID] = true
+	}
+
+	for _, releaseRecord := range deploymentState.Releases {
+		output.Releases = append(output.Releases, stateReleaseOutput{
+			ID:      releaseRecord.ID,
+			Name:    releaseRecord.Name,
+			Version: releaseRecord.Version,
+			Current: currentReleaseIDs[releaseRecord.ID],
+		})
+	}
+
+	err := c.deploymentStateValidator.Validate(deploymentState)
+	if err != nil {
+		if multiErr, ok := err.(bosherr.MultiError); ok {
+			for _, e := range multiErr.Errors {
+				output.Inconsistencies = append(output.Inconsistencies, e.Error())
+			}
+		} else {
+			output.Inconsistencies = append(output.Inconsistencies, err.Error())
+		}
+	}
+
+	return output
+}
+
+func (c *stateCmd) printTable(output stateOutput) {
+	c.ui.PrintLinef("")
+	c.printRows([][]string{
+		{"Director ID", output.DirectorID},
+		{"Installation ID", output.InstallationID},
+		{"Manifest SHA1", output.ManifestSHA1},
+	})
+
+	c.ui.PrintLinef("")
+	c.ui.PrintLinef("VMs")
+	rows := [][]string{{"Instance", "CID", "Current"}}
+	for _, vm := range output.VMs {
+		rows = append(rows, []string{c.instanceName(vm), vm.CID, c.currentMarker(vm.Current)})
+	}
+	c.printRows(rows)
+
+	c.ui.PrintLinef("")
+	c.ui.PrintLinef("Disks")
+	rows = [][]string{{"ID", "CID", "Size", "Current"}}
+	for _, disk := range output.Disks {
+		rows = append(rows, []string{disk.ID, disk.CID, fmt.Sprintf("%d", disk.Size), c.currentMarker(disk.Current)})
+	}
+	c.printRows(rows)
+
+	c.ui.PrintLinef("")
+	c.ui.PrintLinef("Stemcells")
+	rows = [][]string{{"ID", "Name", "Version", "CID", "Current"}}
+	for _, stemcell := range output.Stemcells {
+		rows = append(rows, []string{stemcell.ID, stemcell.Name, stemcell.Version, stemcell.CID, c.currentMarker(stemcell.Current)})
+	}
+	c.printRows(rows)
+
+	c.ui.PrintLinef("")
+	c.ui.PrintLinef("Releases")
+	rows = [][]string{{"ID", "Name", "Version", "Current"}}
+	for _, release := range output.Releases {
+		rows = append(rows, []string{release.ID, release.Name, release.Version, c.currentMarker(release.Current)})
+	}
+	c.printRows(rows)
+
+	if len(output.Inconsistencies) > 0 {
+		c.ui.PrintLinef("")
+		c.ui.PrintLinef("Inconsistencies")
+		for _, inconsistency := range output.Inconsistencies {
+			c.ui.PrintLinef("  - %s", inconsistency)
+		}
+	}
+}
+
+func (c *stateCmd) printRows(rows [][]string) {
+	buffer := bytes.NewBuffer([]byte{})
+	writer := tabwriter.NewWriter(buffer, 0, 4, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(writer, "  %s\n", strings.Join(row, "\t"))
+	}
+	writer.Flush()
+
+	for _, line := range strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n") {
+		c.ui.PrintLinef("%s", strings.TrimRight(line, " "))
+	}
+}
+
+// instanceName is empty for a bootstrap VM recorded before job names were recorded
+func (c *stateCmd) instanceName(vm stateVMOutput) string {
+	if vm.Job == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", vm.Job, vm.Index)
+}
+
+func (c *stateCmd) currentMarker(current bool) string {
+	if current {
+		return "*"
+	}
+	return ""
+}
+
+func (c *stateCmd) parseCmdInputs(args []string) (string, bool, error) {
+	var printJSON bool
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.BoolVar(&printJSON, "json", false, "")
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", false, err
+	}
+
+	return positionalArgs[0], printJSON, nil
+}
@@ -0,0 +1,176 @@
+package cmd_test
+
+import (
+	"encoding/json"
+
+	bicmd "github.com/cloudfoundry/bosh-init/cmd"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+
+	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
+)
+
+var _ = Describe("StateCmd", func() {
+	var (
+		fs                     *fakesys.FakeFileSystem
+		logger                 boshlog.Logger
+		fakeUI                 *fakebiui.FakeUI
+		fakeStage              *fakebiui.FakeStage
+		deploymentStateService biconfig.DeploymentStateService
+		command                bicmd.Cmd
+
+		deploymentManifestPath = "/deployment-dir/fake-deployment-manifest.yml"
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		logger = boshlog.NewLogger(boshlog.LevelNone)
+		fakeUI = &fakebiui.FakeUI{}
+		fakeStage = fakebiui.NewFakeStage()
+
+		deploymentStateService = biconfig.NewFileSystemDeploymentStateService(
+			fs,
+			&fakeuuid.FakeGenerator{},
+			logger,
+			biconfig.DeploymentStatePath(deploymentManifestPath),
+		)
+
+		getter := func(path string) biconfig.DeploymentStateService {
+			Expect(path).To(Equal(deploymentManifestPath))
+			return deploymentStateService
+		}
+
+		command = bicmd.NewStateCmd(fakeUI, getter, biconfig.NewDeploymentStateValidator(), logger)
+	})
+
+	It("has the name 'state'", func() {
+		Expect(command.Name()).To(Equal("state"))
+	})
+
+	Context("when the deployment state does not exist", func() {
+		It("returns an error", func() {
+			err := command.Run(fakeStage, []string{deploymentManifestPath})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Deployment state does not exist at '/deployment-dir/fake-deployment-manifest-state.json'"))
+			Expect(fs.FileExists("/deployment-dir/fake-deployment-manifest-state.json")).To(BeFalse())
+		})
+	})
+
+	Context("when the deployment state exists", func() {
+		BeforeEach(func() {
+			err := deploymentStateService.Save(biconfig.DeploymentState{
+				DirectorID:          "fake-director-id",
+				InstallationID:      "fake-installation-id",
+				CurrentStemcellID:   "fake-stemcell-id",
+				CurrentReleaseIDs:   []string{"fake-release-id"},
+				CurrentManifestSHA1: "fake-manifest-sha1",
+				Disks: []biconfig.DiskRecord{
+					{ID: "fake-disk-id", CID: "fake-disk-cid", Size: 1024},
+					{ID: "fake-other-disk-id", CID: "fake-other-disk-cid", Size: 2048},
+				},
+				Instances: []biconfig.InstanceRecord{
+					{Bootstrap: true, Job: "fake-job-name", VMCID: "fake-vm-cid", DiskID: "fake-missing-disk-id"},
+					{Job: "fake-job-name", Index: 1, VMCID: "fake-other-vm-cid", DiskID: "fake-other-disk-id"},
+				},
+				Stemcells: []biconfig.StemcellRecord{
+					{ID: "fake-stemcell-id", Name: "fake-stemcell-name", Version: "1", CID: "fake-stemcell-cid"},
+				},
+				Releases: []biconfig.ReleaseRecord{
+					{ID: "fake-release-id", Name: "fake-release-name", Version: "2"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("prints the state as a table with current markers and inconsistencies", func() {
+			err := command.Run(fakeStage, []string{deploymentManifestPath})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeUI.Said).To(Equal([]string{
+				"Deployment state: '/deployment-dir/fake-deployment-manifest-state.json'",
+				"",
+				"  Director ID      fake-director-id",
+				"  Installation ID  fake-installation-id",
+				"  Manifest SHA1    fake-manifest-sha1",
+				"",
+				"VMs",
+				"  Instance         CID                Current",
+				"  fake-job-name/0  fake-vm-cid        *",
+				"  fake-job-name/1  fake-other-vm-cid  *",
+				"",
+				"Disks",
+				"  ID                  CID                  Size  Current",
+				"  fake-disk-id        fake-disk-cid        1024",
+				"  fake-other-disk-id  fake-other-disk-cid  2048  *",
+				"",
+				"Stemcells",
+				"  ID                Name                Version  CID                Current",
+				"  fake-stemcell-id  fake-stemcell-name  1        fake-stemcell-cid  *",
+				"",
+				"Releases",
+				"  ID               Name               Version  Current",
+				"  fake-release-id  fake-release-name  2        *",
+				"",
+				"Inconsistencies",
+				"  - instances[0].disk_id 'fake-missing-disk-id' does not match any disk record",
+				"  - disks[0] (cid=fake-disk-cid) is not the current disk",
+			}))
+		})
+
+		It("prints the state as json when --json is given", func() {
+			err := command.Run(fakeStage, []string{deploymentManifestPath, "--json"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeUI.Said).To(HaveLen(1))
+
+			var output map[string]interface{}
+			err = json.Unmarshal([]byte(fakeUI.Said[0]), &output)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output["director_id"]).To(Equal("fake-director-id"))
+			Expect(output["vms"]).To(Equal([]interface{}{
+				map[string]interface{}{"job": "fake-job-name", "index": float64(0), "cid": "fake-vm-cid", "current": true},
+				map[string]interface{}{"job": "fake-job-name", "index": float64(1), "cid": "fake-other-vm-cid", "current": true},
+			}))
+			Expect(output["disks"]).To(Equal([]interface{}{
+				map[string]interface{}{"id": "fake-disk-id", "cid": "fake-disk-cid", "size": float64(1024), "current": false},
+				map[string]interface{}{"id": "fake-other-disk-id", "cid": "fake-other-disk-cid", "size": float64(2048), "current": true},
+			}))
+			Expect(output["stemcells"]).To(Equal([]interface{}{
+				map[string]interface{}{"id": "fake-stemcell-id", "name": "fake-stemcell-name", "version": "1", "cid": "fake-stemcell-cid", "current": true},
+			}))
+			Expect(output["releases"]).To(Equal([]interface{}{
+				map[string]interface{}{"id": "fake-release-id", "name": "fake-release-name", "version": "2", "current": true},
+			}))
+			Expect(output["inconsistencies"]).To(Equal([]interface{}{
+				"instances[0].disk_id 'fake-missing-disk-id' does not match any disk record",
+				"disks[0] (cid=fake-disk-cid) is not the current disk",
+			}))
+		})
+
+		It("accepts --json before the manifest path", func() {
+			err := command.Run(fakeStage, []string{"--json", deploymentManifestPath})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeUI.Said).To(HaveLen(1))
+		})
+	})
+
+	It("returns err unless exactly 1 argument is given", func() {
+		err := command.Run(fakeStage, []string{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+		err = command.Run(fakeStage, []string{"1", "2"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+
+		err = command.Run(fakeStage, []string{"--bogus", deploymentManifestPath})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Invalid usage"))
+	})
+})
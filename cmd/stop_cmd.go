@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type stopCmd struct {
+	jobControllerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error)
+	ui                    biui.UI
+	fs                    boshsys.FileSystem
+	logger                boshlog.Logger
+	logTag                string
+}
+
+func NewStopCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	jobControllerProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (JobController, error),
+) Cmd {
+	return &stopCmd{
+		ui:                    ui,
+		fs:                    fs,
+		jobControllerProvider: jobControllerProvider,
+		logger:                logger,
+		logTag:                "stopCmd",
+	}
+}
+
+func (c *stopCmd) Name() string {
+	return "stop"
+}
+
+func (c *stopCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Stop the jobs on the deployed VMs",
+		Usage:    instanceFlagUsage + " [--hard] " + manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *stopCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, instance, hard, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	jobController, err := c.jobControllerProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return jobController.StopJobs(stage, instance, hard)
+}
+
+func (c *stopCmd) parseCmdInputs(args []string) (string, InstanceSelector, bool, manifestFlags, error) {
+	var instance InstanceSelector
+	var hard bool
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flagSet.Var(&instance, "instance", "")
+	flagSet.BoolVar(&hard, "hard", false, "")
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", instance, false, flags, err
+	}
+
+	return positionalArgs[0], instance, hard, flags, nil
+}
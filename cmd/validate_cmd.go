@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	biui "github.com/cloudfoundry/bosh-init/ui"
+)
+
+type DeploymentValidator interface {
+	ValidateDeployment(stage biui.Stage) (err error)
+}
+
+type validateCmd struct {
+	deploymentValidatorProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentValidator, error)
+	ui                          biui.UI
+	fs                          boshsys.FileSystem
+	logger                      boshlog.Logger
+	logTag                      string
+}
+
+func NewValidateCmd(
+	ui biui.UI,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+	deploymentValidatorProvider func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentValidator, error),
+) Cmd {
+	return &validateCmd{
+		ui:                          ui,
+		fs:                          fs,
+		deploymentValidatorProvider: deploymentValidatorProvider,
+		logger:                      logger,
+		logTag:                      "validateCmd",
+	}
+}
+
+func (c *validateCmd) Name() string {
+	return "validate"
+}
+
+func (c *validateCmd) Meta() Meta {
+	return Meta{
+		Synopsis: "Validate the deployment manifest, releases and stemcell without deploying",
+		Usage:    manifestFlagsUsage + " <deployment_manifest_path>",
+		Env:      genericEnv,
+	}
+}
+
+func (c *validateCmd) Run(stage biui.Stage, args []string) error {
+	deploymentManifestPath, flags, err := c.parseCmdInputs(args)
+	if err != nil {
+		return err
+	}
+
+	manifestAbsFilePath, manifestInterpolator, err := flags.resolveManifest(deploymentManifestPath, c.ui, c.fs)
+	if err != nil {
+		return err
+	}
+
+	deploymentValidator, err := c.deploymentValidatorProvider(manifestAbsFilePath, manifestInterpolator)
+	if err != nil {
+		return err
+	}
+
+	return deploymentValidator.ValidateDeployment(stage)
+}
+
+func (c *validateCmd) parseCmdInputs(args []string) (string, manifestFlags, error) {
+	var flags manifestFlags
+
+	flagSet := newFlagSet(c.Name())
+	flags.register(flagSet)
+
+	positionalArgs, err := parseCmdArgs(c.Name(), flagSet, args, 1, c.logger, c.logTag)
+	if err != nil {
+		return "", flags, err
+	}
+
+	return positionalArgs[0], flags, nil
+}
@@ -0,0 +1,13 @@
+package interpolation_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestInterpolation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Common Interpolation Suite")
+}
@@ -0,0 +1,336 @@
+package interpolation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+var placeholderRegexp = regexp.MustCompile(`\(\(([-\w/.]+)\)\)`)
+
+type Interpolator interface {
+	// Interpolate applies the operations to a YAML document, then replaces the ((name)) placeholders in its string values.
+	// A value that is a single placeholder is replaced by the variable with its type,
+	// otherwise the variable is formatted into the string.
+	// The result is kept, so that the manifest read by each of the parsers is only interpolated,
+	// and the vars store only loaded and saved, once.
+	Interpolate(contents []byte) ([]byte, error)
+
+	// AppliesOps is true when operations change the structure of the document,
+	// so that its paths are no longer those of the file.
+	AppliesOps() bool
+}
+
+type interpolator struct {
+	variables      Variables
+	ops            Ops
+	varsStore      VarsStore
+	valueGenerator ValueGenerator
+	results        map[string]interpolationResult
+}
+
+type interpolationResult struct {
+	contents []byte
+	err      error
+}
+
+func NewInterpolator(variables Variables, ops Ops) Interpolator {
+	return &interpolator{
+		variables: variables,
+		ops:       ops,
+		results:   map[string]interpolationResult{},
+	}
+}
+
+// NewVarsStoreInterpolator returns an interpolator that also generates the variables declared in the manifest
+// 'variables' section that are neither given nor stored yet, and saves them in the vars store.
+// Given variables take precedence over stored variables.
+func NewVarsStoreInterpolator(variables Variables, ops Ops, varsStore VarsStore, valueGenerator ValueGenerator) Interpolator {
+	return &interpolator{
+		variables:      variables,
+		ops:            ops,
+		varsStore:      varsStore,
+		valueGenerator: valueGenerator,
+		results:        map[string]interpolationResult{},
+	}
+}
+
+type missingVariable struct {
+	name string
+	path string
+}
+
+func (i *interpolator) Interpolate(contents []byte) ([]byte, error) {
+	if result, found := i.results[string(contents)]; found {
+		return result.contents, result.err
+	}
+
+	interpolatedContents, err := i.interpolate(contents)
+	i.results[string(contents)] = interpolationResult{contents: interpolatedContents, err: err}
+	return interpolatedContents, err
+}
+
+func (i *interpolator) AppliesOps() bool {
+	return len(i.ops) > 0
+}
+
+func (i *interpolator) interpolate(contents []byte) ([]byte, error) {
+	if len(i.ops) == 0 && i.varsStore == nil && !placeholderRegexp.Match(contents) {
+		return contents, nil
+	}
+
+	var document interface{}
+	err := yaml.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling manifest for interpolation")
+	}
+
+	document, err = i.ops.Apply(document)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := i.variables
+	if i.varsStore != nil {
+		variables, err = i.generateVariables(document)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	missingVariables := []missingVariable{}
+	document = interpolateValue(document, "", variables, &missingVariables)
+	if len(missingVariables) > 0 {
+		return nil, missingVariablesError(missingVariables)
+	}
+
+	interpolatedContents, err := yaml.Marshal(document)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshalling interpolated manifest")
+	}
+
+	return interpolatedContents, nil
+}
+
+func (i *interpolator) generateVariables(document interface{}) (Variables, error) {
+	storedVariables, err := i.varsStore.Load()
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Loading vars store")
+	}
+
+	variables := storedVariables.Merge(i.variables)
+
+	definitions, err := variableDefinitions(document, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions, err = generationOrder(definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := false
+	for _, definition := range definitions {
+		if _, found := variables[definition.Name]; found {
+			continue
+		}
+
+		value, err := i.valueGenerator.Generate(definition, variables)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Generating variable '%s'", definition.Name)
+		}
+
+		storedVariables[definition.Name] = value
+		variables[definition.Name] = value
+		generated = true
+	}
+
+	if generated {
+		err = i.varsStore.Save(storedVariables)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Saving vars store")
+		}
+	}
+
+	return variables, nil
+}
+
+// generationOrder orders the definitions so that each certificate comes after the definition of the CA that signs it,
+// whatever their order in the 'variables' section
+func generationOrder(definitions []VariableDefinition) ([]VariableDefinition, error) {
+	definitionsByName := map[string]VariableDefinition{}
+	for _, definition := range definitions {
+		definitionsByName[definition.Name] = definition
+	}
+
+	ordered := []VariableDefinition{}
+	ordering := map[string]bool{}
+	done := map[string]bool{}
+
+	var visit func(definition VariableDefinition) error
+	visit = func(definition VariableDefinition) error {
+		if done[definition.Name] {
+			return nil
+		}
+
+		if ordering[definition.Name] {
+			return bosherr.Errorf("Expected certificate '%s' not to be signed by itself through its CAs", definition.Name)
+		}
+		ordering[definition.Name] = true
+
+		if caName, ok := definition.Options["ca"].(string); ok && definition.Type == VariableTypeCertificate {
+			if caDefinition, found := definitionsByName[caName]; found {
+				err := visit(caDefinition)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		done[definition.Name] = true
+		ordered = append(ordered, definition)
+		return nil
+	}
+
+	for _, definition := range definitions {
+		err := visit(definition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// variableDefinitions returns the entries of the 'variables' section, with their placeholders replaced
+func variableDefinitions(document interface{}, variables Variables) ([]VariableDefinition, error) {
+	documentMap, ok := document.(map[interface{}]interface{})
+	if !ok || documentMap["variables"] == nil {
+		return []VariableDefinition{}, nil
+	}
+
+	missingVariables := []missingVariable{}
+	section := interpolateValue(documentMap["variables"], "variables", variables, &missingVariables)
+	if len(missingVariables) > 0 {
+		return nil, missingVariablesError(missingVariables)
+	}
+
+	contents, err := yaml.Marshal(section)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshalling variables section")
+	}
+
+	definitions := []VariableDefinition{}
+	err = yaml.Unmarshal(contents, &definitions)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Unmarshalling variables section")
+	}
+
+	for idx, definition := range definitions {
+		if definition.Name == "" {
+			return nil, bosherr.Errorf("Expected variables[%d] to have a name", idx)
+		}
+	}
+
+	return definitions, nil
+}
+
+func interpolateValue(value interface{}, path string, variables Variables, missingVariables *[]missingVariable) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		interpolatedMap := make(map[interface{}]interface{}, len(typedValue))
+		for key, mapValue := range typedValue {
+			interpolatedMap[key] = interpolateValue(mapValue, childPath(path, key), variables, missingVariables)
+		}
+		return interpolatedMap
+	case []interface{}:
+		interpolatedSlice := make([]interface{}, len(typedValue), len(typedValue))
+		for idx, sliceValue := range typedValue {
+			interpolatedSlice[idx] = interpolateValue(sliceValue, fmt.Sprintf("%s[%d]", path, idx), variables, missingVariables)
+		}
+		return interpolatedSlice
+	case string:
+		return interpolateString(typedValue, path, variables, missingVariables)
+	default:
+		return value
+	}
+}
+
+func interpolateString(value string, path string, variables Variables, missingVariables *[]missingVariable) interface{} {
+	// a value that is only a placeholder keeps the type of the variable
+	if match := placeholderRegexp.FindStringSubmatch(value); match != nil && match[0] == value {
+		variable, found := findVariable(match[1], variables)
+		if !found {
+			*missingVariables = append(*missingVariables, missingVariable{name: match[1], path: path})
+			return value
+		}
+		return variable
+	}
+
+	return placeholderRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := placeholderRegexp.FindStringSubmatch(placeholder)[1]
+		variable, found := findVariable(name, variables)
+		if !found {
+			*missingVariables = append(*missingVariables, missingVariable{name: name, path: path})
+			return placeholder
+		}
+		return fmt.Sprintf("%v", variable)
+	})
+}
+
+// findVariable looks up a variable by name, or a value nested in a variable with a dotted name such as 'cert.private_key'
+func findVariable(name string, variables Variables) (interface{}, bool) {
+	if variable, found := variables[name]; found {
+		return variable, true
+	}
+
+	keys := strings.Split(name, ".")
+	variable, found := variables[keys[0]]
+	if !found {
+		return nil, false
+	}
+
+	for _, key := range keys[1:] {
+		switch typedVariable := variable.(type) {
+		case map[interface{}]interface{}:
+			variable, found = typedVariable[key]
+		case map[string]interface{}:
+			variable, found = typedVariable[key]
+		default:
+			found = false
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return variable, true
+}
+
+func missingVariablesError(missingVariables []missingVariable) error {
+	sort.Stable(byPath(missingVariables))
+
+	errs := make([]error, len(missingVariables), len(missingVariables))
+	for idx, missing := range missingVariables {
+		errs[idx] = bosherr.Errorf("Missing variable '%s' for '%s'", missing.name, missing.path)
+	}
+	return bosherr.NewMultiError(errs...)
+}
+
+func childPath(path string, key interface{}) string {
+	if path == "" {
+		return fmt.Sprintf("%v", key)
+	}
+	return fmt.Sprintf("%s.%v", path, key)
+}
+
+type byPath []missingVariable
+
+func (s byPath) Len() int           { return len(s) }
+func (s byPath) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byPath) Less(i, j int) bool { return s[i].path < s[j].path }
@@ -0,0 +1,279 @@
+package interpolation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+const (
+	OpTypeReplace = "replace"
+	OpTypeRemove  = "remove"
+)
+
+// Op is a patch operation on a YAML document.
+// Path segments are separated by '/' and may be a map key, an array index, '-' to append to an array,
+// or 'name=value' to select the array element with that value.
+// A segment ending with '?' is optional: it is created when replacing and ignored when removing.
+type Op struct {
+	Type  string      `yaml:"type"`
+	Path  string      `yaml:"path"`
+	Value interface{} `yaml:"value"`
+}
+
+// Ops are applied in order, each to the result of the previous one
+type Ops []Op
+
+type pathToken struct {
+	key      string
+	index    int
+	isIndex  bool
+	isAppend bool
+	matchKey string
+	optional bool
+}
+
+// NewOpsFromFile reads the list of operations from a YAML ops file
+func NewOpsFromFile(path string, fs boshsys.FileSystem) (Ops, error) {
+	contents, err := fs.ReadFile(path)
+	if err != nil {
+		return Ops{}, bosherr.WrapErrorf(err, "Reading ops file '%s'", path)
+	}
+
+	ops := Ops{}
+	err = yaml.Unmarshal(contents, &ops)
+	if err != nil {
+		return Ops{}, bosherr.WrapErrorf(err, "Unmarshalling ops file '%s'", path)
+	}
+
+	for idx, op := range ops {
+		err = op.validate()
+		if err != nil {
+			return Ops{}, bosherr.WrapErrorf(err, "Validating operation %d in ops file '%s'", idx, path)
+		}
+	}
+
+	return ops, nil
+}
+
+func (o Ops) Apply(document interface{}) (interface{}, error) {
+	for _, op := range o {
+		var err error
+		document, err = op.apply(document)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Applying %s operation for path '%s'", op.Type, op.Path)
+		}
+	}
+	return document, nil
+}
+
+func (o Op) validate() error {
+	if o.Type != OpTypeReplace && o.Type != OpTypeRemove {
+		return bosherr.Errorf("Unknown operation type '%s'", o.Type)
+	}
+
+	tokens, err := parsePath(o.Path)
+	if err != nil {
+		return err
+	}
+
+	for idx, token := range tokens {
+		if token.isAppend && (o.Type != OpTypeReplace || idx != len(tokens)-1) {
+			return bosherr.Errorf("Expected '-' to be the last segment of the path of a replace operation")
+		}
+	}
+
+	return nil
+}
+
+func (o Op) apply(document interface{}) (interface{}, error) {
+	err := o.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, _ := parsePath(o.Path)
+	if len(tokens) == 0 {
+		if o.Type == OpTypeRemove {
+			return nil, bosherr.Error("Expected to not remove the whole document")
+		}
+		return copyValue(o.Value), nil
+	}
+
+	return o.applyTokens(document, tokens, "")
+}
+
+func (o Op) applyTokens(node interface{}, tokens []pathToken, path string) (interface{}, error) {
+	token := tokens[0]
+	isLast := len(tokens) == 1
+
+	if token.key != "" && token.matchKey == "" {
+		typedNode, ok := node.(map[interface{}]interface{})
+		if !ok {
+			return nil, bosherr.Errorf("Expected to find a map at '%s'", pathOrRoot(path))
+		}
+		path = path + "/" + token.key
+
+		child, found := typedNode[token.key]
+		if isLast {
+			if o.Type == OpTypeRemove {
+				if !found && !token.optional {
+					return nil, bosherr.Errorf("Expected to find '%s'", path)
+				}
+				delete(typedNode, token.key)
+			} else {
+				typedNode[token.key] = copyValue(o.Value)
+			}
+			return typedNode, nil
+		}
+
+		if !found {
+			if !token.optional {
+				return nil, bosherr.Errorf("Expected to find '%s'", path)
+			}
+			child = newNodeFor(tokens[1])
+		}
+
+		newChild, err := o.applyTokens(child, tokens[1:], path)
+		if err != nil {
+			return nil, err
+		}
+		typedNode[token.key] = newChild
+		return typedNode, nil
+	}
+
+	typedNode, ok := node.([]interface{})
+	if !ok {
+		return nil, bosherr.Errorf("Expected to find an array at '%s'", pathOrRoot(path))
+	}
+
+	if token.isAppend {
+		return append(typedNode, copyValue(o.Value)), nil
+	}
+
+	index := token.index
+	if token.matchKey != "" {
+		path = fmt.Sprintf("%s/%s=%s", path, token.matchKey, token.key)
+
+		index = -1
+		for idx, element := range typedNode {
+			typedElement, ok := element.(map[interface{}]interface{})
+			if ok && fmt.Sprintf("%v", typedElement[token.matchKey]) == token.key {
+				if index != -1 {
+					return nil, bosherr.Errorf("Expected to find exactly one element matching '%s'", path)
+				}
+				index = idx
+			}
+		}
+
+		if index == -1 {
+			if !token.optional {
+				return nil, bosherr.Errorf("Expected to find '%s'", path)
+			}
+			if isLast && o.Type == OpTypeRemove {
+				return typedNode, nil
+			}
+			typedNode = append(typedNode, map[interface{}]interface{}{token.matchKey: token.key})
+			index = len(typedNode) - 1
+		}
+	} else {
+		path = fmt.Sprintf("%s/%d", path, index)
+		if index < 0 || index >= len(typedNode) {
+			return nil, bosherr.Errorf("Expected to find '%s' in an array of %d elements", path, len(typedNode))
+		}
+	}
+
+	if isLast {
+		if o.Type == OpTypeRemove {
+			return append(typedNode[:index:index], typedNode[index+1:]...), nil
+		}
+		typedNode[index] = copyValue(o.Value)
+		return typedNode, nil
+	}
+
+	newChild, err := o.applyTokens(typedNode[index], tokens[1:], path)
+	if err != nil {
+		return nil, err
+	}
+	typedNode[index] = newChild
+	return typedNode, nil
+}
+
+func parsePath(path string) ([]pathToken, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, bosherr.Errorf("Expected path '%s' to start with '/'", path)
+	}
+
+	tokens := []pathToken{}
+	if path == "/" {
+		return tokens, nil
+	}
+
+	for _, segment := range strings.Split(path[1:], "/") {
+		segment = strings.Replace(strings.Replace(segment, "~1", "/", -1), "~0", "~", -1)
+
+		token := pathToken{}
+		if strings.HasSuffix(segment, "?") {
+			token.optional = true
+			segment = strings.TrimSuffix(segment, "?")
+		}
+
+		if segment == "" {
+			return nil, bosherr.Errorf("Expected path '%s' to not contain empty segments", path)
+		}
+
+		if segment == "-" {
+			token.isAppend = true
+		} else if index, err := strconv.Atoi(segment); err == nil {
+			token.index = index
+			token.isIndex = true
+		} else if pair := strings.SplitN(segment, "=", 2); len(pair) == 2 {
+			token.matchKey = pair[0]
+			token.key = pair[1]
+		} else {
+			token.key = segment
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, nil
+}
+
+func newNodeFor(token pathToken) interface{} {
+	if token.isAppend || token.isIndex || token.matchKey != "" {
+		return []interface{}{}
+	}
+	return map[interface{}]interface{}{}
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// copyValue copies maps and arrays so that applying the same operations again starts from the original values
+func copyValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case map[interface{}]interface{}:
+		copiedMap := make(map[interface{}]interface{}, len(typedValue))
+		for key, mapValue := range typedValue {
+			copiedMap[key] = copyValue(mapValue)
+		}
+		return copiedMap
+	case []interface{}:
+		copiedSlice := make([]interface{}, len(typedValue), len(typedValue))
+		for idx, sliceValue := range typedValue {
+			copiedSlice[idx] = copyValue(sliceValue)
+		}
+		return copiedSlice
+	default:
+		return value
+	}
+}
@@ -0,0 +1,200 @@
+package interpolation_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+var _ = Describe("Ops", func() {
+	var document interface{}
+
+	BeforeEach(func() {
+		document = nil
+		err := yaml.Unmarshal([]byte(`---
+name: fake-deployment-name
+jobs:
+- name: bosh
+  instances: 1
+  templates:
+  - {name: nats, release: bosh}
+  - {name: registry, release: bosh}
+cloud_provider:
+  template: {name: cpi, release: bosh-openstack-cpi}
+`), &document)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	var apply = func(op Op) (interface{}, error) {
+		return Ops{op}.Apply(document)
+	}
+
+	var get = func(value interface{}, keys ...interface{}) interface{} {
+		for _, key := range keys {
+			switch typedKey := key.(type) {
+			case int:
+				value = value.([]interface{})[typedKey]
+			default:
+				value = value.(map[interface{}]interface{})[typedKey]
+			}
+		}
+		return value
+	}
+
+	Describe("replace", func() {
+		It("replaces a map key", func() {
+			result, err := apply(Op{Type: "replace", Path: "/cloud_provider/template/release", Value: "bosh-aws-cpi"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "cloud_provider", "template", "release")).To(Equal("bosh-aws-cpi"))
+		})
+
+		It("adds a missing key to an existing map", func() {
+			result, err := apply(Op{Type: "replace", Path: "/cloud_provider/properties", Value: map[interface{}]interface{}{"aws": "fake-aws"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "cloud_provider", "properties", "aws")).To(Equal("fake-aws"))
+		})
+
+		It("replaces an array element by index", func() {
+			result, err := apply(Op{Type: "replace", Path: "/jobs/0/instances", Value: 2})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "instances")).To(Equal(2))
+		})
+
+		It("replaces an array element selected by name", func() {
+			result, err := apply(Op{Type: "replace", Path: "/jobs/name=bosh/templates/name=nats/release", Value: "nats"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "templates", 0, "release")).To(Equal("nats"))
+		})
+
+		It("appends to an array with '-'", func() {
+			result, err := apply(Op{Type: "replace", Path: "/jobs/name=bosh/templates/-", Value: map[interface{}]interface{}{"name": "powerdns", "release": "bosh"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "templates")).To(HaveLen(3))
+			Expect(get(result, "jobs", 0, "templates", 2, "name")).To(Equal("powerdns"))
+		})
+
+		It("creates missing optional segments", func() {
+			result, err := apply(Op{Type: "replace", Path: "/jobs/name=bosh/properties?/director?/name", Value: "fake-director"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "properties", "director", "name")).To(Equal("fake-director"))
+		})
+
+		It("appends an element for a missing optional selector", func() {
+			result, err := apply(Op{Type: "replace", Path: "/jobs/name=bosh/templates/name=powerdns?/release", Value: "bosh"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "templates", 2)).To(Equal(map[interface{}]interface{}{"name": "powerdns", "release": "bosh"}))
+		})
+
+		It("replaces the whole document with '/'", func() {
+			result, err := apply(Op{Type: "replace", Path: "/", Value: "fake-document"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("fake-document"))
+		})
+
+		It("does not share the value between applications", func() {
+			ops := Ops{
+				{Type: "replace", Path: "/cloud_provider/properties", Value: map[interface{}]interface{}{}},
+				{Type: "replace", Path: "/cloud_provider/properties/aws", Value: "fake-aws"},
+			}
+
+			_, err := ops.Apply(document)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ops[0].Value).To(Equal(map[interface{}]interface{}{}))
+		})
+
+		It("returns an error when a segment is missing", func() {
+			_, err := apply(Op{Type: "replace", Path: "/jobs/name=bosh/properties/director/name", Value: "fake-director"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find '/jobs/name=bosh/properties'"))
+		})
+
+		It("returns an error when the index is out of range", func() {
+			_, err := apply(Op{Type: "replace", Path: "/jobs/1/instances", Value: 2})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find '/jobs/1' in an array of 1 elements"))
+		})
+
+		It("returns an error when indexing a map", func() {
+			_, err := apply(Op{Type: "replace", Path: "/cloud_provider/0", Value: 2})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Expected to find an array at '/cloud_provider'"))
+		})
+	})
+
+	Describe("remove", func() {
+		It("removes a map key", func() {
+			result, err := apply(Op{Type: "remove", Path: "/cloud_provider/template"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "cloud_provider")).To(Equal(map[interface{}]interface{}{}))
+		})
+
+		It("removes an array element selected by name", func() {
+			result, err := apply(Op{Type: "remove", Path: "/jobs/name=bosh/templates/name=registry"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "templates")).To(Equal([]interface{}{
+				map[interface{}]interface{}{"name": "nats", "release": "bosh"},
+			}))
+		})
+
+		It("ignores missing optional segments", func() {
+			result, err := apply(Op{Type: "remove", Path: "/jobs/name=bosh/templates/name=powerdns?"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(get(result, "jobs", 0, "templates")).To(HaveLen(2))
+		})
+
+		It("returns an error when the key is missing", func() {
+			_, err := apply(Op{Type: "remove", Path: "/cloud_provider/properties"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Applying remove operation for path '/cloud_provider/properties': Expected to find '/cloud_provider/properties'"))
+		})
+	})
+
+	It("returns an error for an unknown operation type", func() {
+		_, err := apply(Op{Type: "add", Path: "/name"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("Unknown operation type 'add'"))
+	})
+
+	Describe("NewOpsFromFile", func() {
+		var fs *fakesys.FakeFileSystem
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+		})
+
+		It("reads the operations from the YAML file", func() {
+			fs.WriteFileString("/ops.yml", `---
+- type: replace
+  path: /cloud_provider/template/release
+  value: bosh-aws-cpi
+- type: remove
+  path: /jobs/name=bosh/templates/name=registry
+`)
+
+			ops, err := NewOpsFromFile("/ops.yml", fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ops).To(Equal(Ops{
+				{Type: "replace", Path: "/cloud_provider/template/release", Value: "bosh-aws-cpi"},
+				{Type: "remove", Path: "/jobs/name=bosh/templates/name=registry"},
+			}))
+		})
+
+		It("returns an error when the file cannot be read", func() {
+			_, err := NewOpsFromFile("/missing.yml", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading ops file '/missing.yml'"))
+		})
+
+		It("returns an error when an operation is invalid", func() {
+			fs.WriteFileString("/ops.yml", "---\n- type: replace\n  path: cloud_provider\n")
+
+			_, err := NewOpsFromFile("/ops.yml", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Validating operation 0 in ops file '/ops.yml'"))
+			Expect(err.Error()).To(ContainSubstring("Expected path 'cloud_provider' to start with '/'"))
+		})
+	})
+})
@@ -0,0 +1,259 @@
+package interpolation
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"time"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	"github.com/cloudfoundry/bosh-init/internal/golang.org/x/crypto/ssh"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+const (
+	VariableTypePassword    = "password"
+	VariableTypeCertificate = "certificate"
+	VariableTypeSSH         = "ssh"
+	VariableTypeRSA         = "rsa"
+
+	passwordLength   = 20
+	passwordAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	rsaKeyBits       = 2048
+	certificateTTL   = 365 * 24 * time.Hour
+)
+
+// VariableDefinition is an entry of the manifest 'variables' section
+type VariableDefinition struct {
+	Name    string                      `yaml:"name"`
+	Type    string                      `yaml:"type"`
+	Options map[interface{}]interface{} `yaml:"options"`
+}
+
+type certificateOptions struct {
+	CA               string   `yaml:"ca"`
+	IsCA             bool     `yaml:"is_ca"`
+	CommonName       string   `yaml:"common_name"`
+	AlternativeNames []string `yaml:"alternative_names"`
+}
+
+type ValueGenerator interface {
+	// Generate returns a new value for the variable.
+	// The variables are used to find the CA that signs a certificate.
+	Generate(definition VariableDefinition, variables Variables) (interface{}, error)
+}
+
+type valueGenerator struct{}
+
+func NewValueGenerator() ValueGenerator {
+	return &valueGenerator{}
+}
+
+func (g *valueGenerator) Generate(definition VariableDefinition, variables Variables) (interface{}, error) {
+	switch definition.Type {
+	case VariableTypePassword:
+		return g.generatePassword()
+	case VariableTypeCertificate:
+		return g.generateCertificate(definition, variables)
+	case VariableTypeSSH:
+		return g.generateSSHKey()
+	case VariableTypeRSA:
+		return g.generateRSAKey()
+	default:
+		return nil, bosherr.Errorf("Unknown variable type '%s'", definition.Type)
+	}
+}
+
+func (g *valueGenerator) generatePassword() (interface{}, error) {
+	alphabetLength := big.NewInt(int64(len(passwordAlphabet)))
+
+	password := make([]byte, passwordLength)
+	for idx := range password {
+		// each character is picked uniformly from the alphabet, which a random byte modulo its length would not do
+		alphabetIdx, err := rand.Int(rand.Reader, alphabetLength)
+		if err != nil {
+			return nil, bosherr.WrapError(err, "Reading random number")
+		}
+		password[idx] = passwordAlphabet[alphabetIdx.Int64()]
+	}
+
+	return string(password), nil
+}
+
+func (g *valueGenerator) generateCertificate(definition VariableDefinition, variables Variables) (interface{}, error) {
+	options := certificateOptions{}
+	err := convertOptions(definition.Options, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.CA == "" && !options.IsCA {
+		return nil, bosherr.Errorf("Expected certificate to have a 'ca' option or 'is_ca: true'")
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating private key")
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating serial number")
+	}
+
+	notBefore := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"bosh-init"},
+			CommonName:   options.CommonName,
+		},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(certificateTTL),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	if options.IsCA {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+		template.ExtKeyUsage = nil
+	}
+
+	for _, alternativeName := range options.AlternativeNames {
+		if ip := net.ParseIP(alternativeName); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, alternativeName)
+		}
+	}
+
+	parentCertificate := template
+	var parentKey interface{} = privateKey
+	if options.CA != "" {
+		parentCertificate, parentKey, err = g.findCA(options.CA, variables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, parentCertificate, &privateKey.PublicKey, parentKey)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Creating certificate")
+	}
+
+	certificatePEM := encodePEM("CERTIFICATE", certificateBytes)
+	caPEM := certificatePEM
+	if options.CA != "" {
+		caPEM = encodePEM("CERTIFICATE", parentCertificate.Raw)
+	}
+
+	return map[interface{}]interface{}{
+		"ca":          caPEM,
+		"certificate": certificatePEM,
+		"private_key": encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)),
+	}, nil
+}
+
+func (g *valueGenerator) findCA(name string, variables Variables) (*x509.Certificate, interface{}, error) {
+	value, found := variables[name]
+	if !found {
+		return nil, nil, bosherr.Errorf("Expected to find CA variable '%s', either given or declared in 'variables'", name)
+	}
+
+	ca, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, bosherr.Errorf("Expected CA variable '%s' to be a certificate", name)
+	}
+
+	certificatePEM, _ := ca["certificate"].(string)
+	certificateBlock, _ := pem.Decode([]byte(certificatePEM))
+	if certificateBlock == nil {
+		return nil, nil, bosherr.Errorf("Expected CA variable '%s' to have a PEM encoded certificate", name)
+	}
+
+	certificate, err := x509.ParseCertificate(certificateBlock.Bytes)
+	if err != nil {
+		return nil, nil, bosherr.WrapErrorf(err, "Parsing certificate of CA variable '%s'", name)
+	}
+
+	privateKeyPEM, _ := ca["private_key"].(string)
+	privateKeyBlock, _ := pem.Decode([]byte(privateKeyPEM))
+	if privateKeyBlock == nil {
+		return nil, nil, bosherr.Errorf("Expected CA variable '%s' to have a PEM encoded private key", name)
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(privateKeyBlock.Bytes)
+	if err != nil {
+		return nil, nil, bosherr.WrapErrorf(err, "Parsing private key of CA variable '%s'", name)
+	}
+
+	return certificate, privateKey, nil
+}
+
+func (g *valueGenerator) generateSSHKey() (interface{}, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating private key")
+	}
+
+	publicKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating SSH public key")
+	}
+
+	fingerprint := md5.Sum(publicKey.Marshal())
+	fingerprintParts := make([]string, len(fingerprint), len(fingerprint))
+	for idx, fingerprintByte := range fingerprint {
+		fingerprintParts[idx] = fmt.Sprintf("%02x", fingerprintByte)
+	}
+
+	return map[interface{}]interface{}{
+		"private_key":            encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)),
+		"public_key":             strings.TrimSpace(string(ssh.MarshalAuthorizedKey(publicKey))),
+		"public_key_fingerprint": strings.Join(fingerprintParts, ":"),
+	}, nil
+}
+
+func (g *valueGenerator) generateRSAKey() (interface{}, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Generating private key")
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Marshalling public key")
+	}
+
+	return map[interface{}]interface{}{
+		"private_key": encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)),
+		"public_key":  encodePEM("PUBLIC KEY", publicKeyBytes),
+	}, nil
+}
+
+func convertOptions(options map[interface{}]interface{}, typedOptions interface{}) error {
+	contents, err := yaml.Marshal(options)
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling options")
+	}
+
+	err = yaml.Unmarshal(contents, typedOptions)
+	if err != nil {
+		return bosherr.WrapError(err, "Unmarshalling options")
+	}
+
+	return nil
+}
+
+func encodePEM(blockType string, bytes []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes}))
+}
@@ -0,0 +1,129 @@
+package interpolation_test
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+
+	. "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	"github.com/cloudfoundry/bosh-init/internal/golang.org/x/crypto/ssh"
+)
+
+var _ = Describe("ValueGenerator", func() {
+	var generator ValueGenerator
+
+	BeforeEach(func() {
+		generator = NewValueGenerator()
+	})
+
+	var parseCertificate = func(certificatePEM interface{}) *x509.Certificate {
+		block, _ := pem.Decode([]byte(certificatePEM.(string)))
+		Expect(block).ToNot(BeNil())
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		Expect(err).ToNot(HaveOccurred())
+		return certificate
+	}
+
+	It("generates random passwords", func() {
+		password, err := generator.Generate(VariableDefinition{Name: "nats_password", Type: "password"}, Variables{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(password).To(MatchRegexp("^[a-z0-9]{20}$"))
+
+		otherPassword, err := generator.Generate(VariableDefinition{Name: "nats_password", Type: "password"}, Variables{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherPassword).ToNot(Equal(password))
+	})
+
+	Describe("certificates", func() {
+		var ca interface{}
+
+		BeforeEach(func() {
+			var err error
+			ca, err = generator.Generate(VariableDefinition{
+				Name:    "default_ca",
+				Type:    "certificate",
+				Options: map[interface{}]interface{}{"is_ca": true, "common_name": "fake-ca"},
+			}, Variables{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("generates a self-signed CA", func() {
+			caValue := ca.(map[interface{}]interface{})
+			Expect(caValue["ca"]).To(Equal(caValue["certificate"]))
+			Expect(caValue["private_key"]).To(ContainSubstring("RSA PRIVATE KEY"))
+
+			certificate := parseCertificate(caValue["certificate"])
+			Expect(certificate.IsCA).To(BeTrue())
+			Expect(certificate.Subject.CommonName).To(Equal("fake-ca"))
+		})
+
+		It("generates a certificate signed by the CA, with its alternative names", func() {
+			value, err := generator.Generate(VariableDefinition{
+				Name: "mbus_cert",
+				Type: "certificate",
+				Options: map[interface{}]interface{}{
+					"ca":                "default_ca",
+					"common_name":       "10.0.0.6",
+					"alternative_names": []interface{}{"10.0.0.6", "director.example.com"},
+				},
+			}, Variables{"default_ca": ca})
+			Expect(err).ToNot(HaveOccurred())
+
+			certificateValue := value.(map[interface{}]interface{})
+			Expect(certificateValue["ca"]).To(Equal(ca.(map[interface{}]interface{})["certificate"]))
+
+			certificate := parseCertificate(certificateValue["certificate"])
+			Expect(certificate.IsCA).To(BeFalse())
+			Expect(certificate.IPAddresses).To(HaveLen(1))
+			Expect(certificate.IPAddresses[0].Equal(net.ParseIP("10.0.0.6"))).To(BeTrue())
+			Expect(certificate.DNSNames).To(Equal([]string{"director.example.com"}))
+			Expect(certificate.CheckSignatureFrom(parseCertificate(certificateValue["ca"]))).To(Succeed())
+		})
+
+		It("returns an error when the CA is missing", func() {
+			_, err := generator.Generate(VariableDefinition{
+				Name:    "mbus_cert",
+				Type:    "certificate",
+				Options: map[interface{}]interface{}{"ca": "missing_ca"},
+			}, Variables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected to find CA variable 'missing_ca', either given or declared in 'variables'"))
+		})
+
+		It("returns an error when there is neither a CA nor is_ca", func() {
+			_, err := generator.Generate(VariableDefinition{Name: "mbus_cert", Type: "certificate"}, Variables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected certificate to have a 'ca' option or 'is_ca: true'"))
+		})
+	})
+
+	It("generates SSH keys", func() {
+		value, err := generator.Generate(VariableDefinition{Name: "jumpbox_ssh", Type: "ssh"}, Variables{})
+		Expect(err).ToNot(HaveOccurred())
+
+		sshValue := value.(map[interface{}]interface{})
+		Expect(sshValue["private_key"]).To(ContainSubstring("RSA PRIVATE KEY"))
+		Expect(sshValue["public_key_fingerprint"]).To(MatchRegexp("^([0-9a-f]{2}:){15}[0-9a-f]{2}$"))
+
+		_, _, _, _, err = ssh.ParseAuthorizedKey([]byte(sshValue["public_key"].(string)))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("generates RSA keys", func() {
+		value, err := generator.Generate(VariableDefinition{Name: "director_rsa", Type: "rsa"}, Variables{})
+		Expect(err).ToNot(HaveOccurred())
+
+		rsaValue := value.(map[interface{}]interface{})
+		Expect(rsaValue["private_key"]).To(ContainSubstring("RSA PRIVATE KEY"))
+		Expect(rsaValue["public_key"]).To(ContainSubstring("PUBLIC KEY"))
+	})
+
+	It("returns an error for an unknown type", func() {
+		_, err := generator.Generate(VariableDefinition{Name: "fake-name", Type: "fake-type"}, Variables{})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Unknown variable type 'fake-type'"))
+	})
+})
@@ -0,0 +1,68 @@
+package interpolation
+
+import (
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+// Variables holds the values of the ((name)) placeholders, keyed by name
+type Variables map[string]interface{}
+
+// Merge returns a copy of the variables with the other variables added, replacing variables with the same name
+func (v Variables) Merge(other Variables) Variables {
+	merged := Variables{}
+	for name, value := range v {
+		merged[name] = value
+	}
+	for name, value := range other {
+		merged[name] = value
+	}
+	return merged
+}
+
+// NewVariablesFromFile reads variables from a YAML file with one top-level key per variable.
+// Values keep their YAML type, so they can replace integers, lists or hashes.
+func NewVariablesFromFile(path string, fs boshsys.FileSystem) (Variables, error) {
+	contents, err := fs.ReadFile(path)
+	if err != nil {
+		return Variables{}, bosherr.WrapErrorf(err, "Reading variables file '%s'", path)
+	}
+
+	fileVariables := map[string]interface{}{}
+	err = yaml.Unmarshal(contents, &fileVariables)
+	if err != nil {
+		return Variables{}, bosherr.WrapErrorf(err, "Unmarshalling variables file '%s'", path)
+	}
+
+	return Variables(fileVariables), nil
+}
+
+// NewVariablesFromEnv returns the string values of the environment variables named '<prefix>_<name>', keyed by name
+func NewVariablesFromEnv(prefix string, environ []string) Variables {
+	envVariables := Variables{}
+	for _, envVariable := range environ {
+		pair := strings.SplitN(envVariable, "=", 2)
+		if len(pair) != 2 || !strings.HasPrefix(pair[0], prefix+"_") {
+			continue
+		}
+
+		name := strings.TrimPrefix(pair[0], prefix+"_")
+		if name != "" {
+			envVariables[name] = pair[1]
+		}
+	}
+	return envVariables
+}
+
+// NewVariableFromArg parses a 'name=value' argument into a variable with a string value
+func NewVariableFromArg(arg string) (Variables, error) {
+	pair := strings.SplitN(arg, "=", 2)
+	if len(pair) != 2 || pair[0] == "" {
+		return Variables{}, bosherr.Errorf("Expected variable '%s' to be in the form 'name=value'", arg)
+	}
+
+	return Variables{pair[0]: pair[1]}, nil
+}
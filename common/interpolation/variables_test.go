@@ -0,0 +1,85 @@
+package interpolation_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+)
+
+var _ = Describe("Variables", func() {
+	Describe("Merge", func() {
+		It("returns a copy with the other variables taking precedence", func() {
+			variables := Variables{"a": "1", "b": "2"}
+
+			merged := variables.Merge(Variables{"b": "3", "c": "4"})
+			Expect(merged).To(Equal(Variables{"a": "1", "b": "3", "c": "4"}))
+			Expect(variables).To(Equal(Variables{"a": "1", "b": "2"}))
+		})
+	})
+
+	Describe("NewVariablesFromFile", func() {
+		var fs *fakesys.FakeFileSystem
+
+		BeforeEach(func() {
+			fs = fakesys.NewFakeFileSystem()
+		})
+
+		It("reads typed variables from the YAML file", func() {
+			fs.WriteFileString("/vars.yml", "director_ip: 10.0.0.6\nssh_port: 22\ndns: [8.8.8.8]\n")
+
+			variables, err := NewVariablesFromFile("/vars.yml", fs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(Equal(Variables{
+				"director_ip": "10.0.0.6",
+				"ssh_port":    22,
+				"dns":         []interface{}{"8.8.8.8"},
+			}))
+		})
+
+		It("returns an error when the file cannot be read", func() {
+			_, err := NewVariablesFromFile("/missing.yml", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Reading variables file '/missing.yml'"))
+		})
+
+		It("returns an error when the file is not a YAML hash", func() {
+			fs.WriteFileString("/vars.yml", "- not-a-hash\n")
+
+			_, err := NewVariablesFromFile("/vars.yml", fs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Unmarshalling variables file '/vars.yml'"))
+		})
+	})
+
+	Describe("NewVariablesFromEnv", func() {
+		It("returns the variables with the prefix, keyed by the rest of their name", func() {
+			variables := NewVariablesFromEnv("BOSH_VAR", []string{
+				"BOSH_VAR_director_ip=10.0.0.6",
+				"BOSH_VAR_password=with=equals",
+				"BOSH_VARIABLE=ignored",
+				"PATH=/usr/bin",
+			})
+
+			Expect(variables).To(Equal(Variables{
+				"director_ip": "10.0.0.6",
+				"password":    "with=equals",
+			}))
+		})
+	})
+
+	Describe("NewVariableFromArg", func() {
+		It("parses name=value", func() {
+			variables, err := NewVariableFromArg("password=with=equals")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(Equal(Variables{"password": "with=equals"}))
+		})
+
+		It("returns an error when the argument has no value", func() {
+			_, err := NewVariableFromArg("password")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Expected variable 'password' to be in the form 'name=value'"))
+		})
+	})
+})
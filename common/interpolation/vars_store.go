@@ -0,0 +1,107 @@
+package interpolation
+
+import (
+	"os"
+	"path/filepath"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	"github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v2"
+)
+
+// VarsStore persists the variables generated from the manifest 'variables' section,
+// so that they are reused by later deploys
+type VarsStore interface {
+	Load() (Variables, error)
+	Save(variables Variables) error
+}
+
+type readOnlyVarsStore struct {
+	varsStore VarsStore
+}
+
+// NewReadOnlyVarsStore returns a vars store that loads the stored variables but does not save any,
+// so that generated variables are only kept in memory
+func NewReadOnlyVarsStore(varsStore VarsStore) VarsStore {
+	return readOnlyVarsStore{varsStore: varsStore}
+}
+
+func (s readOnlyVarsStore) Load() (Variables, error) {
+	return s.varsStore.Load()
+}
+
+func (s readOnlyVarsStore) Save(variables Variables) error {
+	return nil
+}
+
+type fileVarsStore struct {
+	path string
+	fs   boshsys.FileSystem
+}
+
+func NewFileVarsStore(path string, fs boshsys.FileSystem) VarsStore {
+	return &fileVarsStore{
+		path: path,
+		fs:   fs,
+	}
+}
+
+func (s *fileVarsStore) Load() (Variables, error) {
+	if !s.fs.FileExists(s.path) {
+		return Variables{}, nil
+	}
+
+	return NewVariablesFromFile(s.path, s.fs)
+}
+
+// Save writes the variables to a file in the same directory, only readable by the user, which then replaces the vars store,
+// so that the stored variables are not lost when writing fails
+func (s *fileVarsStore) Save(variables Variables) error {
+	contents, err := yaml.Marshal(map[string]interface{}(variables))
+	if err != nil {
+		return bosherr.WrapError(err, "Marshalling vars store")
+	}
+
+	err = s.fs.MkdirAll(filepath.Dir(s.path), os.ModePerm)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Creating directory of vars store '%s'", s.path)
+	}
+
+	tmpPath := s.path + ".tmp"
+
+	// a file left by an earlier save may be readable by others, so it is not reused
+	err = s.fs.RemoveAll(tmpPath)
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Removing '%s'", tmpPath)
+	}
+
+	err = s.writeFile(tmpPath, contents)
+	if err != nil {
+		_ = s.fs.RemoveAll(tmpPath)
+		return bosherr.WrapErrorf(err, "Writing vars store '%s'", s.path)
+	}
+
+	err = s.fs.Rename(tmpPath, s.path)
+	if err != nil {
+		_ = s.fs.RemoveAll(tmpPath)
+		return bosherr.WrapErrorf(err, "Replacing vars store '%s'", s.path)
+	}
+
+	return nil
+}
+
+func (s *fileVarsStore) writeFile(path string, contents []byte) error {
+	file, err := s.fs.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(contents)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	// write errors may only be reported when the file is closed
+	return file.Close()
+}
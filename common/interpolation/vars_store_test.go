@@ -0,0 +1,146 @@
+package interpolation_test
+
+import (
+	"errors"
+	"os"
+
+	. "github.com/cloudfoundry/bosh-init/common/interpolation"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+)
+
+var _ = Describe("FileVarsStore", func() {
+	var (
+		fs        *fakesys.FakeFileSystem
+		varsStore VarsStore
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		varsStore = NewFileVarsStore("/creds.yml", fs)
+	})
+
+	Describe("Load", func() {
+		It("returns no variables when the file does not exist", func() {
+			variables, err := varsStore.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(Equal(Variables{}))
+		})
+
+		It("returns the stored variables", func() {
+			fs.WriteFileString("/creds.yml", "nats_password: fake-nats-password\n")
+
+			variables, err := varsStore.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(Equal(Variables{"nats_password": "fake-nats-password"}))
+		})
+	})
+
+	Describe("Save", func() {
+		It("writes the variables to the file, only readable by the user", func() {
+			err := varsStore.Save(Variables{
+				"nats_password": "fake-nats-password",
+				"mbus_cert":     map[interface{}]interface{}{"certificate": "fake-certificate"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.GetFileTestStat("/creds.yml").FileMode).To(Equal(os.FileMode(0600)))
+
+			variables, err := varsStore.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(variables).To(Equal(Variables{
+				"nats_password": "fake-nats-password",
+				"mbus_cert":     map[interface{}]interface{}{"certificate": "fake-certificate"},
+			}))
+		})
+
+		It("replaces an existing file with one only readable by the user", func() {
+			fs.WriteFileString("/creds.yml", "nats_password: fake-old-nats-password\n")
+			err := fs.Chmod("/creds.yml", 0644)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = varsStore.Save(Variables{"nats_password": "fake-nats-password"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fs.GetFileTestStat("/creds.yml").FileMode).To(Equal(os.FileMode(0600)))
+			Expect(fs.RenameOldPaths).To(Equal([]string{"/creds.yml.tmp"}))
+			Expect(fs.RenameNewPaths).To(Equal([]string{"/creds.yml"}))
+			Expect(fs.FileExists("/creds.yml.tmp")).To(BeFalse())
+
+			contents, err := fs.ReadFileString("/creds.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal("nats_password: fake-nats-password\n"))
+		})
+
+		Context("when closing the written file fails", func() {
+			BeforeEach(func() {
+				fs.WriteFileString("/creds.yml", "nats_password: fake-old-nats-password\n")
+
+				file := fakesys.NewFakeFile("/creds.yml.tmp", fs)
+				file.CloseErr = errors.New("fake-close-error")
+				fs.RegisterOpenFile("/creds.yml.tmp", file)
+			})
+
+			It("returns an error and keeps the stored variables", func() {
+				err := varsStore.Save(Variables{"nats_password": "fake-nats-password"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("Writing vars store '/creds.yml'"))
+				Expect(err.Error()).To(ContainSubstring("fake-close-error"))
+
+				contents, err := fs.ReadFileString("/creds.yml")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(contents).To(Equal("nats_password: fake-old-nats-password\n"))
+				Expect(fs.FileExists("/creds.yml.tmp")).To(BeFalse())
+			})
+		})
+
+		It("returns an error and keeps the stored variables when replacing the file fails", func() {
+			fs.WriteFileString("/creds.yml", "nats_password: fake-old-nats-password\n")
+			fs.RenameError = errors.New("fake-rename-error")
+
+			err := varsStore.Save(Variables{"nats_password": "fake-nats-password"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Replacing vars store '/creds.yml'"))
+
+			contents, err := fs.ReadFileString("/creds.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(contents).To(Equal("nats_password: fake-old-nats-password\n"))
+		})
+
+		It("returns an error when writing the file fails", func() {
+			fs.OpenFileErr = errors.New("fake-open-error")
+
+			err := varsStore.Save(Variables{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Writing vars store '/creds.yml'"))
+		})
+	})
+})
+
+var _ = Describe("ReadOnlyVarsStore", func() {
+	var (
+		fs        *fakesys.FakeFileSystem
+		varsStore VarsStore
+	)
+
+	BeforeEach(func() {
+		fs = fakesys.NewFakeFileSystem()
+		varsStore = NewReadOnlyVarsStore(NewFileVarsStore("/creds.yml", fs))
+	})
+
+	It("loads the stored variables", func() {
+		fs.WriteFileString("/creds.yml", "nats_password: fake-password\n")
+
+		variables, err := varsStore.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(variables).To(Equal(Variables{"nats_password": "fake-password"}))
+	})
+
+	It("does not save variables", func() {
+		err := varsStore.Save(Variables{"nats_password": "fake-password"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fs.FileExists("/creds.yml")).To(BeFalse())
+	})
+})
@@ -1,7 +1,10 @@
 package net
 
 import (
+	"bytes"
+	"fmt"
 	"net"
+	"strings"
 )
 
 func LastAddress(n *net.IPNet) net.IP {
@@ -23,3 +26,64 @@ func LastAddress(n *net.IPNet) net.IP {
 		ip[2]|^n.Mask[2],
 		ip[3]|^n.Mask[3])
 }
+
+// Netmask returns the mask of n in the notation of its address family:
+// dotted decimal for IPv4 ("255.255.255.0"), colon hexadecimal for IPv6 ("ffff:ffff:ffff:ffff::").
+func Netmask(n *net.IPNet) string {
+	if n.IP.To4() != nil && len(n.Mask) == net.IPv6len {
+		return net.IP(n.Mask[12:]).String()
+	}
+	return net.IP(n.Mask).String()
+}
+
+// IsIPv4 returns true if ip is an IPv4 address.
+func IsIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+// NextAddress returns the address following ip, wrapping around after the last address.
+func NextAddress(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// CompareAddresses returns -1, 0 or 1 if a is before, equal to or after b.
+func CompareAddresses(a, b net.IP) int {
+	return bytes.Compare(a.To16(), b.To16())
+}
+
+// ParseRange parses a single address ("10.0.0.5") or an inclusive range of addresses ("10.0.0.5 - 10.0.0.9")
+// and returns its first and last address.
+func ParseRange(ipRange string) (net.IP, net.IP, error) {
+	parts := strings.Split(ipRange, "-")
+	if len(parts) > 2 {
+		return nil, nil, fmt.Errorf("Invalid IP range '%s'", ipRange)
+	}
+
+	first := net.ParseIP(strings.TrimSpace(parts[0]))
+	if first == nil {
+		return nil, nil, fmt.Errorf("Invalid IP range '%s'", ipRange)
+	}
+
+	last := first
+	if len(parts) == 2 {
+		last = net.ParseIP(strings.TrimSpace(parts[1]))
+		if last == nil || IsIPv4(first) != IsIPv4(last) || CompareAddresses(first, last) > 0 {
+			return nil, nil, fmt.Errorf("Invalid IP range '%s'", ipRange)
+		}
+	}
+
+	return first, last, nil
+}
+
+// RangeContains returns true if ip is within the inclusive range of addresses from first to last.
+func RangeContains(first, last, ip net.IP) bool {
+	return IsIPv4(first) == IsIPv4(ip) && CompareAddresses(first, ip) <= 0 && CompareAddresses(ip, last) <= 0
+}
@@ -34,6 +34,62 @@ var _ = Describe("common.net ip helpers", func() {
 	})
 })
 
+var _ = Describe("Netmask", func() {
+	It("returns the mask in dotted decimal notation for IPv4", func() {
+		Expect(binet.Netmask(netFor("10.0.0.0/22"))).To(Equal("255.255.252.0"))
+		Expect(binet.Netmask(&net.IPNet{IP: net.ParseIP("10.0.0.0"), Mask: net.CIDRMask(24, 32)})).To(Equal("255.255.255.0"))
+	})
+
+	It("returns the mask in colon hexadecimal notation for IPv6", func() {
+		Expect(binet.Netmask(netFor("2001:db8::/64"))).To(Equal("ffff:ffff:ffff:ffff::"))
+		Expect(binet.Netmask(netFor("2001:db8::/52"))).To(Equal("ffff:ffff:ffff:f000::"))
+	})
+})
+
+var _ = Describe("NextAddress", func() {
+	It("returns the following address", func() {
+		Expect(binet.NextAddress(net.ParseIP("10.0.0.5")).Equal(net.ParseIP("10.0.0.6"))).To(BeTrue())
+		Expect(binet.NextAddress(net.ParseIP("10.0.0.255")).Equal(net.ParseIP("10.0.1.0"))).To(BeTrue())
+		Expect(binet.NextAddress(net.ParseIP("2001:db8::ffff")).Equal(net.ParseIP("2001:db8::1:0"))).To(BeTrue())
+	})
+})
+
+var _ = Describe("ParseRange", func() {
+	It("parses a single address", func() {
+		first, last, err := binet.ParseRange("10.0.0.5")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.Equal(net.ParseIP("10.0.0.5"))).To(BeTrue())
+		Expect(last.Equal(net.ParseIP("10.0.0.5"))).To(BeTrue())
+	})
+
+	It("parses a range of addresses", func() {
+		first, last, err := binet.ParseRange("10.0.0.5 - 10.0.0.9")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.Equal(net.ParseIP("10.0.0.5"))).To(BeTrue())
+		Expect(last.Equal(net.ParseIP("10.0.0.9"))).To(BeTrue())
+
+		Expect(binet.RangeContains(first, last, net.ParseIP("10.0.0.7"))).To(BeTrue())
+		Expect(binet.RangeContains(first, last, net.ParseIP("10.0.0.10"))).To(BeFalse())
+	})
+
+	It("parses a range of IPv6 addresses", func() {
+		first, last, err := binet.ParseRange("2001:db8::5 - 2001:db8::9")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(binet.RangeContains(first, last, net.ParseIP("2001:db8::7"))).To(BeTrue())
+		Expect(binet.RangeContains(first, last, net.ParseIP("2001:db8::a"))).To(BeFalse())
+		Expect(binet.RangeContains(first, last, net.ParseIP("10.0.0.7"))).To(BeFalse())
+	})
+
+	It("returns an error for invalid ranges", func() {
+		for _, ipRange := range []string{"", "10.0.0.5 -", "10.0.0.9 - 10.0.0.5", "a - b", "1 - 2 - 3", "10.0.0.5 - 2001:db8::5"} {
+			_, _, err := binet.ParseRange(ipRange)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Invalid IP range '" + ipRange + "'"))
+		}
+	})
+})
+
 func netFor(ipNetString string) *net.IPNet {
 	_, ipNet, err := net.ParseCIDR(ipNetString)
 	Expect(err).ToNot(HaveOccurred())
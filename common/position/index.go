@@ -0,0 +1,260 @@
+package position
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	yamlv3 "github.com/cloudfoundry/bosh-init/internal/gopkg.in/yaml.v3"
+)
+
+// Position is the location of a YAML node in a file
+type Position struct {
+	File    string
+	Line    int
+	Column  int
+	Snippet string
+}
+
+// String returns the position as 'file:line:col'
+func (p Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Index maps the paths of the nodes of a YAML document (e.g. 'jobs[0].networks[1].static_ips[0]') to their position.
+// Hash values are located at their key and array items at their first character.
+// The zero Index, like the index of a document that cannot be decoded, locates no node.
+type Index struct {
+	file      string
+	root      *yamlv3.Node
+	positions map[string]Position
+	// paths of the nodes of a mapped document that are not in the file
+	unmapped map[string]bool
+}
+
+var pathPattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+(\[\d+\])*(\.[^\s.\[\]]+(\[\d+\])*)*$`)
+
+// NewIndex indexes the positions of the nodes of the YAML document in contents, read from file
+func NewIndex(file string, contents []byte) Index {
+	index := Index{file: file, positions: map[string]Position{}}
+
+	root, err := decodeRoot(contents)
+	if err != nil {
+		return index
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+
+	index.root = root
+	index.record(root, "", lines)
+	return index
+}
+
+// Map indexes the document generated from the indexed one, e.g. by applying ops files, at the positions of the file.
+// Hash values are matched by key, and array items by their 'name' key,
+// or, for items without one, by their index when the array has as many items in both documents.
+// The nodes that cannot be matched are located at their closest matched ancestor.
+func (i Index) Map(generated []byte) Index {
+	mapped := Index{file: i.file, positions: map[string]Position{}, unmapped: map[string]bool{}}
+
+	root, err := decodeRoot(generated)
+	if err != nil || i.root == nil {
+		return mapped
+	}
+
+	mapped.mapNode(root, i.root, "", "", i.positions)
+	return mapped
+}
+
+// Locate returns the position of the node at path, or else of its closest indexed ancestor
+func (i Index) Locate(path string) (Position, bool) {
+	for path != "" {
+		if position, found := i.positions[path]; found {
+			return position, true
+		}
+		path = parentPath(path)
+	}
+	return Position{}, false
+}
+
+// Annotate prefixes each error that starts with the path of a node (e.g. 'jobs[0].name must be provided')
+// with the position of that node, and appends the line of the file where it is.
+// The errors of a MultiError are annotated one by one; other errors are returned unchanged.
+func (i Index) Annotate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if multiErr, ok := err.(bosherr.MultiError); ok {
+		errs := make([]error, len(multiErr.Errors))
+		for idx, err := range multiErr.Errors {
+			errs[idx] = i.Annotate(err)
+		}
+		return bosherr.NewMultiError(errs...)
+	}
+
+	message := err.Error()
+	path := strings.SplitN(message, " ", 2)[0]
+	if !pathPattern.MatchString(path) {
+		return err
+	}
+
+	position, found := i.Locate(path)
+	if !found {
+		return err
+	}
+
+	if unmappedPath, found := i.unmappedAncestor(path); found {
+		message = fmt.Sprintf("%s (%s is not in the file as is, it is set by ops files or variables)", message, unmappedPath)
+	}
+
+	snippetPrefix := "  " + strconv.Itoa(position.Line) + " | "
+	return bosherr.Errorf("%s: %s\n%s%s", position, message, snippetPrefix, position.Snippet)
+}
+
+func (i Index) record(node *yamlv3.Node, path string, lines []string) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			key, value := node.Content[idx], node.Content[idx+1]
+			childPath := keyPath(path, key.Value)
+			i.positions[childPath] = i.position(key, lines)
+			i.record(value, childPath, lines)
+		}
+	case yamlv3.SequenceNode:
+		for idx, item := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, idx)
+			i.positions[childPath] = i.position(item, lines)
+			i.record(item, childPath, lines)
+		}
+	}
+}
+
+func (i Index) position(node *yamlv3.Node, lines []string) Position {
+	snippet := ""
+	if node.Line > 0 && node.Line <= len(lines) {
+		snippet = lines[node.Line-1]
+	}
+	return Position{File: i.file, Line: node.Line, Column: node.Column, Snippet: snippet}
+}
+
+// mapNode locates the children of the generated node at the positions of the children of the file node, which may be nil
+func (i Index) mapNode(generated, fileNode *yamlv3.Node, path, filePath string, filePositions map[string]Position) {
+	switch generated.Kind {
+	case yamlv3.MappingNode:
+		for idx := 0; idx+1 < len(generated.Content); idx += 2 {
+			key, value := generated.Content[idx], generated.Content[idx+1]
+			childPath := keyPath(path, key.Value)
+
+			fileValue := mappingValue(fileNode, key.Value)
+			if fileValue == nil {
+				i.unmapped[childPath] = true
+				i.mapNode(value, nil, childPath, "", filePositions)
+				continue
+			}
+
+			childFilePath := keyPath(filePath, key.Value)
+			i.positions[childPath] = filePositions[childFilePath]
+			i.mapNode(value, fileValue, childPath, childFilePath, filePositions)
+		}
+	case yamlv3.SequenceNode:
+		for idx, item := range generated.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, idx)
+
+			fileIdx, found := matchingItem(generated, fileNode, idx)
+			if !found {
+				i.unmapped[childPath] = true
+				i.mapNode(item, nil, childPath, "", filePositions)
+				continue
+			}
+
+			childFilePath := fmt.Sprintf("%s[%d]", filePath, fileIdx)
+			i.positions[childPath] = filePositions[childFilePath]
+			i.mapNode(item, fileNode.Content[fileIdx], childPath, childFilePath, filePositions)
+		}
+	}
+}
+
+// unmappedAncestor returns the topmost path, among path and its ancestors, of a node that is not in the file
+func (i Index) unmappedAncestor(path string) (string, bool) {
+	unmappedPath := ""
+	for ; path != ""; path = parentPath(path) {
+		if i.unmapped[path] {
+			unmappedPath = path
+		}
+	}
+	return unmappedPath, unmappedPath != ""
+}
+
+func decodeRoot(contents []byte) (*yamlv3.Node, error) {
+	var document yamlv3.Node
+	err := yamlv3.Unmarshal(contents, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	if document.Kind != yamlv3.DocumentNode || len(document.Content) == 0 {
+		return nil, bosherr.Error("Expected a YAML document")
+	}
+
+	return document.Content[0], nil
+}
+
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		if node.Content[idx].Value == key {
+			return node.Content[idx+1]
+		}
+	}
+	return nil
+}
+
+// matchingItem returns the index of the item of the file sequence that is the item at idx of the generated sequence
+func matchingItem(generated, fileNode *yamlv3.Node, idx int) (int, bool) {
+	if fileNode == nil || fileNode.Kind != yamlv3.SequenceNode {
+		return 0, false
+	}
+
+	if name := mappingValue(generated.Content[idx], "name"); name != nil && name.Kind == yamlv3.ScalarNode {
+		for fileIdx, fileItem := range fileNode.Content {
+			fileName := mappingValue(fileItem, "name")
+			if fileName != nil && fileName.Kind == yamlv3.ScalarNode && fileName.Value == name.Value {
+				return fileIdx, true
+			}
+		}
+		return 0, false
+	}
+
+	if len(generated.Content) == len(fileNode.Content) {
+		return idx, true
+	}
+	return 0, false
+}
+
+func keyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func parentPath(path string) string {
+	lastDot := strings.LastIndex(path, ".")
+	lastBracket := strings.LastIndex(path, "[")
+	if lastBracket > lastDot {
+		return path[:lastBracket]
+	}
+	if lastDot >= 0 {
+		return path[:lastDot]
+	}
+	return ""
+}
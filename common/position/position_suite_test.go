@@ -0,0 +1,13 @@
+package position_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPosition(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Common Position Suite")
+}
@@ -5,16 +5,22 @@ import (
 )
 
 type DeploymentState struct {
-	DirectorID          string           `json:"director_id"`
-	InstallationID      string           `json:"installation_id"`
-	CurrentVMCID        string           `json:"current_vm_cid"`
-	CurrentStemcellID   string           `json:"current_stemcell_id"`
-	CurrentDiskID       string           `json:"current_disk_id"`
-	CurrentReleaseIDs   []string         `json:"current_release_ids"`
-	CurrentManifestSHA1 string           `json:"current_manifest_sha1"`
-	Disks               []DiskRecord     `json:"disks"`
-	Stemcells           []StemcellRecord `json:"stemcells"`
-	Releases            []ReleaseRecord  `json:"releases"`
+	DirectorID          string               `json:"director_id"`
+	InstallationID      string               `json:"installation_id"`
+	CurrentStemcellID   string               `json:"current_stemcell_id"`
+	CurrentReleaseIDs   []string             `json:"current_release_ids"`
+	CurrentManifestSHA1 string               `json:"current_manifest_sha1"`
+	Disks               []DiskRecord         `json:"disks"`
+	Stemcells           []StemcellRecord     `json:"stemcells"`
+	Releases            []ReleaseRecord      `json:"releases"`
+	Instances           []InstanceRecord     `json:"instances,omitempty"`
+	IPAllocations       []IPAllocationRecord `json:"ip_allocations,omitempty"`
+
+	// The job name, VM and persistent disk of the bootstrap instance in deployment states saved before
+	// every instance had a record; they are only read, to be migrated to the record of the bootstrap instance
+	LegacyJobName string `json:"current_job_name,omitempty"`
+	LegacyVMCID   string `json:"current_vm_cid,omitempty"`
+	LegacyDiskID  string `json:"current_disk_id,omitempty"`
 }
 
 type StemcellRecord struct {
@@ -31,6 +37,28 @@ type DiskRecord struct {
 	CloudProperties biproperty.Map `json:"cloud_properties"`
 }
 
+// InstanceRecord tracks the ID, VM and persistent disk of an instance.
+// The bootstrap instance (the first instance of the first job) is flagged and has no host, as it is reached on the hosts
+// of the installation manifest. Its job name is empty in deployment states saved before job names were recorded.
+type InstanceRecord struct {
+	ID        string `json:"id,omitempty"`
+	Job       string `json:"job"`
+	Index     int    `json:"index"`
+	Bootstrap bool   `json:"bootstrap,omitempty"`
+	Host      string `json:"host"`
+	VMCID     string `json:"vm_cid"`
+	DiskID    string `json:"disk_id"`
+}
+
+// IPAllocationRecord tracks an IP allocated to an instance from the static ranges of a manual network,
+// so that the instance keeps its IP across deploys
+type IPAllocationRecord struct {
+	Job     string `json:"job"`
+	Index   int    `json:"index"`
+	Network string `json:"network"`
+	IP      string `json:"ip"`
+}
+
 type ReleaseRecord struct {
 	ID      string `json:"id"`
 	Name    string `json:"name"`
@@ -0,0 +1,109 @@
+package config
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// DeploymentStateValidator finds records in the deployment state that do not reference each other correctly
+type DeploymentStateValidator interface {
+	Validate(DeploymentState) error
+}
+
+type deploymentStateValidator struct{}
+
+func NewDeploymentStateValidator() DeploymentStateValidator {
+	return &deploymentStateValidator{}
+}
+
+func (v *deploymentStateValidator) Validate(deploymentState DeploymentState) error {
+	errs := []error{}
+
+	if deploymentState.CurrentStemcellID != "" {
+		if _, found := v.findStemcell(deploymentState.Stemcells, deploymentState.CurrentStemcellID); !found {
+			errs = append(errs, bosherr.Errorf("current_stemcell_id '%s' does not match any stemcell record", deploymentState.CurrentStemcellID))
+		}
+	}
+
+	for idx, stemcellRecord := range deploymentState.Stemcells {
+		if stemcellRecord.ID != deploymentState.CurrentStemcellID {
+			errs = append(errs, bosherr.Errorf("stemcells[%d] (cid=%s) is not the current stemcell", idx, stemcellRecord.CID))
+		}
+	}
+
+	currentDiskIDs := map[string]bool{}
+	for idx, instanceRecord := range deploymentState.Instances {
+		if instanceRecord.DiskID == "" {
+			continue
+		}
+		if _, found := v.findDisk(deploymentState.Disks, instanceRecord.DiskID); !found {
+			errs = append(errs, bosherr.Errorf("instances[%d].disk_id '%s' does not match any disk record", idx, instanceRecord.DiskID))
+		}
+		currentDiskIDs[instanceRecord.DiskID] = true
+	}
+
+	for idx, diskRecord := range deploymentState.Disks {
+		if !currentDiskIDs[diskRecord.ID] {
+			errs = append(errs, bosherr.Errorf("disks[%d] (cid=%s) is not the current disk", idx, diskRecord.CID))
+		}
+	}
+
+	for _, releaseID := range deploymentState.CurrentReleaseIDs {
+		if _, found := v.findRelease(deploymentState.Releases, releaseID); !found {
+			errs = append(errs, bosherr.Errorf("current_release_ids entry '%s' does not match any release record", releaseID))
+		}
+	}
+
+	for idx, releaseRecord := range deploymentState.Releases {
+		if !v.contains(deploymentState.CurrentReleaseIDs, releaseRecord.ID) {
+			errs = append(errs, bosherr.Errorf("releases[%d] (%s/%s) is not a current release", idx, releaseRecord.Name, releaseRecord.Version))
+		}
+	}
+
+	for idx, instanceRecord := range deploymentState.Instances {
+		if instanceRecord.VMCID != "" && deploymentState.CurrentStemcellID == "" {
+			errs = append(errs, bosherr.Errorf("instances[%d].vm_cid '%s' is set but current_stemcell_id is not", idx, instanceRecord.VMCID))
+		}
+	}
+
+	if len(errs) > 0 {
+		return bosherr.NewMultiError(errs...)
+	}
+
+	return nil
+}
+
+func (v *deploymentStateValidator) findStemcell(records []StemcellRecord, id string) (StemcellRecord, bool) {
+	for _, record := range records {
+		if record.ID == id {
+			return record, true
+		}
+	}
+	return StemcellRecord{}, false
+}
+
+func (v *deploymentStateValidator) findDisk(records []DiskRecord, id string) (DiskRecord, bool) {
+	for _, record := range records {
+		if record.ID == id {
+			return record, true
+		}
+	}
+	return DiskRecord{}, false
+}
+
+func (v *deploymentStateValidator) findRelease(records []ReleaseRecord, id string) (ReleaseRecord, bool) {
+	for _, record := range records {
+		if record.ID == id {
+			return record, true
+		}
+	}
+	return ReleaseRecord{}, false
+}
+
+func (v *deploymentStateValidator) contains(ids []string, id string) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/config"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+)
+
+var _ = Describe("DeploymentStateValidator", func() {
+	var (
+		validator       DeploymentStateValidator
+		deploymentState DeploymentState
+	)
+
+	BeforeEach(func() {
+		validator = NewDeploymentStateValidator()
+
+		deploymentState = DeploymentState{
+			DirectorID:        "fake-director-id",
+			CurrentStemcellID: "fake-stemcell-id",
+			CurrentReleaseIDs: []string{"fake-release-id"},
+			Instances: []InstanceRecord{
+				{Bootstrap: true, Job: "fake-job-name", VMCID: "fake-vm-cid", DiskID: "fake-disk-id"},
+			},
+			Stemcells: []StemcellRecord{
+				{ID: "fake-stemcell-id", Name: "fake-stemcell-name", Version: "1", CID: "fake-stemcell-cid"},
+			},
+			Disks: []DiskRecord{
+				{ID: "fake-disk-id", CID: "fake-disk-cid", Size: 1024},
+			},
+			Releases: []ReleaseRecord{
+				{ID: "fake-release-id", Name: "fake-release-name", Version: "2"},
+			},
+		}
+	})
+
+	It("does not error if the state is consistent", func() {
+		err := validator.Validate(deploymentState)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("does not error if the state is empty", func() {
+		err := validator.Validate(DeploymentState{DirectorID: "fake-director-id"})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reports a current disk id that matches no disk record", func() {
+		deploymentState.Instances[0].DiskID = "fake-missing-disk-id"
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("instances[0].disk_id 'fake-missing-disk-id' does not match any disk record"))
+		Expect(err.Error()).To(ContainSubstring("disks[0] (cid=fake-disk-cid) is not the current disk"))
+	})
+
+	It("does not report the disks of other instances", func() {
+		deploymentState.Disks = append(deploymentState.Disks, DiskRecord{ID: "fake-other-disk-id", CID: "fake-other-disk-cid", Size: 1024})
+		deploymentState.Instances = append(deploymentState.Instances, InstanceRecord{
+			Job: "fake-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-other-vm-cid", DiskID: "fake-other-disk-id",
+		})
+
+		err := validator.Validate(deploymentState)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("reports an instance disk id that matches no disk record", func() {
+		deploymentState.Instances = append(deploymentState.Instances, InstanceRecord{
+			Job: "fake-job-name", Index: 1, Host: "10.0.0.7", DiskID: "fake-missing-disk-id",
+		})
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("instances[1].disk_id 'fake-missing-disk-id' does not match any disk record"))
+	})
+
+	It("reports a current stemcell id that matches no stemcell record", func() {
+		deploymentState.CurrentStemcellID = "fake-missing-stemcell-id"
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("current_stemcell_id 'fake-missing-stemcell-id' does not match any stemcell record"))
+	})
+
+	It("reports stemcell records that are not current", func() {
+		deploymentState.Stemcells = append(deploymentState.Stemcells, StemcellRecord{
+			ID: "fake-old-stemcell-id", Name: "fake-stemcell-name", Version: "0", CID: "fake-old-stemcell-cid",
+		})
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(bosherr.MultiError).Errors).To(HaveLen(1))
+		Expect(err.Error()).To(Equal("stemcells[1] (cid=fake-old-stemcell-cid) is not the current stemcell"))
+	})
+
+	It("reports release records that are not current and current release ids without records", func() {
+		deploymentState.CurrentReleaseIDs = []string{"fake-missing-release-id"}
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(bosherr.MultiError).Errors).To(HaveLen(2))
+		Expect(err.Error()).To(ContainSubstring("current_release_ids entry 'fake-missing-release-id' does not match any release record"))
+		Expect(err.Error()).To(ContainSubstring("releases[0] (fake-release-name/2) is not a current release"))
+	})
+
+	It("reports a current vm without a current stemcell", func() {
+		deploymentState.CurrentStemcellID = ""
+		deploymentState.Stemcells = []StemcellRecord{}
+
+		err := validator.Validate(deploymentState)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("instances[0].vm_cid 'fake-vm-cid' is set but current_stemcell_id is not"))
+	})
+})
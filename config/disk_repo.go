@@ -9,6 +9,7 @@ import (
 type DiskRepo interface {
 	UpdateCurrent(diskID string) error
 	FindCurrent() (DiskRecord, bool, error)
+	FindAllCurrent() ([]DiskRecord, error)
 	ClearCurrent() error
 	Save(cid string, size int, cloudProperties biproperty.Map) (DiskRecord, error)
 	Find(cid string) (DiskRecord, bool, error)
@@ -18,12 +19,16 @@ type DiskRepo interface {
 
 type diskRepo struct {
 	deploymentStateService DeploymentStateService
+	instanceKey            InstanceKey
 	uuidGenerator          boshuuid.Generator
 }
 
-func NewDiskRepo(deploymentStateService DeploymentStateService, uuidGenerator boshuuid.Generator) DiskRepo {
+// NewDiskRepo returns a repo of the disks of a deployment.
+// The current disk is the persistent disk of the instance, which is kept in the record of the instance.
+func NewDiskRepo(deploymentStateService DeploymentStateService, instanceKey InstanceKey, uuidGenerator boshuuid.Generator) DiskRepo {
 	return diskRepo{
 		deploymentStateService: deploymentStateService,
+		instanceKey:            instanceKey,
 		uuidGenerator:          uuidGenerator,
 	}
 }
@@ -65,13 +70,13 @@ func (r diskRepo) FindCurrent() (DiskRecord, bool, error) {
 		return DiskRecord{}, false, bosherr.WrapError(err, "Loading existing config")
 	}
 
-	currentDiskID := deploymentState.CurrentDiskID
-	if currentDiskID == "" {
+	instanceRecord, found := r.instanceKey.find(deploymentState)
+	if !found || instanceRecord.DiskID == "" {
 		return DiskRecord{}, false, nil
 	}
 
 	for _, oldRecord := range deploymentState.Disks {
-		if oldRecord.ID == currentDiskID {
+		if oldRecord.ID == instanceRecord.DiskID {
 			return oldRecord, true, nil
 		}
 	}
@@ -79,6 +84,28 @@ func (r diskRepo) FindCurrent() (DiskRecord, bool, error) {
 	return DiskRecord{}, false, nil
 }
 
+// FindAllCurrent returns the current disks of every instance
+func (r diskRepo) FindAllCurrent() ([]DiskRecord, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return []DiskRecord{}, bosherr.WrapError(err, "Loading existing config")
+	}
+
+	currentDiskIDs := map[string]bool{}
+	for _, instance := range deploymentState.Instances {
+		currentDiskIDs[instance.DiskID] = true
+	}
+
+	diskRecords := []DiskRecord{}
+	for _, diskRecord := range deploymentState.Disks {
+		if diskRecord.ID != "" && currentDiskIDs[diskRecord.ID] {
+			diskRecords = append(diskRecords, diskRecord)
+		}
+	}
+
+	return diskRecords, nil
+}
+
 func (r diskRepo) UpdateCurrent(diskID string) error {
 	deploymentState, err := r.deploymentStateService.Load()
 	if err != nil {
@@ -95,7 +122,9 @@ func (r diskRepo) UpdateCurrent(diskID string) error {
 		return bosherr.Errorf("Verifying disk record exists with id '%s'", diskID)
 	}
 
-	deploymentState.CurrentDiskID = diskID
+	r.instanceKey.update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.DiskID = diskID
+	})
 
 	err = r.deploymentStateService.Save(deploymentState)
 	if err != nil {
@@ -138,8 +167,10 @@ func (r diskRepo) Delete(diskRecord DiskRecord) error {
 
 	config.Disks = newRecords
 
-	if config.CurrentDiskID == diskRecord.ID {
-		config.CurrentDiskID = ""
+	for idx := range config.Instances {
+		if config.Instances[idx].DiskID == diskRecord.ID {
+			config.Instances[idx].DiskID = ""
+		}
 	}
 
 	err = r.deploymentStateService.Save(config)
@@ -156,7 +187,9 @@ func (r diskRepo) ClearCurrent() error {
 		return bosherr.WrapError(err, "Loading existing config")
 	}
 
-	deploymentState.CurrentDiskID = ""
+	r.instanceKey.update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.DiskID = ""
+	})
 
 	err = r.deploymentStateService.Save(deploymentState)
 	if err != nil {
@@ -25,7 +25,7 @@ var _ = Describe("DiskRepo", func() {
 		fs = fakesys.NewFakeFileSystem()
 		fakeUUIDGenerator = &fakeuuid.FakeGenerator{}
 		deploymentStateService = NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/fake/path")
-		repo = NewDiskRepo(deploymentStateService, fakeUUIDGenerator)
+		repo = NewDiskRepo(deploymentStateService, BootstrapInstanceKey(), fakeUUIDGenerator)
 		cloudProperties = biproperty.Map{
 			"fake-cloud_property-key": "fake-cloud-property-value",
 		}
@@ -100,7 +100,9 @@ var _ = Describe("DiskRepo", func() {
 				deploymentState, err := deploymentStateService.Load()
 				Expect(err).ToNot(HaveOccurred())
 
-				Expect(deploymentState.CurrentDiskID).To(Equal(recordID))
+				Expect(deploymentState.Instances).To(Equal([]InstanceRecord{
+					{Bootstrap: true, DiskID: recordID},
+				}))
 			})
 		})
 
@@ -169,6 +171,40 @@ var _ = Describe("DiskRepo", func() {
 		})
 	})
 
+	Describe("FindAllCurrent", func() {
+		var (
+			firstDisk DiskRecord
+			thirdDisk DiskRecord
+		)
+
+		BeforeEach(func() {
+			var err error
+
+			firstDisk, err = repo.Save("fake-cid-1", 1024, cloudProperties)
+			Expect(err).ToNot(HaveOccurred())
+			err = repo.UpdateCurrent(firstDisk.ID)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = repo.Save("fake-cid-2", 1024, cloudProperties)
+			Expect(err).ToNot(HaveOccurred())
+
+			thirdDisk, err = repo.Save("fake-cid-3", 1024, cloudProperties)
+			Expect(err).ToNot(HaveOccurred())
+			instanceRepo := NewDiskRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"), fakeUUIDGenerator)
+			err = instanceRepo.UpdateCurrent(thirdDisk.ID)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the current disks of all instances", func() {
+			disks, err := repo.FindAllCurrent()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disks).To(Equal([]DiskRecord{
+				firstDisk,
+				thirdDisk,
+			}))
+		})
+	})
+
 	Describe("All", func() {
 		var (
 			firstDisk  DiskRecord
@@ -242,6 +278,25 @@ var _ = Describe("DiskRepo", func() {
 				Expect(found).To(BeFalse())
 			})
 		})
+
+		Context("when the disk to be deleted is the current disk of another instance", func() {
+			var instanceRepo DiskRepo
+
+			BeforeEach(func() {
+				instanceRepo = NewDiskRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"), fakeUUIDGenerator)
+				err := instanceRepo.UpdateCurrent(firstDisk.ID)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("clears the current disk of the instance", func() {
+				err := repo.Delete(firstDisk)
+				Expect(err).ToNot(HaveOccurred())
+
+				_, found, err := instanceRepo.FindCurrent()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
 	})
 
 	Describe("ClearCurrent", func() {
@@ -253,8 +308,10 @@ var _ = Describe("DiskRepo", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			expectedConfig := DeploymentState{
-				DirectorID:    "fake-uuid-0",
-				CurrentDiskID: "",
+				DirectorID: "fake-uuid-0",
+				Instances: []InstanceRecord{
+					{Bootstrap: true},
+				},
 			}
 			Expect(deploymentState).To(Equal(expectedConfig))
 
@@ -11,6 +11,8 @@ type FakeDiskRepo struct {
 
 	findCurrentOutput diskRepoFindCurrentOutput
 
+	findAllCurrentOutput diskRepoAllOutput
+
 	SaveInputs []DiskRepoSaveInput
 	saveOutput diskRepoSaveOutput
 
@@ -78,6 +80,10 @@ func (r *FakeDiskRepo) FindCurrent() (biconfig.DiskRecord, bool, error) {
 	return r.findCurrentOutput.diskRecord, r.findCurrentOutput.found, r.findCurrentOutput.err
 }
 
+func (r *FakeDiskRepo) FindAllCurrent() ([]biconfig.DiskRecord, error) {
+	return r.findAllCurrentOutput.diskRecords, r.findAllCurrentOutput.err
+}
+
 func (r *FakeDiskRepo) ClearCurrent() error {
 	return nil
 }
@@ -120,6 +126,13 @@ func (r *FakeDiskRepo) SetFindCurrentBehavior(diskRecord biconfig.DiskRecord, fo
 	}
 }
 
+func (r *FakeDiskRepo) SetFindAllCurrentBehavior(diskRecords []biconfig.DiskRecord, err error) {
+	r.findAllCurrentOutput = diskRepoAllOutput{
+		diskRecords: diskRecords,
+		err:         err,
+	}
+}
+
 func (r *FakeDiskRepo) SetSaveBehavior(diskRecord biconfig.DiskRecord, found bool, err error) {
 	r.saveOutput = diskRepoSaveOutput{
 		diskRecord: diskRecord,
@@ -0,0 +1,76 @@
+package fakes
+
+import (
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+)
+
+type FakeInstanceRepo struct {
+	UpdateBootstrapJobNameJobName string
+	UpdateBootstrapJobNameErr     error
+
+	ClearAllCalled bool
+	ClearAllErr    error
+
+	findBootstrapOutput instanceRepoFindBootstrapOutput
+	allOutput           instanceRepoAllOutput
+}
+
+type instanceRepoFindBootstrapOutput struct {
+	instanceRecord biconfig.InstanceRecord
+	err            error
+}
+
+type instanceRepoAllOutput struct {
+	instanceRecords []biconfig.InstanceRecord
+	err             error
+}
+
+func NewFakeInstanceRepo() *FakeInstanceRepo {
+	return &FakeInstanceRepo{}
+}
+
+func (r *FakeInstanceRepo) FindBootstrap() (biconfig.InstanceRecord, error) {
+	return r.findBootstrapOutput.instanceRecord, r.findBootstrapOutput.err
+}
+
+func (r *FakeInstanceRepo) UpdateBootstrapJobName(jobName string) error {
+	r.UpdateBootstrapJobNameJobName = jobName
+	return r.UpdateBootstrapJobNameErr
+}
+
+func (r *FakeInstanceRepo) Find(jobName string, index int) (biconfig.InstanceRecord, bool, error) {
+	bootstrapRecord := r.findBootstrapOutput.instanceRecord
+	if bootstrapRecord.Job == jobName && bootstrapRecord.Index == index {
+		return bootstrapRecord, true, r.findBootstrapOutput.err
+	}
+
+	for _, instanceRecord := range r.allOutput.instanceRecords {
+		if instanceRecord.Job == jobName && instanceRecord.Index == index {
+			return instanceRecord, true, r.allOutput.err
+		}
+	}
+	return biconfig.InstanceRecord{}, false, r.allOutput.err
+}
+
+func (r *FakeInstanceRepo) All() ([]biconfig.InstanceRecord, error) {
+	return r.allOutput.instanceRecords, r.allOutput.err
+}
+
+func (r *FakeInstanceRepo) ClearAll() error {
+	r.ClearAllCalled = true
+	return r.ClearAllErr
+}
+
+func (r *FakeInstanceRepo) SetFindBootstrapBehavior(instanceRecord biconfig.InstanceRecord, err error) {
+	r.findBootstrapOutput = instanceRepoFindBootstrapOutput{
+		instanceRecord: instanceRecord,
+		err:            err,
+	}
+}
+
+func (r *FakeInstanceRepo) SetAllBehavior(instanceRecords []biconfig.InstanceRecord, err error) {
+	r.allOutput = instanceRepoAllOutput{
+		instanceRecords: instanceRecords,
+		err:             err,
+	}
+}
@@ -65,6 +65,8 @@ func (s *fileSystemDeploymentStateService) Load() (DeploymentState, error) {
 		}
 	}
 
+	migrateBootstrapInstance(deploymentState)
+
 	err := s.initDefaults(deploymentState)
 	if err != nil {
 		return DeploymentState{}, bosherr.WrapErrorf(err, "Initializing deployment state defaults")
@@ -110,6 +112,24 @@ func (s *fileSystemDeploymentStateService) initDefaults(deploymentState *Deploym
 	return nil
 }
 
+// migrateBootstrapInstance moves the job name, VM and persistent disk of the bootstrap instance
+// from the fields of deployment states saved before every instance had a record to the record of the bootstrap instance
+func migrateBootstrapInstance(deploymentState *DeploymentState) {
+	if deploymentState.LegacyJobName == "" && deploymentState.LegacyVMCID == "" && deploymentState.LegacyDiskID == "" {
+		return
+	}
+
+	BootstrapInstanceKey().update(deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.Job = deploymentState.LegacyJobName
+		instanceRecord.VMCID = deploymentState.LegacyVMCID
+		instanceRecord.DiskID = deploymentState.LegacyDiskID
+	})
+
+	deploymentState.LegacyJobName = ""
+	deploymentState.LegacyVMCID = ""
+	deploymentState.LegacyDiskID = ""
+}
+
 func (s *fileSystemDeploymentStateService) Cleanup() error {
 	err := s.fs.RemoveAll(s.configPath)
 	if err != nil {
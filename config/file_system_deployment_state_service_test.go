@@ -77,9 +77,10 @@ var _ = Describe("fileSystemDeploymentStateService", func() {
 				"director_id":     "fake-director-id",
 				"deployment_id":   "fake-deployment-id",
 				"stemcells":       stemcells,
-				"current_vm_cid":  "fake-vm-cid",
-				"current_disk_id": "fake-disk-id",
 				"disks":           disks,
+				"instances": []InstanceRecord{
+					{Bootstrap: true, Job: "fake-job-name", VMCID: "fake-vm-cid", DiskID: "fake-disk-id"},
+				},
 			})
 			fakeFs.WriteFile(deploymentStatePath, deploymentStateFileContents)
 
@@ -87,11 +88,50 @@ var _ = Describe("fileSystemDeploymentStateService", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(deploymentState.DirectorID).To(Equal("fake-director-id"))
 			Expect(deploymentState.Stemcells).To(Equal(stemcells))
-			Expect(deploymentState.CurrentVMCID).To(Equal("fake-vm-cid"))
-			Expect(deploymentState.CurrentDiskID).To(Equal("fake-disk-id"))
+			Expect(deploymentState.Instances).To(Equal([]InstanceRecord{
+				{Bootstrap: true, Job: "fake-job-name", VMCID: "fake-vm-cid", DiskID: "fake-disk-id"},
+			}))
 			Expect(deploymentState.Disks).To(Equal(disks))
 		})
 
+		Context("when the config was saved before every instance had a record", func() {
+			BeforeEach(func() {
+				fakeFs.WriteFileString(deploymentStatePath, `{
+					"director_id": "fake-director-id",
+					"current_job_name": "fake-job-name",
+					"current_vm_cid": "fake-vm-cid",
+					"current_disk_id": "fake-disk-id",
+					"instances": [{"job": "fake-other-job-name", "index": 1, "host": "10.0.0.7", "vm_cid": "fake-other-vm-cid", "disk_id": ""}]
+				}`)
+			})
+
+			It("migrates the current vm and disk to the record of the bootstrap instance", func() {
+				deploymentState, err := service.Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deploymentState.Instances).To(Equal([]InstanceRecord{
+					{Bootstrap: true, Job: "fake-job-name", VMCID: "fake-vm-cid", DiskID: "fake-disk-id"},
+					{Job: "fake-other-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-other-vm-cid"},
+				}))
+				Expect(deploymentState.LegacyJobName).To(BeEmpty())
+				Expect(deploymentState.LegacyVMCID).To(BeEmpty())
+				Expect(deploymentState.LegacyDiskID).To(BeEmpty())
+			})
+
+			It("does not save the migrated fields", func() {
+				deploymentState, err := service.Load()
+				Expect(err).NotTo(HaveOccurred())
+
+				err = service.Save(deploymentState)
+				Expect(err).NotTo(HaveOccurred())
+
+				deploymentStateFileContents, err := fakeFs.ReadFileString(deploymentStatePath)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deploymentStateFileContents).ToNot(ContainSubstring("current_vm_cid"))
+				Expect(deploymentStateFileContents).ToNot(ContainSubstring("current_disk_id"))
+				Expect(deploymentStateFileContents).ToNot(ContainSubstring("current_job_name"))
+			})
+		})
+
 		Context("when the config does not exist", func() {
 			It("returns a new DeploymentState with generated defaults", func() {
 				deploymentState, err := service.Load()
@@ -139,7 +179,9 @@ var _ = Describe("fileSystemDeploymentStateService", func() {
 						CID:     "fake-stemcell-cid",
 					},
 				},
-				CurrentVMCID: "fake-vm-cid",
+				Instances: []InstanceRecord{
+					{Bootstrap: true, VMCID: "fake-vm-cid"},
+				},
 				Disks: []DiskRecord{
 					{
 						CID:  "fake-disk-cid",
@@ -164,7 +206,9 @@ var _ = Describe("fileSystemDeploymentStateService", func() {
 						CID:     "fake-stemcell-cid",
 					},
 				},
-				CurrentVMCID: "fake-vm-cid",
+				Instances: []InstanceRecord{
+					{Bootstrap: true, VMCID: "fake-vm-cid"},
+				},
 				Disks: []DiskRecord{
 					{
 						CID:  "fake-disk-cid",
@@ -0,0 +1,54 @@
+package config
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
+)
+
+type InstanceIDRepo interface {
+	// FindOrCreate returns the ID of the instance, which is generated and recorded the first time
+	FindOrCreate() (string, error)
+}
+
+type instanceIDRepo struct {
+	deploymentStateService DeploymentStateService
+	instanceKey            InstanceKey
+	uuidGenerator          boshuuid.Generator
+}
+
+// NewInstanceIDRepo returns a repo of the ID of an instance, which is kept in the record of the instance
+// so that the instance keeps its ID when its VM is recreated
+func NewInstanceIDRepo(deploymentStateService DeploymentStateService, instanceKey InstanceKey, uuidGenerator boshuuid.Generator) InstanceIDRepo {
+	return instanceIDRepo{
+		deploymentStateService: deploymentStateService,
+		instanceKey:            instanceKey,
+		uuidGenerator:          uuidGenerator,
+	}
+}
+
+func (r instanceIDRepo) FindOrCreate() (string, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return "", bosherr.WrapError(err, "Loading existing config")
+	}
+
+	instanceRecord, found := r.instanceKey.find(deploymentState)
+	if found && instanceRecord.ID != "" {
+		return instanceRecord.ID, nil
+	}
+
+	id, err := r.uuidGenerator.Generate()
+	if err != nil {
+		return "", bosherr.WrapError(err, "Generating instance id")
+	}
+
+	r.instanceKey.update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.ID = id
+	})
+
+	err = r.deploymentStateService.Save(deploymentState)
+	if err != nil {
+		return "", bosherr.WrapError(err, "Saving new config")
+	}
+	return id, nil
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/config"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+)
+
+var _ = Describe("InstanceIDRepo", func() {
+	var (
+		repo                   InstanceIDRepo
+		deploymentStateService DeploymentStateService
+		fakeUUIDGenerator      *fakeuuid.FakeGenerator
+	)
+
+	BeforeEach(func() {
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		fs := fakesys.NewFakeFileSystem()
+		deploymentStateService = NewFileSystemDeploymentStateService(fs, &fakeuuid.FakeGenerator{}, logger, "/fake/path")
+		fakeUUIDGenerator = &fakeuuid.FakeGenerator{GeneratedUUID: "fake-instance-id"}
+		repo = NewInstanceIDRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"), fakeUUIDGenerator)
+	})
+
+	Describe("FindOrCreate", func() {
+		It("generates the id of the instance and records it with its host", func() {
+			id, err := repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("fake-instance-id"))
+
+			deploymentState, err := deploymentStateService.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentState.Instances).To(ContainElement(
+				InstanceRecord{ID: "fake-instance-id", Job: "fake-job-name", Index: 1, Host: "10.0.0.7"},
+			))
+		})
+
+		It("returns the recorded id of the instance", func() {
+			_, err := repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			fakeUUIDGenerator.GeneratedUUID = "fake-other-instance-id"
+
+			id, err := repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("fake-instance-id"))
+		})
+
+		It("keeps the id of the instance when its vm is recreated", func() {
+			vmRepo := NewVMRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"))
+			err := vmRepo.UpdateCurrent("fake-vm-cid")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+
+			err = vmRepo.ClearCurrent()
+			Expect(err).ToNot(HaveOccurred())
+			err = vmRepo.UpdateCurrent("fake-new-vm-cid")
+			Expect(err).ToNot(HaveOccurred())
+
+			fakeUUIDGenerator.GeneratedUUID = "fake-other-instance-id"
+			id, err := repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("fake-instance-id"))
+		})
+
+		It("keeps the ids of the bootstrap instance and of the other instances apart", func() {
+			bootstrapRepo := NewInstanceIDRepo(deploymentStateService, BootstrapInstanceKey(), &fakeuuid.FakeGenerator{GeneratedUUID: "fake-bootstrap-instance-id"})
+			bootstrapID, err := bootstrapRepo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bootstrapID).To(Equal("fake-bootstrap-instance-id"))
+
+			id, err := repo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(id).To(Equal("fake-instance-id"))
+
+			bootstrapID, err = bootstrapRepo.FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bootstrapID).To(Equal("fake-bootstrap-instance-id"))
+		})
+	})
+})
@@ -0,0 +1,84 @@
+package config
+
+// InstanceKey identifies the record of an instance in the deployment state.
+// The bootstrap instance is identified as such rather than by its job name, which may not be recorded.
+type InstanceKey struct {
+	Bootstrap bool
+	Job       string
+	Index     int
+
+	// Host is recorded with the VM and persistent disk of the instance
+	Host string
+}
+
+func BootstrapInstanceKey() InstanceKey {
+	return InstanceKey{Bootstrap: true}
+}
+
+func NewInstanceKey(job string, index int, host string) InstanceKey {
+	return InstanceKey{
+		Job:   job,
+		Index: index,
+		Host:  host,
+	}
+}
+
+func (k InstanceKey) matches(record InstanceRecord) bool {
+	if k.Bootstrap {
+		return record.Bootstrap
+	}
+
+	return !record.Bootstrap && record.Job == k.Job && record.Index == k.Index
+}
+
+func (k InstanceKey) find(deploymentState DeploymentState) (InstanceRecord, bool) {
+	for _, record := range deploymentState.Instances {
+		if k.matches(record) {
+			return record, true
+		}
+	}
+
+	return InstanceRecord{}, false
+}
+
+// update changes the record of the instance, which is added when it does not exist yet, the bootstrap instance first.
+// Records of instances other than the bootstrap instance are removed once they have neither an ID, a VM nor a persistent disk.
+func (k InstanceKey) update(deploymentState *DeploymentState, change func(record *InstanceRecord)) {
+	instances := []InstanceRecord{}
+
+	found := false
+	for _, record := range deploymentState.Instances {
+		if k.matches(record) {
+			found = true
+			record.Host = k.Host
+			change(&record)
+		}
+		instances = k.appendKept(instances, record)
+	}
+
+	if !found {
+		record := InstanceRecord{
+			Bootstrap: k.Bootstrap,
+			Job:       k.Job,
+			Index:     k.Index,
+			Host:      k.Host,
+		}
+		change(&record)
+
+		if k.Bootstrap {
+			instances = append([]InstanceRecord{record}, instances...)
+		} else {
+			instances = k.appendKept(instances, record)
+		}
+	}
+
+	deploymentState.Instances = instances
+}
+
+func (k InstanceKey) appendKept(instances []InstanceRecord, record InstanceRecord) []InstanceRecord {
+	if record.Bootstrap || record.ID != "" || record.VMCID != "" || record.DiskID != "" {
+		return append(instances, record)
+	}
+
+	return instances
+}
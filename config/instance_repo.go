@@ -0,0 +1,113 @@
+package config
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// InstanceRepo finds the instances of a deployment.
+// All only returns the instances other than the bootstrap instance that have a VM or a persistent disk, since records are
+// kept with the ID alone while the VM of an instance is recreated. FindBootstrap and Find also cover the bootstrap instance,
+// whose record has no host as it is reached on the hosts of the installation manifest.
+type InstanceRepo interface {
+	FindBootstrap() (InstanceRecord, error)
+	UpdateBootstrapJobName(jobName string) error
+	Find(jobName string, index int) (InstanceRecord, bool, error)
+	All() ([]InstanceRecord, error)
+	ClearAll() error
+}
+
+type instanceRepo struct {
+	deploymentStateService DeploymentStateService
+}
+
+func NewInstanceRepo(deploymentStateService DeploymentStateService) InstanceRepo {
+	return instanceRepo{
+		deploymentStateService: deploymentStateService,
+	}
+}
+
+// FindBootstrap returns the record of the bootstrap instance.
+// The job name is empty for deployment states saved before job names were recorded.
+func (r instanceRepo) FindBootstrap() (InstanceRecord, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return InstanceRecord{}, bosherr.WrapError(err, "Loading existing config")
+	}
+
+	bootstrapRecord, found := BootstrapInstanceKey().find(deploymentState)
+	if !found {
+		return InstanceRecord{Bootstrap: true}, nil
+	}
+
+	return bootstrapRecord, nil
+}
+
+func (r instanceRepo) UpdateBootstrapJobName(jobName string) error {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading existing config")
+	}
+
+	BootstrapInstanceKey().update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.Job = jobName
+	})
+
+	err = r.deploymentStateService.Save(deploymentState)
+	if err != nil {
+		return bosherr.WrapError(err, "Saving new config")
+	}
+	return nil
+}
+
+func (r instanceRepo) Find(jobName string, index int) (InstanceRecord, bool, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return InstanceRecord{}, false, bosherr.WrapError(err, "Loading existing config")
+	}
+
+	for _, instance := range deploymentState.Instances {
+		if instance.Job == jobName && instance.Index == index {
+			return instance, true, nil
+		}
+	}
+
+	return InstanceRecord{}, false, nil
+}
+
+func (r instanceRepo) All() ([]InstanceRecord, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return []InstanceRecord{}, bosherr.WrapError(err, "Loading existing config")
+	}
+
+	instances := []InstanceRecord{}
+	for _, instance := range deploymentState.Instances {
+		if !instance.Bootstrap && (instance.VMCID != "" || instance.DiskID != "") {
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances, nil
+}
+
+// ClearAll removes the records of the instances other than the bootstrap instance
+func (r instanceRepo) ClearAll() error {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading existing config")
+	}
+
+	instances := []InstanceRecord{}
+	for _, instance := range deploymentState.Instances {
+		if instance.Bootstrap {
+			instances = append(instances, instance)
+		}
+	}
+	deploymentState.Instances = instances
+
+	err = r.deploymentStateService.Save(deploymentState)
+	if err != nil {
+		return bosherr.WrapError(err, "Saving new config")
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package config_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/config"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+)
+
+var _ = Describe("InstanceRepo", func() {
+	var (
+		repo                   InstanceRepo
+		deploymentStateService DeploymentStateService
+	)
+
+	BeforeEach(func() {
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		fs := fakesys.NewFakeFileSystem()
+		fakeUUIDGenerator := &fakeuuid.FakeGenerator{}
+		deploymentStateService = NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/fake/path")
+		repo = NewInstanceRepo(deploymentStateService)
+
+		vmRepo := NewVMRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"))
+		err := vmRepo.UpdateCurrent("fake-vm-cid")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("FindBootstrap", func() {
+		BeforeEach(func() {
+			err := NewVMRepo(deploymentStateService, BootstrapInstanceKey()).UpdateCurrent("fake-bootstrap-vm-cid")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the record of the bootstrap instance", func() {
+			err := repo.UpdateBootstrapJobName("fake-bootstrap-job-name")
+			Expect(err).ToNot(HaveOccurred())
+
+			record, err := repo.FindBootstrap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record).To(Equal(InstanceRecord{Bootstrap: true, Job: "fake-bootstrap-job-name", Index: 0, VMCID: "fake-bootstrap-vm-cid"}))
+		})
+
+		It("returns an empty job name when none has been recorded", func() {
+			record, err := repo.FindBootstrap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record).To(Equal(InstanceRecord{Bootstrap: true, Job: "", Index: 0, VMCID: "fake-bootstrap-vm-cid"}))
+		})
+	})
+
+	Describe("Find", func() {
+		BeforeEach(func() {
+			err := repo.UpdateBootstrapJobName("fake-job-name")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("returns the record of the bootstrap instance", func() {
+			record, found, err := repo.Find("fake-job-name", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(record).To(Equal(InstanceRecord{Bootstrap: true, Job: "fake-job-name", Index: 0}))
+		})
+
+		It("returns the record of another instance", func() {
+			record, found, err := repo.Find("fake-job-name", 1)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(record).To(Equal(InstanceRecord{Job: "fake-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-vm-cid"}))
+		})
+
+		It("returns false when the instance does not exist", func() {
+			_, found, err := repo.Find("fake-job-name", 2)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found).To(BeFalse())
+		})
+	})
+
+	Describe("All", func() {
+		It("returns the records of the instances other than the bootstrap instance", func() {
+			records, err := repo.All()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]InstanceRecord{
+				{Job: "fake-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-vm-cid"},
+			}))
+		})
+
+		It("does not return the records of instances that only have an ID", func() {
+			instanceKey := NewInstanceKey("fake-job-name", 2, "10.0.0.8")
+			fakeUUIDGenerator := &fakeuuid.FakeGenerator{GeneratedUUID: "fake-instance-id"}
+			_, err := NewInstanceIDRepo(deploymentStateService, instanceKey, fakeUUIDGenerator).FindOrCreate()
+			Expect(err).ToNot(HaveOccurred())
+
+			records, err := repo.All()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(Equal([]InstanceRecord{
+				{Job: "fake-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-vm-cid"},
+			}))
+		})
+	})
+
+	Describe("ClearAll", func() {
+		It("removes the records of the instances other than the bootstrap instance", func() {
+			err := repo.UpdateBootstrapJobName("fake-bootstrap-job-name")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = repo.ClearAll()
+			Expect(err).ToNot(HaveOccurred())
+
+			records, err := repo.All()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(BeEmpty())
+
+			record, err := repo.FindBootstrap()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record).To(Equal(InstanceRecord{Bootstrap: true, Job: "fake-bootstrap-job-name"}))
+		})
+	})
+})
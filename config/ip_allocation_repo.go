@@ -0,0 +1,45 @@
+package config
+
+import (
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+)
+
+// IPAllocationRepo finds and replaces the IPs allocated to instances from the static ranges of manual networks
+type IPAllocationRepo interface {
+	All() ([]IPAllocationRecord, error)
+	UpdateAll([]IPAllocationRecord) error
+}
+
+type ipAllocationRepo struct {
+	deploymentStateService DeploymentStateService
+}
+
+func NewIPAllocationRepo(deploymentStateService DeploymentStateService) IPAllocationRepo {
+	return ipAllocationRepo{
+		deploymentStateService: deploymentStateService,
+	}
+}
+
+func (r ipAllocationRepo) All() ([]IPAllocationRecord, error) {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return []IPAllocationRecord{}, bosherr.WrapError(err, "Loading existing config")
+	}
+
+	return deploymentState.IPAllocations, nil
+}
+
+func (r ipAllocationRepo) UpdateAll(records []IPAllocationRecord) error {
+	deploymentState, err := r.deploymentStateService.Load()
+	if err != nil {
+		return bosherr.WrapError(err, "Loading existing config")
+	}
+
+	deploymentState.IPAllocations = records
+
+	err = r.deploymentStateService.Save(deploymentState)
+	if err != nil {
+		return bosherr.WrapError(err, "Saving new config")
+	}
+	return nil
+}
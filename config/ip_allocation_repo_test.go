@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/config"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+)
+
+var _ = Describe("IPAllocationRepo", func() {
+	var (
+		repo                   IPAllocationRepo
+		deploymentStateService DeploymentStateService
+	)
+
+	BeforeEach(func() {
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		fs := fakesys.NewFakeFileSystem()
+		fakeUUIDGenerator := &fakeuuid.FakeGenerator{}
+		deploymentStateService = NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/fake/path")
+		repo = NewIPAllocationRepo(deploymentStateService)
+	})
+
+	It("returns no records when no IP was allocated", func() {
+		records, err := repo.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(BeEmpty())
+	})
+
+	It("replaces all the records", func() {
+		err := repo.UpdateAll([]IPAllocationRecord{
+			{Job: "fake-job-name", Index: 0, Network: "fake-network-name", IP: "10.0.0.10"},
+			{Job: "fake-job-name", Index: 1, Network: "fake-network-name", IP: "10.0.0.11"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = repo.UpdateAll([]IPAllocationRecord{
+			{Job: "fake-job-name", Index: 0, Network: "fake-network-name", IP: "10.0.0.10"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		records, err := repo.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(Equal([]IPAllocationRecord{
+			{Job: "fake-job-name", Index: 0, Network: "fake-network-name", IP: "10.0.0.10"},
+		}))
+
+		deploymentState, err := deploymentStateService.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deploymentState.IPAllocations).To(Equal(records))
+	})
+})
@@ -100,6 +100,8 @@ func (m *legacyDeploymentStateMigrator) migrate(configPath string) (deploymentSt
 
 	if len(legacyDeploymentState.Instances) > 0 {
 		instance := legacyDeploymentState.Instances[0]
+		bootstrapRecord := InstanceRecord{Bootstrap: true}
+
 		diskCID := instance.DiskCID
 		if diskCID != "" {
 			uuid, err := m.uuidGenerator.Generate()
@@ -107,7 +109,7 @@ func (m *legacyDeploymentStateMigrator) migrate(configPath string) (deploymentSt
 				return deploymentState, bosherr.WrapError(err, "Generating UUID")
 			}
 
-			deploymentState.CurrentDiskID = uuid
+			bootstrapRecord.DiskID = uuid
 			deploymentState.Disks = []DiskRecord{
 				{
 					ID:              uuid,
@@ -120,7 +122,7 @@ func (m *legacyDeploymentStateMigrator) migrate(configPath string) (deploymentSt
 
 		vmCID := instance.VMCID
 		if vmCID != "" {
-			deploymentState.CurrentVMCID = vmCID
+			bootstrapRecord.VMCID = vmCID
 		}
 
 		stemcellCID := instance.StemcellCID
@@ -144,6 +146,10 @@ func (m *legacyDeploymentStateMigrator) migrate(configPath string) (deploymentSt
 				},
 			}
 		}
+
+		if bootstrapRecord.VMCID != "" || bootstrapRecord.DiskID != "" {
+			deploymentState.Instances = []InstanceRecord{bootstrapRecord}
+		}
 	}
 
 	m.logger.Debug(m.logTag, "New deployment.json (migrated from legacy bosh-deployments.yml): %#v", deploymentState)
@@ -114,9 +114,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
@@ -166,9 +164,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "fake-uuid-0",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
@@ -218,9 +214,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
@@ -270,9 +264,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "i-a1624150",
     "current_stemcell_id": "",
-    "current_disk_id": "fake-uuid-0",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[
@@ -291,7 +283,17 @@ registry_instances:
             "cid": "ami-f2503e9a light"
         }
     \],
-    "releases": \[\]
+    "releases": \[\],
+    "instances": \[
+        {
+            "job": "",
+            "index": 0,
+            "bootstrap": true,
+            "host": "",
+            "vm_cid": "i-a1624150",
+            "disk_id": "fake-uuid-0"
+        }
+    \]
 }`))
 			})
 		})
@@ -336,14 +338,22 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "i-a1624150",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
     "stemcells": \[\],
-    "releases": \[\]
+    "releases": \[\],
+    "instances": \[
+        {
+            "job": "",
+            "index": 0,
+            "bootstrap": true,
+            "host": "",
+            "vm_cid": "i-a1624150",
+            "disk_id": ""
+        }
+    \]
 }`))
 			})
 		})
@@ -388,9 +398,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "fake-uuid-0",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[
@@ -402,7 +410,17 @@ registry_instances:
         }
     \],
     "stemcells": \[\],
-    "releases": \[\]
+    "releases": \[\],
+    "instances": \[
+        {
+            "job": "",
+            "index": 0,
+            "bootstrap": true,
+            "host": "",
+            "vm_cid": "",
+            "disk_id": "fake-uuid-0"
+        }
+    \]
 }`))
 			})
 		})
@@ -447,9 +465,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
@@ -499,9 +515,7 @@ registry_instances:
 				Expect(content).To(MatchRegexp(`{
     "director_id": "bm-5480c6bb-3ba8-449a-a262-a2e75fbe5daf",
     "installation_id": "",
-    "current_vm_cid": "",
     "current_stemcell_id": "",
-    "current_disk_id": "",
     "current_release_ids": null,
     "current_manifest_sha1": "",
     "disks": \[\],
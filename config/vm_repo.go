@@ -12,11 +12,14 @@ type VMRepo interface {
 
 type vMRepo struct {
 	deploymentStateService DeploymentStateService
+	instanceKey            InstanceKey
 }
 
-func NewVMRepo(deploymentStateService DeploymentStateService) VMRepo {
+// NewVMRepo returns a repo of the current VM of an instance, which is kept in the record of the instance
+func NewVMRepo(deploymentStateService DeploymentStateService, instanceKey InstanceKey) VMRepo {
 	return vMRepo{
 		deploymentStateService: deploymentStateService,
+		instanceKey:            instanceKey,
 	}
 }
 
@@ -26,9 +29,9 @@ func (r vMRepo) FindCurrent() (string, bool, error) {
 		return "", false, bosherr.WrapError(err, "Loading existing config")
 	}
 
-	currentVMCID := deploymentState.CurrentVMCID
-	if currentVMCID != "" {
-		return currentVMCID, true, nil
+	instanceRecord, found := r.instanceKey.find(deploymentState)
+	if found && instanceRecord.VMCID != "" {
+		return instanceRecord.VMCID, true, nil
 	}
 
 	return "", false, nil
@@ -40,7 +43,9 @@ func (r vMRepo) UpdateCurrent(cid string) error {
 		return bosherr.WrapError(err, "Loading existing config")
 	}
 
-	deploymentState.CurrentVMCID = cid
+	r.instanceKey.update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.VMCID = cid
+	})
 
 	err = r.deploymentStateService.Save(deploymentState)
 	if err != nil {
@@ -55,7 +60,9 @@ func (r vMRepo) ClearCurrent() error {
 		return bosherr.WrapError(err, "Loading existing config")
 	}
 
-	deploymentState.CurrentVMCID = ""
+	r.instanceKey.update(&deploymentState, func(instanceRecord *InstanceRecord) {
+		instanceRecord.VMCID = ""
+	})
 
 	err = r.deploymentStateService.Save(deploymentState)
 	if err != nil {
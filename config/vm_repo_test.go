@@ -22,7 +22,7 @@ var _ = Describe("VMRepo", func() {
 		fs = fakesys.NewFakeFileSystem()
 		fakeUUIDGenerator = &fakeuuid.FakeGenerator{}
 		deploymentStateService = NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/fake/path")
-		repo = NewVMRepo(deploymentStateService)
+		repo = NewVMRepo(deploymentStateService, BootstrapInstanceKey())
 	})
 
 	Describe("FindCurrent", func() {
@@ -58,11 +58,34 @@ var _ = Describe("VMRepo", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			expectedConfig := DeploymentState{
-				DirectorID:   "fake-uuid-0",
-				CurrentVMCID: "fake-vm-cid",
+				DirectorID: "fake-uuid-0",
+				Instances: []InstanceRecord{
+					{Bootstrap: true, VMCID: "fake-vm-cid"},
+				},
 			}
 			Expect(deploymentState).To(Equal(expectedConfig))
 		})
+
+		Context("for an instance other than the bootstrap instance", func() {
+			BeforeEach(func() {
+				repo = NewVMRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"))
+			})
+
+			It("updates the vm cid in the record of the instance, with its host", func() {
+				err := NewVMRepo(deploymentStateService, BootstrapInstanceKey()).UpdateCurrent("fake-bootstrap-vm-cid")
+				Expect(err).ToNot(HaveOccurred())
+
+				err = repo.UpdateCurrent("fake-vm-cid")
+				Expect(err).ToNot(HaveOccurred())
+
+				deploymentState, err := deploymentStateService.Load()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deploymentState.Instances).To(Equal([]InstanceRecord{
+					{Bootstrap: true, VMCID: "fake-bootstrap-vm-cid"},
+					{Job: "fake-job-name", Index: 1, Host: "10.0.0.7", VMCID: "fake-vm-cid"},
+				}))
+			})
+		})
 	})
 
 	Describe("ClearCurrent", func() {
@@ -74,8 +97,10 @@ var _ = Describe("VMRepo", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			expectedConfig := DeploymentState{
-				DirectorID:   "fake-uuid-0",
-				CurrentVMCID: "",
+				DirectorID: "fake-uuid-0",
+				Instances: []InstanceRecord{
+					{Bootstrap: true},
+				},
 			}
 			Expect(deploymentState).To(Equal(expectedConfig))
 
@@ -83,5 +108,18 @@ var _ = Describe("VMRepo", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(found).To(BeFalse())
 		})
+
+		It("removes the record of an instance other than the bootstrap instance once it has neither a vm nor a disk", func() {
+			repo = NewVMRepo(deploymentStateService, NewInstanceKey("fake-job-name", 1, "10.0.0.7"))
+			err := repo.UpdateCurrent("fake-vm-cid")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = repo.ClearCurrent()
+			Expect(err).ToNot(HaveOccurred())
+
+			deploymentState, err := deploymentStateService.Load()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentState.Instances).To(BeEmpty())
+		})
 	})
 })
@@ -1,7 +1,8 @@
 package fakes
 
 type FakeSha1Calculator struct {
-	calculateInputs map[string]CalculateInput
+	calculateInputs        map[string]CalculateInput
+	calculateContentsSha1s map[string]string
 }
 
 func NewFakeSha1Calculator() *FakeSha1Calculator {
@@ -21,3 +22,11 @@ func (c *FakeSha1Calculator) Calculate(path string) (string, error) {
 func (c *FakeSha1Calculator) SetCalculateBehavior(calculateInputs map[string]CalculateInput) {
 	c.calculateInputs = calculateInputs
 }
+
+func (c *FakeSha1Calculator) CalculateContents(contents []byte) string {
+	return c.calculateContentsSha1s[string(contents)]
+}
+
+func (c *FakeSha1Calculator) SetCalculateContentsBehavior(sha1sByContents map[string]string) {
+	c.calculateContentsSha1s = sha1sByContents
+}
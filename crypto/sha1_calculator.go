@@ -13,6 +13,7 @@ import (
 
 type SHA1Calculator interface {
 	Calculate(filePath string) (string, error)
+	CalculateContents(contents []byte) string
 }
 
 type sha1Calculator struct {
@@ -64,6 +65,10 @@ func (c sha1Calculator) Calculate(filePath string) (string, error) {
 	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
+func (c sha1Calculator) CalculateContents(contents []byte) string {
+	return fmt.Sprintf("%x", sha1.Sum(contents))
+}
+
 func (c sha1Calculator) populateSha1(filePath string, hash hash.Hash) error {
 	file, err := c.fs.OpenFile(filePath, os.O_RDONLY, 0)
 	if err != nil {
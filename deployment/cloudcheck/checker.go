@@ -0,0 +1,242 @@
+package cloudcheck
+
+import (
+	"fmt"
+	"time"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
+	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+type ProblemType string
+
+const (
+	MissingVM         ProblemType = "missing_vm"
+	UnresponsiveAgent ProblemType = "unresponsive_agent"
+	MissingDisk       ProblemType = "missing_disk"
+)
+
+type Resolution string
+
+const (
+	Skip         Resolution = "skip"
+	RecreateVM   Resolution = "recreate_vm"
+	ForgetVM     Resolution = "forget_vm"
+	ReattachDisk Resolution = "reattach_disk"
+	ForgetDisk   Resolution = "forget_disk"
+)
+
+// Problem is a mismatch between the deployment state and the IaaS.
+// The first of the Resolutions is the recommended one.
+type Problem struct {
+	Type        ProblemType
+	Description string
+	Resolutions []Resolution
+
+	vm   bivm.VM
+	disk bidisk.Disk
+}
+
+func (p Problem) HasResolution(resolution Resolution) bool {
+	if resolution == Skip {
+		return true
+	}
+
+	for _, r := range p.Resolutions {
+		if r == resolution {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checker checks the VM and persistent disks of a single instance.
+// Problems are resolved by the Checker that found them.
+type Checker interface {
+	Check() ([]Problem, error)
+	Resolve(Problem, Resolution) error
+}
+
+type checker struct {
+	instanceName string
+	vmManager    bivm.Manager
+	diskManager  bidisk.Manager
+	vmRepo       biconfig.VMRepo
+	diskRepo     biconfig.DiskRepo
+	stemcellRepo biconfig.StemcellRepo
+	pingTimeout  time.Duration
+	pingDelay    time.Duration
+	logger       boshlog.Logger
+	logTag       string
+}
+
+func NewChecker(
+	instanceName string,
+	vmManager bivm.Manager,
+	diskManager bidisk.Manager,
+	vmRepo biconfig.VMRepo,
+	diskRepo biconfig.DiskRepo,
+	stemcellRepo biconfig.StemcellRepo,
+	pingTimeout time.Duration,
+	pingDelay time.Duration,
+	logger boshlog.Logger,
+) Checker {
+	return &checker{
+		instanceName: instanceName,
+		vmManager:    vmManager,
+		diskManager:  diskManager,
+		vmRepo:       vmRepo,
+		diskRepo:     diskRepo,
+		stemcellRepo: stemcellRepo,
+		pingTimeout:  pingTimeout,
+		pingDelay:    pingDelay,
+		logger:       logger,
+		logTag:       "cloudChecker",
+	}
+}
+
+func (c *checker) Check() ([]Problem, error) {
+	problems := []Problem{}
+
+	vm, found, err := c.vmManager.FindCurrent()
+	if err != nil {
+		return problems, bosherr.WrapError(err, "Finding current vm")
+	}
+
+	if !found {
+		c.logger.Debug(c.logTag, "No current vm found for instance '%s', nothing to check", c.instanceName)
+		return problems, nil
+	}
+
+	exists, err := vm.Exists()
+	if err != nil {
+		return problems, err
+	}
+
+	if !exists {
+		return append(problems, Problem{
+			Type:        MissingVM,
+			Description: fmt.Sprintf("VM '%s' of instance '%s' is missing from the IaaS", vm.CID(), c.instanceName),
+			Resolutions: []Resolution{RecreateVM, ForgetVM},
+			vm:          vm,
+		}), nil
+	}
+
+	err = c.checkAgent(vm)
+	if err != nil {
+		c.logger.Debug(c.logTag, "Agent on vm '%s' is unresponsive: %s", vm.CID(), err.Error())
+		return append(problems, Problem{
+			Type:        UnresponsiveAgent,
+			Description: fmt.Sprintf("Agent on VM '%s' of instance '%s' is not responding", vm.CID(), c.instanceName),
+			Resolutions: []Resolution{RecreateVM, ForgetVM},
+			vm:          vm,
+		}), nil
+	}
+
+	disks, err := c.diskManager.FindCurrent()
+	if err != nil {
+		return problems, bosherr.WrapError(err, "Finding current disks")
+	}
+
+	if len(disks) == 0 {
+		return problems, nil
+	}
+
+	attachedDisks, err := vm.Disks()
+	if err != nil {
+		return problems, bosherr.WrapErrorf(err, "Listing disks of vm '%s'", vm.CID())
+	}
+
+	attachedDiskCIDs := map[string]bool{}
+	for _, attachedDisk := range attachedDisks {
+		attachedDiskCIDs[attachedDisk.CID()] = true
+	}
+
+	for _, disk := range disks {
+		if attachedDiskCIDs[disk.CID()] {
+			continue
+		}
+
+		problems = append(problems, Problem{
+			Type:        MissingDisk,
+			Description: fmt.Sprintf("Disk '%s' is not attached to VM '%s' of instance '%s'", disk.CID(), vm.CID(), c.instanceName),
+			Resolutions: []Resolution{ReattachDisk, ForgetDisk},
+			vm:          vm,
+			disk:        disk,
+		})
+	}
+
+	return problems, nil
+}
+
+func (c *checker) checkAgent(vm bivm.VM) error {
+	err := vm.WaitUntilReady(c.pingTimeout, c.pingDelay)
+	if err != nil {
+		return bosherr.WrapError(err, "Pinging agent")
+	}
+
+	_, err = vm.AgentClient().GetState()
+	if err != nil {
+		return bosherr.WrapError(err, "Getting agent state")
+	}
+
+	return nil
+}
+
+func (c *checker) Resolve(problem Problem, resolution Resolution) error {
+	if !problem.HasResolution(resolution) {
+		return bosherr.Errorf("Resolution '%s' is not valid for problem '%s'", resolution, problem.Type)
+	}
+
+	switch resolution {
+	case Skip:
+		return nil
+
+	case RecreateVM:
+		// the vm is deleted and created again with its disks by recreating the deployment once all problems are resolved;
+		// deleting it here would clear the current stemcell, and the deployment could no longer be recreated
+		return nil
+
+	case ForgetVM:
+		err := c.vmRepo.ClearCurrent()
+		if err != nil {
+			return bosherr.WrapError(err, "Clearing current vm")
+		}
+
+		// without a current stemcell, the next deploy will not be skipped
+		err = c.stemcellRepo.ClearCurrent()
+		if err != nil {
+			return bosherr.WrapError(err, "Clearing current stemcell")
+		}
+		return nil
+
+	case ReattachDisk:
+		err := problem.vm.AttachDisk(problem.disk)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Reattaching disk '%s' to vm '%s'", problem.disk.CID(), problem.vm.CID())
+		}
+		return nil
+
+	case ForgetDisk:
+		diskRecord, found, err := c.diskRepo.Find(problem.disk.CID())
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Finding disk record '%s'", problem.disk.CID())
+		}
+
+		if !found {
+			return nil
+		}
+
+		err = c.diskRepo.Delete(diskRecord)
+		if err != nil {
+			return bosherr.WrapErrorf(err, "Deleting disk record '%s'", problem.disk.CID())
+		}
+		return nil
+	}
+
+	return bosherr.Errorf("Unknown resolution '%s'", resolution)
+}
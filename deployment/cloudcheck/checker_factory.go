@@ -0,0 +1,115 @@
+package cloudcheck
+
+import (
+	"fmt"
+	"time"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
+	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
+)
+
+// CheckerFactory creates a Checker for every instance of the deployment, the bootstrap instance first.
+// The bootstrap instance is reached on the installation mbus URL, every other instance on its own host.
+type CheckerFactory interface {
+	NewCheckers(cloud bicloud.Cloud, directorID string, mbusURL string) ([]Checker, error)
+}
+
+type checkerFactory struct {
+	deploymentStateService biconfig.DeploymentStateService
+	agentClientFactory     biagentclient.AgentClientFactory
+	stemcellRepo           biconfig.StemcellRepo
+	uuidGenerator          boshuuid.Generator
+	fs                     boshsys.FileSystem
+	logger                 boshlog.Logger
+}
+
+func NewCheckerFactory(
+	deploymentStateService biconfig.DeploymentStateService,
+	agentClientFactory biagentclient.AgentClientFactory,
+	stemcellRepo biconfig.StemcellRepo,
+	uuidGenerator boshuuid.Generator,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+) CheckerFactory {
+	return &checkerFactory{
+		deploymentStateService: deploymentStateService,
+		agentClientFactory:     agentClientFactory,
+		stemcellRepo:           stemcellRepo,
+		uuidGenerator:          uuidGenerator,
+		fs:                     fs,
+		logger:                 logger,
+	}
+}
+
+func (f *checkerFactory) NewCheckers(cloud bicloud.Cloud, directorID string, mbusURL string) ([]Checker, error) {
+	checkers := []Checker{}
+	instanceRepo := biconfig.NewInstanceRepo(f.deploymentStateService)
+
+	bootstrapRecord, err := instanceRepo.FindBootstrap()
+	if err != nil {
+		return checkers, bosherr.WrapError(err, "Finding bootstrap instance")
+	}
+
+	checkers = append(checkers, f.newChecker(cloud, directorID, mbusURL, bootstrapRecord, biconfig.BootstrapInstanceKey()))
+
+	instanceRecords, err := instanceRepo.All()
+	if err != nil {
+		return checkers, bosherr.WrapError(err, "Finding instance records")
+	}
+
+	for _, instanceRecord := range instanceRecords {
+		instanceMbusURL, err := biinstance.InstanceMbusURL(mbusURL, instanceRecord.Host)
+		if err != nil {
+			return checkers, bosherr.WrapErrorf(err, "Building mbus URL for instance '%s/%d'", instanceRecord.Job, instanceRecord.Index)
+		}
+
+		instanceKey := biconfig.NewInstanceKey(instanceRecord.Job, instanceRecord.Index, instanceRecord.Host)
+		checkers = append(checkers, f.newChecker(cloud, directorID, instanceMbusURL, instanceRecord, instanceKey))
+	}
+
+	return checkers, nil
+}
+
+func (f *checkerFactory) newChecker(
+	cloud bicloud.Cloud,
+	directorID string,
+	mbusURL string,
+	instanceRecord biconfig.InstanceRecord,
+	instanceKey biconfig.InstanceKey,
+) Checker {
+	pingTimeout := 10 * time.Second
+	pingDelay := 500 * time.Millisecond
+
+	vmRepo := biconfig.NewVMRepo(f.deploymentStateService, instanceKey)
+	diskRepo := biconfig.NewDiskRepo(f.deploymentStateService, instanceKey, f.uuidGenerator)
+	diskManagerFactory := bidisk.NewManagerFactory(diskRepo, f.logger)
+	diskDeployer := bivm.NewDiskDeployer(diskManagerFactory, diskRepo, f.logger)
+	vmManagerFactory := bivm.NewManagerFactory(vmRepo, f.stemcellRepo, diskDeployer, f.uuidGenerator, f.fs, f.logger)
+	agentClient := f.agentClientFactory.NewAgentClient(directorID, mbusURL)
+
+	// the job of a bootstrap instance deployed before job names were recorded is unknown
+	jobName := instanceRecord.Job
+	if jobName == "" {
+		jobName = "unknown"
+	}
+
+	return NewChecker(
+		fmt.Sprintf("%s/%d", jobName, instanceRecord.Index),
+		vmManagerFactory.NewManager(cloud, agentClient),
+		diskManagerFactory.NewManager(cloud),
+		vmRepo,
+		diskRepo,
+		f.stemcellRepo,
+		pingTimeout,
+		pingDelay,
+		f.logger,
+	)
+}
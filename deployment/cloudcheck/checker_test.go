@@ -0,0 +1,314 @@
+package cloudcheck_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/cloudfoundry/bosh-init/deployment/cloudcheck"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
+	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+
+	fakebiconfig "github.com/cloudfoundry/bosh-init/config/fakes"
+	fakebidisk "github.com/cloudfoundry/bosh-init/deployment/disk/fakes"
+	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
+	fakebiagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/fakes"
+)
+
+var _ = Describe("Checker", func() {
+	var (
+		checker          Checker
+		fakeVMManager    *fakebivm.FakeManager
+		fakeDiskManager  *fakebidisk.FakeManager
+		fakeVMRepo       *fakebiconfig.FakeVMRepo
+		fakeDiskRepo     *fakebiconfig.FakeDiskRepo
+		fakeStemcellRepo *fakebiconfig.FakeStemcellRepo
+		fakeVM           *fakebivm.FakeVM
+		fakeAgentClient  *fakebiagentclient.FakeAgentClient
+		fakeDisk         *fakebidisk.FakeDisk
+
+		pingTimeout = 10 * time.Second
+		pingDelay   = 500 * time.Millisecond
+	)
+
+	BeforeEach(func() {
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		fakeVMManager = fakebivm.NewFakeManager()
+		fakeDiskManager = fakebidisk.NewFakeManager()
+		fakeVMRepo = fakebiconfig.NewFakeVMRepo()
+		fakeDiskRepo = fakebiconfig.NewFakeDiskRepo()
+		fakeStemcellRepo = fakebiconfig.NewFakeStemcellRepo()
+
+		fakeAgentClient = fakebiagentclient.NewFakeAgentClient()
+		fakeAgentClient.SetGetStateBehavior(biagentclient.AgentState{JobState: "running"}, nil)
+
+		fakeVM = fakebivm.NewFakeVM("fake-vm-cid")
+		fakeVM.AgentClientReturn = fakeAgentClient
+		fakeVMManager.SetFindCurrentBehavior(fakeVM, true, nil)
+
+		fakeDisk = fakebidisk.NewFakeDisk("fake-disk-cid")
+		fakeDiskManager.SetFindCurrentBehavior([]bidisk.Disk{fakeDisk}, nil)
+		fakeVM.ListDisksDisks = []bidisk.Disk{fakebidisk.NewFakeDisk("fake-disk-cid")}
+
+		checker = NewChecker(
+			"fake-job-name/0",
+			fakeVMManager,
+			fakeDiskManager,
+			fakeVMRepo,
+			fakeDiskRepo,
+			fakeStemcellRepo,
+			pingTimeout,
+			pingDelay,
+			logger,
+		)
+	})
+
+	Describe("Check", func() {
+		It("returns no problems when the vm exists, the agent responds and the disk is attached", func() {
+			problems, err := checker.Check()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(problems).To(BeEmpty())
+
+			Expect(fakeVM.ExistsCalled).To(Equal(1))
+			Expect(fakeVM.WaitUntilReadyInputs).To(Equal([]fakebivm.WaitUntilReadyInput{
+				{Timeout: pingTimeout, Delay: pingDelay},
+			}))
+			Expect(fakeAgentClient.GetStateCalledTimes).To(Equal(1))
+		})
+
+		Context("when there is no current vm", func() {
+			BeforeEach(func() {
+				fakeVMManager.SetFindCurrentBehavior(nil, false, nil)
+			})
+
+			It("returns no problems", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(BeEmpty())
+			})
+		})
+
+		Context("when finding the current vm fails", func() {
+			BeforeEach(func() {
+				fakeVMManager.SetFindCurrentBehavior(nil, false, errors.New("fake-find-error"))
+			})
+
+			It("returns an error", func() {
+				_, err := checker.Check()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-find-error"))
+			})
+		})
+
+		Context("when the vm does not exist in the IaaS", func() {
+			BeforeEach(func() {
+				fakeVM.ExistsFound = false
+			})
+
+			It("returns a missing vm problem", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].Type).To(Equal(MissingVM))
+				Expect(problems[0].Description).To(Equal("VM 'fake-vm-cid' of instance 'fake-job-name/0' is missing from the IaaS"))
+				Expect(problems[0].Resolutions).To(Equal([]Resolution{RecreateVM, ForgetVM}))
+			})
+
+			It("does not contact the agent", func() {
+				_, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeVM.WaitUntilReadyInputs).To(BeEmpty())
+			})
+		})
+
+		Context("when checking the vm existence fails", func() {
+			BeforeEach(func() {
+				fakeVM.ExistsErr = errors.New("fake-has-vm-error")
+			})
+
+			It("returns an error", func() {
+				_, err := checker.Check()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-has-vm-error"))
+			})
+		})
+
+		Context("when the agent does not respond to ping", func() {
+			BeforeEach(func() {
+				fakeVM.WaitUntilReadyErr = errors.New("fake-ping-error")
+			})
+
+			It("returns an unresponsive agent problem", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].Type).To(Equal(UnresponsiveAgent))
+				Expect(problems[0].Description).To(Equal("Agent on VM 'fake-vm-cid' of instance 'fake-job-name/0' is not responding"))
+				Expect(problems[0].Resolutions).To(Equal([]Resolution{RecreateVM, ForgetVM}))
+			})
+		})
+
+		Context("when the agent fails to get state", func() {
+			BeforeEach(func() {
+				fakeAgentClient = fakebiagentclient.NewFakeAgentClient()
+				fakeAgentClient.SetGetStateBehavior(biagentclient.AgentState{}, errors.New("fake-get-state-error"))
+				fakeVM.AgentClientReturn = fakeAgentClient
+			})
+
+			It("returns an unresponsive agent problem", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].Type).To(Equal(UnresponsiveAgent))
+			})
+		})
+
+		Context("when the current disk is not in the agent's disk list", func() {
+			BeforeEach(func() {
+				fakeVM.ListDisksDisks = []bidisk.Disk{}
+			})
+
+			It("returns a missing disk problem", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(HaveLen(1))
+				Expect(problems[0].Type).To(Equal(MissingDisk))
+				Expect(problems[0].Description).To(Equal("Disk 'fake-disk-cid' is not attached to VM 'fake-vm-cid' of instance 'fake-job-name/0'"))
+				Expect(problems[0].Resolutions).To(Equal([]Resolution{ReattachDisk, ForgetDisk}))
+			})
+		})
+
+		Context("when there is no current disk", func() {
+			BeforeEach(func() {
+				fakeDiskManager.SetFindCurrentBehavior([]bidisk.Disk{}, nil)
+				fakeVM.ListDisksErr = errors.New("fake-list-disk-error")
+			})
+
+			It("does not list the vm disks", func() {
+				problems, err := checker.Check()
+				Expect(err).ToNot(HaveOccurred())
+				Expect(problems).To(BeEmpty())
+			})
+		})
+
+		Context("when listing the vm disks fails", func() {
+			BeforeEach(func() {
+				fakeVM.ListDisksErr = errors.New("fake-list-disk-error")
+			})
+
+			It("returns an error", func() {
+				_, err := checker.Check()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-list-disk-error"))
+			})
+		})
+	})
+
+	Describe("Resolve", func() {
+		var (
+			missingVMProblem   Problem
+			missingDiskProblem Problem
+		)
+
+		BeforeEach(func() {
+			fakeVM.ExistsFound = false
+			problems, err := checker.Check()
+			Expect(err).ToNot(HaveOccurred())
+			missingVMProblem = problems[0]
+
+			fakeVM.ExistsFound = true
+			fakeVM.ListDisksDisks = []bidisk.Disk{}
+			problems, err = checker.Check()
+			Expect(err).ToNot(HaveOccurred())
+			missingDiskProblem = problems[0]
+		})
+
+		It("rejects resolutions that do not apply to the problem", func() {
+			err := checker.Resolve(missingVMProblem, ReattachDisk)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Resolution 'reattach_disk' is not valid for problem 'missing_vm'"))
+		})
+
+		It("does nothing when skipping", func() {
+			err := checker.Resolve(missingVMProblem, Skip)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeVM.DeleteCalled).To(Equal(0))
+			Expect(fakeVMRepo.ClearCurrentCalled).To(BeFalse())
+		})
+
+		Describe("recreate_vm", func() {
+			It("leaves the vm and the deployment state for the deployment to be recreated", func() {
+				err := checker.Resolve(missingVMProblem, RecreateVM)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeVM.DeleteCalled).To(Equal(0))
+				Expect(fakeVMRepo.ClearCurrentCalled).To(BeFalse())
+				Expect(fakeStemcellRepo.ClearCurrentCalled).To(BeFalse())
+			})
+		})
+
+		Describe("forget_vm", func() {
+			It("clears the current vm & stemcell", func() {
+				err := checker.Resolve(missingVMProblem, ForgetVM)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeVMRepo.ClearCurrentCalled).To(BeTrue())
+				Expect(fakeStemcellRepo.ClearCurrentCalled).To(BeTrue())
+				Expect(fakeVM.DeleteCalled).To(Equal(0))
+			})
+
+			It("returns an error when clearing the current vm fails", func() {
+				fakeVMRepo.ClearCurrentErr = errors.New("fake-clear-error")
+
+				err := checker.Resolve(missingVMProblem, ForgetVM)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-clear-error"))
+			})
+		})
+
+		Describe("reattach_disk", func() {
+			It("attaches the disk to the vm", func() {
+				err := checker.Resolve(missingDiskProblem, ReattachDisk)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeVM.AttachDiskInputs).To(Equal([]fakebivm.AttachDiskInput{
+					{Disk: fakeDisk},
+				}))
+			})
+
+			It("returns an error when attaching the disk fails", func() {
+				fakeVM.SetAttachDiskBehavior(fakeDisk, errors.New("fake-attach-error"))
+
+				err := checker.Resolve(missingDiskProblem, ReattachDisk)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-attach-error"))
+			})
+		})
+
+		Describe("forget_disk", func() {
+			var diskRecord biconfig.DiskRecord
+
+			BeforeEach(func() {
+				diskRecord = biconfig.DiskRecord{ID: "fake-disk-id", CID: "fake-disk-cid"}
+				fakeDiskRepo.SetFindBehavior("fake-disk-cid", diskRecord, true, nil)
+			})
+
+			It("deletes the disk record", func() {
+				err := checker.Resolve(missingDiskProblem, ForgetDisk)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeDiskRepo.DeleteInputs).To(Equal([]fakebiconfig.DiskRepoDeleteInput{
+					{DiskRecord: diskRecord},
+				}))
+			})
+
+			It("does nothing when the disk record does not exist", func() {
+				fakeDiskRepo.SetFindBehavior("fake-disk-cid", biconfig.DiskRecord{}, false, nil)
+
+				err := checker.Resolve(missingDiskProblem, ForgetDisk)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeDiskRepo.DeleteInputs).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -3,7 +3,6 @@ package deployment
 import (
 	"time"
 
-	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
@@ -22,8 +21,7 @@ type Deployer interface {
 		bideplmanifest.Manifest,
 		bistemcell.CloudStemcell,
 		biinstallmanifest.Registry,
-		bivm.Manager,
-		biblobstore.Blobstore,
+		biinstance.ManagerProvider,
 		biui.Stage,
 	) (Deployment, error)
 }
@@ -56,19 +54,23 @@ func (d *deployer) Deploy(
 	deploymentManifest bideplmanifest.Manifest,
 	cloudStemcell bistemcell.CloudStemcell,
 	registryConfig biinstallmanifest.Registry,
-	vmManager bivm.Manager,
-	blobstore biblobstore.Blobstore,
+	instanceManagerProvider biinstance.ManagerProvider,
 	deployStage biui.Stage,
 ) (Deployment, error) {
-	instanceManager := d.instanceManagerFactory.NewManager(cloud, vmManager, blobstore)
+	instanceManagers, err := instanceManagerProvider.FindAll()
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Finding current instances")
+	}
 
 	pingTimeout := 10 * time.Second
 	pingDelay := 500 * time.Millisecond
-	if err := instanceManager.DeleteAll(pingTimeout, pingDelay, deployStage); err != nil {
-		return nil, err
+	for _, instanceManager := range instanceManagers {
+		if err := instanceManager.DeleteAll(pingTimeout, pingDelay, deployStage); err != nil {
+			return nil, err
+		}
 	}
 
-	instances, disks, err := d.createAllInstances(deploymentManifest, instanceManager, cloudStemcell, registryConfig, deployStage)
+	instances, disks, err := d.createAllInstances(deploymentManifest, instanceManagerProvider, cloudStemcell, registryConfig, deployStage)
 	if err != nil {
 		return nil, err
 	}
@@ -77,9 +79,12 @@ func (d *deployer) Deploy(
 	return d.deploymentFactory.NewDeployment(instances, disks, stemcells), nil
 }
 
+// createAllInstances creates the instances in manifest order, so that the bootstrap instance
+// (the first instance of the first job) is up before any other instance is created.
+// Errand jobs are skipped: their instance is only created by run-errand.
 func (d *deployer) createAllInstances(
 	deploymentManifest bideplmanifest.Manifest,
-	instanceManager biinstance.Manager,
+	instanceManagerProvider biinstance.ManagerProvider,
 	cloudStemcell bistemcell.CloudStemcell,
 	registryConfig biinstallmanifest.Registry,
 	deployStage biui.Stage,
@@ -87,15 +92,17 @@ func (d *deployer) createAllInstances(
 	instances := []biinstance.Instance{}
 	disks := []bidisk.Disk{}
 
-	if len(deploymentManifest.Jobs) != 1 {
-		return instances, disks, bosherr.Errorf("There must only be one job, found %d", len(deploymentManifest.Jobs))
-	}
-
 	for _, jobSpec := range deploymentManifest.Jobs {
-		if jobSpec.Instances != 1 {
-			return instances, disks, bosherr.Errorf("Job '%s' must have only one instance, found %d", jobSpec.Name, jobSpec.Instances)
+		if jobSpec.Lifecycle == bideplmanifest.JobLifecycleErrand {
+			continue
 		}
+
 		for instanceID := 0; instanceID < jobSpec.Instances; instanceID++ {
+			instanceManager, err := instanceManagerProvider.Get(deploymentManifest, jobSpec.Name, instanceID)
+			if err != nil {
+				return instances, disks, bosherr.WrapErrorf(err, "Creating instance '%s/%d'", jobSpec.Name, instanceID)
+			}
+
 			instance, instanceDisks, err := instanceManager.Create(jobSpec.Name, instanceID, deploymentManifest, cloudStemcell, registryConfig, deployStage)
 			if err != nil {
 				return instances, disks, bosherr.WrapErrorf(err, "Creating instance '%s/%d'", jobSpec.Name, instanceID)
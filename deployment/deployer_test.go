@@ -6,16 +6,17 @@ import (
 
 	. "github.com/cloudfoundry/bosh-init/deployment"
 
+	mock_agentclient "github.com/cloudfoundry/bosh-init/agentclient/mocks"
 	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
+	mock_instance "github.com/cloudfoundry/bosh-init/deployment/instance/mocks"
 	mock_instance_state "github.com/cloudfoundry/bosh-init/deployment/instance/state/mocks"
 	mock_vm "github.com/cloudfoundry/bosh-init/deployment/vm/mocks"
-	mock_httpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
 	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
@@ -28,8 +29,11 @@ import (
 
 	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
 	fakebiconfig "github.com/cloudfoundry/bosh-init/config/fakes"
+	fakebidisk "github.com/cloudfoundry/bosh-init/deployment/disk/fakes"
 	fakebisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel/fakes"
 	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
 	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
 )
 
@@ -49,10 +53,11 @@ var _ = Describe("Deployer", func() {
 		mockVMManagerFactory   *mock_vm.MockManagerFactory
 		fakeVMManager          *fakebivm.FakeManager
 		mockAgentClient        *mock_agentclient.MockAgentClient
-		mockAgentClientFactory *mock_httpagent.MockAgentClientFactory
+		mockAgentClientFactory *mock_agentclient.MockAgentClientFactory
 		fakeSSHTunnelFactory   *fakebisshtunnel.FakeFactory
 		fakeSSHTunnel          *fakebisshtunnel.FakeTunnel
 		cloud                  *fakebicloud.FakeCloud
+		instanceRepo           biconfig.InstanceRepo
 		deploymentManifest     bideplmanifest.Manifest
 		diskPool               bideplmanifest.DiskPool
 		registryConfig         biinstallmanifest.Registry
@@ -68,6 +73,8 @@ var _ = Describe("Deployer", func() {
 		mockState               *mock_instance_state.MockState
 
 		mockBlobstore *mock_blobstore.MockBlobstore
+
+		mockInstanceManagerProvider *mock_instance.MockManagerProvider
 	)
 
 	BeforeEach(func() {
@@ -108,7 +115,7 @@ var _ = Describe("Deployer", func() {
 
 		cloud = fakebicloud.NewFakeCloud()
 
-		mockAgentClientFactory = mock_httpagent.NewMockAgentClientFactory(mockCtrl)
+		mockAgentClientFactory = mock_agentclient.NewMockAgentClientFactory(mockCtrl)
 		mockAgentClient = mock_agentclient.NewMockAgentClient(mockCtrl)
 		mockAgentClientFactory.EXPECT().NewAgentClient(gomock.Any(), gomock.Any()).Return(mockAgentClient).AnyTimes()
 
@@ -150,6 +157,13 @@ var _ = Describe("Deployer", func() {
 
 		mockBlobstore = mock_blobstore.NewMockBlobstore(mockCtrl)
 
+		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fakesys.NewFakeFileSystem(), fakeuuid.NewFakeGenerator(), logger, "/deployment.json")
+		instanceRepo = biconfig.NewInstanceRepo(deploymentStateService)
+		instanceManager := instanceManagerFactory.NewManager(cloud, fakeVMManager, fakebidisk.NewFakeManager(), mockBlobstore, instanceRepo, biconfig.NewInstanceIDRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeuuid.NewFakeGenerator()))
+		mockInstanceManagerProvider = mock_instance.NewMockManagerProvider(mockCtrl)
+		mockInstanceManagerProvider.EXPECT().FindAll().Return([]biinstance.Manager{instanceManager}, nil).AnyTimes()
+		mockInstanceManagerProvider.EXPECT().Get(gomock.Any(), "fake-job-name", 0).Return(instanceManager, nil).AnyTimes()
+
 		pingTimeout := 10 * time.Second
 		pingDelay := 500 * time.Millisecond
 		deploymentFactory := NewFactory(pingTimeout, pingDelay)
@@ -172,7 +186,7 @@ var _ = Describe("Deployer", func() {
 		}
 
 		mockStateBuilderFactory.EXPECT().NewBuilder(mockBlobstore, mockAgentClient).Return(mockStateBuilder).AnyTimes()
-		mockStateBuilder.EXPECT().Build(jobName, jobIndex, deploymentManifest, fakeStage).Return(mockState, nil).AnyTimes()
+		mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), deploymentManifest, gomock.Any(), fakeStage).Return(mockState, nil).AnyTimes()
 		mockState.EXPECT().ToApplySpec().Return(applySpec).AnyTimes()
 	})
 
@@ -186,7 +200,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("deletes existing vm", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(fakeExistingVM.DeleteCalled).To(Equal(1))
@@ -200,15 +214,68 @@ var _ = Describe("Deployer", func() {
 	})
 
 	It("creates a vm", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeVMManager.CreateInput).To(Equal(fakebivm.CreateInput{
 			Stemcell: cloudStemcell,
 			Manifest: deploymentManifest,
+			JobName:  "fake-job-name",
+			Index:    0,
 		}))
 	})
 
+	It("records the job name of the bootstrap instance", func() {
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
+		Expect(err).NotTo(HaveOccurred())
+
+		bootstrapRecord, err := instanceRepo.FindBootstrap()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bootstrapRecord.Job).To(Equal("fake-job-name"))
+	})
+
+	Context("when the deployment has more than one job", func() {
+		var (
+			mockOtherInstanceManager *mock_instance.MockManager
+			mockOtherInstance        *mock_instance.MockInstance
+		)
+
+		BeforeEach(func() {
+			deploymentManifest.Jobs = append(deploymentManifest.Jobs, bideplmanifest.Job{
+				Name:      "fake-other-job-name",
+				Instances: 1,
+			})
+
+			mockOtherInstanceManager = mock_instance.NewMockManager(mockCtrl)
+			mockOtherInstance = mock_instance.NewMockInstance(mockCtrl)
+		})
+
+		It("creates and updates the instances of every job after the bootstrap instance", func() {
+			mockInstanceManagerProvider.EXPECT().Get(gomock.Any(), "fake-other-job-name", 0).Return(mockOtherInstanceManager, nil)
+			mockOtherInstanceManager.EXPECT().Create("fake-other-job-name", 0, deploymentManifest, cloudStemcell, registryConfig, fakeStage).Return(mockOtherInstance, []bidisk.Disk{}, nil)
+			mockOtherInstance.EXPECT().UpdateJobs(deploymentManifest, fakeStage)
+
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeVMManager.CreateInput.JobName).To(Equal("fake-job-name"))
+		})
+
+		It("returns an error when the manager of an instance cannot be provided", func() {
+			mockInstanceManagerProvider.EXPECT().Get(gomock.Any(), "fake-other-job-name", 0).Return(nil, bosherr.Error("fake-provider-error"))
+
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("Creating instance 'fake-other-job-name/0': fake-provider-error"))
+		})
+
+		It("does not create the instances of errand jobs", func() {
+			deploymentManifest.Jobs[1].Lifecycle = bideplmanifest.JobLifecycleErrand
+
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Context("when registry & ssh tunnel configs are not empty", func() {
 		BeforeEach(func() {
 			registryConfig = biinstallmanifest.Registry{
@@ -227,7 +294,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("starts the SSH tunnel", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakeSSHTunnel.Started).To(BeTrue())
 			Expect(fakeSSHTunnelFactory.NewSSHTunnelOptions).To(Equal(bisshtunnel.Options{
@@ -247,7 +314,7 @@ var _ = Describe("Deployer", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+				_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-ssh-tunnel-start-error"))
 			})
@@ -255,7 +322,7 @@ var _ = Describe("Deployer", func() {
 	})
 
 	It("waits for the vm", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(fakeVM.WaitUntilReadyInputs).To(ContainElement(fakebivm.WaitUntilReadyInput{
 			Timeout: 10 * time.Minute,
@@ -264,7 +331,7 @@ var _ = Describe("Deployer", func() {
 	})
 
 	It("logs start and stop events to the eventLogger", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeStage.PerformCalls[1]).To(Equal(&fakebiui.PerformCall{
@@ -282,7 +349,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("logs start and stop events to the eventLogger", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-wait-error"))
 
@@ -294,7 +361,7 @@ var _ = Describe("Deployer", func() {
 	})
 
 	It("updates the vm", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeVM.ApplyInputs).To(Equal([]fakebivm.ApplyInput{
@@ -303,14 +370,14 @@ var _ = Describe("Deployer", func() {
 	})
 
 	It("starts the agent", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeVM.StartCalled).To(Equal(1))
 	})
 
 	It("waits until agent reports state as running", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeVM.WaitToBeRunningInputs).To(ContainElement(fakebivm.WaitInput{
@@ -325,13 +392,13 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("returns an error", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).To(HaveOccurred())
 		})
 	})
 
 	It("logs instance update ui stages", func() {
-		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+		_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 		Expect(err).NotTo(HaveOccurred())
 
 		Expect(fakeStage.PerformCalls[2:4]).To(Equal([]*fakebiui.PerformCall{
@@ -346,7 +413,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("logs start and stop events to the eventLogger", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-apply-error"))
 
@@ -362,7 +429,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("logs start and stop events to the eventLogger", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-start-error"))
 
@@ -382,7 +449,7 @@ var _ = Describe("Deployer", func() {
 		})
 
 		It("logs start and stop events to the eventLogger", func() {
-			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, fakeVMManager, mockBlobstore, fakeStage)
+			_, err := deployer.Deploy(cloud, deploymentManifest, cloudStemcell, registryConfig, mockInstanceManagerProvider, fakeStage)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("fake-wait-running-error"))
 
@@ -7,12 +7,15 @@ import (
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
 type Deployment interface {
 	Delete(biui.Stage) error
+	Stop(hard bool, stage biui.Stage) error
+	Start(updateWatchTime bideplmanifest.WatchTime, stage biui.Stage) error
 }
 
 type deployment struct {
@@ -77,6 +80,26 @@ func (d *deployment) Delete(deleteStage biui.Stage) error {
 	return nil
 }
 
+func (d *deployment) Stop(hard bool, stopStage biui.Stage) error {
+	for _, instance := range d.instances {
+		if err := instance.Stop(hard, stopStage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *deployment) Start(updateWatchTime bideplmanifest.WatchTime, startStage biui.Stage) error {
+	for _, instance := range d.instances {
+		if err := instance.Start(d.disks, updateWatchTime, startStage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (d *deployment) deleteDisk(deleteStage biui.Stage, disk bidisk.Disk) error {
 	stepName := fmt.Sprintf("Deleting disk '%s'", disk.CID())
 	return deleteStage.Perform(stepName, func() error {
@@ -7,16 +7,18 @@ import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 	"time"
 
+	mock_agentclient "github.com/cloudfoundry/bosh-init/agentclient/mocks"
 	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
 	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
+	mock_instance "github.com/cloudfoundry/bosh-init/deployment/instance/mocks"
 	mock_instance_state "github.com/cloudfoundry/bosh-init/deployment/instance/state/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
 	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
@@ -107,7 +109,7 @@ var _ = Describe("Deployment", func() {
 			}
 
 			mockStateBuilderFactory.EXPECT().NewBuilder(mockBlobstore, mockAgentClient).Return(mockStateBuilder).AnyTimes()
-			mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), fakeStage).Return(mockState, nil).AnyTimes()
+			mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), gomock.Any(), gomock.Any(), fakeStage).Return(mockState, nil).AnyTimes()
 			mockState.EXPECT().ToApplySpec().Return(applySpec).AnyTimes()
 		}
 
@@ -119,8 +121,8 @@ var _ = Describe("Deployment", func() {
 			deploymentStateService = biconfig.NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/deployment.json")
 
 			fakeRepoUUIDGenerator = fakeuuid.NewFakeGenerator()
-			vmRepo = biconfig.NewVMRepo(deploymentStateService)
-			diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeRepoUUIDGenerator)
+			vmRepo = biconfig.NewVMRepo(deploymentStateService, biconfig.BootstrapInstanceKey())
+			diskRepo = biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeRepoUUIDGenerator)
 			stemcellRepo = biconfig.NewStemcellRepo(deploymentStateService, fakeRepoUUIDGenerator)
 
 			mockCloud = mock_cloud.NewMockCloud(mockCtrl)
@@ -151,7 +153,7 @@ var _ = Describe("Deployment", func() {
 
 			mockBlobstore = mock_blobstore.NewMockBlobstore(mockCtrl)
 
-			deploymentManagerFactory := NewManagerFactory(vmManagerFactory, instanceManagerFactory, diskManagerFactory, stemcellManagerFactory, deploymentFactory)
+			deploymentManagerFactory := NewManagerFactory(vmManagerFactory, instanceManagerFactory, diskManagerFactory, stemcellManagerFactory, deploymentFactory, biconfig.NewInstanceRepo(deploymentStateService), biconfig.NewInstanceIDRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeRepoUUIDGenerator))
 			deploymentManager := deploymentManagerFactory.NewManager(mockCloud, mockAgentClient, mockBlobstore)
 
 			allowApplySpecToBeCreated()
@@ -168,9 +170,7 @@ var _ = Describe("Deployment", func() {
 				deploymentStateService.Save(biconfig.DeploymentState{
 					DirectorID:        "fake-director-id",
 					InstallationID:    "fake-installation-id",
-					CurrentVMCID:      "fake-vm-cid",
 					CurrentStemcellID: "fake-stemcell-guid",
-					CurrentDiskID:     "fake-disk-guid",
 					Disks: []biconfig.DiskRecord{
 						{
 							ID:   "fake-disk-guid",
@@ -184,6 +184,9 @@ var _ = Describe("Deployment", func() {
 							CID: "fake-stemcell-cid",
 						},
 					},
+					Instances: []biconfig.InstanceRecord{
+						{Bootstrap: true, VMCID: "fake-vm-cid", DiskID: "fake-disk-guid"},
+					},
 				})
 			})
 
@@ -415,4 +418,68 @@ var _ = Describe("Deployment", func() {
 			})
 		})
 	})
+
+	Describe("Stop and Start", func() {
+		var (
+			mockInstance *mock_instance.MockInstance
+			disks        []bidisk.Disk
+			fakeStage    *fakebiui.FakeStage
+
+			deployment Deployment
+		)
+
+		BeforeEach(func() {
+			mockInstance = mock_instance.NewMockInstance(mockCtrl)
+			disks = []bidisk.Disk{bidisk.NewDisk(biconfig.DiskRecord{CID: "fake-disk-cid"}, nil, nil)}
+			fakeStage = fakebiui.NewFakeStage()
+
+			deployment = NewDeployment(
+				[]biinstance.Instance{mockInstance},
+				disks,
+				[]bistemcell.CloudStemcell{},
+				1*time.Second,
+				500*time.Millisecond,
+			)
+		})
+
+		Describe("Stop", func() {
+			It("stops each instance", func() {
+				mockInstance.EXPECT().Stop(true, fakeStage)
+
+				err := deployment.Stop(true, fakeStage)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns an error when stopping an instance fails", func() {
+				mockInstance.EXPECT().Stop(false, fakeStage).Return(bosherr.Error("fake-stop-error"))
+
+				err := deployment.Stop(false, fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-stop-error"))
+			})
+		})
+
+		Describe("Start", func() {
+			var updateWatchTime bideplmanifest.WatchTime
+
+			BeforeEach(func() {
+				updateWatchTime = bideplmanifest.WatchTime{Start: 0, End: 5478}
+			})
+
+			It("starts each instance with the deployment disks", func() {
+				mockInstance.EXPECT().Start(disks, updateWatchTime, fakeStage)
+
+				err := deployment.Start(updateWatchTime, fakeStage)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("returns an error when starting an instance fails", func() {
+				mockInstance.EXPECT().Start(disks, updateWatchTime, fakeStage).Return(bosherr.Error("fake-start-error"))
+
+				err := deployment.Start(updateWatchTime, fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-start-error"))
+			})
+		})
+	})
 })
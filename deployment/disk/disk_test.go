@@ -43,7 +43,7 @@ var _ = Describe("Disk", func() {
 		fakeUUIDGenerator = &fakeuuid.FakeGenerator{}
 		//		todo: come back to this?
 		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/fake/path")
-		diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeUUIDGenerator)
+		diskRepo = biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeUUIDGenerator)
 
 		disk = NewDisk(diskRecord, fakeCloud, diskRepo)
 	})
@@ -85,13 +85,18 @@ func (m *manager) FindUnused() ([]Disk, error) {
 		return disks, bosherr.WrapError(err, "Getting all disk records")
 	}
 
-	currentDiskRecord, found, err := m.diskRepo.FindCurrent()
+	currentDiskRecords, err := m.diskRepo.FindAllCurrent()
 	if err != nil {
-		return disks, bosherr.WrapError(err, "Finding current disk record")
+		return disks, bosherr.WrapError(err, "Finding current disk records")
+	}
+
+	currentDiskIDs := map[string]bool{}
+	for _, currentDiskRecord := range currentDiskRecords {
+		currentDiskIDs[currentDiskRecord.ID] = true
 	}
 
 	for _, diskRecord := range diskRecords {
-		if !found || diskRecord.ID != currentDiskRecord.ID {
+		if !currentDiskIDs[diskRecord.ID] {
 			disks = append(disks, NewDisk(diskRecord, m.cloud, m.diskRepo))
 		}
 	}
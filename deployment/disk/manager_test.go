@@ -24,14 +24,16 @@ var _ = Describe("Manager", func() {
 		fakeFs            *fakesys.FakeFileSystem
 		fakeUUIDGenerator *fakeuuid.FakeGenerator
 		diskRepo          biconfig.DiskRepo
+
+		deploymentStateService biconfig.DeploymentStateService
 	)
 
 	BeforeEach(func() {
 		logger := boshlog.NewLogger(boshlog.LevelNone)
 		fakeFs = fakesys.NewFakeFileSystem()
 		fakeUUIDGenerator = &fakeuuid.FakeGenerator{}
-		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fakeFs, fakeUUIDGenerator, logger, "/fake/path")
-		diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeUUIDGenerator)
+		deploymentStateService = biconfig.NewFileSystemDeploymentStateService(fakeFs, fakeUUIDGenerator, logger, "/fake/path")
+		diskRepo = biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeUUIDGenerator)
 		managerFactory := NewManagerFactory(diskRepo, logger)
 		fakeCloud = fakebicloud.NewFakeCloud()
 		manager = managerFactory.NewManager(fakeCloud)
@@ -182,6 +184,24 @@ var _ = Describe("Manager", func() {
 				thirdDisk,
 			}))
 		})
+
+		Context("when a disk is the current disk of another instance", func() {
+			BeforeEach(func() {
+				instanceKey := biconfig.NewInstanceKey("fake-job-name", 1, "10.0.0.7")
+				instanceDiskRepo := biconfig.NewDiskRepo(deploymentStateService, instanceKey, fakeUUIDGenerator)
+				err := instanceDiskRepo.UpdateCurrent("fake-guid-3")
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("does not return the disk", func() {
+				disks, err := manager.FindUnused()
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(disks).To(Equal([]bidisk.Disk{
+					firstDisk,
+				}))
+			})
+		})
 	})
 
 	Describe("DeleteUnused", func() {
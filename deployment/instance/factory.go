@@ -2,6 +2,7 @@ package instance
 
 import (
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	biinstancestate "github.com/cloudfoundry/bosh-init/deployment/instance/state"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
 	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
@@ -16,6 +17,7 @@ type Factory interface {
 		vmManager bivm.Manager,
 		sshTunnelFactory bisshtunnel.Factory,
 		blobstore biblobstore.Blobstore,
+		instanceIDRepo biconfig.InstanceIDRepo,
 		logger boshlog.Logger,
 	) Instance
 }
@@ -39,6 +41,7 @@ func (f *factory) NewInstance(
 	vmManager bivm.Manager,
 	sshTunnelFactory bisshtunnel.Factory,
 	blobstore biblobstore.Blobstore,
+	instanceIDRepo biconfig.InstanceIDRepo,
 	logger boshlog.Logger,
 ) Instance {
 	stateBuilder := f.stateBuilderFactory.NewBuilder(blobstore, vm.AgentClient())
@@ -50,6 +53,7 @@ func (f *factory) NewInstance(
 		vmManager,
 		sshTunnelFactory,
 		stateBuilder,
+		instanceIDRepo,
 		logger,
 	)
 }
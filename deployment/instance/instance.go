@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstancestate "github.com/cloudfoundry/bosh-init/deployment/instance/state"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
@@ -23,6 +25,9 @@ type Instance interface {
 	WaitUntilReady(biinstallmanifest.Registry, biui.Stage) error
 	UpdateDisks(bideplmanifest.Manifest, biui.Stage) ([]bidisk.Disk, error)
 	UpdateJobs(bideplmanifest.Manifest, biui.Stage) error
+	Stop(detachDisks bool, stage biui.Stage) error
+	Start(disks []bidisk.Disk, updateWatchTime bideplmanifest.WatchTime, stage biui.Stage) error
+	RunErrand(stage biui.Stage) (biagentclient.ErrandResult, error)
 	Delete(
 		pingTimeout time.Duration,
 		pingDelay time.Duration,
@@ -37,6 +42,7 @@ type instance struct {
 	vmManager        bivm.Manager
 	sshTunnelFactory bisshtunnel.Factory
 	stateBuilder     biinstancestate.Builder
+	instanceIDRepo   biconfig.InstanceIDRepo
 	logger           boshlog.Logger
 	logTag           string
 }
@@ -48,6 +54,7 @@ func NewInstance(
 	vmManager bivm.Manager,
 	sshTunnelFactory bisshtunnel.Factory,
 	stateBuilder biinstancestate.Builder,
+	instanceIDRepo biconfig.InstanceIDRepo,
 	logger boshlog.Logger,
 ) Instance {
 	return &instance{
@@ -57,6 +64,7 @@ func NewInstance(
 		vmManager:        vmManager,
 		sshTunnelFactory: sshTunnelFactory,
 		stateBuilder:     stateBuilder,
+		instanceIDRepo:   instanceIDRepo,
 		logger:           logger,
 		logTag:           "instance",
 	}
@@ -134,7 +142,12 @@ func (i *instance) UpdateJobs(
 	deploymentManifest bideplmanifest.Manifest,
 	stage biui.Stage,
 ) error {
-	newState, err := i.stateBuilder.Build(i.jobName, i.id, deploymentManifest, stage)
+	instanceID, err := i.instanceIDRepo.FindOrCreate()
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Finding ID of instance '%s/%d'", i.jobName, i.id)
+	}
+
+	newState, err := i.stateBuilder.Build(i.jobName, i.id, instanceID, deploymentManifest, i.vm.Networks(), stage)
 	if err != nil {
 		return bosherr.WrapErrorf(err, "Building state for instance '%s/%d'", i.jobName, i.id)
 	}
@@ -165,6 +178,100 @@ func (i *instance) UpdateJobs(
 	return i.waitUntilJobsAreRunning(deploymentManifest.Update.UpdateWatchTime, stage)
 }
 
+// Stop stops the jobs on the instance, leaving the VM running.
+// If detachDisks is true, the persistent disks are also unmounted and detached from the VM.
+func (i *instance) Stop(detachDisks bool, stage biui.Stage) error {
+	if err := i.stopJobs(stage); err != nil {
+		return err
+	}
+
+	if !detachDisks {
+		return nil
+	}
+
+	disks, err := i.vm.Disks()
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting VM '%s' disks", i.vm.CID())
+	}
+
+	for _, disk := range disks {
+		stepName := fmt.Sprintf("Unmounting disk '%s'", disk.CID())
+		err = stage.Perform(stepName, func() error {
+			if err := i.vm.UnmountDisk(disk); err != nil {
+				return bosherr.WrapErrorf(err, "Unmounting disk '%s' from VM '%s'", disk.CID(), i.vm.CID())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		stepName = fmt.Sprintf("Detaching disk '%s'", disk.CID())
+		err = stage.Perform(stepName, func() error {
+			if err := i.vm.DetachDisk(disk); err != nil {
+				return bosherr.WrapErrorf(err, "Detaching disk '%s' from VM '%s'", disk.CID(), i.vm.CID())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunErrand runs the errand job of the instance and returns its exit status and output.
+// A non-zero exit status is not an error.
+func (i *instance) RunErrand(stage biui.Stage) (biagentclient.ErrandResult, error) {
+	var result biagentclient.ErrandResult
+	stepName := fmt.Sprintf("Running errand on instance '%s/%d'", i.jobName, i.id)
+	err := stage.Perform(stepName, func() error {
+		var err error
+		result, err = i.vm.RunErrand()
+		return err
+	})
+	return result, err
+}
+
+// Start attaches any of the given disks that are not attached to the VM (e.g. after a hard stop),
+// starts the jobs on the instance and waits for them to be running.
+func (i *instance) Start(disks []bidisk.Disk, updateWatchTime bideplmanifest.WatchTime, stage biui.Stage) error {
+	attachedDisks, err := i.vm.Disks()
+	if err != nil {
+		return bosherr.WrapErrorf(err, "Getting VM '%s' disks", i.vm.CID())
+	}
+
+	attachedDiskCIDs := map[string]bool{}
+	for _, attachedDisk := range attachedDisks {
+		attachedDiskCIDs[attachedDisk.CID()] = true
+	}
+
+	for _, disk := range disks {
+		if attachedDiskCIDs[disk.CID()] {
+			continue
+		}
+
+		stepName := fmt.Sprintf("Attaching disk '%s' to VM '%s'", disk.CID(), i.vm.CID())
+		err = stage.Perform(stepName, func() error {
+			return i.vm.AttachDisk(disk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	stepName := fmt.Sprintf("Starting jobs on instance '%s/%d'", i.jobName, i.id)
+	err = stage.Perform(stepName, func() error {
+		return i.vm.Start()
+	})
+	if err != nil {
+		return err
+	}
+
+	return i.waitUntilJobsAreRunning(updateWatchTime, stage)
+}
+
 func (i *instance) Delete(
 	pingTimeout time.Duration,
 	pingDelay time.Duration,
@@ -10,7 +10,9 @@ import (
 	mock_instance_state "github.com/cloudfoundry/bosh-init/deployment/instance/state/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
@@ -18,6 +20,9 @@ import (
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
 
 	fakebidisk "github.com/cloudfoundry/bosh-init/deployment/disk/fakes"
 	fakebisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel/fakes"
@@ -69,6 +74,9 @@ var _ = Describe("Instance", func() {
 
 		logger := boshlog.NewLogger(boshlog.LevelNone)
 
+		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fakesys.NewFakeFileSystem(), fakeuuid.NewFakeGenerator(), logger, "/deployment.json")
+		instanceIDRepo := biconfig.NewInstanceIDRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), &fakeuuid.FakeGenerator{GeneratedUUID: "fake-instance-id"})
+
 		instance = NewInstance(
 			jobName,
 			jobIndex,
@@ -76,6 +84,7 @@ var _ = Describe("Instance", func() {
 			fakeVMManager,
 			fakeSSHTunnelFactory,
 			mockStateBuilder,
+			instanceIDRepo,
 			logger,
 		)
 
@@ -288,14 +297,18 @@ var _ = Describe("Instance", func() {
 			applySpec = bias.ApplySpec{
 				Deployment: "fake-deployment-name",
 			}
+
+			fakeVM.NetworksReturn = map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{"ip": "10.0.0.5"},
+			}
 		})
 
 		JustBeforeEach(func() {
-			expectStateBuild = mockStateBuilder.EXPECT().Build(jobName, jobIndex, deploymentManifest, fakeStage).Return(mockState, nil).AnyTimes()
+			expectStateBuild = mockStateBuilder.EXPECT().Build(jobName, jobIndex, "fake-instance-id", deploymentManifest, fakeVM.NetworksReturn, fakeStage).Return(mockState, nil).AnyTimes()
 			mockState.EXPECT().ToApplySpec().Return(applySpec).AnyTimes()
 		})
 
-		It("builds a new instance state", func() {
+		It("builds a new instance state with the id recorded for the instance and the networks reported for the vm", func() {
 			expectStateBuild.Times(1)
 
 			err := instance.UpdateJobs(deploymentManifest, fakeStage)
@@ -418,6 +431,196 @@ var _ = Describe("Instance", func() {
 		})
 	})
 
+	Describe("Stop", func() {
+		It("stops jobs on the vm", func() {
+			err := instance.Stop(false, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVM.StopCalled).To(Equal(1))
+			Expect(fakeStage.PerformCalls).To(Equal([]*fakebiui.PerformCall{
+				{Name: "Stopping jobs on instance 'fake-job-name/0'"},
+			}))
+		})
+
+		It("does not touch vm disks", func() {
+			fakeVM.ListDisksDisks = []bidisk.Disk{fakebidisk.NewFakeDisk("fake-disk")}
+
+			err := instance.Stop(false, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVM.UnmountDiskInputs).To(BeEmpty())
+			Expect(fakeVM.DetachDiskInputs).To(BeEmpty())
+		})
+
+		Context("when detaching disks", func() {
+			var (
+				firstDisk  *fakebidisk.FakeDisk
+				secondDisk *fakebidisk.FakeDisk
+			)
+
+			BeforeEach(func() {
+				firstDisk = fakebidisk.NewFakeDisk("fake-disk-1")
+				secondDisk = fakebidisk.NewFakeDisk("fake-disk-2")
+				fakeVM.ListDisksDisks = []bidisk.Disk{firstDisk, secondDisk}
+			})
+
+			It("unmounts and detaches vm disks", func() {
+				err := instance.Stop(true, fakeStage)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeVM.UnmountDiskInputs).To(Equal([]fakebivm.UnmountDiskInput{
+					{Disk: firstDisk},
+					{Disk: secondDisk},
+				}))
+				Expect(fakeVM.DetachDiskInputs).To(Equal([]fakebivm.DetachDiskInput{
+					{Disk: firstDisk},
+					{Disk: secondDisk},
+				}))
+
+				Expect(fakeStage.PerformCalls).To(Equal([]*fakebiui.PerformCall{
+					{Name: "Stopping jobs on instance 'fake-job-name/0'"},
+					{Name: "Unmounting disk 'fake-disk-1'"},
+					{Name: "Detaching disk 'fake-disk-1'"},
+					{Name: "Unmounting disk 'fake-disk-2'"},
+					{Name: "Detaching disk 'fake-disk-2'"},
+				}))
+			})
+
+			Context("when unmounting disk fails", func() {
+				BeforeEach(func() {
+					fakeVM.UnmountDiskErr = bosherr.Error("fake-unmount-error")
+				})
+
+				It("returns an error and does not detach the disk", func() {
+					err := instance.Stop(true, fakeStage)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-unmount-error"))
+
+					Expect(fakeVM.DetachDiskInputs).To(BeEmpty())
+				})
+			})
+		})
+
+		Context("when stopping vm fails", func() {
+			BeforeEach(func() {
+				fakeVM.StopErr = bosherr.Error("fake-stop-error")
+			})
+
+			It("returns an error", func() {
+				err := instance.Stop(true, fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-stop-error"))
+
+				Expect(fakeVM.UnmountDiskInputs).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("RunErrand", func() {
+		It("runs the errand on the vm and returns its result", func() {
+			fakeVM.RunErrandResult = biagentclient.ErrandResult{ExitStatus: 1, Stdout: "fake-stdout", Stderr: "fake-stderr"}
+
+			result, err := instance.RunErrand(fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(biagentclient.ErrandResult{ExitStatus: 1, Stdout: "fake-stdout", Stderr: "fake-stderr"}))
+
+			Expect(fakeVM.RunErrandCalledTimes).To(Equal(1))
+			Expect(fakeStage.PerformCalls).To(Equal([]*fakebiui.PerformCall{
+				{Name: "Running errand on instance 'fake-job-name/0'"},
+			}))
+		})
+
+		Context("when running the errand fails", func() {
+			BeforeEach(func() {
+				fakeVM.RunErrandErr = bosherr.Error("fake-run-errand-error")
+			})
+
+			It("returns an error", func() {
+				_, err := instance.RunErrand(fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("fake-run-errand-error"))
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		var (
+			updateWatchTime bideplmanifest.WatchTime
+			disk            *fakebidisk.FakeDisk
+		)
+
+		BeforeEach(func() {
+			updateWatchTime = bideplmanifest.WatchTime{
+				Start: 0,
+				End:   5478,
+			}
+			disk = fakebidisk.NewFakeDisk("fake-disk-cid")
+		})
+
+		It("starts jobs and waits until agent reports state as running", func() {
+			err := instance.Start([]bidisk.Disk{}, updateWatchTime, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVM.StartCalled).To(Equal(1))
+			Expect(fakeVM.WaitToBeRunningInputs).To(ContainElement(fakebivm.WaitInput{
+				MaxAttempts: 5,
+				Delay:       1 * time.Second,
+			}))
+
+			Expect(fakeStage.PerformCalls).To(Equal([]*fakebiui.PerformCall{
+				{Name: "Starting jobs on instance 'fake-job-name/0'"},
+				{Name: "Waiting for instance 'fake-job-name/0' to be running"},
+			}))
+		})
+
+		It("attaches disks that are not attached to the vm", func() {
+			err := instance.Start([]bidisk.Disk{disk}, updateWatchTime, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVM.AttachDiskInputs).To(Equal([]fakebivm.AttachDiskInput{
+				{Disk: disk},
+			}))
+			Expect(fakeStage.PerformCalls[0]).To(Equal(&fakebiui.PerformCall{
+				Name: "Attaching disk 'fake-disk-cid' to VM 'fake-vm-cid'",
+			}))
+		})
+
+		It("does not re-attach disks that are already attached", func() {
+			fakeVM.ListDisksDisks = []bidisk.Disk{disk}
+
+			err := instance.Start([]bidisk.Disk{disk}, updateWatchTime, fakeStage)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeVM.AttachDiskInputs).To(BeEmpty())
+		})
+
+		Context("when starting vm fails", func() {
+			BeforeEach(func() {
+				fakeVM.StartErr = bosherr.Error("fake-start-error")
+			})
+
+			It("returns an error", func() {
+				err := instance.Start([]bidisk.Disk{}, updateWatchTime, fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-start-error"))
+
+				Expect(fakeVM.WaitToBeRunningInputs).To(BeEmpty())
+			})
+		})
+
+		Context("when waiting for running state fails", func() {
+			BeforeEach(func() {
+				fakeVM.WaitToBeRunningErr = bosherr.Error("fake-wait-running-error")
+			})
+
+			It("returns an error", func() {
+				err := instance.Start([]bidisk.Disk{}, updateWatchTime, fakeStage)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-wait-running-error"))
+			})
+		})
+	})
+
 	Describe("WaitUntilReady", func() {
 		var (
 			registryConfig biinstallmanifest.Registry
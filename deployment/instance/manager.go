@@ -6,6 +6,7 @@ import (
 
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
@@ -19,6 +20,7 @@ import (
 
 type Manager interface {
 	FindCurrent() ([]Instance, error)
+	FindCurrentDisks() ([]bidisk.Disk, error)
 	Create(
 		jobName string,
 		id int,
@@ -37,17 +39,28 @@ type Manager interface {
 type manager struct {
 	cloud            bicloud.Cloud
 	vmManager        bivm.Manager
+	diskManager      bidisk.Manager
 	blobstore        biblobstore.Blobstore
+	instanceRepo     biconfig.InstanceRepo
+	instanceIDRepo   biconfig.InstanceIDRepo
+	jobName          string
+	id               int
+	host             string
 	sshTunnelFactory bisshtunnel.Factory
 	instanceFactory  Factory
 	logger           boshlog.Logger
 	logTag           string
 }
 
+// NewManager returns the Manager of the bootstrap instance.
+// The job name of the bootstrap instance is recorded in the instance repo when the instance is created.
 func NewManager(
 	cloud bicloud.Cloud,
 	vmManager bivm.Manager,
+	diskManager bidisk.Manager,
 	blobstore biblobstore.Blobstore,
+	instanceRepo biconfig.InstanceRepo,
+	instanceIDRepo biconfig.InstanceIDRepo,
 	sshTunnelFactory bisshtunnel.Factory,
 	instanceFactory Factory,
 	logger boshlog.Logger,
@@ -55,7 +68,41 @@ func NewManager(
 	return &manager{
 		cloud:            cloud,
 		vmManager:        vmManager,
+		diskManager:      diskManager,
 		blobstore:        blobstore,
+		instanceRepo:     instanceRepo,
+		instanceIDRepo:   instanceIDRepo,
+		sshTunnelFactory: sshTunnelFactory,
+		instanceFactory:  instanceFactory,
+		logger:           logger,
+		logTag:           "vmDeployer",
+	}
+}
+
+// NewInstanceManager returns the Manager of an instance other than the bootstrap instance.
+// The SSH tunnel to the registry is opened to the host of the instance.
+func NewInstanceManager(
+	cloud bicloud.Cloud,
+	vmManager bivm.Manager,
+	diskManager bidisk.Manager,
+	blobstore biblobstore.Blobstore,
+	instanceIDRepo biconfig.InstanceIDRepo,
+	jobName string,
+	id int,
+	host string,
+	sshTunnelFactory bisshtunnel.Factory,
+	instanceFactory Factory,
+	logger boshlog.Logger,
+) Manager {
+	return &manager{
+		cloud:            cloud,
+		vmManager:        vmManager,
+		diskManager:      diskManager,
+		blobstore:        blobstore,
+		instanceIDRepo:   instanceIDRepo,
+		jobName:          jobName,
+		id:               id,
+		host:             host,
 		sshTunnelFactory: sshTunnelFactory,
 		instanceFactory:  instanceFactory,
 		logger:           logger,
@@ -66,24 +113,26 @@ func NewManager(
 func (m *manager) FindCurrent() ([]Instance, error) {
 	instances := []Instance{}
 
-	// Only one current instance will exist (for now)
+	// Only one current instance exists per manager
 	vm, found, err := m.vmManager.FindCurrent()
 	if err != nil {
 		return instances, bosherr.WrapError(err, "Finding currently deployed instances")
 	}
 
 	if found {
-		// TODO: store the name of the job for each instance in the repo, so that we can print it when deleting
-		jobName := "unknown"
-		instanceID := 0
+		jobName, err := m.findJobName()
+		if err != nil {
+			return instances, err
+		}
 
 		instance := m.instanceFactory.NewInstance(
 			jobName,
-			instanceID,
+			m.id,
 			vm,
 			m.vmManager,
 			m.sshTunnelFactory,
 			m.blobstore,
+			m.instanceIDRepo,
 			m.logger,
 		)
 		instances = append(instances, instance)
@@ -92,6 +141,16 @@ func (m *manager) FindCurrent() ([]Instance, error) {
 	return instances, nil
 }
 
+// FindCurrentDisks returns the persistent disks recorded for the instance, whether or not they are attached to its VM
+func (m *manager) FindCurrentDisks() ([]bidisk.Disk, error) {
+	disks, err := m.diskManager.FindCurrent()
+	if err != nil {
+		return disks, bosherr.WrapError(err, "Finding current disks")
+	}
+
+	return disks, nil
+}
+
 func (m *manager) Create(
 	jobName string,
 	id int,
@@ -100,11 +159,17 @@ func (m *manager) Create(
 	registryConfig biinstallmanifest.Registry,
 	eventLoggerStage biui.Stage,
 ) (Instance, []bidisk.Disk, error) {
+	if m.instanceRepo != nil {
+		if err := m.instanceRepo.UpdateBootstrapJobName(jobName); err != nil {
+			return nil, []bidisk.Disk{}, bosherr.WrapError(err, "Recording job name of bootstrap instance")
+		}
+	}
+
 	var vm bivm.VM
 	stepName := fmt.Sprintf("Creating VM for instance '%s/%d' from stemcell '%s'", jobName, id, cloudStemcell.CID())
 	err := eventLoggerStage.Perform(stepName, func() error {
 		var err error
-		vm, err = m.vmManager.Create(cloudStemcell, deploymentManifest)
+		vm, err = m.vmManager.Create(cloudStemcell, deploymentManifest, jobName, id)
 		if err != nil {
 			return bosherr.WrapError(err, "Creating VM")
 		}
@@ -119,7 +184,11 @@ func (m *manager) Create(
 		return nil, []bidisk.Disk{}, err
 	}
 
-	instance := m.instanceFactory.NewInstance(jobName, id, vm, m.vmManager, m.sshTunnelFactory, m.blobstore, m.logger)
+	instance := m.instanceFactory.NewInstance(jobName, id, vm, m.vmManager, m.sshTunnelFactory, m.blobstore, m.instanceIDRepo, m.logger)
+
+	if m.host != "" && registryConfig.SSHTunnel.Host != "" {
+		registryConfig.SSHTunnel.Host = m.host
+	}
 
 	if err := instance.WaitUntilReady(registryConfig, eventLoggerStage); err != nil {
 		return instance, []bidisk.Disk{}, bosherr.WrapError(err, "Waiting until instance is ready")
@@ -150,3 +219,23 @@ func (m *manager) DeleteAll(
 	}
 	return nil
 }
+
+// findJobName returns the job name of the instance.
+// For the bootstrap instance it is read from the instance repo; deployment states saved before
+// job names were recorded do not have it.
+func (m *manager) findJobName() (string, error) {
+	if m.instanceRepo == nil {
+		return m.jobName, nil
+	}
+
+	bootstrapRecord, err := m.instanceRepo.FindBootstrap()
+	if err != nil {
+		return "", bosherr.WrapError(err, "Finding bootstrap instance record")
+	}
+
+	if bootstrapRecord.Job == "" {
+		return "unknown", nil
+	}
+
+	return bootstrapRecord.Job, nil
+}
@@ -3,13 +3,16 @@ package instance
 import (
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
 	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 )
 
 type ManagerFactory interface {
-	NewManager(bicloud.Cloud, bivm.Manager, biblobstore.Blobstore) Manager
+	NewManager(cloud bicloud.Cloud, vmManager bivm.Manager, diskManager bidisk.Manager, blobstore biblobstore.Blobstore, instanceRepo biconfig.InstanceRepo, instanceIDRepo biconfig.InstanceIDRepo) Manager
+	NewInstanceManager(cloud bicloud.Cloud, vmManager bivm.Manager, diskManager bidisk.Manager, blobstore biblobstore.Blobstore, instanceIDRepo biconfig.InstanceIDRepo, jobName string, id int, host string) Manager
 }
 
 type managerFactory struct {
@@ -30,11 +33,30 @@ func NewManagerFactory(
 	}
 }
 
-func (f *managerFactory) NewManager(cloud bicloud.Cloud, vmManager bivm.Manager, blobstore biblobstore.Blobstore) Manager {
+func (f *managerFactory) NewManager(cloud bicloud.Cloud, vmManager bivm.Manager, diskManager bidisk.Manager, blobstore biblobstore.Blobstore, instanceRepo biconfig.InstanceRepo, instanceIDRepo biconfig.InstanceIDRepo) Manager {
 	return NewManager(
 		cloud,
 		vmManager,
+		diskManager,
 		blobstore,
+		instanceRepo,
+		instanceIDRepo,
+		f.sshTunnelFactory,
+		f.instanceFactory,
+		f.logger,
+	)
+}
+
+func (f *managerFactory) NewInstanceManager(cloud bicloud.Cloud, vmManager bivm.Manager, diskManager bidisk.Manager, blobstore biblobstore.Blobstore, instanceIDRepo biconfig.InstanceIDRepo, jobName string, id int, host string) Manager {
+	return NewInstanceManager(
+		cloud,
+		vmManager,
+		diskManager,
+		blobstore,
+		instanceIDRepo,
+		jobName,
+		id,
+		host,
 		f.sshTunnelFactory,
 		f.instanceFactory,
 		f.logger,
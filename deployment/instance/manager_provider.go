@@ -0,0 +1,193 @@
+package instance
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
+	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
+	boshuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid"
+)
+
+// ManagerProvider provides a Manager for each instance of a deployment.
+// The bootstrap instance (the first instance of the first job) is reached on the installation mbus URL,
+// every other instance on the same URL with the host replaced by the static IP of the instance.
+// FindAll returns the Manager of the bootstrap instance first.
+type ManagerProvider interface {
+	Get(deploymentManifest bideplmanifest.Manifest, jobName string, id int) (Manager, error)
+	FindAll() ([]Manager, error)
+}
+
+type ManagerProviderFactory interface {
+	NewManagerProvider(cloud bicloud.Cloud, directorID string, mbusURL string) ManagerProvider
+}
+
+type managerProviderFactory struct {
+	deploymentStateService biconfig.DeploymentStateService
+	vmManagerFactory       bivm.ManagerFactory
+	instanceManagerFactory ManagerFactory
+	agentClientFactory     biagentclient.AgentClientFactory
+	blobstoreFactory       biblobstore.Factory
+	stemcellRepo           biconfig.StemcellRepo
+	uuidGenerator          boshuuid.Generator
+	fs                     boshsys.FileSystem
+	logger                 boshlog.Logger
+}
+
+func NewManagerProviderFactory(
+	deploymentStateService biconfig.DeploymentStateService,
+	vmManagerFactory bivm.ManagerFactory,
+	instanceManagerFactory ManagerFactory,
+	agentClientFactory biagentclient.AgentClientFactory,
+	blobstoreFactory biblobstore.Factory,
+	stemcellRepo biconfig.StemcellRepo,
+	uuidGenerator boshuuid.Generator,
+	fs boshsys.FileSystem,
+	logger boshlog.Logger,
+) ManagerProviderFactory {
+	return &managerProviderFactory{
+		deploymentStateService: deploymentStateService,
+		vmManagerFactory:       vmManagerFactory,
+		instanceManagerFactory: instanceManagerFactory,
+		agentClientFactory:     agentClientFactory,
+		blobstoreFactory:       blobstoreFactory,
+		stemcellRepo:           stemcellRepo,
+		uuidGenerator:          uuidGenerator,
+		fs:                     fs,
+		logger:                 logger,
+	}
+}
+
+func (f *managerProviderFactory) NewManagerProvider(cloud bicloud.Cloud, directorID string, mbusURL string) ManagerProvider {
+	return &managerProvider{
+		factory:    f,
+		cloud:      cloud,
+		directorID: directorID,
+		mbusURL:    mbusURL,
+	}
+}
+
+type managerProvider struct {
+	factory    *managerProviderFactory
+	cloud      bicloud.Cloud
+	directorID string
+	mbusURL    string
+}
+
+func (p *managerProvider) Get(deploymentManifest bideplmanifest.Manifest, jobName string, id int) (Manager, error) {
+	if id == 0 && jobName == deploymentManifest.JobName() {
+		return p.bootstrapManager()
+	}
+
+	job, found := deploymentManifest.FindJobByName(jobName)
+	if !found {
+		return nil, bosherr.Errorf("Job '%s' not found in deployment manifest", jobName)
+	}
+
+	host, found := job.StaticIP(id)
+	if !found {
+		return nil, bosherr.Errorf("Instance '%s/%d' has no static IP", jobName, id)
+	}
+
+	return p.instanceManager(jobName, id, host)
+}
+
+func (p *managerProvider) FindAll() ([]Manager, error) {
+	managers := []Manager{}
+
+	bootstrapManager, err := p.bootstrapManager()
+	if err != nil {
+		return managers, err
+	}
+	managers = append(managers, bootstrapManager)
+
+	instanceRecords, err := biconfig.NewInstanceRepo(p.factory.deploymentStateService).All()
+	if err != nil {
+		return managers, bosherr.WrapError(err, "Finding instance records")
+	}
+
+	for _, instanceRecord := range instanceRecords {
+		instanceManager, err := p.instanceManager(instanceRecord.Job, instanceRecord.Index, instanceRecord.Host)
+		if err != nil {
+			return managers, err
+		}
+		managers = append(managers, instanceManager)
+	}
+
+	return managers, nil
+}
+
+func (p *managerProvider) bootstrapManager() (Manager, error) {
+	f := p.factory
+	agentClient := f.agentClientFactory.NewAgentClient(p.directorID, p.mbusURL)
+	vmManager := f.vmManagerFactory.NewManager(p.cloud, agentClient)
+	diskRepo := biconfig.NewDiskRepo(f.deploymentStateService, biconfig.BootstrapInstanceKey(), f.uuidGenerator)
+	diskManager := bidisk.NewManagerFactory(diskRepo, f.logger).NewManager(p.cloud)
+	instanceRepo := biconfig.NewInstanceRepo(f.deploymentStateService)
+	instanceIDRepo := biconfig.NewInstanceIDRepo(f.deploymentStateService, biconfig.BootstrapInstanceKey(), f.uuidGenerator)
+
+	blobstore, err := f.blobstoreFactory.Create(p.mbusURL)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Creating blobstore client")
+	}
+
+	return f.instanceManagerFactory.NewManager(p.cloud, vmManager, diskManager, blobstore, instanceRepo, instanceIDRepo), nil
+}
+
+func (p *managerProvider) instanceManager(jobName string, id int, host string) (Manager, error) {
+	mbusURL, err := InstanceMbusURL(p.mbusURL, host)
+	if err != nil {
+		return nil, bosherr.WrapErrorf(err, "Building mbus URL for instance '%s/%d'", jobName, id)
+	}
+
+	f := p.factory
+	instanceKey := biconfig.NewInstanceKey(jobName, id, host)
+	vmRepo := biconfig.NewVMRepo(f.deploymentStateService, instanceKey)
+	diskRepo := biconfig.NewDiskRepo(f.deploymentStateService, instanceKey, f.uuidGenerator)
+	instanceIDRepo := biconfig.NewInstanceIDRepo(f.deploymentStateService, instanceKey, f.uuidGenerator)
+	diskManagerFactory := bidisk.NewManagerFactory(diskRepo, f.logger)
+	diskDeployer := bivm.NewDiskDeployer(diskManagerFactory, diskRepo, f.logger)
+	vmManagerFactory := bivm.NewManagerFactory(vmRepo, f.stemcellRepo, diskDeployer, f.uuidGenerator, f.fs, f.logger)
+
+	agentClient := f.agentClientFactory.NewAgentClient(p.directorID, mbusURL)
+	vmManager := vmManagerFactory.NewManager(p.cloud, agentClient)
+	diskManager := diskManagerFactory.NewManager(p.cloud)
+
+	blobstore, err := f.blobstoreFactory.Create(mbusURL)
+	if err != nil {
+		return nil, bosherr.WrapError(err, "Creating blobstore client")
+	}
+
+	return f.instanceManagerFactory.NewInstanceManager(p.cloud, vmManager, diskManager, blobstore, instanceIDRepo, jobName, id, host), nil
+}
+
+// InstanceMbusURL returns the mbus URL of an instance other than the bootstrap instance:
+// the installation mbus URL with the host replaced by the host of the instance.
+func InstanceMbusURL(mbusURL string, host string) (string, error) {
+	parsedURL, err := url.Parse(mbusURL)
+	if err != nil {
+		return "", bosherr.WrapErrorf(err, "Parsing mbus URL")
+	}
+
+	_, port, err := net.SplitHostPort(parsedURL.Host)
+	if err != nil {
+		parsedURL.Host = host
+		if strings.Contains(host, ":") {
+			// IPv6 addresses must be bracketed in URLs
+			parsedURL.Host = "[" + host + "]"
+		}
+	} else {
+		parsedURL.Host = net.JoinHostPort(host, port)
+	}
+
+	return parsedURL.String(), nil
+}
@@ -13,6 +13,7 @@ import (
 	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	bisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel"
@@ -25,6 +26,8 @@ import (
 	fakebidisk "github.com/cloudfoundry/bosh-init/deployment/disk/fakes"
 	fakebisshtunnel "github.com/cloudfoundry/bosh-init/deployment/sshtunnel/fakes"
 	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
 	fakebistemcell "github.com/cloudfoundry/bosh-init/stemcell/fakes"
 	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
 )
@@ -50,6 +53,9 @@ var _ = Describe("Manager", func() {
 		mockBlobstore *mock_blobstore.MockBlobstore
 
 		fakeVMManager        *fakebivm.FakeManager
+		fakeDiskManager      *fakebidisk.FakeManager
+		instanceRepo         biconfig.InstanceRepo
+		instanceIDRepo       biconfig.InstanceIDRepo
 		fakeSSHTunnelFactory *fakebisshtunnel.FakeFactory
 		fakeSSHTunnel        *fakebisshtunnel.FakeTunnel
 		instanceFactory      Factory
@@ -81,10 +87,19 @@ var _ = Describe("Manager", func() {
 
 		fakeStage = fakebiui.NewFakeStage()
 
+		fakeDiskManager = fakebidisk.NewFakeManager()
+		fs := fakesys.NewFakeFileSystem()
+		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fs, fakeuuid.NewFakeGenerator(), logger, "/deployment.json")
+		instanceRepo = biconfig.NewInstanceRepo(deploymentStateService)
+		instanceIDRepo = biconfig.NewInstanceIDRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeuuid.NewFakeGenerator())
+
 		manager = NewManager(
 			fakeCloud,
 			fakeVMManager,
+			fakeDiskManager,
 			mockBlobstore,
+			instanceRepo,
+			instanceIDRepo,
 			fakeSSHTunnelFactory,
 			instanceFactory,
 			logger,
@@ -128,7 +143,7 @@ var _ = Describe("Manager", func() {
 			}
 
 			mockStateBuilderFactory.EXPECT().NewBuilder(mockBlobstore, mockAgentClient).Return(mockStateBuilder).AnyTimes()
-			mockStateBuilder.EXPECT().Build(jobName, jobIndex, deploymentManifest, fakeStage).Return(mockState, nil).AnyTimes()
+			mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), deploymentManifest, gomock.Any(), fakeStage).Return(mockState, nil).AnyTimes()
 			mockState.EXPECT().ToApplySpec().Return(applySpec).AnyTimes()
 		}
 
@@ -176,6 +191,7 @@ var _ = Describe("Manager", func() {
 				fakeVMManager,
 				fakeSSHTunnelFactory,
 				mockStateBuilder,
+				instanceIDRepo,
 				logger,
 			)
 
@@ -202,9 +218,27 @@ var _ = Describe("Manager", func() {
 			Expect(fakeVMManager.CreateInput).To(Equal(fakebivm.CreateInput{
 				Stemcell: fakeCloudStemcell,
 				Manifest: deploymentManifest,
+				JobName:  "fake-job-name",
+				Index:    0,
 			}))
 		})
 
+		It("records the job name of the bootstrap instance", func() {
+			_, _, err := manager.Create(
+				"fake-job-name",
+				0,
+				deploymentManifest,
+				fakeCloudStemcell,
+				registry,
+				fakeStage,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			bootstrapRecord, err := instanceRepo.FindBootstrap()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bootstrapRecord.Job).To(Equal("fake-job-name"))
+		})
+
 		It("updates the current stemcell", func() {
 			_, _, err := manager.Create(
 				"fake-job-name",
@@ -337,6 +371,37 @@ var _ = Describe("Manager", func() {
 				Expect(fakeSSHTunnel.Stopped).To(BeTrue())
 			})
 
+			Context("when the manager is for an instance other than the bootstrap instance", func() {
+				BeforeEach(func() {
+					manager = NewInstanceManager(
+						fakeCloud,
+						fakeVMManager,
+						fakeDiskManager,
+						mockBlobstore,
+						instanceIDRepo,
+						"fake-job-name",
+						1,
+						"fake-instance-host",
+						fakeSSHTunnelFactory,
+						instanceFactory,
+						logger,
+					)
+				})
+
+				It("opens the ssh tunnel to the host of the instance", func() {
+					_, _, err := manager.Create(
+						"fake-job-name",
+						1,
+						deploymentManifest,
+						fakeCloudStemcell,
+						registry,
+						fakeStage,
+					)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(fakeSSHTunnelFactory.NewSSHTunnelOptions.Host).To(Equal("fake-instance-host"))
+				})
+			})
+
 			Context("when starting the ssh tunnel fails", func() {
 				BeforeEach(func() {
 					fakeSSHTunnel.SetStartBehavior(errors.New("fake-ssh-tunnel-start-error"), nil)
@@ -408,4 +473,56 @@ var _ = Describe("Manager", func() {
 			})
 		})
 	})
+
+	Describe("FindCurrent", func() {
+		BeforeEach(func() {
+			fakeVMManager.SetFindCurrentBehavior(fakebivm.NewFakeVM("fake-vm-cid"), true, nil)
+			mockStateBuilderFactory.EXPECT().NewBuilder(mockBlobstore, gomock.Any()).Return(mockStateBuilder).AnyTimes()
+		})
+
+		It("returns the bootstrap instance with the job name recorded in the instance repo", func() {
+			err := instanceRepo.UpdateBootstrapJobName("fake-job-name")
+			Expect(err).ToNot(HaveOccurred())
+
+			instances, err := manager.FindCurrent()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].JobName()).To(Equal("fake-job-name"))
+			Expect(instances[0].ID()).To(Equal(0))
+		})
+
+		It("returns the bootstrap instance of a deployment state without a recorded job name", func() {
+			instances, err := manager.FindCurrent()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(HaveLen(1))
+			Expect(instances[0].JobName()).To(Equal("unknown"))
+		})
+
+		It("returns no instances when there is no current vm", func() {
+			fakeVMManager.SetFindCurrentBehavior(nil, false, nil)
+
+			instances, err := manager.FindCurrent()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(instances).To(BeEmpty())
+		})
+	})
+
+	Describe("FindCurrentDisks", func() {
+		It("returns the current disks of the instance", func() {
+			disk := fakebidisk.NewFakeDisk("fake-disk-cid")
+			fakeDiskManager.SetFindCurrentBehavior([]bidisk.Disk{disk}, nil)
+
+			disks, err := manager.FindCurrentDisks()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(disks).To(Equal([]bidisk.Disk{disk}))
+		})
+
+		It("returns an error when finding the current disks fails", func() {
+			fakeDiskManager.SetFindCurrentBehavior([]bidisk.Disk{}, errors.New("fake-find-error"))
+
+			_, err := manager.FindCurrentDisks()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-find-error"))
+		})
+	})
 })
@@ -1,9 +1,11 @@
 // Automatically generated by MockGen. DO NOT EDIT!
-// Source: github.com/cloudfoundry/bosh-init/deployment/instance (interfaces: Instance,Manager)
+// Source: github.com/cloudfoundry/bosh-init/deployment/instance (interfaces: Instance,Manager,ManagerProvider,ManagerProviderFactory)
 
 package mocks
 
 import (
+	agentclient "github.com/cloudfoundry/bosh-init/agentclient"
+	cloud "github.com/cloudfoundry/bosh-init/cloud"
 	disk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	instance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	manifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
@@ -76,6 +78,37 @@ func (_mr *_MockInstanceRecorder) JobName() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "JobName")
 }
 
+func (_m *MockInstance) RunErrand(_param0 ui.Stage) (agentclient.ErrandResult, error) {
+	ret := _m.ctrl.Call(_m, "RunErrand", _param0)
+	ret0, _ := ret[0].(agentclient.ErrandResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockInstanceRecorder) RunErrand(arg0 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "RunErrand", arg0)
+}
+
+func (_m *MockInstance) Start(_param0 []disk.Disk, _param1 manifest.WatchTime, _param2 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "Start", _param0, _param1, _param2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockInstanceRecorder) Start(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Start", arg0, arg1, arg2)
+}
+
+func (_m *MockInstance) Stop(_param0 bool, _param1 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "Stop", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockInstanceRecorder) Stop(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stop", arg0, arg1)
+}
+
 func (_m *MockInstance) UpdateDisks(_param0 manifest.Manifest, _param1 ui.Stage) ([]disk.Disk, error) {
 	ret := _m.ctrl.Call(_m, "UpdateDisks", _param0, _param1)
 	ret0, _ := ret[0].([]disk.Disk)
@@ -160,3 +193,88 @@ func (_m *MockManager) FindCurrent() ([]instance.Instance, error) {
 func (_mr *_MockManagerRecorder) FindCurrent() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindCurrent")
 }
+
+func (_m *MockManager) FindCurrentDisks() ([]disk.Disk, error) {
+	ret := _m.ctrl.Call(_m, "FindCurrentDisks")
+	ret0, _ := ret[0].([]disk.Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockManagerRecorder) FindCurrentDisks() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindCurrentDisks")
+}
+
+// Mock of ManagerProvider interface
+type MockManagerProvider struct {
+	ctrl     *gomock.Controller
+	recorder *_MockManagerProviderRecorder
+}
+
+// Recorder for MockManagerProvider (not exported)
+type _MockManagerProviderRecorder struct {
+	mock *MockManagerProvider
+}
+
+func NewMockManagerProvider(ctrl *gomock.Controller) *MockManagerProvider {
+	mock := &MockManagerProvider{ctrl: ctrl}
+	mock.recorder = &_MockManagerProviderRecorder{mock}
+	return mock
+}
+
+func (_m *MockManagerProvider) EXPECT() *_MockManagerProviderRecorder {
+	return _m.recorder
+}
+
+func (_m *MockManagerProvider) FindAll() ([]instance.Manager, error) {
+	ret := _m.ctrl.Call(_m, "FindAll")
+	ret0, _ := ret[0].([]instance.Manager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockManagerProviderRecorder) FindAll() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "FindAll")
+}
+
+func (_m *MockManagerProvider) Get(_param0 manifest.Manifest, _param1 string, _param2 int) (instance.Manager, error) {
+	ret := _m.ctrl.Call(_m, "Get", _param0, _param1, _param2)
+	ret0, _ := ret[0].(instance.Manager)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+func (_mr *_MockManagerProviderRecorder) Get(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Get", arg0, arg1, arg2)
+}
+
+// Mock of ManagerProviderFactory interface
+type MockManagerProviderFactory struct {
+	ctrl     *gomock.Controller
+	recorder *_MockManagerProviderFactoryRecorder
+}
+
+// Recorder for MockManagerProviderFactory (not exported)
+type _MockManagerProviderFactoryRecorder struct {
+	mock *MockManagerProviderFactory
+}
+
+func NewMockManagerProviderFactory(ctrl *gomock.Controller) *MockManagerProviderFactory {
+	mock := &MockManagerProviderFactory{ctrl: ctrl}
+	mock.recorder = &_MockManagerProviderFactoryRecorder{mock}
+	return mock
+}
+
+func (_m *MockManagerProviderFactory) EXPECT() *_MockManagerProviderFactoryRecorder {
+	return _m.recorder
+}
+
+func (_m *MockManagerProviderFactory) NewManagerProvider(_param0 cloud.Cloud, _param1 string, _param2 string) instance.ManagerProvider {
+	ret := _m.ctrl.Call(_m, "NewManagerProvider", _param0, _param1, _param2)
+	ret0, _ := ret[0].(instance.ManagerProvider)
+	return ret0
+}
+
+func (_mr *_MockManagerProviderFactoryRecorder) NewManagerProvider(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "NewManagerProvider", arg0, arg1, arg2)
+}
@@ -7,6 +7,7 @@ import (
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	birel "github.com/cloudfoundry/bosh-init/release"
 	bireljob "github.com/cloudfoundry/bosh-init/release/job"
 	bistatejob "github.com/cloudfoundry/bosh-init/state/job"
 	bitemplate "github.com/cloudfoundry/bosh-init/templatescompiler"
@@ -14,11 +15,15 @@ import (
 )
 
 type Builder interface {
-	Build(jobName string, instanceID int, deploymentManifest bideplmanifest.Manifest, stage biui.Stage) (State, error)
+	// Build builds the state of the instance with the given index of the job.
+	// id is the ID recorded for the instance in the deployment state, exposed to job templates as spec.id.
+	// vmNetworks are the network settings the CPI reported for the VM of the instance, which are merged into the networks of the manifest.
+	Build(jobName string, index int, id string, deploymentManifest bideplmanifest.Manifest, vmNetworks map[string]biproperty.Map, stage biui.Stage) (State, error)
 }
 
 type builder struct {
 	releaseJobResolver        bideplrel.JobResolver
+	releaseManager            birel.Manager
 	jobDependencyCompiler     bistatejob.DependencyCompiler
 	jobListRenderer           bitemplate.JobListRenderer
 	renderedJobListCompressor bitemplate.RenderedJobListCompressor
@@ -29,6 +34,7 @@ type builder struct {
 
 func NewBuilder(
 	releaseJobResolver bideplrel.JobResolver,
+	releaseManager birel.Manager,
 	jobDependencyCompiler bistatejob.DependencyCompiler,
 	jobListRenderer bitemplate.JobListRenderer,
 	renderedJobListCompressor bitemplate.RenderedJobListCompressor,
@@ -37,6 +43,7 @@ func NewBuilder(
 ) Builder {
 	return &builder{
 		releaseJobResolver:        releaseJobResolver,
+		releaseManager:            releaseManager,
 		jobDependencyCompiler:     jobDependencyCompiler,
 		jobListRenderer:           jobListRenderer,
 		renderedJobListCompressor: renderedJobListCompressor,
@@ -51,7 +58,7 @@ type renderedJobs struct {
 	Archive     bitemplate.RenderedJobListArchive
 }
 
-func (b *builder) Build(jobName string, instanceID int, deploymentManifest bideplmanifest.Manifest, stage biui.Stage) (State, error) {
+func (b *builder) Build(jobName string, index int, id string, deploymentManifest bideplmanifest.Manifest, vmNetworks map[string]biproperty.Map, stage biui.Stage) (State, error) {
 	deploymentJob, found := deploymentManifest.FindJobByName(jobName)
 	if !found {
 		return nil, bosherr.Errorf("Job '%s' not found in deployment manifest", jobName)
@@ -59,22 +66,24 @@ func (b *builder) Build(jobName string, instanceID int, deploymentManifest bidep
 
 	releaseJobs, err := b.resolveJobs(deploymentJob.Templates)
 	if err != nil {
-		return nil, bosherr.WrapErrorf(err, "Resolving jobs for instance '%s/%d'", jobName, instanceID)
+		return nil, bosherr.WrapErrorf(err, "Resolving jobs for instance '%s/%d'", jobName, index)
 	}
 
-	renderedJobTemplates, err := b.renderJobTemplates(releaseJobs, deploymentJob.Properties, deploymentManifest.Properties, deploymentManifest.Name, stage)
+	instanceSpec, err := bitemplate.NewInstanceSpec(deploymentManifest, deploymentJob.Name, index, id, releaseJobs, b.releaseManager)
 	if err != nil {
-		return nil, bosherr.WrapErrorf(err, "Rendering job templates for instance '%s/%d'", jobName, instanceID)
+		return nil, bosherr.WrapErrorf(err, "Describing instance '%s/%d'", jobName, index)
 	}
+	instanceSpec = instanceSpec.WithVMNetworks(deploymentJob, vmNetworks)
+	networkInterfaces := instanceSpec.Networks
 
-	compiledPackageRefs, err := b.jobDependencyCompiler.Compile(releaseJobs, stage)
+	renderedJobTemplates, err := b.renderJobTemplates(releaseJobs, deploymentJob.Properties, deploymentManifest.Properties, deploymentManifest.Name, instanceSpec, stage)
 	if err != nil {
-		return nil, bosherr.WrapErrorf(err, "Compiling job package dependencies for instance '%s/%d'", jobName, instanceID)
+		return nil, bosherr.WrapErrorf(err, "Rendering job templates for instance '%s/%d'", jobName, index)
 	}
 
-	networkInterfaces, err := deploymentManifest.NetworkInterfaces(deploymentJob.Name)
+	compiledPackageRefs, err := b.jobDependencyCompiler.Compile(releaseJobs, stage)
 	if err != nil {
-		return nil, bosherr.WrapErrorf(err, "Finding networks for job '%s", jobName)
+		return nil, bosherr.WrapErrorf(err, "Compiling job package dependencies for instance '%s/%d'", jobName, index)
 	}
 
 	// convert map to array
@@ -115,7 +124,7 @@ func (b *builder) Build(jobName string, instanceID int, deploymentManifest bidep
 	return &state{
 		deploymentName:         deploymentManifest.Name,
 		name:                   jobName,
-		id:                     instanceID,
+		id:                     index,
 		networks:               networkRefs,
 		compiledPackages:       compiledDeploymentPackageRefs,
 		renderedJobs:           renderedJobRefs,
@@ -143,6 +152,7 @@ func (b *builder) renderJobTemplates(
 	jobProperties biproperty.Map,
 	globalProperties biproperty.Map,
 	deploymentName string,
+	instanceSpec bitemplate.InstanceSpec,
 	stage biui.Stage,
 ) (renderedJobs, error) {
 	var (
@@ -150,7 +160,7 @@ func (b *builder) renderJobTemplates(
 		blobID                 string
 	)
 	err := stage.Perform("Rendering job templates", func() error {
-		renderedJobList, err := b.jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName)
+		renderedJobList, err := b.jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName, instanceSpec)
 		if err != nil {
 			return err
 		}
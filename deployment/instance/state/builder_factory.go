@@ -5,6 +5,7 @@ import (
 	bideplrel "github.com/cloudfoundry/bosh-init/deployment/release"
 	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	birel "github.com/cloudfoundry/bosh-init/release"
 	bistatejob "github.com/cloudfoundry/bosh-init/state/job"
 	bistatepkg "github.com/cloudfoundry/bosh-init/state/pkg"
 	bitemplate "github.com/cloudfoundry/bosh-init/templatescompiler"
@@ -17,6 +18,7 @@ type BuilderFactory interface {
 type builderFactory struct {
 	packageRepo               bistatepkg.CompiledPackageRepo
 	releaseJobResolver        bideplrel.JobResolver
+	releaseManager            birel.Manager
 	jobRenderer               bitemplate.JobListRenderer
 	renderedJobListCompressor bitemplate.RenderedJobListCompressor
 	logger                    boshlog.Logger
@@ -25,6 +27,7 @@ type builderFactory struct {
 func NewBuilderFactory(
 	packageRepo bistatepkg.CompiledPackageRepo,
 	releaseJobResolver bideplrel.JobResolver,
+	releaseManager birel.Manager,
 	jobRenderer bitemplate.JobListRenderer,
 	renderedJobListCompressor bitemplate.RenderedJobListCompressor,
 	logger boshlog.Logger,
@@ -32,6 +35,7 @@ func NewBuilderFactory(
 	return &builderFactory{
 		packageRepo:               packageRepo,
 		releaseJobResolver:        releaseJobResolver,
+		releaseManager:            releaseManager,
 		jobRenderer:               jobRenderer,
 		renderedJobListCompressor: renderedJobListCompressor,
 		logger: logger,
@@ -44,6 +48,7 @@ func (f *builderFactory) NewBuilder(blobstore biblobstore.Blobstore, agentClient
 
 	return NewBuilder(
 		f.releaseJobResolver,
+		f.releaseManager,
 		jobDependencyCompiler,
 		f.jobRenderer,
 		f.renderedJobListCompressor,
@@ -15,9 +15,13 @@ import (
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	birel "github.com/cloudfoundry/bosh-init/release"
 	bireljob "github.com/cloudfoundry/bosh-init/release/job"
 	birelpkg "github.com/cloudfoundry/bosh-init/release/pkg"
 	bistatejob "github.com/cloudfoundry/bosh-init/state/job"
+	bitemplate "github.com/cloudfoundry/bosh-init/templatescompiler"
+
+	fakebirel "github.com/cloudfoundry/bosh-init/release/fakes"
 
 	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
 )
@@ -43,6 +47,7 @@ func describeBuilder() {
 		mockJobListRenderer    *mock_template.MockJobListRenderer
 		mockCompressor         *mock_template.MockRenderedJobListCompressor
 		mockBlobstore          *mock_blobstore.MockBlobstore
+		releaseManager         birel.Manager
 
 		stateBuilder Builder
 	)
@@ -55,6 +60,10 @@ func describeBuilder() {
 		mockJobListRenderer = mock_template.NewMockJobListRenderer(mockCtrl)
 		mockCompressor = mock_template.NewMockRenderedJobListCompressor(mockCtrl)
 		mockBlobstore = mock_blobstore.NewMockBlobstore(mockCtrl)
+
+		fakeRelease := fakebirel.New("fake-release-name", "fake-release-version")
+		releaseManager = birel.NewManager(logger)
+		releaseManager.Add(fakeRelease)
 	})
 
 	Describe("Build", func() {
@@ -63,7 +72,8 @@ func describeBuilder() {
 			mockRenderedJobListArchive *mock_template.MockRenderedJobListArchive
 
 			jobName            string
-			instanceID         int
+			instanceIndex      int
+			instanceID         string
 			deploymentManifest bideplmanifest.Manifest
 			fakeStage          *fakebiui.FakeStage
 
@@ -79,7 +89,8 @@ func describeBuilder() {
 			mockRenderedJobListArchive = mock_template.NewMockRenderedJobListArchive(mockCtrl)
 
 			jobName = "fake-deployment-job-name"
-			instanceID = 0
+			instanceIndex = 0
+			instanceID = "fake-instance-id"
 
 			deploymentManifest = bideplmanifest.Manifest{
 				Name: "fake-deployment-name",
@@ -120,6 +131,7 @@ func describeBuilder() {
 
 			stateBuilder = NewBuilder(
 				mockReleaseJobResolver,
+				releaseManager,
 				mockDependencyCompiler,
 				mockJobListRenderer,
 				mockCompressor,
@@ -187,7 +199,29 @@ func describeBuilder() {
 			globalProperties := biproperty.Map{
 				"fake-job-property": "fake-global-property-value",
 			}
-			mockJobListRenderer.EXPECT().Render(releaseJobs, jobProperties, globalProperties, "fake-deployment-name").Return(mockRenderedJobList, nil)
+			networks := map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{
+					"type":    "fake-network-type",
+					"default": []bideplmanifest.NetworkDefault{"dns", "gateway"},
+					"cloud_properties": biproperty.Map{
+						"fake-network-cloud-property": "fake-network-cloud-property-value",
+					},
+				},
+			}
+			instanceSpec := bitemplate.InstanceSpec{
+				ID:        "fake-instance-id",
+				Name:      "fake-deployment-job-name",
+				Index:     0,
+				Bootstrap: true,
+				Networks:  networks,
+				Links: map[string]map[string]bideplmanifest.Link{
+					"fake-release-job-name": {},
+				},
+				Releases: map[string]bitemplate.ReleaseSpec{
+					"fake-release-job-name": {Name: "fake-release-name", Version: "fake-release-version"},
+				},
+			}
+			mockJobListRenderer.EXPECT().Render(releaseJobs, jobProperties, globalProperties, "fake-deployment-name", instanceSpec).Return(mockRenderedJobList, nil)
 
 			mockRenderedJobList.EXPECT().DeleteSilently()
 
@@ -205,12 +239,12 @@ func describeBuilder() {
 		It("compiles the dependencies of the jobs", func() {
 			expectCompile.Times(1)
 
-			_, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			_, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 		})
 
 		It("builds a new instance state with zero-to-many networks", func() {
-			state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(state.NetworkInterfaces()).To(ContainElement(NetworkRef{
@@ -227,7 +261,7 @@ func describeBuilder() {
 		})
 
 		It("builds a new instance state with zero-to-many rendered jobs from one or more releases", func() {
-			state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(state.RenderedJobs()).To(ContainElement(JobRef{
@@ -244,7 +278,7 @@ func describeBuilder() {
 		})
 
 		It("prints ui stages for compiling packages and rendering job templates", func() {
-			_, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			_, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeStage.PerformCalls).To(Equal([]*fakebiui.PerformCall{
@@ -254,7 +288,7 @@ func describeBuilder() {
 		})
 
 		It("builds a new instance state with the compiled packages required by the release jobs", func() {
-			state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(state.CompiledPackages()).To(ContainElement(PackageRef{
@@ -276,7 +310,7 @@ func describeBuilder() {
 		})
 
 		It("builds a new instance state that includes transitively dependent compiled packages", func() {
-			state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(state.CompiledPackages()).To(ContainElement(PackageRef{
@@ -312,7 +346,7 @@ func describeBuilder() {
 			})
 
 			It("does not recompile dependant packages", func() {
-				state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+				state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 				Expect(err).ToNot(HaveOccurred())
 
 				Expect(state.CompiledPackages()).To(ContainElement(PackageRef{
@@ -344,7 +378,7 @@ func describeBuilder() {
 		})
 
 		It("builds an instance state that can be converted to an ApplySpec", func() {
-			state, err := stateBuilder.Build(jobName, instanceID, deploymentManifest, fakeStage)
+			state, err := stateBuilder.Build(jobName, instanceIndex, instanceID, deploymentManifest, nil, fakeStage)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(state.ToApplySpec()).To(Equal(bias.ApplySpec{
@@ -9,6 +9,7 @@ import (
 	manifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	applyspec "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
+	property "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	gomock "github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	ui "github.com/cloudfoundry/bosh-init/ui"
 )
@@ -65,15 +66,15 @@ func (_m *MockBuilder) EXPECT() *_MockBuilderRecorder {
 	return _m.recorder
 }
 
-func (_m *MockBuilder) Build(_param0 string, _param1 int, _param2 manifest.Manifest, _param3 ui.Stage) (state.State, error) {
-	ret := _m.ctrl.Call(_m, "Build", _param0, _param1, _param2, _param3)
+func (_m *MockBuilder) Build(_param0 string, _param1 int, _param2 string, _param3 manifest.Manifest, _param4 map[string]property.Map, _param5 ui.Stage) (state.State, error) {
+	ret := _m.ctrl.Call(_m, "Build", _param0, _param1, _param2, _param3, _param4, _param5)
 	ret0, _ := ret[0].(state.State)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockBuilderRecorder) Build(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Build", arg0, arg1, arg2, arg3)
+func (_mr *_MockBuilderRecorder) Build(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Build", arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // Mock of State interface
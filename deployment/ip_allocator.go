@@ -0,0 +1,215 @@
+package deployment
+
+import (
+	"fmt"
+	"net"
+
+	binet "github.com/cloudfoundry/bosh-init/common/net"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+// IPAllocator gives an IP to every instance on a manual network with static ranges that has no static_ips in the manifest.
+// IPs allocated by a previous deploy are kept as long as they are still within a static range of the network.
+type IPAllocator interface {
+	// Allocate returns a copy of the manifest with the static_ips of those job networks filled in,
+	// and records the allocated IPs in the deployment state.
+	Allocate(bideplmanifest.Manifest) (bideplmanifest.Manifest, error)
+}
+
+type ipAllocator struct {
+	ipAllocationRepo biconfig.IPAllocationRepo
+	logger           boshlog.Logger
+	logTag           string
+}
+
+func NewIPAllocator(ipAllocationRepo biconfig.IPAllocationRepo, logger boshlog.Logger) IPAllocator {
+	return &ipAllocator{
+		ipAllocationRepo: ipAllocationRepo,
+		logger:           logger,
+		logTag:           "ipAllocator",
+	}
+}
+
+type ipAllocationKey struct {
+	job     string
+	index   int
+	network string
+}
+
+type jobNetworkKey struct {
+	job     string
+	network string
+}
+
+func (a *ipAllocator) Allocate(deploymentManifest bideplmanifest.Manifest) (bideplmanifest.Manifest, error) {
+	previousRecords, err := a.ipAllocationRepo.All()
+	if err != nil {
+		return deploymentManifest, bosherr.WrapError(err, "Finding allocated IPs")
+	}
+
+	previousIPs := map[ipAllocationKey]string{}
+	for _, record := range previousRecords {
+		previousIPs[ipAllocationKey{job: record.Job, index: record.Index, network: record.Network}] = record.IP
+	}
+
+	networks := map[string]bideplmanifest.Network{}
+	for _, network := range deploymentManifest.Networks {
+		networks[network.Name] = network
+	}
+
+	usedIPs := map[string]bool{}
+	for _, job := range deploymentManifest.Jobs {
+		for _, jobNetwork := range job.Networks {
+			for _, ip := range jobNetwork.StaticIPs {
+				usedIPs[a.usedIPKey(jobNetwork.Name, net.ParseIP(ip))] = true
+			}
+		}
+	}
+
+	// the job networks without static_ips get an empty IP for each instance, to be allocated below
+	jobs := append([]bideplmanifest.Job(nil), deploymentManifest.Jobs...)
+	allocatedNetworks := map[jobNetworkKey]bool{}
+	for jobIdx := range jobs {
+		job := &jobs[jobIdx]
+		job.Networks = append([]bideplmanifest.JobNetwork(nil), job.Networks...)
+		for networkIdx, jobNetwork := range job.Networks {
+			network := networks[jobNetwork.Name]
+			if len(jobNetwork.StaticIPs) > 0 || network.Type != bideplmanifest.Manual || !network.HasStatic() {
+				continue
+			}
+			job.Networks[networkIdx].StaticIPs = make([]string, job.Instances)
+			allocatedNetworks[jobNetworkKey{job: job.Name, network: jobNetwork.Name}] = true
+		}
+	}
+
+	// keep the IPs allocated by a previous deploy before allocating new ones, so that they are not given to another instance
+	a.forEachAllocation(jobs, networks, func(key ipAllocationKey, network bideplmanifest.Network) string {
+		ip := net.ParseIP(previousIPs[key])
+		if ip == nil || usedIPs[a.usedIPKey(network.Name, ip)] {
+			return ""
+		}
+
+		subnet, found := network.SubnetFor(ip.String())
+		if !found || !subnet.IsStatic(ip) || subnet.IsReserved(ip) {
+			return ""
+		}
+
+		usedIPs[a.usedIPKey(network.Name, ip)] = true
+		return ip.String()
+	})
+
+	var allocationErr error
+	a.forEachAllocation(jobs, networks, func(key ipAllocationKey, network bideplmanifest.Network) string {
+		ip, found := a.nextFreeIP(network, usedIPs)
+		if !found {
+			if allocationErr == nil {
+				allocationErr = bosherr.Errorf("No free IP left in the static ranges of network '%s' for instance '%s/%d'", network.Name, key.job, key.index)
+			}
+			return ""
+		}
+
+		a.logger.Debug(a.logTag, "Allocated IP '%s' on network '%s' to instance '%s/%d'", ip, network.Name, key.job, key.index)
+		usedIPs[a.usedIPKey(network.Name, ip)] = true
+		return ip.String()
+	})
+	if allocationErr != nil {
+		return deploymentManifest, allocationErr
+	}
+
+	records := []biconfig.IPAllocationRecord{}
+	for _, job := range jobs {
+		for _, jobNetwork := range job.Networks {
+			if !allocatedNetworks[jobNetworkKey{job: job.Name, network: jobNetwork.Name}] {
+				continue
+			}
+			for index, ip := range jobNetwork.StaticIPs {
+				records = append(records, biconfig.IPAllocationRecord{Job: job.Name, Index: index, Network: jobNetwork.Name, IP: ip})
+			}
+		}
+	}
+
+	err = a.ipAllocationRepo.UpdateAll(records)
+	if err != nil {
+		return deploymentManifest, bosherr.WrapError(err, "Recording allocated IPs")
+	}
+
+	deploymentManifest.Jobs = jobs
+	return deploymentManifest, nil
+}
+
+// forEachAllocation calls fn for each instance on a network that still needs an IP, and records the IP it returns
+func (a *ipAllocator) forEachAllocation(jobs []bideplmanifest.Job, networks map[string]bideplmanifest.Network, fn func(ipAllocationKey, bideplmanifest.Network) string) {
+	for _, job := range jobs {
+		for _, jobNetwork := range job.Networks {
+			for index, ip := range jobNetwork.StaticIPs {
+				if ip != "" {
+					continue
+				}
+				key := ipAllocationKey{job: job.Name, index: index, network: jobNetwork.Name}
+				jobNetwork.StaticIPs[index] = fn(key, networks[jobNetwork.Name])
+			}
+		}
+	}
+}
+
+// nextFreeIP returns the first IP of the static ranges of the network that is neither reserved, the gateway nor used.
+// Reserved ranges are skipped as a whole, so that only used IPs are stepped over one at a time
+// and large ranges, such as those of IPv6 subnets, are not walked address by address.
+func (a *ipAllocator) nextFreeIP(network bideplmanifest.Network, usedIPs map[string]bool) (net.IP, bool) {
+	for _, subnet := range network.Subnets {
+		gateway := net.ParseIP(subnet.Gateway)
+
+		for _, staticRange := range subnet.Static {
+			first, last, err := binet.ParseRange(staticRange)
+			if err != nil {
+				continue
+			}
+
+			ip := first
+			for {
+				if reservedLast, found := a.reservedRangeLast(subnet, ip); found {
+					if binet.CompareAddresses(reservedLast, last) >= 0 {
+						break
+					}
+					ip = binet.NextAddress(reservedLast)
+					continue
+				}
+
+				isFree := subnet.Contains(ip) && !ip.Equal(gateway) && !usedIPs[a.usedIPKey(network.Name, ip)]
+				if isFree {
+					return ip, true
+				}
+
+				if binet.CompareAddresses(ip, last) >= 0 {
+					break
+				}
+				ip = binet.NextAddress(ip)
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// reservedRangeLast returns the last IP of the reserved ranges of the subnet that contain ip
+func (a *ipAllocator) reservedRangeLast(subnet bideplmanifest.Subnet, ip net.IP) (net.IP, bool) {
+	var reservedLast net.IP
+	for _, reservedRange := range subnet.Reserved {
+		first, last, err := binet.ParseRange(reservedRange)
+		if err != nil || !binet.RangeContains(first, last, ip) {
+			continue
+		}
+		if reservedLast == nil || binet.CompareAddresses(last, reservedLast) > 0 {
+			reservedLast = last
+		}
+	}
+
+	return reservedLast, reservedLast != nil
+}
+
+func (a *ipAllocator) usedIPKey(networkName string, ip net.IP) string {
+	return fmt.Sprintf("%s/%s", networkName, ip.String())
+}
@@ -0,0 +1,185 @@
+package deployment_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/deployment"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+)
+
+var _ = Describe("IPAllocator", func() {
+	var (
+		ipAllocationRepo   biconfig.IPAllocationRepo
+		ipAllocator        IPAllocator
+		deploymentManifest bideplmanifest.Manifest
+	)
+
+	BeforeEach(func() {
+		logger := boshlog.NewLogger(boshlog.LevelNone)
+		fs := fakesys.NewFakeFileSystem()
+		deploymentStateService := biconfig.NewFileSystemDeploymentStateService(fs, fakeuuid.NewFakeGenerator(), logger, "/deployment.json")
+		ipAllocationRepo = biconfig.NewIPAllocationRepo(deploymentStateService)
+		ipAllocator = NewIPAllocator(ipAllocationRepo, logger)
+
+		deploymentManifest = bideplmanifest.Manifest{
+			Networks: []bideplmanifest.Network{
+				{
+					Name: "fake-manual-network",
+					Type: bideplmanifest.Manual,
+					Subnets: []bideplmanifest.Subnet{
+						{
+							Range:    "10.0.0.0/24",
+							Gateway:  "10.0.0.1",
+							Reserved: []string{"10.0.0.11"},
+							Static:   []string{"10.0.0.10 - 10.0.0.12"},
+						},
+						{
+							Range:   "10.0.1.0/24",
+							Gateway: "10.0.1.1",
+							Static:  []string{"10.0.1.10 - 10.0.1.20"},
+						},
+					},
+				},
+				{
+					Name: "fake-dynamic-network",
+					Type: bideplmanifest.Dynamic,
+				},
+			},
+			Jobs: []bideplmanifest.Job{
+				{
+					Name:      "fake-bootstrap-job",
+					Instances: 1,
+					Networks: []bideplmanifest.JobNetwork{
+						{Name: "fake-manual-network", StaticIPs: []string{"10.0.0.10"}},
+					},
+				},
+				{
+					Name:      "fake-job",
+					Instances: 2,
+					Networks: []bideplmanifest.JobNetwork{
+						{Name: "fake-manual-network"},
+						{Name: "fake-dynamic-network"},
+					},
+				},
+			},
+		}
+	})
+
+	It("allocates free IPs from the static ranges, skipping used and reserved IPs", func() {
+		allocatedManifest, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(allocatedManifest.Jobs[1].Networks[0].StaticIPs).To(Equal([]string{"10.0.0.12", "10.0.1.10"}))
+		Expect(allocatedManifest.Jobs[1].Networks[1].StaticIPs).To(BeEmpty())
+		Expect(deploymentManifest.Jobs[1].Networks[0].StaticIPs).To(BeEmpty())
+
+		records, err := ipAllocationRepo.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(Equal([]biconfig.IPAllocationRecord{
+			{Job: "fake-job", Index: 0, Network: "fake-manual-network", IP: "10.0.0.12"},
+			{Job: "fake-job", Index: 1, Network: "fake-manual-network", IP: "10.0.1.10"},
+		}))
+	})
+
+	It("keeps the IPs allocated by a previous deploy", func() {
+		err := ipAllocationRepo.UpdateAll([]biconfig.IPAllocationRecord{
+			{Job: "fake-job", Index: 0, Network: "fake-manual-network", IP: "10.0.1.15"},
+			{Job: "fake-job", Index: 1, Network: "fake-manual-network", IP: "10.0.0.12"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		allocatedManifest, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocatedManifest.Jobs[1].Networks[0].StaticIPs).To(Equal([]string{"10.0.1.15", "10.0.0.12"}))
+	})
+
+	It("replaces previously allocated IPs that are no longer in a static range or are now used", func() {
+		err := ipAllocationRepo.UpdateAll([]biconfig.IPAllocationRecord{
+			{Job: "fake-job", Index: 0, Network: "fake-manual-network", IP: "10.0.1.50"},
+			{Job: "fake-job", Index: 1, Network: "fake-manual-network", IP: "10.0.0.10"},
+			{Job: "fake-removed-job", Index: 0, Network: "fake-manual-network", IP: "10.0.0.12"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		allocatedManifest, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocatedManifest.Jobs[1].Networks[0].StaticIPs).To(Equal([]string{"10.0.0.12", "10.0.1.10"}))
+
+		records, err := ipAllocationRepo.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+	})
+
+	It("returns an error when the static ranges are exhausted", func() {
+		deploymentManifest.Jobs[1].Instances = 13
+
+		_, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("No free IP left in the static ranges of network 'fake-manual-network' for instance 'fake-job/12'"))
+	})
+
+	It("records the IPs allocated on each network of a job", func() {
+		deploymentManifest.Networks = append(deploymentManifest.Networks, bideplmanifest.Network{
+			Name: "fake-other-manual-network",
+			Type: bideplmanifest.Manual,
+			Subnets: []bideplmanifest.Subnet{
+				{Range: "10.0.2.0/24", Gateway: "10.0.2.1", Static: []string{"10.0.2.10 - 10.0.2.20"}},
+			},
+		})
+		deploymentManifest.Jobs[1].Networks = append(deploymentManifest.Jobs[1].Networks, bideplmanifest.JobNetwork{Name: "fake-other-manual-network"})
+
+		_, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).ToNot(HaveOccurred())
+
+		records, err := ipAllocationRepo.All()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(records).To(Equal([]biconfig.IPAllocationRecord{
+			{Job: "fake-job", Index: 0, Network: "fake-manual-network", IP: "10.0.0.12"},
+			{Job: "fake-job", Index: 1, Network: "fake-manual-network", IP: "10.0.1.10"},
+			{Job: "fake-job", Index: 0, Network: "fake-other-manual-network", IP: "10.0.2.10"},
+			{Job: "fake-job", Index: 1, Network: "fake-other-manual-network", IP: "10.0.2.11"},
+		}))
+	})
+
+	Context("when the static range is as large as an IPv6 subnet", func() {
+		BeforeEach(func() {
+			deploymentManifest.Networks[0].Subnets = []bideplmanifest.Subnet{
+				{
+					Range:    "2001:db8::/64",
+					Gateway:  "2001:db8::1",
+					Reserved: []string{"2001:db8:: - 2001:db8::ffff:ffff:ffff"},
+					Static:   []string{"2001:db8:: - 2001:db8::ffff:ffff:ffff:ffff"},
+				},
+			}
+			deploymentManifest.Jobs[0].Networks[0].StaticIPs = []string{"2001:db8::1:0:0:0"}
+		})
+
+		It("skips the reserved ranges as a whole", func() {
+			allocatedManifest, err := ipAllocator.Allocate(deploymentManifest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allocatedManifest.Jobs[1].Networks[0].StaticIPs).To(Equal([]string{"2001:db8::1:0:0:1", "2001:db8::1:0:0:2"}))
+		})
+
+		It("returns an error when the whole static range is reserved", func() {
+			deploymentManifest.Networks[0].Subnets[0].Reserved = []string{"2001:db8:: - 2001:db8::ffff:ffff:ffff:ffff"}
+
+			_, err := ipAllocator.Allocate(deploymentManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("No free IP left in the static ranges of network 'fake-manual-network' for instance 'fake-job/0'"))
+		})
+	})
+
+	It("does not allocate IPs on manual networks without static ranges", func() {
+		deploymentManifest.Networks[0].Subnets[0].Static = nil
+		deploymentManifest.Networks[0].Subnets[1].Static = nil
+
+		allocatedManifest, err := ipAllocator.Allocate(deploymentManifest)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allocatedManifest).To(Equal(deploymentManifest))
+	})
+})
@@ -1,12 +1,13 @@
 package deployment
 
 import (
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	biblobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	biinstance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	bivm "github.com/cloudfoundry/bosh-init/deployment/vm"
-	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
 )
 
@@ -20,6 +21,8 @@ type managerFactory struct {
 	diskManagerFactory     bidisk.ManagerFactory
 	stemcellManagerFactory bistemcell.ManagerFactory
 	deploymentFactory      Factory
+	instanceRepo           biconfig.InstanceRepo
+	instanceIDRepo         biconfig.InstanceIDRepo
 }
 
 func NewManagerFactory(
@@ -28,6 +31,8 @@ func NewManagerFactory(
 	diskManagerFactory bidisk.ManagerFactory,
 	stemcellManagerFactory bistemcell.ManagerFactory,
 	deploymentFactory Factory,
+	instanceRepo biconfig.InstanceRepo,
+	instanceIDRepo biconfig.InstanceIDRepo,
 ) ManagerFactory {
 	return &managerFactory{
 		vmManagerFactory:       vmManagerFactory,
@@ -35,13 +40,15 @@ func NewManagerFactory(
 		diskManagerFactory:     diskManagerFactory,
 		stemcellManagerFactory: stemcellManagerFactory,
 		deploymentFactory:      deploymentFactory,
+		instanceRepo:           instanceRepo,
+		instanceIDRepo:         instanceIDRepo,
 	}
 }
 
 func (f *managerFactory) NewManager(cloud bicloud.Cloud, agentClient biagentclient.AgentClient, blobstore biblobstore.Blobstore) Manager {
 	vmManager := f.vmManagerFactory.NewManager(cloud, agentClient)
-	instanceManager := f.instanceManagerFactory.NewManager(cloud, vmManager, blobstore)
 	diskManager := f.diskManagerFactory.NewManager(cloud)
+	instanceManager := f.instanceManagerFactory.NewManager(cloud, vmManager, diskManager, blobstore, f.instanceRepo, f.instanceIDRepo)
 	stemcellManager := f.stemcellManagerFactory.NewManager(cloud)
 
 	return NewManager(instanceManager, diskManager, stemcellManager, f.deploymentFactory)
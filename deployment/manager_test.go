@@ -3,13 +3,13 @@ package deployment_test
 import (
 	. "github.com/cloudfoundry/bosh-init/deployment"
 
+	mock_agentclient "github.com/cloudfoundry/bosh-init/agentclient/mocks"
 	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
 	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
 	mock_disk "github.com/cloudfoundry/bosh-init/deployment/disk/mocks"
 	mock_instance "github.com/cloudfoundry/bosh-init/deployment/instance/mocks"
 	mock_instance_state "github.com/cloudfoundry/bosh-init/deployment/instance/state/mocks"
 	mock_deployment "github.com/cloudfoundry/bosh-init/deployment/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
@@ -173,8 +173,8 @@ var _ = Describe("Manager", func() {
 			deploymentStateService = biconfig.NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, "/deployment.json")
 
 			fakeRepoUUIDGenerator = fakeuuid.NewFakeGenerator()
-			vmRepo = biconfig.NewVMRepo(deploymentStateService)
-			diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeRepoUUIDGenerator)
+			vmRepo = biconfig.NewVMRepo(deploymentStateService, biconfig.BootstrapInstanceKey())
+			diskRepo = biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeRepoUUIDGenerator)
 			stemcellRepo = biconfig.NewStemcellRepo(deploymentStateService, fakeRepoUUIDGenerator)
 
 			mockCloud = mock_cloud.NewMockCloud(mockCtrl)
@@ -198,7 +198,7 @@ var _ = Describe("Manager", func() {
 
 			mockBlobstore = mock_blobstore.NewMockBlobstore(mockCtrl)
 
-			deploymentManagerFactory := NewManagerFactory(vmManagerFactory, instanceManagerFactory, diskManagerFactory, stemcellManagerFactory, mockDeploymentFactory)
+			deploymentManagerFactory := NewManagerFactory(vmManagerFactory, instanceManagerFactory, diskManagerFactory, stemcellManagerFactory, mockDeploymentFactory, biconfig.NewInstanceRepo(deploymentStateService), biconfig.NewInstanceIDRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeuuid.NewFakeGenerator()))
 			deploymentManager = deploymentManagerFactory.NewManager(mockCloud, mockAgentClient, mockBlobstore)
 		})
 
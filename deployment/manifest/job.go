@@ -16,6 +16,17 @@ type Job struct {
 	Properties         biproperty.Map
 }
 
+// StaticIP returns the static IP of the instance with the given index on the first network that has one.
+// It is the address used to reach the agent of an instance other than the bootstrap instance.
+func (j Job) StaticIP(index int) (string, bool) {
+	for _, jobNetwork := range j.Networks {
+		if len(jobNetwork.StaticIPs) > index {
+			return jobNetwork.StaticIPs[index], true
+		}
+	}
+	return "", false
+}
+
 type JobLifecycle string
 
 const (
@@ -26,6 +37,26 @@ const (
 type ReleaseJobRef struct {
 	Name    string
 	Release string
+	// Consumes and Provides configure the links of the release job, by link name
+	Consumes map[string]ConsumedLink
+	Provides map[string]ProvidedLink
+}
+
+// ConsumedLink configures a link consumed by a release job.
+// By default the link is provided by the colocated job that provides a link of its type;
+// From picks the provider by link name when several colocated jobs provide one.
+// A manual link gives the content of the link instead, and a disabled link is not consumed.
+type ConsumedLink struct {
+	From     string
+	Manual   *Link
+	Disabled bool
+}
+
+// ProvidedLink configures a link provided by a release job.
+// As renames the link, and a disabled link is not provided.
+type ProvidedLink struct {
+	As       string
+	Disabled bool
 }
 
 type JobNetwork struct {
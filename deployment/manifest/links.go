@@ -0,0 +1,203 @@
+package manifest
+
+import (
+	"sort"
+	"strings"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+)
+
+// Link is the content of a link consumed by a release job:
+// the instances of the job providing it, and the properties the provider shares
+type Link struct {
+	Instances  []LinkInstance
+	Properties biproperty.Map
+}
+
+type LinkInstance struct {
+	Name      string
+	Index     int
+	Address   string
+	Bootstrap bool
+}
+
+type linkProvider struct {
+	name       string
+	releaseJob bireljob.Job
+	definition bireljob.LinkDefinition
+}
+
+// Links returns the links consumed by each release job of the job, by release job name and link name.
+// releaseJobs are the release jobs of the templates of the job, in the same order.
+// Links are provided by the release jobs colocated on the same instances, unless they are manual links.
+func (d Manifest) Links(jobName string, releaseJobs []bireljob.Job) (map[string]map[string]Link, error) {
+	jobIdx := -1
+	for idx, job := range d.Jobs {
+		if job.Name == jobName {
+			jobIdx = idx
+			break
+		}
+	}
+	if jobIdx < 0 {
+		return map[string]map[string]Link{}, bosherr.Errorf("Could not find job with name: %s", jobName)
+	}
+	job := d.Jobs[jobIdx]
+
+	if len(releaseJobs) != len(job.Templates) {
+		return map[string]map[string]Link{}, bosherr.Errorf("Expected %d release jobs for job '%s', got %d", len(job.Templates), jobName, len(releaseJobs))
+	}
+
+	errs := []error{}
+	providers := []linkProvider{}
+	for templateIdx, releaseJob := range releaseJobs {
+		template := job.Templates[templateIdx]
+
+		providedLinkNames := []string{}
+		for linkName := range template.Provides {
+			providedLinkNames = append(providedLinkNames, linkName)
+		}
+		sort.Strings(providedLinkNames)
+		for _, linkName := range providedLinkNames {
+			if _, found := d.findLinkDefinition(releaseJob.Provides, linkName); !found {
+				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d].provides.%s must refer to a link provided by job '%s'", jobIdx, templateIdx, linkName, releaseJob.Name))
+			}
+		}
+
+		for _, definition := range releaseJob.Provides {
+			providedLink := template.Provides[definition.Name]
+			if providedLink.Disabled {
+				continue
+			}
+
+			name := definition.Name
+			if providedLink.As != "" {
+				name = providedLink.As
+			}
+			providers = append(providers, linkProvider{name: name, releaseJob: releaseJob, definition: definition})
+		}
+	}
+
+	links := map[string]map[string]Link{}
+	for templateIdx, releaseJob := range releaseJobs {
+		template := job.Templates[templateIdx]
+
+		consumedLinkNames := []string{}
+		for linkName := range template.Consumes {
+			consumedLinkNames = append(consumedLinkNames, linkName)
+		}
+		sort.Strings(consumedLinkNames)
+		for _, linkName := range consumedLinkNames {
+			if _, found := d.findLinkDefinition(releaseJob.Consumes, linkName); !found {
+				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d].consumes.%s must refer to a link consumed by job '%s'", jobIdx, templateIdx, linkName, releaseJob.Name))
+			}
+		}
+
+		jobLinks := map[string]Link{}
+		for _, definition := range releaseJob.Consumes {
+			consumedLink := template.Consumes[definition.Name]
+			if consumedLink.Disabled {
+				continue
+			}
+			if consumedLink.Manual != nil {
+				jobLinks[definition.Name] = *consumedLink.Manual
+				continue
+			}
+
+			candidates := []linkProvider{}
+			for _, provider := range providers {
+				if provider.definition.Type == definition.Type && (consumedLink.From == "" || consumedLink.From == provider.name) {
+					candidates = append(candidates, provider)
+				}
+			}
+
+			switch {
+			case len(candidates) == 1:
+				jobLinks[definition.Name] = d.link(job, candidates[0])
+			case len(candidates) > 1:
+				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d] job '%s' consumes link '%s' of type '%s', which is provided by more than one job in jobs[%d].templates; set consumes.%s.from to pick one", jobIdx, templateIdx, releaseJob.Name, definition.Name, definition.Type, jobIdx, definition.Name))
+			case consumedLink.From != "":
+				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d].consumes.%s.from '%s' must refer to a link of type '%s' provided by a job in jobs[%d].templates", jobIdx, templateIdx, definition.Name, consumedLink.From, definition.Type, jobIdx))
+			case !definition.Optional:
+				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d] job '%s' consumes link '%s' of type '%s', which is not provided by any job in jobs[%d].templates", jobIdx, templateIdx, releaseJob.Name, definition.Name, definition.Type, jobIdx))
+			}
+		}
+		links[releaseJob.Name] = jobLinks
+	}
+
+	if len(errs) > 0 {
+		return map[string]map[string]Link{}, bosherr.NewMultiError(errs...)
+	}
+
+	return links, nil
+}
+
+func (d Manifest) findLinkDefinition(definitions []bireljob.LinkDefinition, name string) (bireljob.LinkDefinition, bool) {
+	for _, definition := range definitions {
+		if definition.Name == name {
+			return definition, true
+		}
+	}
+	return bireljob.LinkDefinition{}, false
+}
+
+// link builds the link provided by a release job of the job: all the instances of the job,
+// and the properties of the release job listed by the link, with the same precedence as when rendering its templates
+func (d Manifest) link(job Job, provider linkProvider) Link {
+	link := Link{
+		Instances:  make([]LinkInstance, job.Instances, job.Instances),
+		Properties: biproperty.Map{},
+	}
+
+	for index := range link.Instances {
+		address, _ := job.StaticIP(index)
+		link.Instances[index] = LinkInstance{
+			Name:      job.Name,
+			Index:     index,
+			Address:   address,
+			Bootstrap: index == 0,
+		}
+	}
+
+	for _, propertyName := range provider.definition.Properties {
+		value, found := d.lookupLinkProperty(job.Properties, propertyName)
+		if !found {
+			value, found = d.lookupLinkProperty(d.Properties, propertyName)
+		}
+		if !found {
+			value = provider.releaseJob.Properties[propertyName].Default
+		}
+		d.setLinkProperty(link.Properties, propertyName, value)
+	}
+
+	return link
+}
+
+func (d Manifest) lookupLinkProperty(properties biproperty.Map, name string) (biproperty.Property, bool) {
+	var value biproperty.Property = properties
+	for _, key := range strings.Split(name, ".") {
+		propertyMap, ok := value.(biproperty.Map)
+		if !ok {
+			return nil, false
+		}
+		value, ok = propertyMap[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func (d Manifest) setLinkProperty(properties biproperty.Map, name string, value biproperty.Property) {
+	keys := strings.Split(name, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := properties[key].(biproperty.Map)
+		if !ok {
+			nested = biproperty.Map{}
+			properties[key] = nested
+		}
+		properties = nested
+	}
+	properties[keys[len(keys)-1]] = value
+}
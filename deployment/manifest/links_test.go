@@ -0,0 +1,178 @@
+package manifest_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+)
+
+var _ = Describe("Links", func() {
+	var (
+		deploymentManifest Manifest
+		releaseJobs        []bireljob.Job
+	)
+
+	BeforeEach(func() {
+		deploymentManifest = Manifest{
+			Properties: biproperty.Map{
+				"fake-global-property": "fake-global-value",
+			},
+			Jobs: []Job{
+				{
+					Name:      "fake-other-job-name",
+					Instances: 1,
+				},
+				{
+					Name:      "fake-job-name",
+					Instances: 2,
+					Templates: []ReleaseJobRef{
+						{Name: "fake-consumer-job-name", Release: "fake-release-name"},
+						{Name: "fake-provider-job-name", Release: "fake-release-name"},
+					},
+					Networks: []JobNetwork{
+						{Name: "fake-network-name", StaticIPs: []string{"10.0.0.5", "10.0.0.6"}},
+					},
+					Properties: biproperty.Map{
+						"fake-property": biproperty.Map{
+							"nested": "fake-job-value",
+						},
+					},
+				},
+			},
+		}
+
+		releaseJobs = []bireljob.Job{
+			{
+				Name: "fake-consumer-job-name",
+				Consumes: []bireljob.LinkDefinition{
+					{Name: "fake-link-name", Type: "fake-link-type"},
+					{Name: "fake-optional-link-name", Type: "fake-other-link-type", Optional: true},
+				},
+			},
+			{
+				Name: "fake-provider-job-name",
+				Provides: []bireljob.LinkDefinition{
+					{Name: "fake-provided-link-name", Type: "fake-link-type", Properties: []string{"fake-property.nested", "fake-global-property", "fake-default-property"}},
+				},
+				Properties: map[string]bireljob.PropertyDefinition{
+					"fake-property.nested":  {},
+					"fake-global-property":  {},
+					"fake-default-property": {Default: "fake-default-value"},
+				},
+			},
+		}
+	})
+
+	It("returns the links provided by colocated jobs, with the instances of the job and the properties of the provider", func() {
+		links, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(links).To(Equal(map[string]map[string]Link{
+			"fake-consumer-job-name": {
+				"fake-link-name": {
+					Instances: []LinkInstance{
+						{Name: "fake-job-name", Index: 0, Address: "10.0.0.5", Bootstrap: true},
+						{Name: "fake-job-name", Index: 1, Address: "10.0.0.6"},
+					},
+					Properties: biproperty.Map{
+						"fake-property":         biproperty.Map{"nested": "fake-job-value"},
+						"fake-global-property":  "fake-global-value",
+						"fake-default-property": "fake-default-value",
+					},
+				},
+			},
+			"fake-provider-job-name": {},
+		}))
+	})
+
+	It("returns manual links as given in the manifest", func() {
+		manualLink := &Link{
+			Instances:  []LinkInstance{{Name: "fake-link-name", Address: "10.0.1.5", Bootstrap: true}},
+			Properties: biproperty.Map{"fake-link-property": "fake-value"},
+		}
+		deploymentManifest.Jobs[1].Templates[0].Consumes = map[string]ConsumedLink{
+			"fake-link-name": {Manual: manualLink},
+		}
+
+		links, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(links["fake-consumer-job-name"]["fake-link-name"]).To(Equal(*manualLink))
+	})
+
+	It("does not return disabled links", func() {
+		deploymentManifest.Jobs[1].Templates[0].Consumes = map[string]ConsumedLink{
+			"fake-link-name": {Disabled: true},
+		}
+
+		links, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(links["fake-consumer-job-name"]).To(BeEmpty())
+	})
+
+	Context("when several colocated jobs provide a link of the type", func() {
+		BeforeEach(func() {
+			releaseJobs[1].Provides = append(releaseJobs[1].Provides, bireljob.LinkDefinition{Name: "fake-other-provided-link-name", Type: "fake-link-type"})
+		})
+
+		It("returns an error", func() {
+			_, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("jobs[1].templates[0] job 'fake-consumer-job-name' consumes link 'fake-link-name' of type 'fake-link-type', which is provided by more than one job in jobs[1].templates; set consumes.fake-link-name.from to pick one"))
+		})
+
+		It("picks the provider by name with 'from'", func() {
+			deploymentManifest.Jobs[1].Templates[0].Consumes = map[string]ConsumedLink{
+				"fake-link-name": {From: "fake-renamed-link-name"},
+			}
+			deploymentManifest.Jobs[1].Templates[1].Provides = map[string]ProvidedLink{
+				"fake-other-provided-link-name": {As: "fake-renamed-link-name"},
+			}
+
+			links, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(links["fake-consumer-job-name"]["fake-link-name"].Properties).To(BeEmpty())
+		})
+	})
+
+	It("returns an error when a required link is not provided", func() {
+		deploymentManifest.Jobs[1].Templates[1].Provides = map[string]ProvidedLink{
+			"fake-provided-link-name": {Disabled: true},
+		}
+
+		_, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("jobs[1].templates[0] job 'fake-consumer-job-name' consumes link 'fake-link-name' of type 'fake-link-type', which is not provided by any job in jobs[1].templates"))
+	})
+
+	It("returns an error when 'from' does not refer to a provided link", func() {
+		deploymentManifest.Jobs[1].Templates[0].Consumes = map[string]ConsumedLink{
+			"fake-link-name": {From: "fake-unknown-link-name"},
+		}
+
+		_, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("jobs[1].templates[0].consumes.fake-link-name.from 'fake-unknown-link-name' must refer to a link of type 'fake-link-type' provided by a job in jobs[1].templates"))
+	})
+
+	It("returns an error when configured links are not defined by the release job", func() {
+		deploymentManifest.Jobs[1].Templates[0].Consumes = map[string]ConsumedLink{
+			"fake-unknown-link-name": {Disabled: true},
+		}
+		deploymentManifest.Jobs[1].Templates[1].Provides = map[string]ProvidedLink{
+			"fake-unknown-link-name": {As: "fake-renamed-link-name"},
+		}
+
+		_, err := deploymentManifest.Links("fake-job-name", releaseJobs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("jobs[1].templates[1].provides.fake-unknown-link-name must refer to a link provided by job 'fake-provider-job-name'"))
+		Expect(err.Error()).To(ContainSubstring("jobs[1].templates[0].consumes.fake-unknown-link-name must refer to a link consumed by job 'fake-consumer-job-name'"))
+	})
+
+	It("returns an error when the job does not exist", func() {
+		_, err := deploymentManifest.Links("fake-unknown-job-name", releaseJobs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Could not find job with name: fake-unknown-job-name"))
+	})
+})
@@ -21,8 +21,9 @@ type Update struct {
 
 // NetworkInterfaces returns a map of network names to network interfaces.
 // We can't use map[string]NetworkInterface, because it's impossible to down-cast to what the cloud client requires.
+// Each instance of the job gets the static IP at its index.
 //TODO: refactor to NetworkInterfaces(Job) and use FindJobByName before using (then remove error)
-func (d Manifest) NetworkInterfaces(jobName string) (map[string]biproperty.Map, error) {
+func (d Manifest) NetworkInterfaces(jobName string, index int) (map[string]biproperty.Map, error) {
 	job, found := d.FindJobByName(jobName)
 	if !found {
 		return map[string]biproperty.Map{}, bosherr.Errorf("Could not find job with name: %s", jobName)
@@ -34,7 +35,11 @@ func (d Manifest) NetworkInterfaces(jobName string) (map[string]biproperty.Map,
 	var err error
 	for _, jobNetwork := range job.Networks {
 		network := networkMap[jobNetwork.Name]
-		ifaceMap[jobNetwork.Name], err = network.Interface(jobNetwork.StaticIPs, jobNetwork.Defaults)
+		staticIPs := []string{}
+		if len(jobNetwork.StaticIPs) > index {
+			staticIPs = jobNetwork.StaticIPs[index : index+1]
+		}
+		ifaceMap[jobNetwork.Name], err = network.Interface(staticIPs, jobNetwork.Defaults)
 		if err != nil {
 			return map[string]biproperty.Map{}, bosherr.WrapError(err, "Building network interface")
 		}
@@ -46,8 +51,8 @@ func (d Manifest) NetworkInterfaces(jobName string) (map[string]biproperty.Map,
 	return ifaceMap, nil
 }
 
+// JobName returns the name of the first job, whose first instance is the bootstrap instance
 func (d Manifest) JobName() string {
-	// Currently we deploy only one job
 	return d.Jobs[0].Name
 }
 
@@ -120,13 +125,15 @@ func (d Manifest) FindJobByName(jobName string) (Job, bool) {
 }
 
 func (d Manifest) GetListOfTemplateReleases() (map[string]string, bool) {
-	if (len(d.Jobs) != 1) {
+	if (len(d.Jobs) == 0) {
 		return nil, false
 	} else {
 		result := make(map[string]string)
 
-		for _, job := range d.Jobs[0].Templates {
-			result[job.Release] = job.Release
+		for _, job := range d.Jobs {
+			for _, template := range job.Templates {
+				result[template.Release] = template.Release
+			}
 		}
 
 		return result, true
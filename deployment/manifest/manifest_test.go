@@ -81,7 +81,7 @@ var _ = Describe("Manifest", func() {
 			})
 
 			It("is a map of the network names to network interfaces", func() {
-				Expect(deploymentManifest.NetworkInterfaces("fake-job-name")).To(Equal(map[string]biproperty.Map{
+				Expect(deploymentManifest.NetworkInterfaces("fake-job-name", 0)).To(Equal(map[string]biproperty.Map{
 					"fake-network-name": biproperty.Map{
 						"type":             "dynamic",
 						"ip":               "5.6.7.8",
@@ -102,6 +102,19 @@ var _ = Describe("Manifest", func() {
 				}))
 			})
 
+			It("uses the static IPs at the index of the instance", func() {
+				deploymentManifest.Jobs[2].Networks[0].StaticIPs = []string{"1.2.3.4", "1.2.3.5"}
+
+				Expect(deploymentManifest.NetworkInterfaces("job-with-single-network", 1)).To(Equal(map[string]biproperty.Map{
+					"vip": biproperty.Map{
+						"type":             "vip",
+						"ip":               "1.2.3.5",
+						"cloud_properties": biproperty.Map{},
+						"default":          []NetworkDefault{"dns", "gateway"},
+					},
+				}))
+			})
+
 			Context("given a job with a single network", func() {
 				var singleNetworkJob Job
 				BeforeEach(func() {
@@ -109,7 +122,7 @@ var _ = Describe("Manifest", func() {
 				})
 
 				It("sets network defaults for both dns and gateway when none are specified", func() {
-					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network")).To(Equal(map[string]biproperty.Map{
+					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network", 0)).To(Equal(map[string]biproperty.Map{
 						"vip": biproperty.Map{
 							"type":             "vip",
 							"ip":               "1.2.3.4",
@@ -121,7 +134,7 @@ var _ = Describe("Manifest", func() {
 
 				It("sets network defaults for both dns and gateway when only dns specified", func() {
 					singleNetworkJob.Networks[0].Defaults = []NetworkDefault{NetworkDefaultDNS}
-					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network")).To(Equal(map[string]biproperty.Map{
+					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network", 0)).To(Equal(map[string]biproperty.Map{
 						"vip": biproperty.Map{
 							"type":             "vip",
 							"ip":               "1.2.3.4",
@@ -133,7 +146,7 @@ var _ = Describe("Manifest", func() {
 
 				It("sets network defaults for both dns and gateway when only gateway specified", func() {
 					singleNetworkJob.Networks[0].Defaults = []NetworkDefault{NetworkDefaultGateway}
-					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network")).To(Equal(map[string]biproperty.Map{
+					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network", 0)).To(Equal(map[string]biproperty.Map{
 						"vip": biproperty.Map{
 							"type":             "vip",
 							"ip":               "1.2.3.4",
@@ -145,7 +158,7 @@ var _ = Describe("Manifest", func() {
 
 				It("sets network defaults for both dns and gateway when both gateway and dns specified", func() {
 					singleNetworkJob.Networks[0].Defaults = []NetworkDefault{NetworkDefaultDNS, NetworkDefaultGateway}
-					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network")).To(Equal(map[string]biproperty.Map{
+					Expect(deploymentManifest.NetworkInterfaces("job-with-single-network", 0)).To(Equal(map[string]biproperty.Map{
 						"vip": biproperty.Map{
 							"type":             "vip",
 							"ip":               "1.2.3.4",
@@ -157,14 +170,14 @@ var _ = Describe("Manifest", func() {
 			})
 
 			It("returns an error when the deployment does not have a job with requested name", func() {
-				networkInterfaces, err := deploymentManifest.NetworkInterfaces("non-existant-job")
+				networkInterfaces, err := deploymentManifest.NetworkInterfaces("non-existant-job", 0)
 				Expect(networkInterfaces).To(Equal(map[string]biproperty.Map{}))
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("Could not find job with name: non-existant-job"))
 			})
 
 			It("returns an empty map when job does not specify networks", func() {
-				Expect(deploymentManifest.NetworkInterfaces("job-without-networks")).To(Equal(map[string]biproperty.Map{}))
+				Expect(deploymentManifest.NetworkInterfaces("job-without-networks", 0)).To(Equal(map[string]biproperty.Map{}))
 			})
 
 			Context("when the deployment does not have networks", func() {
@@ -180,12 +193,36 @@ var _ = Describe("Manifest", func() {
 				})
 
 				It("is an empty map", func() {
-					Expect(deploymentManifest.NetworkInterfaces("fake-job-name")).To(Equal(map[string]biproperty.Map{}))
+					Expect(deploymentManifest.NetworkInterfaces("fake-job-name", 0)).To(Equal(map[string]biproperty.Map{}))
 				})
 			})
 		})
 	})
 
+	Describe("GetListOfTemplateReleases", func() {
+		It("returns the releases of the templates of all jobs", func() {
+			deploymentManifest = Manifest{
+				Jobs: []Job{
+					{
+						Name:      "fake-job-name",
+						Templates: []ReleaseJobRef{{Name: "fake-template-name", Release: "fake-release-name"}},
+					},
+					{
+						Name:      "fake-other-job-name",
+						Templates: []ReleaseJobRef{{Name: "fake-other-template-name", Release: "fake-other-release-name"}},
+					},
+				},
+			}
+
+			releases, found := deploymentManifest.GetListOfTemplateReleases()
+			Expect(found).To(BeTrue())
+			Expect(releases).To(Equal(map[string]string{
+				"fake-release-name":       "fake-release-name",
+				"fake-other-release-name": "fake-other-release-name",
+			}))
+		})
+	})
+
 	Describe("ResourcePool", func() {
 		BeforeEach(func() {
 			deploymentManifest = Manifest{
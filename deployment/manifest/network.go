@@ -1,10 +1,9 @@
 package manifest
 
 import (
-	"encoding/hex"
-	"fmt"
 	"net"
 
+	binet "github.com/cloudfoundry/bosh-init/common/net"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 )
@@ -33,10 +32,64 @@ type Subnet struct {
 	Range           string
 	Gateway         string
 	DNS             []string
+	Reserved        []string
+	Static          []string
 	CloudProperties biproperty.Map
 }
 
+// Contains returns true if ip is within the range of the subnet.
+func (s Subnet) Contains(ip net.IP) bool {
+	_, ipNet, err := net.ParseCIDR(s.Range)
+	return err == nil && ipNet.Contains(ip)
+}
+
+// IsReserved returns true if ip is within one of the reserved ranges of the subnet.
+func (s Subnet) IsReserved(ip net.IP) bool {
+	return s.rangesContain(s.Reserved, ip)
+}
+
+// IsStatic returns true if ip is within one of the static ranges of the subnet.
+func (s Subnet) IsStatic(ip net.IP) bool {
+	return s.rangesContain(s.Static, ip)
+}
+
+func (s Subnet) rangesContain(ipRanges []string, ip net.IP) bool {
+	for _, ipRange := range ipRanges {
+		first, last, err := binet.ParseRange(ipRange)
+		if err == nil && binet.RangeContains(first, last, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubnetFor returns the subnet whose range contains ip.
+func (n Network) SubnetFor(ip string) (Subnet, bool) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return Subnet{}, false
+	}
+
+	for _, subnet := range n.Subnets {
+		if subnet.Contains(parsedIP) {
+			return subnet, true
+		}
+	}
+	return Subnet{}, false
+}
+
+// HasStatic returns true if a subnet of the network has static ranges to allocate IPs from.
+func (n Network) HasStatic() bool {
+	for _, subnet := range n.Subnets {
+		if len(subnet.Static) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Interface returns a property map representing a generic network interface.
+// On a manual network, the subnet is the one containing the static IP, or else the first subnet.
 // Expected Keys: ip, type, cloud properties.
 // Optional Keys: netmask, gateway, dns
 func (n Network) Interface(staticIPs []string, networkDefaults []NetworkDefault) (biproperty.Map, error) {
@@ -45,22 +98,25 @@ func (n Network) Interface(staticIPs []string, networkDefaults []NetworkDefault)
 	}
 
 	if n.Type == Manual {
-		networkInterface["gateway"] = n.Subnets[0].Gateway
-		if len(n.Subnets[0].DNS) > 0 {
-			networkInterface["dns"] = n.Subnets[0].DNS
+		subnet := n.Subnets[0]
+		if len(staticIPs) > 0 {
+			if ipSubnet, found := n.SubnetFor(staticIPs[0]); found {
+				subnet = ipSubnet
+			}
 		}
 
-		_, ipNet, err := net.ParseCIDR(n.Subnets[0].Range)
-		if err != nil {
-			return biproperty.Map{}, bosherr.WrapError(err, "Failed to parse subnet range")
+		networkInterface["gateway"] = subnet.Gateway
+		if len(subnet.DNS) > 0 {
+			networkInterface["dns"] = subnet.DNS
 		}
-		ipParts, err := hex.DecodeString(ipNet.Mask.String())
+
+		_, ipNet, err := net.ParseCIDR(subnet.Range)
 		if err != nil {
-			return biproperty.Map{}, bosherr.WrapError(err, "Failed to convert subnet range to IP string")
+			return biproperty.Map{}, bosherr.WrapError(err, "Failed to parse subnet range")
 		}
-		networkInterface["netmask"] = fmt.Sprintf("%v.%v.%v.%v", ipParts[0], ipParts[1], ipParts[2], ipParts[3])
+		networkInterface["netmask"] = binet.Netmask(ipNet)
 
-		networkInterface["cloud_properties"] = n.Subnets[0].CloudProperties
+		networkInterface["cloud_properties"] = subnet.CloudProperties
 	} else {
 		networkInterface["cloud_properties"] = n.CloudProperties
 	}
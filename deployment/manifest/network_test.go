@@ -1,6 +1,8 @@
 package manifest_test
 
 import (
+	"net"
+
 	. "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
@@ -65,6 +67,63 @@ var _ = Describe("Network", func() {
 				}))
 			})
 
+			Context("when there are multiple subnets", func() {
+				BeforeEach(func() {
+					network.Subnets = append(network.Subnets, Subnet{
+						Range:   "5.6.7.0/24",
+						Gateway: "5.6.7.1",
+						CloudProperties: biproperty.Map{
+							"cp_key": "other_cp_value",
+						},
+					})
+				})
+
+				It("uses the subnet containing the ip from the job", func() {
+					iface, err := network.Interface([]string{"5.6.7.9"}, []NetworkDefault{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(iface).To(Equal(biproperty.Map{
+						"type":    "manual",
+						"ip":      "5.6.7.9",
+						"gateway": "5.6.7.1",
+						"netmask": "255.255.255.0",
+						"cloud_properties": biproperty.Map{
+							"cp_key": "other_cp_value",
+						},
+					}))
+				})
+
+				It("uses the first subnet without ip from the job", func() {
+					iface, err := network.Interface([]string{}, []NetworkDefault{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(iface["gateway"]).To(Equal("1.1.1.1"))
+				})
+			})
+
+			Context("when the subnet is IPv6", func() {
+				BeforeEach(func() {
+					network.Subnets = []Subnet{
+						{
+							Range:   "2001:db8::/64",
+							Gateway: "2001:db8::1",
+							DNS:     []string{"2001:db8::53"},
+						},
+					}
+				})
+
+				It("includes the netmask in IPv6 notation", func() {
+					iface, err := network.Interface([]string{"2001:db8::5"}, []NetworkDefault{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(iface).To(Equal(biproperty.Map{
+						"type":             "manual",
+						"ip":               "2001:db8::5",
+						"gateway":          "2001:db8::1",
+						"netmask":          "ffff:ffff:ffff:ffff::",
+						"dns":              []string{"2001:db8::53"},
+						"cloud_properties": biproperty.Map(nil),
+					}))
+				})
+			})
+
 			Context("when range is invalid", func() {
 				BeforeEach(func() {
 					network.Subnets[0].Range = "invalid-range"
@@ -104,3 +163,27 @@ var _ = Describe("Network", func() {
 		})
 	})
 })
+
+var _ = Describe("Subnet", func() {
+	var subnet Subnet
+
+	BeforeEach(func() {
+		subnet = Subnet{
+			Range:    "10.0.0.0/24",
+			Reserved: []string{"10.0.0.2 - 10.0.0.9"},
+			Static:   []string{"10.0.0.10 - 10.0.0.20", "10.0.0.30"},
+		}
+	})
+
+	It("knows which IPs are within its range, reserved and static ranges", func() {
+		Expect(subnet.Contains(net.ParseIP("10.0.0.100"))).To(BeTrue())
+		Expect(subnet.Contains(net.ParseIP("10.0.1.100"))).To(BeFalse())
+
+		Expect(subnet.IsReserved(net.ParseIP("10.0.0.5"))).To(BeTrue())
+		Expect(subnet.IsReserved(net.ParseIP("10.0.0.10"))).To(BeFalse())
+
+		Expect(subnet.IsStatic(net.ParseIP("10.0.0.20"))).To(BeTrue())
+		Expect(subnet.IsStatic(net.ParseIP("10.0.0.30"))).To(BeTrue())
+		Expect(subnet.IsStatic(net.ParseIP("10.0.0.21"))).To(BeFalse())
+	})
+})
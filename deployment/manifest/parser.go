@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	biutil "github.com/cloudfoundry/bosh-init/common/util"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
@@ -14,9 +15,10 @@ type Parser interface {
 }
 
 type parser struct {
-	fs     boshsys.FileSystem
-	logger boshlog.Logger
-	logTag string
+	fs           boshsys.FileSystem
+	interpolator biinterpolation.Interpolator
+	logger       boshlog.Logger
+	logTag       string
 }
 
 type manifest struct {
@@ -48,6 +50,8 @@ type subnet struct {
 	Range           string                      `yaml:"range"`
 	Gateway         string                      `yaml:"gateway"`
 	DNS             []string                    `yaml:"dns"`
+	Reserved        []string                    `yaml:"reserved"`
+	Static          []string                    `yaml:"static"`
 	CloudProperties map[interface{}]interface{} `yaml:"cloud_properties"`
 }
 
@@ -78,8 +82,10 @@ type job struct {
 }
 
 type releaseJobRef struct {
-	Name    string
-	Release string
+	Name     string
+	Release  string
+	Consumes map[string]interface{}
+	Provides map[string]interface{}
 }
 
 type stemcellRef struct {
@@ -102,11 +108,12 @@ var boshDeploymentDefaults = Manifest{
 	},
 }
 
-func NewParser(fs boshsys.FileSystem, logger boshlog.Logger) Parser {
+func NewParser(fs boshsys.FileSystem, interpolator biinterpolation.Interpolator, logger boshlog.Logger) Parser {
 	return &parser{
-		fs:     fs,
-		logger: logger,
-		logTag: "deploymentParser",
+		fs:           fs,
+		interpolator: interpolator,
+		logger:       logger,
+		logTag:       "deploymentParser",
 	}
 }
 
@@ -116,12 +123,17 @@ func (p *parser) Parse(path string) (Manifest, error) {
 		return Manifest{}, bosherr.WrapErrorf(err, "Reading file %s", path)
 	}
 
+	contents, err = p.interpolator.Interpolate(contents)
+	if err != nil {
+		return Manifest{}, bosherr.WrapErrorf(err, "Interpolating variables in %s", path)
+	}
+
 	comboManifest := manifest{}
 	err = yaml.Unmarshal(contents, &comboManifest)
 	if err != nil {
 		return Manifest{}, bosherr.WrapError(err, "Unmarshalling BOSH deployment manifest")
 	}
-	p.logger.Debug(p.logTag, "Parsed BOSH deployment manifest: %#v", comboManifest)
+	p.logger.Debug(p.logTag, "Parsed BOSH deployment manifest '%s'", path)
 
 	deploymentManifest, err := p.parseDeploymentManifest(comboManifest, path)
 	if err != nil {
@@ -195,10 +207,28 @@ func (p *parser) parseJobManifests(rawJobs []job) ([]Job, error) {
 		if rawJob.Templates != nil {
 			releaseJobRefs := make([]ReleaseJobRef, len(rawJob.Templates), len(rawJob.Templates))
 			for i, rawJobRef := range rawJob.Templates {
-				releaseJobRefs[i] = ReleaseJobRef{
+				releaseJobRef := ReleaseJobRef{
 					Name:    rawJobRef.Name,
 					Release: rawJobRef.Release,
 				}
+
+				if rawJobRef.Consumes != nil {
+					consumedLinks, err := p.parseConsumedLinks(rawJobRef.Consumes)
+					if err != nil {
+						return jobs, bosherr.WrapErrorf(err, "Parsing job '%s' template '%s' consumes: %#v", rawJob.Name, rawJobRef.Name, rawJobRef.Consumes)
+					}
+					releaseJobRef.Consumes = consumedLinks
+				}
+
+				if rawJobRef.Provides != nil {
+					providedLinks, err := p.parseProvidedLinks(rawJobRef.Provides)
+					if err != nil {
+						return jobs, bosherr.WrapErrorf(err, "Parsing job '%s' template '%s' provides: %#v", rawJob.Name, rawJobRef.Name, rawJobRef.Provides)
+					}
+					releaseJobRef.Provides = providedLinks
+				}
+
+				releaseJobRefs[i] = releaseJobRef
 			}
 			job.Templates = releaseJobRefs
 		}
@@ -238,6 +268,81 @@ func (p *parser) parseJobManifests(rawJobs []job) ([]Job, error) {
 	return jobs, nil
 }
 
+// parseConsumedLinks parses the links consumed by a template: 'nil' disables a link,
+// 'from' picks its provider, and 'instances' or 'properties' make it a manual link.
+func (p *parser) parseConsumedLinks(rawConsumedLinks map[string]interface{}) (map[string]ConsumedLink, error) {
+	consumedLinks := map[string]ConsumedLink{}
+	for linkName, rawConsumedLink := range rawConsumedLinks {
+		if p.isDisabledLink(rawConsumedLink) {
+			consumedLinks[linkName] = ConsumedLink{Disabled: true}
+			continue
+		}
+
+		config, err := biproperty.Build(rawConsumedLink)
+		if err != nil {
+			return consumedLinks, bosherr.WrapErrorf(err, "Parsing link '%s'", linkName)
+		}
+		configMap, ok := config.(biproperty.Map)
+		if !ok {
+			return consumedLinks, bosherr.Errorf("Link '%s' must be a hash or 'nil'", linkName)
+		}
+
+		consumedLink := ConsumedLink{}
+		consumedLink.From, _ = configMap["from"].(string)
+
+		rawInstances, hasInstances := configMap["instances"]
+		rawProperties, hasProperties := configMap["properties"]
+		if hasInstances || hasProperties {
+			link := &Link{Properties: biproperty.Map{}}
+			if properties, ok := rawProperties.(biproperty.Map); ok {
+				link.Properties = properties
+			}
+
+			instances, _ := rawInstances.(biproperty.List)
+			for index, rawInstance := range instances {
+				instance, _ := rawInstance.(biproperty.Map)
+				address, _ := instance["address"].(string)
+				link.Instances = append(link.Instances, LinkInstance{Name: linkName, Index: index, Address: address, Bootstrap: index == 0})
+			}
+			consumedLink.Manual = link
+		}
+
+		consumedLinks[linkName] = consumedLink
+	}
+
+	return consumedLinks, nil
+}
+
+// parseProvidedLinks parses the links provided by a template: 'nil' disables a link, and 'as' renames it.
+func (p *parser) parseProvidedLinks(rawProvidedLinks map[string]interface{}) (map[string]ProvidedLink, error) {
+	providedLinks := map[string]ProvidedLink{}
+	for linkName, rawProvidedLink := range rawProvidedLinks {
+		if p.isDisabledLink(rawProvidedLink) {
+			providedLinks[linkName] = ProvidedLink{Disabled: true}
+			continue
+		}
+
+		config, err := biproperty.Build(rawProvidedLink)
+		if err != nil {
+			return providedLinks, bosherr.WrapErrorf(err, "Parsing link '%s'", linkName)
+		}
+		configMap, ok := config.(biproperty.Map)
+		if !ok {
+			return providedLinks, bosherr.Errorf("Link '%s' must be a hash or 'nil'", linkName)
+		}
+
+		providedLink := ProvidedLink{}
+		providedLink.As, _ = configMap["as"].(string)
+		providedLinks[linkName] = providedLink
+	}
+
+	return providedLinks, nil
+}
+
+func (p *parser) isDisabledLink(rawLink interface{}) bool {
+	return rawLink == nil || rawLink == "nil"
+}
+
 func (p *parser) parseNetworkManifests(rawNetworks []network) ([]Network, error) {
 	networks := make([]Network, len(rawNetworks), len(rawNetworks))
 	for i, rawNetwork := range rawNetworks {
@@ -263,6 +368,8 @@ func (p *parser) parseNetworkManifests(rawNetworks []network) ([]Network, error)
 				Range:           subnet.Range,
 				Gateway:         subnet.Gateway,
 				DNS:             subnet.DNS,
+				Reserved:        subnet.Reserved,
+				Static:          subnet.Static,
 				CloudProperties: cloudProperties,
 			})
 		}
@@ -7,6 +7,7 @@ import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -23,7 +24,10 @@ var _ = Describe("Parser", func() {
 		comboManifestPath = "fake-deployment-path"
 		fakeFs = fakesys.NewFakeFileSystem()
 		logger := boshlog.NewLogger(boshlog.LevelNone)
-		parser = NewParser(fakeFs, logger)
+		interpolator := biinterpolation.NewInterpolator(biinterpolation.Variables{
+			"deployment_name": "fake-interpolated-deployment-name",
+		}, biinterpolation.Ops{})
+		parser = NewParser(fakeFs, interpolator, logger)
 	})
 
 	Context("when combo manifest path does not exist", func() {
@@ -72,6 +76,8 @@ networks:
   - range: 1.2.3.0/22
     gateway: 1.1.1.1
     dns: [2.2.2.2]
+    reserved: [1.2.3.2 - 1.2.3.9]
+    static: [1.2.3.10 - 1.2.3.20, 1.2.3.30]
     cloud_properties:
       cp_key: cp_value
   cloud_properties:
@@ -124,9 +130,11 @@ properties:
 					DNS:  []string{"5.5.5.5", "6.6.6.6"},
 					Subnets: []Subnet{
 						{
-							Range:   "1.2.3.0/22",
-							Gateway: "1.1.1.1",
-							DNS:     []string{"2.2.2.2"},
+							Range:    "1.2.3.0/22",
+							Gateway:  "1.1.1.1",
+							DNS:      []string{"2.2.2.2"},
+							Reserved: []string{"1.2.3.2 - 1.2.3.9"},
+							Static:   []string{"1.2.3.10 - 1.2.3.20", "1.2.3.30"},
 							CloudProperties: biproperty.Map{
 								"cp_key": "cp_value",
 							},
@@ -408,6 +416,78 @@ jobs:
 		})
 	})
 
+	Context("when job templates configure links", func() {
+		BeforeEach(func() {
+			contents := `
+---
+jobs:
+- name: fake-deployment-job
+  templates:
+  - name: fake-consumer-job
+    release: fake-release
+    consumes:
+      fake-link: {from: fake-renamed-link}
+      fake-disabled-link: nil
+      fake-manual-link:
+        instances:
+        - address: 10.0.0.5
+        properties: {port: 5432}
+  - name: fake-provider-job
+    release: fake-release
+    provides:
+      fake-provided-link: {as: fake-renamed-link}
+      fake-disabled-link: ~
+`
+			fakeFs.WriteFileString(comboManifestPath, contents)
+		})
+
+		It("parses the consumed and provided links", func() {
+			deploymentManifest, err := parser.Parse(comboManifestPath)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(deploymentManifest.Jobs[0].Templates).To(Equal([]ReleaseJobRef{
+				{
+					Name:    "fake-consumer-job",
+					Release: "fake-release",
+					Consumes: map[string]ConsumedLink{
+						"fake-link":          {From: "fake-renamed-link"},
+						"fake-disabled-link": {Disabled: true},
+						"fake-manual-link": {
+							Manual: &Link{
+								Instances:  []LinkInstance{{Name: "fake-manual-link", Index: 0, Address: "10.0.0.5", Bootstrap: true}},
+								Properties: biproperty.Map{"port": 5432},
+							},
+						},
+					},
+				},
+				{
+					Name:    "fake-provider-job",
+					Release: "fake-release",
+					Provides: map[string]ProvidedLink{
+						"fake-provided-link": {As: "fake-renamed-link"},
+						"fake-disabled-link": {Disabled: true},
+					},
+				},
+			}))
+		})
+
+		It("returns an error when a link is not a hash", func() {
+			fakeFs.WriteFileString(comboManifestPath, `
+---
+jobs:
+- name: fake-deployment-job
+  templates:
+  - name: fake-consumer-job
+    consumes:
+      fake-link: fake-provider
+`)
+
+			_, err := parser.Parse(comboManifestPath)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Link 'fake-link' must be a hash or 'nil'"))
+		})
+	})
+
 	Context("when network cloud_properties keys are not strings", func() {
 		BeforeEach(func() {
 			contents := `
@@ -484,6 +564,24 @@ disk_pools:
 		})
 	})
 
+	Context("when the manifest contains variables", func() {
+		It("interpolates them before parsing", func() {
+			fakeFs.WriteFileString(comboManifestPath, "---\nname: ((deployment_name))\n")
+
+			deploymentManifest, err := parser.Parse(comboManifestPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentManifest.Name).To(Equal("fake-interpolated-deployment-name"))
+		})
+
+		It("returns an error when a variable is missing", func() {
+			fakeFs.WriteFileString(comboManifestPath, "---\nname: ((missing_name))\n")
+
+			_, err := parser.Parse(comboManifestPath)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Missing variable 'missing_name' for 'name'"))
+		})
+	})
+
 	Context("when update watch time is not set", func() {
 		BeforeEach(func() {
 			contents := `
@@ -1,20 +1,26 @@
 package manifest
 
 import (
+	"fmt"
 	"net"
 	"regexp"
+	"sort"
 	"strings"
 
 	binet "github.com/cloudfoundry/bosh-init/common/net"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	birel "github.com/cloudfoundry/bosh-init/release"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
 	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
 )
 
 type Validator interface {
 	Validate(Manifest, birelsetmanifest.Manifest) error
-	ValidateReleaseJobs(Manifest, birel.Manager) error
+	// ValidateReleaseJobs validates the manifest against the specs of the release jobs,
+	// and returns warnings about what may be wrong but does not prevent a deploy
+	ValidateReleaseJobs(Manifest, birel.Manager) ([]string, error)
 }
 
 type validator struct {
@@ -69,14 +75,18 @@ func (v *validator) Validate(deploymentManifest Manifest, releaseSetManifest bir
 		}
 	}
 
-	if len(deploymentManifest.Jobs) > 1 {
-		errs = append(errs, bosherr.Error("jobs must be of size 1"))
+	if len(deploymentManifest.Jobs) == 0 {
+		errs = append(errs, bosherr.Error("jobs must be a non-empty array"))
 	}
 
+	jobNames := map[string]struct{}{}
 	for idx, job := range deploymentManifest.Jobs {
 		if v.isBlank(job.Name) {
 			errs = append(errs, bosherr.Errorf("jobs[%d].name must be provided", idx))
+		} else if _, found := jobNames[job.Name]; found {
+			errs = append(errs, bosherr.Errorf("jobs[%d].name '%s' must be unique", idx, job.Name))
 		}
+		jobNames[job.Name] = struct{}{}
 		if job.PersistentDisk < 0 {
 			errs = append(errs, bosherr.Errorf("jobs[%d].persistent_disk must be >= 0", idx))
 		}
@@ -85,7 +95,10 @@ func (v *validator) Validate(deploymentManifest Manifest, releaseSetManifest bir
 				errs = append(errs, bosherr.Errorf("jobs[%d].persistent_disk_pool must be the name of a disk pool", idx))
 			}
 		}
-		if job.Instances < 0 {
+		if idx == 0 && job.Instances < 1 {
+			// the first instance of the first job is the bootstrap instance
+			errs = append(errs, bosherr.Errorf("jobs[%d].instances must be > 0", idx))
+		} else if job.Instances < 0 {
 			errs = append(errs, bosherr.Errorf("jobs[%d].instances must be >= 0", idx))
 		}
 		if len(job.Networks) == 0 {
@@ -96,13 +109,24 @@ func (v *validator) Validate(deploymentManifest Manifest, releaseSetManifest bir
 		} else {
 			if _, ok := v.resourcePoolNames(deploymentManifest)[job.ResourcePool]; !ok {
 				errs = append(errs, bosherr.Errorf("jobs[%d].resource_pool must be the name of a resource pool", idx))
+			} else if idx > 0 && v.stemcellURL(deploymentManifest, job) != v.stemcellURL(deploymentManifest, deploymentManifest.Jobs[0]) {
+				errs = append(errs, bosherr.Errorf("jobs[%d].resource_pool must use the same stemcell as jobs[0]", idx))
 			}
 		}
 
 		errs = append(errs, v.validateJobNetworks(job.Networks, deploymentManifest.Networks, idx)...)
-
-		if job.Lifecycle != "" && job.Lifecycle != JobLifecycleService {
-			errs = append(errs, bosherr.Errorf("jobs[%d].lifecycle must be 'service' ('%s' not supported)", idx, job.Lifecycle))
+		errs = append(errs, v.validateJobStaticIPs(job, deploymentManifest.Networks, idx)...)
+
+		switch job.Lifecycle {
+		case "", JobLifecycleService:
+		case JobLifecycleErrand:
+			if idx == 0 {
+				errs = append(errs, bosherr.Errorf("jobs[%d].lifecycle must be 'service' ('errand' not supported for the first job)", idx))
+			} else if job.Instances != 1 {
+				errs = append(errs, bosherr.Errorf("jobs[%d].instances must be 1 for errand jobs", idx))
+			}
+		default:
+			errs = append(errs, bosherr.Errorf("jobs[%d].lifecycle must be 'service' or 'errand' ('%s' not supported)", idx, job.Lifecycle))
 		}
 
 		templateNames := map[string]struct{}{}
@@ -126,6 +150,8 @@ func (v *validator) Validate(deploymentManifest Manifest, releaseSetManifest bir
 		}
 	}
 
+	errs = append(errs, v.validateUniqueStaticIPs(deploymentManifest.Jobs)...)
+
 	if len(errs) > 0 {
 		return bosherr.NewMultiError(errs...)
 	}
@@ -133,28 +159,226 @@ func (v *validator) Validate(deploymentManifest Manifest, releaseSetManifest bir
 	return nil
 }
 
-func (v *validator) ValidateReleaseJobs(deploymentManifest Manifest, releaseManager birel.Manager) error {
+// validateUniqueStaticIPs checks that no static IP is given to more than one instance on the same network
+func (v *validator) validateUniqueStaticIPs(jobs []Job) []error {
+	errs := []error{}
+	usedIPs := map[string]struct{}{}
+
+	for jobIdx, job := range jobs {
+		for networkIdx, jobNetwork := range job.Networks {
+			for _, ip := range jobNetwork.StaticIPs {
+				key := jobNetwork.Name + "/" + ip
+				if _, found := usedIPs[key]; found {
+					errs = append(errs, bosherr.Errorf("jobs[%d].networks[%d] static ip '%s' must not be used by more than one instance", jobIdx, networkIdx, ip))
+				}
+				usedIPs[key] = struct{}{}
+			}
+		}
+	}
+
+	return errs
+}
+
+func (v *validator) ValidateReleaseJobs(deploymentManifest Manifest, releaseManager birel.Manager) ([]string, error) {
+	warnings := []string{}
 	errs := []error{}
 
 	for idx, job := range deploymentManifest.Jobs {
+		releaseJobs := []bireljob.Job{}
 		for templateIdx, template := range job.Templates {
 			release, found := releaseManager.Find(template.Release)
 			if !found {
 				errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d].release '%s' must refer to release in releases", idx, templateIdx, template.Release))
 			} else {
-				_, found := release.FindJobByName(template.Name)
+				releaseJob, found := release.FindJobByName(template.Name)
 				if !found {
 					errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d] must refer to a job in '%s', but there is no job named '%s'", idx, templateIdx, release.Name(), template.Name))
+				} else {
+					releaseJobs = append(releaseJobs, releaseJob)
+					propertyWarnings, propertyErrs := v.validateJobProperties(idx, templateIdx, releaseJob, job.Properties, deploymentManifest.Properties)
+					warnings = append(warnings, propertyWarnings...)
+					errs = append(errs, propertyErrs...)
 				}
 			}
 		}
+
+		// properties can only be known to be unused, and links to be resolvable, when the specs of all the templates are available
+		if len(releaseJobs) == len(job.Templates) {
+			errs = append(errs, v.validateUnknownJobProperties(idx, releaseJobs, job.Properties)...)
+			errs = append(errs, v.validateLinks(deploymentManifest, job, releaseJobs)...)
+		}
 	}
 
 	if len(errs) > 0 {
-		return bosherr.NewMultiError(errs...)
+		return warnings, bosherr.NewMultiError(errs...)
 	}
 
-	return nil
+	return warnings, nil
+}
+
+// validateJobProperties checks that the properties set, in the job properties or the global properties, are of the kind given by the type
+// or the example of the spec. Properties of the spec without a default that are not set only cause a warning,
+// since releases commonly declare optional properties that way and guard their use in templates with if_p.
+func (v *validator) validateJobProperties(idx, templateIdx int, releaseJob bireljob.Job, jobProperties, globalProperties biproperty.Map) ([]string, []error) {
+	warnings := []string{}
+	errs := []error{}
+
+	for _, propertyName := range v.sortedPropertyNames(releaseJob.Properties) {
+		definition := releaseJob.Properties[propertyName]
+
+		value, found := v.lookupProperty(jobProperties, propertyName)
+		if !found {
+			value, found = v.lookupProperty(globalProperties, propertyName)
+		}
+
+		if !found {
+			if definition.Default == nil {
+				warnings = append(warnings, fmt.Sprintf("jobs[%d].templates[%d] job '%s' property '%s' is not set and has no default", idx, templateIdx, releaseJob.Name, propertyName))
+			}
+			continue
+		}
+
+		expectedKind, ok := v.expectedPropertyKind(definition)
+		if ok && !v.propertyKindMatches(v.propertyKind(value), expectedKind) {
+			errs = append(errs, bosherr.Errorf("jobs[%d].templates[%d] job '%s' property '%s' must be of type '%s'", idx, templateIdx, releaseJob.Name, propertyName, expectedKind))
+		}
+	}
+
+	return warnings, errs
+}
+
+// validateLinks checks that the links consumed by the templates of the job are provided by a colocated template or are manual links
+func (v *validator) validateLinks(deploymentManifest Manifest, job Job, releaseJobs []bireljob.Job) []error {
+	_, err := deploymentManifest.Links(job.Name, releaseJobs)
+	if err == nil {
+		return []error{}
+	}
+
+	if multiErr, ok := err.(bosherr.MultiError); ok {
+		return multiErr.Errors
+	}
+	return []error{err}
+}
+
+// validateUnknownJobProperties checks that each property set on the job is defined by the spec of one of its templates.
+// Global properties are not checked, since they are shared by all the jobs.
+func (v *validator) validateUnknownJobProperties(idx int, releaseJobs []bireljob.Job, jobProperties biproperty.Map) []error {
+	errs := []error{}
+
+	for _, propertyPath := range v.propertyPaths("", jobProperties) {
+		known := false
+		for _, releaseJob := range releaseJobs {
+			for propertyName := range releaseJob.Properties {
+				if propertyPath == propertyName || strings.HasPrefix(propertyPath, propertyName+".") {
+					known = true
+				}
+			}
+		}
+
+		if !known {
+			errs = append(errs, bosherr.Errorf("jobs[%d].properties.%s is not defined by any of the jobs in jobs[%d].templates", idx, propertyPath, idx))
+		}
+	}
+
+	return errs
+}
+
+// propertyPaths returns the sorted dotted paths of the values of properties that are not hashes
+func (v *validator) propertyPaths(prefix string, properties biproperty.Map) []string {
+	paths := []string{}
+	for key, value := range properties {
+		path := prefix + key
+		if nested, ok := value.(biproperty.Map); ok && len(nested) > 0 {
+			paths = append(paths, v.propertyPaths(path+".", nested)...)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// lookupProperty returns the value at the dotted path of a job spec property name, if it is set and not null
+func (v *validator) lookupProperty(properties biproperty.Map, propertyName string) (biproperty.Property, bool) {
+	var value biproperty.Property = properties
+	for _, key := range strings.Split(propertyName, ".") {
+		valueMap, ok := value.(biproperty.Map)
+		if !ok {
+			return nil, false
+		}
+		value, ok = valueMap[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return value, value != nil
+}
+
+func (v *validator) sortedPropertyNames(properties map[string]bireljob.PropertyDefinition) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expectedPropertyKind returns the kind of value required by the type of the property definition, or else by its example
+func (v *validator) expectedPropertyKind(definition bireljob.PropertyDefinition) (string, bool) {
+	switch strings.ToLower(definition.Type) {
+	case "":
+	case "string":
+		return "string", true
+	case "integer", "int":
+		return "integer", true
+	case "float", "number":
+		return "number", true
+	case "boolean", "bool":
+		return "boolean", true
+	case "array", "list":
+		return "array", true
+	case "hash", "map", "object":
+		return "hash", true
+	default:
+		// unknown types are not checked
+		return "", false
+	}
+
+	if definition.Example == nil {
+		return "", false
+	}
+
+	kind := v.propertyKind(definition.Example)
+	if kind == "integer" || kind == "float" {
+		kind = "number"
+	}
+	return kind, kind != ""
+}
+
+func (v *validator) propertyKind(value biproperty.Property) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case float32, float64:
+		return "float"
+	case bool:
+		return "boolean"
+	case biproperty.List, []interface{}:
+		return "array"
+	case biproperty.Map, map[interface{}]interface{}:
+		return "hash"
+	default:
+		return ""
+	}
+}
+
+func (v *validator) propertyKindMatches(kind, expectedKind string) bool {
+	if expectedKind == "number" {
+		return kind == "integer" || kind == "float"
+	}
+	return kind == expectedKind
 }
 
 func (v *validator) isBlank(str string) bool {
@@ -185,6 +409,47 @@ func (v *validator) resourcePoolNames(deploymentManifest Manifest) map[string]st
 	return names
 }
 
+// stemcellURL returns the stemcell of the resource pool of the job,
+// all jobs must use the same stemcell as only one stemcell is uploaded
+func (v *validator) stemcellURL(deploymentManifest Manifest, job Job) string {
+	for _, resourcePool := range deploymentManifest.ResourcePools {
+		if resourcePool.Name == job.ResourcePool {
+			return resourcePool.Stemcell.URL
+		}
+	}
+	return ""
+}
+
+// validateJobStaticIPs checks that every instance other than the bootstrap instance has a static IP,
+// which is used to reach its agent, unless it can be allocated from the static ranges of a manual network
+func (v *validator) validateJobStaticIPs(job Job, networks []Network, jobIdx int) []error {
+	errs := []error{}
+
+	canAllocate := false
+	for networkIdx, jobNetwork := range job.Networks {
+		if len(jobNetwork.StaticIPs) > 0 && len(jobNetwork.StaticIPs) < job.Instances {
+			errs = append(errs, bosherr.Errorf("jobs[%d].networks[%d].static_ips must have an IP for each of the %d instances", jobIdx, networkIdx, job.Instances))
+		}
+
+		for _, network := range networks {
+			if network.Name == jobNetwork.Name && network.Type == Manual && network.HasStatic() {
+				canAllocate = true
+			}
+		}
+	}
+
+	if (jobIdx > 0 || job.Instances > 1) && !canAllocate {
+		for instanceIdx := 0; instanceIdx < job.Instances; instanceIdx++ {
+			if _, found := job.StaticIP(instanceIdx); !found {
+				errs = append(errs, bosherr.Errorf("jobs[%d].networks must have static_ips for each instance when deploying more than one instance", jobIdx))
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
 func (v *validator) isValidIP(ip string) bool {
 	parsedIP := net.ParseIP(ip)
 	return parsedIP != nil
@@ -208,13 +473,13 @@ func (in *somethingIpNet) Try(fn func(*net.IPNet) error) error {
 	return fn(in.ipNet)
 }
 
-func (v *validator) validateRange(idx int, ipRange string) ([]error, maybeIPNet) {
+func (v *validator) validateRange(idx, subnetIdx int, ipRange string) ([]error, maybeIPNet) {
 	if v.isBlank(ipRange) {
-		return []error{bosherr.Errorf("networks[%d].subnets[0].range must be provided", idx)}, &nothingIpNet{}
+		return []error{bosherr.Errorf("networks[%d].subnets[%d].range must be provided", idx, subnetIdx)}, &nothingIpNet{}
 	} else {
 		_, ipNet, err := net.ParseCIDR(ipRange)
 		if err != nil {
-			return []error{bosherr.Errorf("networks[%d].subnets[0].range must be an ip range", idx)}, &nothingIpNet{}
+			return []error{bosherr.Errorf("networks[%d].subnets[%d].range must be an ip range", idx, subnetIdx)}, &nothingIpNet{}
 		}
 
 		return []error{}, &somethingIpNet{ipNet: ipNet}
@@ -242,22 +507,64 @@ func (v *validator) validateNetwork(network Network, networkIdx int) []error {
 		errs = append(errs, bosherr.Errorf("networks[%d].type must be 'manual', 'dynamic', or 'vip'", networkIdx))
 	}
 	if network.Type == Manual {
-		if len(network.Subnets) != 1 {
-			errs = append(errs, bosherr.Errorf("networks[%d].subnets must be of size 1", networkIdx))
-		} else {
-			ipRange := network.Subnets[0].Range
-			rangeErrors, maybeIpNet := v.validateRange(networkIdx, ipRange)
+		if len(network.Subnets) == 0 {
+			errs = append(errs, bosherr.Errorf("networks[%d].subnets must be provided", networkIdx))
+		}
+
+		for subnetIdx, subnet := range network.Subnets {
+			rangeErrors, maybeIpNet := v.validateRange(networkIdx, subnetIdx, subnet.Range)
 			errs = append(errs, rangeErrors...)
 
-			gateway := network.Subnets[0].Gateway
-			gatewayErrors := v.validateGateway(networkIdx, gateway, maybeIpNet)
+			gatewayErrors := v.validateGateway(networkIdx, subnetIdx, subnet.Gateway, maybeIpNet)
 			errs = append(errs, gatewayErrors...)
+
+			errs = append(errs, v.validateSubnetRanges(networkIdx, subnetIdx, "reserved", subnet.Reserved, maybeIpNet)...)
+			errs = append(errs, v.validateSubnetRanges(networkIdx, subnetIdx, "static", subnet.Static, maybeIpNet)...)
+
+			for staticIdx, staticRange := range subnet.Static {
+				first, last, err := binet.ParseRange(staticRange)
+				if err == nil && v.overlapsRanges(first, last, subnet.Reserved) {
+					errs = append(errs, bosherr.Errorf("networks[%d].subnets[%d].static[%d] must not overlap the reserved ranges", networkIdx, subnetIdx, staticIdx))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateSubnetRanges checks that each of the reserved or static entries of a subnet is an IP or an IP range within the subnet range
+func (v *validator) validateSubnetRanges(networkIdx, subnetIdx int, key string, ipRanges []string, ipNet maybeIPNet) []error {
+	errs := []error{}
+
+	for rangeIdx, ipRange := range ipRanges {
+		first, last, err := binet.ParseRange(ipRange)
+		if err != nil {
+			errs = append(errs, bosherr.Errorf("networks[%d].subnets[%d].%s[%d] must be an IP or an IP range ('<first> - <last>')", networkIdx, subnetIdx, key, rangeIdx))
+			continue
 		}
+
+		_ = ipNet.Try(func(ipNet *net.IPNet) error {
+			if !ipNet.Contains(first) || !ipNet.Contains(last) {
+				errs = append(errs, bosherr.Errorf("networks[%d].subnets[%d].%s[%d] must be within the subnet range '%s'", networkIdx, subnetIdx, key, rangeIdx, ipNet))
+			}
+			return nil
+		})
 	}
 
 	return errs
 }
 
+func (v *validator) overlapsRanges(first, last net.IP, ipRanges []string) bool {
+	for _, ipRange := range ipRanges {
+		otherFirst, otherLast, err := binet.ParseRange(ipRange)
+		if err == nil && binet.CompareAddresses(first, otherLast) <= 0 && binet.CompareAddresses(otherFirst, last) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *validator) validateJobNetworks(jobNetworks []JobNetwork, networks []Network, jobIdx int) []error {
 	errs := []error{}
 	defaultCounts := make(map[NetworkDefault]int)
@@ -322,30 +629,31 @@ func (v *validator) validateStaticIP(ip string, jobNetwork JobNetwork, network N
 		return []error{}
 	}
 
-	foundInSubnetRange := false
-	for _, subnet := range network.Subnets {
-		_, rangeNet, err := net.ParseCIDR(subnet.Range)
-		if err == nil && rangeNet.Contains(net.ParseIP(ip)) {
-			foundInSubnetRange = true
-		}
+	subnet, found := network.SubnetFor(ip)
+	if !found {
+		return []error{bosherr.Errorf("jobs[%d].networks[%d] static ip '%s' must be within subnet range", jobIdx, networkIdx, ip)}
 	}
 
-	if foundInSubnetRange {
-		return []error{}
-	} else {
-		return []error{bosherr.Errorf("jobs[%d].networks[%d] static ip '%s' must be within subnet range", jobIdx, networkIdx, ip)}
+	if subnet.IsReserved(net.ParseIP(ip)) {
+		return []error{bosherr.Errorf("jobs[%d].networks[%d] static ip '%s' must not be within a reserved range", jobIdx, networkIdx, ip)}
+	}
+
+	if len(subnet.Static) > 0 && !subnet.IsStatic(net.ParseIP(ip)) {
+		return []error{bosherr.Errorf("jobs[%d].networks[%d] static ip '%s' must be within a static range of its subnet", jobIdx, networkIdx, ip)}
 	}
+
+	return []error{}
 }
 
-func (v *validator) validateGateway(idx int, gateway string, ipNet maybeIPNet) []error {
+func (v *validator) validateGateway(idx, subnetIdx int, gateway string, ipNet maybeIPNet) []error {
 	if v.isBlank(gateway) {
-		return []error{bosherr.Errorf("networks[%d].subnets[0].gateway must be provided", idx)}
+		return []error{bosherr.Errorf("networks[%d].subnets[%d].gateway must be provided", idx, subnetIdx)}
 	} else {
 		errors := []error{}
 		_ = ipNet.Try(func(ipNet *net.IPNet) error {
 			gatewayIp := net.ParseIP(gateway)
 			if gatewayIp == nil {
-				errors = append(errors, bosherr.Errorf("networks[%d].subnets[0].gateway must be an ip", idx))
+				errors = append(errors, bosherr.Errorf("networks[%d].subnets[%d].gateway must be an ip", idx, subnetIdx))
 			}
 
 			if !ipNet.Contains(gatewayIp) {
@@ -356,7 +664,8 @@ func (v *validator) validateGateway(idx int, gateway string, ipNet maybeIPNet) [
 				errors = append(errors, bosherr.Errorf("subnet gateway can't be the network address '%s'", gatewayIp))
 			}
 
-			if binet.LastAddress(ipNet).Equal(gatewayIp) {
+			// IPv6 has no broadcast address
+			if binet.IsIPv4(ipNet.IP) && binet.LastAddress(ipNet).Equal(gatewayIp) {
 				errors = append(errors, bosherr.Errorf("subnet gateway can't be the broadcast address '%s'", gatewayIp))
 			}
 
@@ -66,7 +66,8 @@ var _ = Describe("Validator", func() {
 			},
 			Jobs: []Job{
 				{
-					Name: "fake-job-name",
+					Name:      "fake-job-name",
+					Instances: 1,
 					Templates: []ReleaseJobRef{
 						{
 							Name:    "fake-job-name",
@@ -120,6 +121,47 @@ var _ = Describe("Validator", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("does not error if a job is on an IPv4 and an IPv6 manual network", func() {
+			deploymentManifest := validManifest
+			deploymentManifest.Networks = []Network{
+				{
+					Name: "fake-ipv4-network",
+					Type: "manual",
+					Subnets: []Subnet{{
+						Range:   "10.10.0.0/24",
+						Gateway: "10.10.0.1",
+					}},
+				},
+				{
+					Name: "fake-ipv6-network",
+					Type: "manual",
+					Subnets: []Subnet{{
+						Range:    "2001:db8::/64",
+						Gateway:  "2001:db8::1",
+						Reserved: []string{"2001:db8::2 - 2001:db8::9"},
+						Static:   []string{"2001:db8::10 - 2001:db8::20"},
+					}},
+				},
+			}
+			deploymentManifest.ResourcePools = []ResourcePool{validManifest.ResourcePools[0]}
+			deploymentManifest.ResourcePools[0].Network = "fake-ipv4-network"
+			deploymentManifest.Jobs = []Job{validManifest.Jobs[0]}
+			deploymentManifest.Jobs[0].Networks = []JobNetwork{
+				{
+					Name:      "fake-ipv4-network",
+					StaticIPs: []string{"10.10.0.5"},
+					Defaults:  []NetworkDefault{NetworkDefaultDNS, NetworkDefaultGateway},
+				},
+				{
+					Name:      "fake-ipv6-network",
+					StaticIPs: []string{"2001:db8::10"},
+				},
+			}
+
+			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("validates name is not empty", func() {
 			deploymentManifest := Manifest{
 				Name: "",
@@ -356,7 +398,7 @@ var _ = Describe("Validator", func() {
 			})
 
 			Context("manual networks", func() {
-				It("validates that there is exactly 1 subnet", func() {
+				It("validates that there is at least 1 subnet", func() {
 					deploymentManifest := Manifest{
 						Networks: []Network{
 							{
@@ -368,7 +410,73 @@ var _ = Describe("Validator", func() {
 
 					err := validator.Validate(deploymentManifest, validReleaseSetManifest)
 					Expect(err).To(HaveOccurred())
-					Expect(err.Error()).To(ContainSubstring("networks[0].subnets must be of size 1"))
+					Expect(err.Error()).To(ContainSubstring("networks[0].subnets must be provided"))
+				})
+
+				It("validates every subnet", func() {
+					deploymentManifest := Manifest{
+						Networks: []Network{
+							{
+								Type: "manual",
+								Subnets: []Subnet{
+									{Range: "10.10.0.0/24", Gateway: "10.10.0.1"},
+									{Range: "10.10.1.0/24"},
+								},
+							},
+						},
+					}
+
+					err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).ToNot(ContainSubstring("networks[0].subnets[0]"))
+					Expect(err.Error()).To(ContainSubstring("networks[0].subnets[1].gateway must be provided"))
+				})
+
+				It("validates that reserved and static entries are IPs or IP ranges within the subnet range", func() {
+					deploymentManifest := Manifest{
+						Networks: []Network{
+							{
+								Type: "manual",
+								Subnets: []Subnet{
+									{
+										Range:    "10.10.0.0/24",
+										Gateway:  "10.10.0.1",
+										Reserved: []string{"10.10.0.2 - 10.10.0.9", "not-an-ip"},
+										Static:   []string{"10.10.0.10", "10.10.0.250 - 10.10.1.5"},
+									},
+								},
+							},
+						},
+					}
+
+					err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).ToNot(ContainSubstring("reserved[0]"))
+					Expect(err.Error()).To(ContainSubstring("networks[0].subnets[0].reserved[1] must be an IP or an IP range ('<first> - <last>')"))
+					Expect(err.Error()).ToNot(ContainSubstring("static[0]"))
+					Expect(err.Error()).To(ContainSubstring("networks[0].subnets[0].static[1] must be within the subnet range '10.10.0.0/24'"))
+				})
+
+				It("validates that static ranges do not overlap reserved ranges", func() {
+					deploymentManifest := Manifest{
+						Networks: []Network{
+							{
+								Type: "manual",
+								Subnets: []Subnet{
+									{
+										Range:    "10.10.0.0/24",
+										Gateway:  "10.10.0.1",
+										Reserved: []string{"10.10.0.2 - 10.10.0.9"},
+										Static:   []string{"10.10.0.5 - 10.10.0.20"},
+									},
+								},
+							},
+						},
+					}
+
+					err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("networks[0].subnets[0].static[0] must not overlap the reserved ranges"))
 				})
 
 				It("validates that range is present", func() {
@@ -514,6 +622,39 @@ var _ = Describe("Validator", func() {
 					Expect(err).To(HaveOccurred())
 					Expect(err.Error()).To(ContainSubstring("subnet gateway can't be the broadcast address '10.10.0.255'"))
 				})
+
+				It("allows the gateway to be the last ip in an IPv6 range", func() {
+					err := validator.Validate(Manifest{
+						Networks: []Network{
+							{
+								Type: "manual",
+								Subnets: []Subnet{{
+									Range:   "2001:db8::/120",
+									Gateway: "2001:db8::ff",
+								}},
+							},
+						},
+					}, validReleaseSetManifest)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).ToNot(ContainSubstring("broadcast address"))
+					Expect(err.Error()).ToNot(ContainSubstring("subnet gateway"))
+				})
+
+				It("validates that the gateway is of the same address family as the range", func() {
+					err := validator.Validate(Manifest{
+						Networks: []Network{
+							{
+								Type: "manual",
+								Subnets: []Subnet{{
+									Range:   "2001:db8::/64",
+									Gateway: "10.10.0.1",
+								}},
+							},
+						},
+					}, validReleaseSetManifest)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("subnet gateway '10.10.0.1' must be within the specified range '2001:db8::/64'"))
+				})
 			})
 
 			Context("dynamic networks", func() {
@@ -567,17 +708,101 @@ var _ = Describe("Validator", func() {
 			})
 		})
 
-		It("validates that there is only one job", func() {
+		It("validates that job names are unique", func() {
 			deploymentManifest := Manifest{
 				Jobs: []Job{
-					{},
-					{},
+					{Name: "fake-job-name"},
+					{Name: "fake-job-name"},
 				},
 			}
 
 			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("jobs must be of size 1"))
+			Expect(err.Error()).To(ContainSubstring("jobs[1].name 'fake-job-name' must be unique"))
+		})
+
+		Context("when there are multiple jobs and instances", func() {
+			var deploymentManifest Manifest
+
+			BeforeEach(func() {
+				deploymentManifest = validManifest
+				deploymentManifest.Jobs = []Job{
+					validManifest.Jobs[0],
+					validManifest.Jobs[0],
+				}
+				deploymentManifest.Jobs[0].Instances = 1
+				deploymentManifest.Jobs[1].Name = "fake-other-job-name"
+				deploymentManifest.Jobs[1].Instances = 2
+				deploymentManifest.Jobs[1].Networks = []JobNetwork{
+					{
+						Name:      "fake-network-name",
+						Defaults:  []NetworkDefault{NetworkDefaultDNS, NetworkDefaultGateway},
+						StaticIPs: []string{"10.10.0.42", "10.10.0.43"},
+					},
+				}
+			})
+
+			It("does not error when every other instance has a static IP", func() {
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("validates that every instance other than the bootstrap instance has a static IP", func() {
+				deploymentManifest.Jobs[1].Networks[0].StaticIPs = []string{}
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[1].networks must have static_ips for each instance when deploying more than one instance"))
+			})
+
+			It("does not require static IPs when they can be allocated from a static range", func() {
+				deploymentManifest.Networks = []Network{
+					{
+						Name: "fake-network-name",
+						Type: "manual",
+						Subnets: []Subnet{
+							{
+								Range:   "10.10.0.0/24",
+								Gateway: "10.10.0.1",
+								Static:  []string{"10.10.0.10 - 10.10.0.20"},
+							},
+						},
+					},
+				}
+				deploymentManifest.Jobs[1].Networks[0].StaticIPs = []string{}
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("validates that a static IP is not used by more than one instance", func() {
+				deploymentManifest.Jobs[1].Networks[0].StaticIPs = []string{"10.10.0.42", "10.10.0.42"}
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[1].networks[0] static ip '10.10.0.42' must not be used by more than one instance"))
+			})
+
+			It("validates that static IPs are given for each instance", func() {
+				deploymentManifest.Jobs[1].Networks[0].StaticIPs = []string{"10.10.0.42"}
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[1].networks[0].static_ips must have an IP for each of the 2 instances"))
+			})
+
+			It("validates that all jobs use the same stemcell", func() {
+				deploymentManifest.ResourcePools = append(deploymentManifest.ResourcePools, ResourcePool{
+					Name:     "fake-other-resource-pool-name",
+					Network:  "fake-network-name",
+					Stemcell: StemcellRef{URL: "file://fake-other-stemcell-url"},
+				})
+				deploymentManifest.Jobs[1].ResourcePool = "fake-other-resource-pool-name"
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[1].resource_pool must use the same stemcell as jobs[0]"))
+			})
 		})
 
 		It("validates job name", func() {
@@ -671,9 +896,18 @@ var _ = Describe("Validator", func() {
 			Expect(err.Error()).To(ContainSubstring("jobs[0].resource_pool must be the name of a resource pool"))
 		})
 
+		It("validates jobs are given", func() {
+			err := validator.Validate(Manifest{}, validReleaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("jobs must be a non-empty array"))
+		})
+
 		It("validates job instances", func() {
 			deploymentManifest := Manifest{
 				Jobs: []Job{
+					{
+						Instances: 1,
+					},
 					{
 						Instances: -1234,
 					},
@@ -682,7 +916,18 @@ var _ = Describe("Validator", func() {
 
 			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("jobs[0].instances must be >= 0"))
+			Expect(err.Error()).ToNot(ContainSubstring("jobs[0].instances"))
+			Expect(err.Error()).To(ContainSubstring("jobs[1].instances must be >= 0"))
+		})
+
+		It("validates the first job has an instance, the bootstrap instance", func() {
+			deploymentManifest := validManifest
+			deploymentManifest.Jobs = []Job{validManifest.Jobs[0]}
+			deploymentManifest.Jobs[0].Instances = 0
+
+			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("jobs[0].instances must be > 0"))
 		})
 
 		Describe("job networks", func() {
@@ -841,6 +1086,46 @@ var _ = Describe("Validator", func() {
 
 			})
 
+			It("validates job network static ip is in a static range and not in a reserved range of its subnet", func() {
+				deploymentManifest := Manifest{
+					Networks: []Network{
+						{
+							Name: "fake-network-name",
+							Type: "manual",
+							Subnets: []Subnet{
+								{
+									Range:   "10.10.0.0/24",
+									Gateway: "10.10.0.1",
+								},
+								{
+									Range:    "10.10.1.0/24",
+									Gateway:  "10.10.1.1",
+									Reserved: []string{"10.10.1.2 - 10.10.1.9"},
+									Static:   []string{"10.10.1.10 - 10.10.1.20"},
+								},
+							},
+						},
+					},
+					Jobs: []Job{
+						{
+							Networks: []JobNetwork{
+								{
+									Name:      "fake-network-name",
+									StaticIPs: []string{"10.10.0.2", "10.10.1.5", "10.10.1.30", "10.10.1.15"},
+								},
+							},
+						},
+					},
+				}
+
+				err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).ToNot(ContainSubstring("'10.10.0.2'"))
+				Expect(err.Error()).To(ContainSubstring("jobs[0].networks[0] static ip '10.10.1.5' must not be within a reserved range"))
+				Expect(err.Error()).To(ContainSubstring("jobs[0].networks[0] static ip '10.10.1.30' must be within a static range of its subnet"))
+				Expect(err.Error()).ToNot(ContainSubstring("'10.10.1.15'"))
+			})
+
 			Describe("defaults", func() {
 				var deploymentManifest Manifest
 				Context("with multiple networks", func() {
@@ -936,7 +1221,36 @@ var _ = Describe("Validator", func() {
 
 			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("jobs[0].lifecycle must be 'service' ('errand' not supported)"))
+			Expect(err.Error()).To(ContainSubstring("jobs[0].lifecycle must be 'service' ('errand' not supported for the first job)"))
+
+			deploymentManifest = Manifest{
+				Jobs: []Job{
+					{},
+					{
+						Lifecycle: "fake-lifecycle",
+					},
+				},
+			}
+
+			err = validator.Validate(deploymentManifest, validReleaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("jobs[1].lifecycle must be 'service' or 'errand' ('fake-lifecycle' not supported)"))
+		})
+
+		It("validates errand jobs have exactly one instance", func() {
+			deploymentManifest := Manifest{
+				Jobs: []Job{
+					{},
+					{
+						Lifecycle: "errand",
+						Instances: 2,
+					},
+				},
+			}
+
+			err := validator.Validate(deploymentManifest, validReleaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("jobs[1].instances must be 1 for errand jobs"))
 		})
 
 		It("permits job templates to reference an undeclared release", func() {
@@ -1020,9 +1334,130 @@ var _ = Describe("Validator", func() {
 			fakeRelease.ReleaseJobs = []bireljob.Job{{Name: "fake-job-name"}}
 			releaseManager.Add(fakeRelease)
 
-			err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+			_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("jobs[0].templates[0] must refer to a job in 'fake-release-name', but there is no job named 'fake-other-job-name'"))
 		})
+
+		Context("when the release job spec defines properties", func() {
+			var deploymentManifest Manifest
+
+			BeforeEach(func() {
+				fakeRelease = fakebirel.New("fake-release-name", "1.0")
+				fakeRelease.ReleaseJobs = []bireljob.Job{
+					{
+						Name: "fake-job-name",
+						Properties: map[string]bireljob.PropertyDefinition{
+							"fake-prop-key":              {Default: "fake-default"},
+							"fake-prop-map-key.fake-key": {},
+							"fake-global-key":            {Example: 5},
+							"fake-typed-key":             {Type: "array", Default: biproperty.List{}},
+						},
+					},
+				}
+				releaseManager = birel.NewManager(logger)
+				releaseManager.Add(fakeRelease)
+
+				deploymentManifest = validManifest
+				deploymentManifest.Jobs = []Job{validManifest.Jobs[0]}
+				deploymentManifest.Jobs[0].Properties = biproperty.Map{
+					"fake-prop-key": "fake-prop-value",
+					"fake-prop-map-key": biproperty.Map{
+						"fake-key": "fake-value",
+					},
+				}
+				deploymentManifest.Properties = biproperty.Map{
+					"fake-global-key": 4.5,
+				}
+			})
+
+			It("does not error or warn when the properties match the spec", func() {
+				warnings, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(warnings).To(BeEmpty())
+			})
+
+			It("validates that the job properties are defined by the spec of one of its templates", func() {
+				deploymentManifest.Jobs[0].Properties["fake-prop-map-key"] = biproperty.Map{
+					"fake-key":      "fake-value",
+					"fake-typo-key": "fake-value",
+				}
+
+				_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[0].properties.fake-prop-map-key.fake-typo-key is not defined by any of the jobs in jobs[0].templates"))
+			})
+
+			It("warns about properties without default that are not set in the job or global properties", func() {
+				delete(deploymentManifest.Jobs[0].Properties, "fake-prop-map-key")
+				delete(deploymentManifest.Properties, "fake-global-key")
+
+				warnings, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(warnings).To(ConsistOf(
+					"jobs[0].templates[0] job 'fake-job-name' property 'fake-global-key' is not set and has no default",
+					"jobs[0].templates[0] job 'fake-job-name' property 'fake-prop-map-key.fake-key' is not set and has no default",
+				))
+			})
+
+			It("validates that properties are of the kind of the example or type of the spec", func() {
+				deploymentManifest.Properties["fake-global-key"] = "fake-string"
+				deploymentManifest.Jobs[0].Properties["fake-typed-key"] = biproperty.Map{}
+
+				_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[0].templates[0] job 'fake-job-name' property 'fake-global-key' must be of type 'number'"))
+				Expect(err.Error()).To(ContainSubstring("jobs[0].templates[0] job 'fake-job-name' property 'fake-typed-key' must be of type 'array'"))
+			})
+
+			It("prefers job properties over global properties", func() {
+				deploymentManifest.Properties["fake-global-key"] = "fake-string"
+				deploymentManifest.Jobs[0].Properties["fake-global-key"] = 3
+
+				_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).ToNot(HaveOccurred())
+			})
+		})
+
+		Context("when the release job spec consumes links", func() {
+			var deploymentManifest Manifest
+
+			BeforeEach(func() {
+				fakeRelease = fakebirel.New("fake-release-name", "1.0")
+				fakeRelease.ReleaseJobs = []bireljob.Job{
+					{
+						Name:     "fake-job-name",
+						Consumes: []bireljob.LinkDefinition{{Name: "fake-link-name", Type: "fake-link-type"}},
+					},
+					{
+						Name:     "fake-provider-job-name",
+						Provides: []bireljob.LinkDefinition{{Name: "fake-link-name", Type: "fake-link-type"}},
+					},
+				}
+				releaseManager = birel.NewManager(logger)
+				releaseManager.Add(fakeRelease)
+
+				deploymentManifest = validManifest
+				deploymentManifest.Jobs = []Job{validManifest.Jobs[0]}
+				deploymentManifest.Jobs[0].Properties = nil
+				deploymentManifest.Jobs[0].Templates = []ReleaseJobRef{
+					{Name: "fake-job-name", Release: "fake-release-name"},
+					{Name: "fake-provider-job-name", Release: "fake-release-name"},
+				}
+			})
+
+			It("does not error when the links are provided by a colocated job", func() {
+				_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("validates that the links are provided by a colocated job", func() {
+				deploymentManifest.Jobs[0].Templates = deploymentManifest.Jobs[0].Templates[:1]
+
+				_, err := validator.ValidateReleaseJobs(deploymentManifest, releaseManager)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("jobs[0].templates[0] job 'fake-job-name' consumes link 'fake-link-name' of type 'fake-link-type', which is not provided by any job in jobs[0].templates"))
+			})
+		})
 	})
 })
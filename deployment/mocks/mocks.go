@@ -4,15 +4,14 @@
 package mocks
 
 import (
+	agentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	blobstore "github.com/cloudfoundry/bosh-init/blobstore"
 	cloud "github.com/cloudfoundry/bosh-init/cloud"
 	deployment "github.com/cloudfoundry/bosh-init/deployment"
 	disk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	instance "github.com/cloudfoundry/bosh-init/deployment/instance"
 	manifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	vm "github.com/cloudfoundry/bosh-init/deployment/vm"
 	manifest0 "github.com/cloudfoundry/bosh-init/installation/manifest"
-	agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	gomock "github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	stemcell "github.com/cloudfoundry/bosh-init/stemcell"
 	ui "github.com/cloudfoundry/bosh-init/ui"
@@ -49,6 +48,26 @@ func (_mr *_MockDeploymentRecorder) Delete(arg0 interface{}) *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Delete", arg0)
 }
 
+func (_m *MockDeployment) Start(_param0 manifest.WatchTime, _param1 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "Start", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDeploymentRecorder) Start(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Start", arg0, arg1)
+}
+
+func (_m *MockDeployment) Stop(_param0 bool, _param1 ui.Stage) error {
+	ret := _m.ctrl.Call(_m, "Stop", _param0, _param1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+func (_mr *_MockDeploymentRecorder) Stop(arg0, arg1 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Stop", arg0, arg1)
+}
+
 // Mock of Factory interface
 type MockFactory struct {
 	ctrl     *gomock.Controller
@@ -101,15 +120,15 @@ func (_m *MockDeployer) EXPECT() *_MockDeployerRecorder {
 	return _m.recorder
 }
 
-func (_m *MockDeployer) Deploy(_param0 cloud.Cloud, _param1 manifest.Manifest, _param2 stemcell.CloudStemcell, _param3 manifest0.Registry, _param4 vm.Manager, _param5 blobstore.Blobstore, _param6 ui.Stage) (deployment.Deployment, error) {
-	ret := _m.ctrl.Call(_m, "Deploy", _param0, _param1, _param2, _param3, _param4, _param5, _param6)
+func (_m *MockDeployer) Deploy(_param0 cloud.Cloud, _param1 manifest.Manifest, _param2 stemcell.CloudStemcell, _param3 manifest0.Registry, _param4 instance.ManagerProvider, _param5 ui.Stage) (deployment.Deployment, error) {
+	ret := _m.ctrl.Call(_m, "Deploy", _param0, _param1, _param2, _param3, _param4, _param5)
 	ret0, _ := ret[0].(deployment.Deployment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-func (_mr *_MockDeployerRecorder) Deploy(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "Deploy", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+func (_mr *_MockDeployerRecorder) Deploy(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Deploy", arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // Mock of Manager interface
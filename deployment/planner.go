@@ -0,0 +1,380 @@
+package deployment
+
+import (
+	"fmt"
+	"reflect"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	bicrypto "github.com/cloudfoundry/bosh-init/crypto"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
+)
+
+type ChangeType string
+
+const (
+	ChangeUploadStemcell ChangeType = "upload stemcell"
+	ChangeCreateVM       ChangeType = "create vm"
+	ChangeRecreateVM     ChangeType = "recreate vm"
+	ChangeDeleteVM       ChangeType = "delete vm"
+	ChangeCreateDisk     ChangeType = "create disk"
+	ChangeMigrateDisk    ChangeType = "migrate disk"
+	ChangeRenderJob      ChangeType = "render job"
+	ChangeDeleteStemcell ChangeType = "delete stemcell"
+)
+
+// Change is a single action that deploy would take
+type Change struct {
+	Type        ChangeType
+	Description string
+	Reasons     []string
+}
+
+// Plan is the list of changes that deploy would make, in the order it would make them
+type Plan struct {
+	Changes []Change
+}
+
+func (p Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+// Planner compares a deployment against the deployment state without talking to the CPI.
+// The manifest contents are those after ops files and variables are applied, as for Record.
+// Every instance of the service jobs is planned, the bootstrap instance first.
+type Planner interface {
+	Plan(
+		manifestContents []byte,
+		deploymentManifest bideplmanifest.Manifest,
+		releases []birel.Release,
+		stemcell bistemcell.ExtractedStemcell,
+	) (Plan, error)
+}
+
+type planner struct {
+	deploymentRepo biconfig.DeploymentRepo
+	releaseRepo    biconfig.ReleaseRepo
+	stemcellRepo   biconfig.StemcellRepo
+	instanceRepo   biconfig.InstanceRepo
+	diskRepo       biconfig.DiskRepo
+	sha1Calculator bicrypto.SHA1Calculator
+}
+
+func NewPlanner(
+	deploymentRepo biconfig.DeploymentRepo,
+	releaseRepo biconfig.ReleaseRepo,
+	stemcellRepo biconfig.StemcellRepo,
+	instanceRepo biconfig.InstanceRepo,
+	diskRepo biconfig.DiskRepo,
+	sha1Calculator bicrypto.SHA1Calculator,
+) Planner {
+	return &planner{
+		deploymentRepo: deploymentRepo,
+		releaseRepo:    releaseRepo,
+		stemcellRepo:   stemcellRepo,
+		instanceRepo:   instanceRepo,
+		diskRepo:       diskRepo,
+		sha1Calculator: sha1Calculator,
+	}
+}
+
+func (p *planner) Plan(
+	manifestContents []byte,
+	deploymentManifest bideplmanifest.Manifest,
+	releases []birel.Release,
+	stemcell bistemcell.ExtractedStemcell,
+) (Plan, error) {
+	plan := Plan{Changes: []Change{}}
+
+	manifestChanged, err := p.manifestChanged(manifestContents)
+	if err != nil {
+		return plan, err
+	}
+
+	changedReleases, releaseReasons, err := p.changedReleases(releases)
+	if err != nil {
+		return plan, err
+	}
+
+	stemcellReasons, err := p.stemcellChanges(stemcell)
+	if err != nil {
+		return plan, err
+	}
+
+	reasons := []string{}
+	if manifestChanged {
+		reasons = append(reasons, "deployment manifest changed")
+	}
+	reasons = append(reasons, stemcellReasons...)
+	reasons = append(reasons, releaseReasons...)
+
+	if len(reasons) == 0 {
+		return plan, nil
+	}
+
+	stemcellManifest := stemcell.Manifest()
+	stemcellRecord, stemcellUploaded, err := p.stemcellRepo.Find(stemcellManifest.Name, stemcellManifest.Version)
+	if err != nil {
+		return plan, bosherr.WrapError(err, "Finding stemcell record")
+	}
+
+	if !stemcellUploaded {
+		plan.Changes = append(plan.Changes, Change{
+			Type:        ChangeUploadStemcell,
+			Description: fmt.Sprintf("Upload stemcell '%s/%s'", stemcellManifest.Name, stemcellManifest.Version),
+		})
+	}
+
+	instanceChanges, err := p.instanceChanges(deploymentManifest, reasons, manifestChanged, changedReleases)
+	if err != nil {
+		return plan, err
+	}
+	plan.Changes = append(plan.Changes, instanceChanges...)
+
+	stemcellRecords, err := p.stemcellRepo.All()
+	if err != nil {
+		return plan, bosherr.WrapError(err, "Getting all stemcell records")
+	}
+
+	for _, record := range stemcellRecords {
+		if stemcellUploaded && record.ID == stemcellRecord.ID {
+			continue
+		}
+
+		plan.Changes = append(plan.Changes, Change{
+			Type:        ChangeDeleteStemcell,
+			Description: fmt.Sprintf("Delete unused stemcell '%s/%s' (cid=%s)", record.Name, record.Version, record.CID),
+		})
+	}
+
+	return plan, nil
+}
+
+// instanceChanges plans the instances in the order deploy creates them, then the deletion of the VMs
+// of recorded instances that are no longer in the manifest
+func (p *planner) instanceChanges(
+	deploymentManifest bideplmanifest.Manifest,
+	reasons []string,
+	manifestChanged bool,
+	changedReleases map[string]bool,
+) ([]Change, error) {
+	changes := []Change{}
+
+	bootstrapRecord, err := p.instanceRepo.FindBootstrap()
+	if err != nil {
+		return changes, bosherr.WrapError(err, "Finding bootstrap instance")
+	}
+
+	instanceRecords, err := p.instanceRepo.All()
+	if err != nil {
+		return changes, bosherr.WrapError(err, "Finding instances")
+	}
+
+	planned := map[string]bool{}
+	for jobIdx, job := range deploymentManifest.Jobs {
+		if job.Lifecycle == bideplmanifest.JobLifecycleErrand {
+			continue
+		}
+
+		for index := 0; index < job.Instances; index++ {
+			record := findInstanceRecord(instanceRecords, job.Name, index)
+			if jobIdx == 0 && index == 0 {
+				record = bootstrapRecord
+			}
+			planned[record.VMCID] = true
+
+			if record.VMCID != "" {
+				changes = append(changes, Change{
+					Type:        ChangeRecreateVM,
+					Description: fmt.Sprintf("Recreate vm '%s' for instance '%s/%d'", record.VMCID, job.Name, index),
+					Reasons:     reasons,
+				})
+			} else {
+				changes = append(changes, Change{
+					Type:        ChangeCreateVM,
+					Description: fmt.Sprintf("Create vm for instance '%s/%d'", job.Name, index),
+					Reasons:     reasons,
+				})
+			}
+
+			diskChange, found, err := p.diskChange(deploymentManifest, job.Name, record.DiskID)
+			if err != nil {
+				return changes, err
+			}
+
+			if found {
+				changes = append(changes, diskChange)
+			}
+		}
+
+		for _, template := range job.Templates {
+			if !manifestChanged && !changedReleases[template.Release] {
+				continue
+			}
+
+			changes = append(changes, Change{
+				Type:        ChangeRenderJob,
+				Description: fmt.Sprintf("Render job '%s' from release '%s'", template.Name, template.Release),
+			})
+		}
+	}
+
+	for _, record := range instanceRecords {
+		if record.VMCID == "" || planned[record.VMCID] {
+			continue
+		}
+
+		changes = append(changes, Change{
+			Type:        ChangeDeleteVM,
+			Description: fmt.Sprintf("Delete vm '%s' for instance '%s/%d'", record.VMCID, record.Job, record.Index),
+			Reasons:     []string{"instance removed from deployment manifest"},
+		})
+	}
+
+	return changes, nil
+}
+
+func findInstanceRecord(instanceRecords []biconfig.InstanceRecord, jobName string, index int) biconfig.InstanceRecord {
+	for _, record := range instanceRecords {
+		if record.Job == jobName && record.Index == index {
+			return record
+		}
+	}
+	return biconfig.InstanceRecord{}
+}
+
+func (p *planner) manifestChanged(manifestContents []byte) (bool, error) {
+	manifestSHA1, found, err := p.deploymentRepo.FindCurrent()
+	if err != nil {
+		return false, bosherr.WrapError(err, "Finding sha1 of currently deployed manifest")
+	}
+
+	if !found || manifestSHA1 == "" {
+		return true, nil
+	}
+
+	return manifestSHA1 != p.sha1Calculator.CalculateContents(manifestContents), nil
+}
+
+func (p *planner) changedReleases(releases []birel.Release) (map[string]bool, []string, error) {
+	changed := map[string]bool{}
+	reasons := []string{}
+
+	releaseRecords, err := p.releaseRepo.List()
+	if err != nil {
+		return changed, reasons, bosherr.WrapError(err, "Finding currently deployed releases")
+	}
+
+	recordVersions := map[string]string{}
+	for _, releaseRecord := range releaseRecords {
+		recordVersions[releaseRecord.Name] = releaseRecord.Version
+	}
+
+	releaseNames := map[string]bool{}
+	for _, release := range releases {
+		releaseNames[release.Name()] = true
+
+		version, found := recordVersions[release.Name()]
+		if !found {
+			changed[release.Name()] = true
+			reasons = append(reasons, fmt.Sprintf("release '%s/%s' added", release.Name(), release.Version()))
+		} else if version != release.Version() {
+			changed[release.Name()] = true
+			reasons = append(reasons, fmt.Sprintf("release '%s' changed from '%s' to '%s'", release.Name(), version, release.Version()))
+		}
+	}
+
+	for _, releaseRecord := range releaseRecords {
+		if !releaseNames[releaseRecord.Name] {
+			reasons = append(reasons, fmt.Sprintf("release '%s/%s' removed", releaseRecord.Name, releaseRecord.Version))
+		}
+	}
+
+	return changed, reasons, nil
+}
+
+func (p *planner) stemcellChanges(stemcell bistemcell.ExtractedStemcell) ([]string, error) {
+	stemcellManifest := stemcell.Manifest()
+
+	currentStemcell, found, err := p.stemcellRepo.FindCurrent()
+	if err != nil {
+		return []string{}, bosherr.WrapError(err, "Finding currently deployed stemcell")
+	}
+
+	if !found {
+		return []string{"no stemcell currently deployed"}, nil
+	}
+
+	if currentStemcell.Name != stemcellManifest.Name || currentStemcell.Version != stemcellManifest.Version {
+		return []string{
+			fmt.Sprintf(
+				"stemcell changed from '%s/%s' to '%s/%s'",
+				currentStemcell.Name, currentStemcell.Version,
+				stemcellManifest.Name, stemcellManifest.Version,
+			),
+		}, nil
+	}
+
+	return []string{}, nil
+}
+
+func (p *planner) diskChange(deploymentManifest bideplmanifest.Manifest, jobName string, diskID string) (Change, bool, error) {
+	diskPool, err := deploymentManifest.DiskPool(jobName)
+	if err != nil {
+		return Change{}, false, bosherr.WrapError(err, "Finding disk pool")
+	}
+
+	if diskPool.DiskSize == 0 {
+		return Change{}, false, nil
+	}
+
+	diskRecord, found, err := p.findDisk(diskID)
+	if err != nil {
+		return Change{}, false, err
+	}
+
+	if !found {
+		return Change{
+			Type:        ChangeCreateDisk,
+			Description: fmt.Sprintf("Create persistent disk of size %d", diskPool.DiskSize),
+		}, true, nil
+	}
+
+	reasons := []string{}
+	if diskRecord.Size != diskPool.DiskSize {
+		reasons = append(reasons, fmt.Sprintf("size changed from %d to %d", diskRecord.Size, diskPool.DiskSize))
+	}
+	if !reflect.DeepEqual(diskRecord.CloudProperties, diskPool.CloudProperties) {
+		reasons = append(reasons, "cloud properties changed")
+	}
+
+	if len(reasons) == 0 {
+		return Change{}, false, nil
+	}
+
+	return Change{
+		Type:        ChangeMigrateDisk,
+		Description: fmt.Sprintf("Migrate persistent disk '%s'", diskRecord.CID),
+		Reasons:     reasons,
+	}, true, nil
+}
+
+func (p *planner) findDisk(diskID string) (biconfig.DiskRecord, bool, error) {
+	if diskID == "" {
+		return biconfig.DiskRecord{}, false, nil
+	}
+
+	diskRecords, err := p.diskRepo.All()
+	if err != nil {
+		return biconfig.DiskRecord{}, false, bosherr.WrapError(err, "Finding current disk")
+	}
+
+	for _, diskRecord := range diskRecords {
+		if diskRecord.ID == diskID {
+			return diskRecord, true, nil
+		}
+	}
+	return biconfig.DiskRecord{}, false, nil
+}
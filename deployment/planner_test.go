@@ -0,0 +1,340 @@
+package deployment_test
+
+import (
+	"errors"
+
+	biconfig "github.com/cloudfoundry/bosh-init/config"
+	fakebiconfig "github.com/cloudfoundry/bosh-init/config/fakes"
+	fakebicrypto "github.com/cloudfoundry/bosh-init/crypto/fakes"
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	fakebirel "github.com/cloudfoundry/bosh-init/release/fakes"
+	bistemcell "github.com/cloudfoundry/bosh-init/stemcell"
+
+	. "github.com/cloudfoundry/bosh-init/deployment"
+)
+
+var _ = Describe("Planner", func() {
+	var (
+		deploymentRepo     *fakebiconfig.FakeDeploymentRepo
+		releaseRepo        *fakebiconfig.FakeReleaseRepo
+		stemcellRepo       *fakebiconfig.FakeStemcellRepo
+		instanceRepo       *fakebiconfig.FakeInstanceRepo
+		diskRepo           *fakebiconfig.FakeDiskRepo
+		fakeSHA1Calculator *fakebicrypto.FakeSha1Calculator
+
+		deploymentManifest bideplmanifest.Manifest
+		releases           []birel.Release
+		stemcell           bistemcell.ExtractedStemcell
+
+		planner Planner
+	)
+
+	BeforeEach(func() {
+		deploymentRepo = fakebiconfig.NewFakeDeploymentRepo()
+		releaseRepo = &fakebiconfig.FakeReleaseRepo{}
+		stemcellRepo = fakebiconfig.NewFakeStemcellRepo()
+		instanceRepo = fakebiconfig.NewFakeInstanceRepo()
+		diskRepo = fakebiconfig.NewFakeDiskRepo()
+		fakeSHA1Calculator = fakebicrypto.NewFakeSha1Calculator()
+
+		deploymentManifest = bideplmanifest.Manifest{
+			Name: "fake-deployment-name",
+			Jobs: []bideplmanifest.Job{
+				{
+					Name:      "fake-job-name",
+					Instances: 1,
+					Templates: []bideplmanifest.ReleaseJobRef{
+						{Name: "fake-template-1", Release: "fake-release-name"},
+						{Name: "fake-template-2", Release: "fake-other-release-name"},
+					},
+					PersistentDiskPool: "fake-disk-pool-name",
+				},
+			},
+			DiskPools: []bideplmanifest.DiskPool{
+				{
+					Name:            "fake-disk-pool-name",
+					DiskSize:        1024,
+					CloudProperties: biproperty.Map{"fake-disk-property": "fake-disk-value"},
+				},
+			},
+		}
+
+		releases = []birel.Release{
+			&fakebirel.FakeRelease{ReleaseName: "fake-release-name", ReleaseVersion: "2"},
+			&fakebirel.FakeRelease{ReleaseName: "fake-other-release-name", ReleaseVersion: "1"},
+		}
+
+		stemcell = bistemcell.NewExtractedStemcell(
+			bistemcell.Manifest{
+				Name:    "fake-stemcell-name",
+				Version: "fake-stemcell-version",
+			},
+			"fake-extracted-path",
+			fakesys.NewFakeFileSystem(),
+		)
+
+		stemcellRepo.SetFindBehavior("fake-stemcell-name", "fake-stemcell-version", biconfig.StemcellRecord{}, false, nil)
+
+		fakeSHA1Calculator.SetCalculateContentsBehavior(map[string]string{
+			"fake-manifest-contents": "fake-manifest-sha1",
+		})
+
+		planner = NewPlanner(deploymentRepo, releaseRepo, stemcellRepo, instanceRepo, diskRepo, fakeSHA1Calculator)
+	})
+
+	changeTypes := func(plan Plan) []ChangeType {
+		types := []ChangeType{}
+		for _, change := range plan.Changes {
+			types = append(types, change.Type)
+		}
+		return types
+	}
+
+	Context("when nothing has been deployed", func() {
+		It("plans to upload the stemcell, create the vm & disk and render all jobs", func() {
+			plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(changeTypes(plan)).To(Equal([]ChangeType{
+				ChangeUploadStemcell,
+				ChangeCreateVM,
+				ChangeCreateDisk,
+				ChangeRenderJob,
+				ChangeRenderJob,
+			}))
+			Expect(plan.Changes[0].Description).To(Equal("Upload stemcell 'fake-stemcell-name/fake-stemcell-version'"))
+			Expect(plan.Changes[1].Description).To(Equal("Create vm for instance 'fake-job-name/0'"))
+			Expect(plan.Changes[2].Description).To(Equal("Create persistent disk of size 1024"))
+			Expect(plan.Changes[3].Description).To(Equal("Render job 'fake-template-1' from release 'fake-release-name'"))
+			Expect(plan.Changes[4].Description).To(Equal("Render job 'fake-template-2' from release 'fake-other-release-name'"))
+		})
+	})
+
+	Context("when a previous deployment exists", func() {
+		var currentStemcell biconfig.StemcellRecord
+
+		BeforeEach(func() {
+			deploymentRepo.SetFindCurrentBehavior("fake-manifest-sha1", true, nil)
+			releaseRepo.ListReturns([]biconfig.ReleaseRecord{
+				{ID: "fake-release-id-1", Name: "fake-release-name", Version: "2"},
+				{ID: "fake-release-id-2", Name: "fake-other-release-name", Version: "1"},
+			}, nil)
+
+			currentStemcell = biconfig.StemcellRecord{
+				ID:      "fake-stemcell-id",
+				Name:    "fake-stemcell-name",
+				Version: "fake-stemcell-version",
+				CID:     "fake-stemcell-cid",
+			}
+			stemcellRepo.SetFindCurrentBehavior(currentStemcell, true, nil)
+			stemcellRepo.SetFindBehavior("fake-stemcell-name", "fake-stemcell-version", currentStemcell, true, nil)
+			stemcellRepo.AllStemcellRecords = []biconfig.StemcellRecord{currentStemcell}
+
+			instanceRepo.SetFindBootstrapBehavior(biconfig.InstanceRecord{
+				Job:    "fake-job-name",
+				Index:  0,
+				VMCID:  "fake-vm-cid",
+				DiskID: "fake-disk-id",
+			}, nil)
+			diskRepo.SetAllBehavior([]biconfig.DiskRecord{
+				{
+					ID:              "fake-disk-id",
+					CID:             "fake-disk-cid",
+					Size:            1024,
+					CloudProperties: biproperty.Map{"fake-disk-property": "fake-disk-value"},
+				},
+			}, nil)
+		})
+
+		Context("when nothing has changed", func() {
+			It("returns an empty plan", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(plan.HasChanges()).To(BeFalse())
+			})
+		})
+
+		Context("when the manifest has changed", func() {
+			BeforeEach(func() {
+				deploymentRepo.SetFindCurrentBehavior("fake-old-manifest-sha1", true, nil)
+			})
+
+			It("plans to recreate the vm and render all jobs", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(plan.Changes).To(Equal([]Change{
+					{
+						Type:        ChangeRecreateVM,
+						Description: "Recreate vm 'fake-vm-cid' for instance 'fake-job-name/0'",
+						Reasons:     []string{"deployment manifest changed"},
+					},
+					{
+						Type:        ChangeRenderJob,
+						Description: "Render job 'fake-template-1' from release 'fake-release-name'",
+					},
+					{
+						Type:        ChangeRenderJob,
+						Description: "Render job 'fake-template-2' from release 'fake-other-release-name'",
+					},
+				}))
+			})
+		})
+
+		Context("when a release has changed", func() {
+			BeforeEach(func() {
+				releaseRepo.ListReturns([]biconfig.ReleaseRecord{
+					{ID: "fake-release-id-1", Name: "fake-release-name", Version: "1"},
+					{ID: "fake-release-id-2", Name: "fake-other-release-name", Version: "1"},
+				}, nil)
+			})
+
+			It("plans to recreate the vm and render only the jobs from the changed release", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(plan.Changes).To(Equal([]Change{
+					{
+						Type:        ChangeRecreateVM,
+						Description: "Recreate vm 'fake-vm-cid' for instance 'fake-job-name/0'",
+						Reasons:     []string{"release 'fake-release-name' changed from '1' to '2'"},
+					},
+					{
+						Type:        ChangeRenderJob,
+						Description: "Render job 'fake-template-1' from release 'fake-release-name'",
+					},
+				}))
+			})
+		})
+
+		Context("when the stemcell has changed", func() {
+			BeforeEach(func() {
+				stemcellRepo.SetFindBehavior("fake-stemcell-name", "fake-stemcell-version", biconfig.StemcellRecord{}, false, nil)
+				stemcellRepo.SetFindCurrentBehavior(biconfig.StemcellRecord{
+					ID:      "fake-old-stemcell-id",
+					Name:    "fake-stemcell-name",
+					Version: "fake-old-stemcell-version",
+					CID:     "fake-old-stemcell-cid",
+				}, true, nil)
+				stemcellRepo.AllStemcellRecords = []biconfig.StemcellRecord{
+					{
+						ID:      "fake-old-stemcell-id",
+						Name:    "fake-stemcell-name",
+						Version: "fake-old-stemcell-version",
+						CID:     "fake-old-stemcell-cid",
+					},
+				}
+			})
+
+			It("plans to upload the new stemcell, recreate the vm and delete the unused stemcell", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(plan.Changes).To(Equal([]Change{
+					{
+						Type:        ChangeUploadStemcell,
+						Description: "Upload stemcell 'fake-stemcell-name/fake-stemcell-version'",
+					},
+					{
+						Type:        ChangeRecreateVM,
+						Description: "Recreate vm 'fake-vm-cid' for instance 'fake-job-name/0'",
+						Reasons:     []string{"stemcell changed from 'fake-stemcell-name/fake-old-stemcell-version' to 'fake-stemcell-name/fake-stemcell-version'"},
+					},
+					{
+						Type:        ChangeDeleteStemcell,
+						Description: "Delete unused stemcell 'fake-stemcell-name/fake-old-stemcell-version' (cid=fake-old-stemcell-cid)",
+					},
+				}))
+			})
+		})
+
+		Context("when the deployment has several instances", func() {
+			BeforeEach(func() {
+				deploymentRepo.SetFindCurrentBehavior("fake-old-manifest-sha1", true, nil)
+
+				deploymentManifest.Jobs[0].Instances = 2
+				deploymentManifest.Jobs = append(deploymentManifest.Jobs, bideplmanifest.Job{
+					Name:      "fake-other-job-name",
+					Instances: 1,
+				}, bideplmanifest.Job{
+					Name:      "fake-errand-name",
+					Instances: 1,
+					Lifecycle: bideplmanifest.JobLifecycleErrand,
+				})
+
+				instanceRepo.SetAllBehavior([]biconfig.InstanceRecord{
+					{Job: "fake-job-name", Index: 1, VMCID: "fake-vm-cid-1", DiskID: "fake-disk-id-1"},
+					{Job: "fake-removed-job-name", Index: 0, VMCID: "fake-removed-vm-cid"},
+				}, nil)
+				diskRepo.SetAllBehavior([]biconfig.DiskRecord{
+					{
+						ID:              "fake-disk-id",
+						CID:             "fake-disk-cid",
+						Size:            1024,
+						CloudProperties: biproperty.Map{"fake-disk-property": "fake-disk-value"},
+					},
+					{
+						ID:              "fake-disk-id-1",
+						CID:             "fake-disk-cid-1",
+						Size:            512,
+						CloudProperties: biproperty.Map{"fake-disk-property": "fake-disk-value"},
+					},
+				}, nil)
+			})
+
+			It("plans every instance of the service jobs and deletes the vms of removed instances", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+
+				descriptions := []string{}
+				for _, change := range plan.Changes {
+					descriptions = append(descriptions, change.Description)
+				}
+				Expect(descriptions).To(Equal([]string{
+					"Recreate vm 'fake-vm-cid' for instance 'fake-job-name/0'",
+					"Recreate vm 'fake-vm-cid-1' for instance 'fake-job-name/1'",
+					"Migrate persistent disk 'fake-disk-cid-1'",
+					"Render job 'fake-template-1' from release 'fake-release-name'",
+					"Render job 'fake-template-2' from release 'fake-other-release-name'",
+					"Create vm for instance 'fake-other-job-name/0'",
+					"Delete vm 'fake-removed-vm-cid' for instance 'fake-removed-job-name/0'",
+				}))
+			})
+		})
+
+		Context("when the disk pool has changed", func() {
+			BeforeEach(func() {
+				deploymentRepo.SetFindCurrentBehavior("fake-old-manifest-sha1", true, nil)
+				deploymentManifest.DiskPools[0].DiskSize = 2048
+			})
+
+			It("plans to migrate the disk", func() {
+				plan, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(plan.Changes).To(ContainElement(Change{
+					Type:        ChangeMigrateDisk,
+					Description: "Migrate persistent disk 'fake-disk-cid'",
+					Reasons:     []string{"size changed from 1024 to 2048"},
+				}))
+			})
+		})
+	})
+
+	Context("when finding the bootstrap instance fails", func() {
+		BeforeEach(func() {
+			instanceRepo.SetFindBootstrapBehavior(biconfig.InstanceRecord{}, errors.New("fake-find-error"))
+		})
+
+		It("returns an error", func() {
+			_, err := planner.Plan([]byte("fake-manifest-contents"), deploymentManifest, releases, stemcell)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("fake-find-error"))
+		})
+	})
+})
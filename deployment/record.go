@@ -9,9 +9,11 @@ import (
 )
 
 type Record interface {
-	IsDeployed(manifestPath string, releases []birel.Release, stemcell bistemcell.ExtractedStemcell) (bool, error)
+	// IsDeployed and Update take the manifest contents after ops files and variables are applied,
+	// so that a change of the variables or ops files alone is a change of the deployment
+	IsDeployed(manifestContents []byte, releases []birel.Release, stemcell bistemcell.ExtractedStemcell) (bool, error)
 	Clear() error
-	Update(manifestPath string, releases []birel.Release) error
+	Update(manifestContents []byte, releases []birel.Release) error
 }
 
 type deploymentRecord struct {
@@ -35,7 +37,7 @@ func NewRecord(
 	}
 }
 
-func (v *deploymentRecord) IsDeployed(manifestPath string, releases []birel.Release, stemcell bistemcell.ExtractedStemcell) (bool, error) {
+func (v *deploymentRecord) IsDeployed(manifestContents []byte, releases []birel.Release, stemcell bistemcell.ExtractedStemcell) (bool, error) {
 	manifestSHA1, found, err := v.deploymentRepo.FindCurrent()
 	if err != nil {
 		return false, bosherr.WrapError(err, "Finding sha1 of currently deployed manifest")
@@ -45,12 +47,7 @@ func (v *deploymentRecord) IsDeployed(manifestPath string, releases []birel.Rele
 		return false, nil
 	}
 
-	newSHA1, err := v.sha1Calculator.Calculate(manifestPath)
-	if err != nil {
-		return false, bosherr.WrapError(err, "Calculating sha1 of current deployment manifest")
-	}
-
-	if manifestSHA1 != newSHA1 {
+	if manifestSHA1 != v.sha1Calculator.CalculateContents(manifestContents) {
 		return false, nil
 	}
 
@@ -110,13 +107,8 @@ func (v *deploymentRecord) Clear() error {
 	return nil
 }
 
-func (v *deploymentRecord) Update(manifestPath string, releases []birel.Release) error {
-	manifestSHA1, err := v.sha1Calculator.Calculate(manifestPath)
-	if err != nil {
-		return bosherr.WrapError(err, "Calculating sha1 of current deployment manifest")
-	}
-
-	err = v.deploymentRepo.UpdateCurrent(manifestSHA1)
+func (v *deploymentRecord) Update(manifestContents []byte, releases []birel.Release) error {
+	err := v.deploymentRepo.UpdateCurrent(v.sha1Calculator.CalculateContents(manifestContents))
 	if err != nil {
 		return bosherr.WrapError(err, "Saving sha1 of deployed manifest")
 	}
@@ -63,11 +63,8 @@ func rootDesc() {
 			stemcellRepo.SetFindCurrentBehavior(stemcellRecord, true, nil)
 
 			deploymentRepo.SetFindCurrentBehavior("fake-manifest-sha1", true, nil)
-			fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-				"fake-manifest-path": fakebicrypto.CalculateInput{
-					Sha1: "fake-manifest-sha1",
-					Err:  nil,
-				},
+			fakeSHA1Calculator.SetCalculateContentsBehavior(map[string]string{
+				"fake-manifest-contents": "fake-manifest-sha1",
 			})
 		})
 
@@ -78,7 +75,7 @@ func rootDesc() {
 				})
 
 				It("returns false", func() {
-					isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+					isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(isDeployed).To(BeFalse())
 				})
@@ -95,7 +92,7 @@ func rootDesc() {
 				})
 
 				It("returns true", func() {
-					isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+					isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(isDeployed).To(BeTrue())
 				})
@@ -113,7 +110,7 @@ func rootDesc() {
 				})
 
 				It("returns false", func() {
-					isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+					isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(isDeployed).To(BeFalse())
 				})
@@ -131,7 +128,7 @@ func rootDesc() {
 				})
 
 				It("returns false", func() {
-					isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+					isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 					Expect(err).ToNot(HaveOccurred())
 					Expect(isDeployed).To(BeFalse())
 				})
@@ -170,7 +167,7 @@ func rootDesc() {
 						})
 
 						It("returns true", func() {
-							isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+							isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 							Expect(err).ToNot(HaveOccurred())
 							Expect(isDeployed).To(BeTrue())
 						})
@@ -185,7 +182,7 @@ func rootDesc() {
 						})
 
 						It("returns true", func() {
-							isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+							isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 							Expect(err).ToNot(HaveOccurred())
 							Expect(isDeployed).To(BeTrue())
 						})
@@ -199,7 +196,7 @@ func rootDesc() {
 						})
 
 						It("returns false", func() {
-							isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+							isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 							Expect(err).ToNot(HaveOccurred())
 							Expect(isDeployed).To(BeFalse())
 						})
@@ -214,7 +211,7 @@ func rootDesc() {
 			})
 
 			It("returns an error", func() {
-				_, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				_, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-find-error"))
 			})
@@ -226,36 +223,19 @@ func rootDesc() {
 			})
 
 			It("returns false", func() {
-				isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(isDeployed).To(BeFalse())
 			})
 		})
 
-		Context("when calculating the deployment manifest sha1 fails", func() {
-			BeforeEach(func() {
-				fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-					"fake-manifest-path": fakebicrypto.CalculateInput{
-						Sha1: "",
-						Err:  errors.New("fake-calculate-error"),
-					},
-				})
-			})
-
-			It("returns an error", func() {
-				_, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("fake-calculate-error"))
-			})
-		})
-
 		Context("when a different deployment manifest is currently deployed", func() {
 			BeforeEach(func() {
 				deploymentRepo.SetFindCurrentBehavior("fake-manifest-sha1-2", true, nil)
 			})
 
 			It("returns false", func() {
-				isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(isDeployed).To(BeFalse())
 			})
@@ -267,7 +247,7 @@ func rootDesc() {
 			})
 
 			It("returns an error", func() {
-				_, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				_, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-find-error"))
 			})
@@ -279,7 +259,7 @@ func rootDesc() {
 			})
 
 			It("returns false", func() {
-				isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(isDeployed).To(BeFalse())
 			})
@@ -297,7 +277,7 @@ func rootDesc() {
 			})
 
 			It("returns false", func() {
-				isDeployed, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				isDeployed, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).ToNot(HaveOccurred())
 				Expect(isDeployed).To(BeFalse())
 			})
@@ -309,7 +289,7 @@ func rootDesc() {
 			})
 
 			It("returns an error", func() {
-				_, err := deploymentRecord.IsDeployed("fake-manifest-path", releases, stemcell)
+				_, err := deploymentRecord.IsDeployed([]byte("fake-manifest-contents"), releases, stemcell)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-find-error"))
 			})
@@ -318,62 +298,37 @@ func rootDesc() {
 
 	Describe("Update", func() {
 		BeforeEach(func() {
-			fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-				"fake-manifest-path": fakebicrypto.CalculateInput{
-					Sha1: "fake-manifest-sha1",
-					Err:  nil,
-				},
+			fakeSHA1Calculator.SetCalculateContentsBehavior(map[string]string{
+				"fake-manifest-contents": "fake-manifest-sha1",
 			})
 		})
 
 		It("calculates and updates sha1 of currently deployed manifest", func() {
-			err := deploymentRecord.Update("fake-manifest-path", releases)
+			err := deploymentRecord.Update([]byte("fake-manifest-contents"), releases)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(deploymentRepo.UpdateCurrentManifestSHA1).To(Equal("fake-manifest-sha1"))
 		})
 
 		It("passes the releases to the release repo", func() {
-			err := deploymentRecord.Update("fake-manifest-path", releases)
+			err := deploymentRecord.Update([]byte("fake-manifest-contents"), releases)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(releaseRepo.UpdateCallCount()).To(Equal(1))
 			Expect(releaseRepo.UpdateArgsForCall(0)).To(Equal(releases))
 		})
 
-		Context("when calculating the deployment manifest sha1 fails", func() {
-			BeforeEach(func() {
-				fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-					"fake-manifest-path": fakebicrypto.CalculateInput{
-						Sha1: "",
-						Err:  errors.New("fake-calculate-error"),
-					},
-				})
-			})
-
-			It("returns an error", func() {
-				err := deploymentRecord.Update("fake-manifest-path", releases)
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(ContainSubstring("fake-calculate-error"))
-			})
-
-			It("does not update the release records", func() {
-				deploymentRecord.Update("fake-manifest-path", releases)
-				Expect(releaseRepo.UpdateCallCount()).To(Equal(0))
-			})
-		})
-
 		Context("when updating currently deployed manifest sha1 fails", func() {
 			BeforeEach(func() {
 				deploymentRepo.UpdateCurrentErr = errors.New("fake-update-error")
 			})
 
 			It("returns an error", func() {
-				err := deploymentRecord.Update("fake-manifest-path", releases)
+				err := deploymentRecord.Update([]byte("fake-manifest-contents"), releases)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-update-error"))
 			})
 
 			It("does not update the release records", func() {
-				deploymentRecord.Update("fake-manifest-path", releases)
+				deploymentRecord.Update([]byte("fake-manifest-contents"), releases)
 				Expect(releaseRepo.UpdateCallCount()).To(Equal(0))
 			})
 		})
@@ -384,7 +339,7 @@ func rootDesc() {
 			})
 
 			It("returns an error", func() {
-				err := deploymentRecord.Update("fake-manifest-path", releases)
+				err := deploymentRecord.Update([]byte("fake-manifest-contents"), releases)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-update-error"))
 			})
@@ -0,0 +1,15 @@
+package fakes
+
+type FakeSession struct {
+	RunCommands []string
+	RunErr      error
+}
+
+func NewFakeSession() *FakeSession {
+	return &FakeSession{}
+}
+
+func (s *FakeSession) Run(command string) error {
+	s.RunCommands = append(s.RunCommands, command)
+	return s.RunErr
+}
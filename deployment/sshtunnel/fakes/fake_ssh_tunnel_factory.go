@@ -7,6 +7,9 @@ import (
 type FakeFactory struct {
 	SSHTunnel           bisshtunnel.SSHTunnel
 	NewSSHTunnelOptions bisshtunnel.Options
+
+	SSHSession           bisshtunnel.SSHSession
+	NewSSHSessionOptions bisshtunnel.Options
 }
 
 func NewFakeFactory() *FakeFactory {
@@ -18,3 +21,9 @@ func (f *FakeFactory) NewSSHTunnel(options bisshtunnel.Options) bisshtunnel.SSHT
 
 	return f.SSHTunnel
 }
+
+func (f *FakeFactory) NewSSHSession(options bisshtunnel.Options) bisshtunnel.SSHSession {
+	f.NewSSHSessionOptions = options
+
+	return f.SSHSession
+}
@@ -0,0 +1,114 @@
+package sshtunnel
+
+import (
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/cloudfoundry/bosh-init/internal/golang.org/x/crypto/ssh"
+	"github.com/cloudfoundry/bosh-init/internal/golang.org/x/crypto/ssh/terminal"
+
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+type SSHSession interface {
+	// Run runs the command on the remote server, or opens an interactive shell if the command is empty.
+	Run(command string) error
+}
+
+type sshSession struct {
+	options Options
+	stdin   *os.File
+	stdout  io.Writer
+	stderr  io.Writer
+	logger  boshlog.Logger
+	logTag  string
+}
+
+func (s *sshSession) Run(command string) error {
+	sshConfig, err := newSSHClientConfig(s.options, s.logger, s.logTag)
+	if err != nil {
+		return err
+	}
+
+	remoteAddr := net.JoinHostPort(s.options.Host, strconv.Itoa(s.options.Port))
+	s.logger.Debug(s.logTag, "Dialing remote server at %s", remoteAddr)
+	conn, err := ssh.Dial("tcp", remoteAddr, sshConfig)
+	if err != nil {
+		return bosherr.WrapError(err, "Failed to connect to remote server")
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			s.logger.Warn(s.logTag, "Failed to close ssh connection: %s", err.Error())
+		}
+	}()
+
+	session, err := conn.NewSession()
+	if err != nil {
+		return bosherr.WrapError(err, "Opening ssh session")
+	}
+	defer func() {
+		if err := session.Close(); err != nil && err != io.EOF {
+			s.logger.Warn(s.logTag, "Failed to close ssh session: %s", err.Error())
+		}
+	}()
+
+	session.Stdin = s.stdin
+	session.Stdout = s.stdout
+	session.Stderr = s.stderr
+
+	if command != "" {
+		s.logger.Debug(s.logTag, "Running command on remote server")
+		return s.wrapExitError(session.Run(command), command)
+	}
+
+	stdinFd := int(s.stdin.Fd())
+	if terminal.IsTerminal(stdinFd) {
+		width, height, err := terminal.GetSize(stdinFd)
+		if err != nil {
+			return bosherr.WrapError(err, "Getting terminal size")
+		}
+
+		term := os.Getenv("TERM")
+		if term == "" {
+			term = "xterm"
+		}
+
+		err = session.RequestPty(term, height, width, ssh.TerminalModes{ssh.ECHO: 1})
+		if err != nil {
+			return bosherr.WrapError(err, "Requesting pseudo terminal")
+		}
+
+		state, err := terminal.MakeRaw(stdinFd)
+		if err != nil {
+			return bosherr.WrapError(err, "Setting terminal to raw mode")
+		}
+		defer func() {
+			if err := terminal.Restore(stdinFd, state); err != nil {
+				s.logger.Warn(s.logTag, "Failed to restore terminal: %s", err.Error())
+			}
+		}()
+	}
+
+	s.logger.Debug(s.logTag, "Starting interactive shell on remote server")
+	err = session.Shell()
+	if err != nil {
+		return bosherr.WrapError(err, "Starting interactive shell")
+	}
+
+	return s.wrapExitError(session.Wait(), "shell")
+}
+
+func (s *sshSession) wrapExitError(err error, command string) error {
+	if err == nil {
+		return nil
+	}
+
+	if exitErr, ok := err.(*ssh.ExitError); ok {
+		return bosherr.Errorf("Running '%s' on remote server exited with status %d", command, exitErr.ExitStatus())
+	}
+
+	return bosherr.WrapErrorf(err, "Running '%s' on remote server", command)
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,51 +33,14 @@ type sshTunnel struct {
 }
 
 func (s *sshTunnel) Start(readyErrCh chan<- error, errCh chan<- error) {
-	authMethods := []ssh.AuthMethod{}
-
-	if s.options.PrivateKey != "" {
-		s.logger.Debug(s.logTag, "Reading private key file '%s'", s.options.PrivateKey)
-		keyContents, err := ioutil.ReadFile(s.options.PrivateKey)
-		if err != nil {
-			readyErrCh <- bosherr.WrapErrorf(err, "Reading private key file '%s'", s.options.PrivateKey)
-			return
-		}
-
-		s.logger.Debug(s.logTag, "Parsing private key file '%s'", s.options.PrivateKey)
-		signer, err := ssh.ParsePrivateKey(keyContents)
-		if err != nil {
-			readyErrCh <- bosherr.WrapErrorf(err, "Parsing private key file '%s'", s.options.PrivateKey)
-			return
-		}
-
-		authMethods = append(authMethods, ssh.PublicKeys(signer))
-	}
-
-	if s.options.Password != "" {
-		s.logger.Debug(s.logTag, "Adding password auth method to ssh tunnel config")
-
-		keyboardInteractiveChallenge := func(
-			user,
-			instruction string,
-			questions []string,
-			echos []bool,
-		) (answers []string, err error) {
-			if len(questions) == 0 {
-				return []string{}, nil
-			}
-			return []string{s.options.Password}, nil
-		}
-		authMethods = append(authMethods, ssh.KeyboardInteractive(keyboardInteractiveChallenge))
-		authMethods = append(authMethods, ssh.Password(s.options.Password))
-	}
-
-	sshConfig := &ssh.ClientConfig{
-		User: s.options.User,
-		Auth: authMethods,
+	sshConfig, err := newSSHClientConfig(s.options, s.logger, s.logTag)
+	if err != nil {
+		readyErrCh <- err
+		return
 	}
 
-	s.logger.Debug(s.logTag, "Dialing remote server at %s:%d", s.options.Host, s.options.Port)
-	remoteAddr := fmt.Sprintf("%s:%d", s.options.Host, s.options.Port)
+	remoteAddr := net.JoinHostPort(s.options.Host, strconv.Itoa(s.options.Port))
+	s.logger.Debug(s.logTag, "Dialing remote server at %s", remoteAddr)
 
 	retryStrategy := &SSHRetryStrategy{
 		TimeService:              s.timeService,
@@ -85,7 +49,6 @@ func (s *sshTunnel) Start(readyErrCh chan<- error, errCh chan<- error) {
 	}
 
 	var conn *ssh.Client
-	var err error
 	for i := 0; ; i++ {
 		s.logger.Debug(s.logTag, "Making attempt #%d", i)
 		conn, err = ssh.Dial("tcp", remoteAddr, sshConfig)
@@ -169,6 +132,49 @@ func (s *sshTunnel) Stop() error {
 	return s.remoteListener.Close()
 }
 
+func newSSHClientConfig(options Options, logger boshlog.Logger, logTag string) (*ssh.ClientConfig, error) {
+	authMethods := []ssh.AuthMethod{}
+
+	if options.PrivateKey != "" {
+		logger.Debug(logTag, "Reading private key file '%s'", options.PrivateKey)
+		keyContents, err := ioutil.ReadFile(options.PrivateKey)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Reading private key file '%s'", options.PrivateKey)
+		}
+
+		logger.Debug(logTag, "Parsing private key file '%s'", options.PrivateKey)
+		signer, err := ssh.ParsePrivateKey(keyContents)
+		if err != nil {
+			return nil, bosherr.WrapErrorf(err, "Parsing private key file '%s'", options.PrivateKey)
+		}
+
+		authMethods = append(authMethods, ssh.PublicKeys(signer))
+	}
+
+	if options.Password != "" {
+		logger.Debug(logTag, "Adding password auth method to ssh config")
+
+		keyboardInteractiveChallenge := func(
+			user,
+			instruction string,
+			questions []string,
+			echos []bool,
+		) (answers []string, err error) {
+			if len(questions) == 0 {
+				return []string{}, nil
+			}
+			return []string{options.Password}, nil
+		}
+		authMethods = append(authMethods, ssh.KeyboardInteractive(keyboardInteractiveChallenge))
+		authMethods = append(authMethods, ssh.Password(options.Password))
+	}
+
+	return &ssh.ClientConfig{
+		User: options.User,
+		Auth: authMethods,
+	}, nil
+}
+
 type SSHRetryStrategy struct {
 	ConnectionRefusedTimeout time.Duration
 	AuthFailureTimeout       time.Duration
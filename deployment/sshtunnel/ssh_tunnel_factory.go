@@ -1,6 +1,7 @@
 package sshtunnel
 
 import (
+	"os"
 	"time"
 
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
@@ -24,6 +25,7 @@ func (o Options) IsEmpty() bool {
 
 type Factory interface {
 	NewSSHTunnel(Options) SSHTunnel
+	NewSSHSession(Options) SSHSession
 }
 
 type factory struct {
@@ -48,3 +50,14 @@ func (s *factory) NewSSHTunnel(options Options) SSHTunnel {
 		logTag:                   "sshTunnel",
 	}
 }
+
+func (s *factory) NewSSHSession(options Options) SSHSession {
+	return &sshSession{
+		options: options,
+		stdin:   os.Stdin,
+		stdout:  os.Stdout,
+		stderr:  os.Stderr,
+		logger:  s.logger,
+		logTag:  "sshSession",
+	}
+}
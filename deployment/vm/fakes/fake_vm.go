@@ -3,21 +3,25 @@ package fakes
 import (
 	"time"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	biui "github.com/cloudfoundry/bosh-init/ui"
 )
 
 type FakeVM struct {
 	cid string
 
+	NetworksReturn map[string]biproperty.Map
+
 	ExistsCalled int
 	ExistsFound  bool
 	ExistsErr    error
 
-	AgentClientReturn biagentclient.AgentClient
+	AgentClientReturn boshagentclient.AgentClient
 
 	UpdateDisksInputs []UpdateDisksInput
 	UpdateDisksDisks  []bidisk.Disk
@@ -55,6 +59,10 @@ type FakeVM struct {
 
 	MigrateDiskCalledTimes int
 	MigrateDiskErr         error
+
+	RunErrandCalledTimes int
+	RunErrandResult      biagentclient.ErrandResult
+	RunErrandErr         error
 }
 
 type UpdateDisksInput struct {
@@ -107,12 +115,16 @@ func (vm *FakeVM) CID() string {
 	return vm.cid
 }
 
+func (vm *FakeVM) Networks() map[string]biproperty.Map {
+	return vm.NetworksReturn
+}
+
 func (vm *FakeVM) Exists() (bool, error) {
 	vm.ExistsCalled++
 	return vm.ExistsFound, vm.ExistsErr
 }
 
-func (vm *FakeVM) AgentClient() biagentclient.AgentClient {
+func (vm *FakeVM) AgentClient() boshagentclient.AgentClient {
 	return vm.AgentClientReturn
 }
 
@@ -183,6 +195,12 @@ func (vm *FakeVM) MigrateDisk() error {
 	return vm.MigrateDiskErr
 }
 
+func (vm *FakeVM) RunErrand() (biagentclient.ErrandResult, error) {
+	vm.RunErrandCalledTimes++
+
+	return vm.RunErrandResult, vm.RunErrandErr
+}
+
 func (vm *FakeVM) Stop() error {
 	vm.StopCalled++
 	return vm.StopErr
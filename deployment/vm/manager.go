@@ -1,11 +1,12 @@
 package vm
 
 import (
+	"strconv"
+
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
-	bihttpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
@@ -16,7 +17,7 @@ import (
 
 type Manager interface {
 	FindCurrent() (VM, bool, error)
-	Create(bistemcell.CloudStemcell, bideplmanifest.Manifest) (VM, error)
+	Create(stemcell bistemcell.CloudStemcell, deploymentManifest bideplmanifest.Manifest, jobName string, index int) (VM, error)
 }
 
 type manager struct {
@@ -24,7 +25,7 @@ type manager struct {
 	stemcellRepo       biconfig.StemcellRepo
 	diskDeployer       DiskDeployer
 	agentClient        biagentclient.AgentClient
-	agentClientFactory bihttpagent.AgentClientFactory
+	agentClientFactory biagentclient.AgentClientFactory
 	cloud              bicloud.Cloud
 	uuidGenerator      boshuuid.Generator
 	fs                 boshsys.FileSystem
@@ -67,6 +68,7 @@ func (m *manager) FindCurrent() (VM, bool, error) {
 
 	vm := NewVM(
 		vmCID,
+		nil,
 		m.vmRepo,
 		m.stemcellRepo,
 		m.diskDeployer,
@@ -79,9 +81,8 @@ func (m *manager) FindCurrent() (VM, bool, error) {
 	return vm, true, err
 }
 
-func (m *manager) Create(stemcell bistemcell.CloudStemcell, deploymentManifest bideplmanifest.Manifest) (VM, error) {
-	jobName := deploymentManifest.JobName()
-	networkInterfaces, err := deploymentManifest.NetworkInterfaces(jobName)
+func (m *manager) Create(stemcell bistemcell.CloudStemcell, deploymentManifest bideplmanifest.Manifest, jobName string, index int) (VM, error) {
+	networkInterfaces, err := deploymentManifest.NetworkInterfaces(jobName, index)
 	m.logger.Debug(m.logTag, "Creating VM with network interfaces: %#v", networkInterfaces)
 	if err != nil {
 		return nil, bosherr.WrapError(err, "Getting network spec")
@@ -97,15 +98,15 @@ func (m *manager) Create(stemcell bistemcell.CloudStemcell, deploymentManifest b
 		return nil, bosherr.WrapError(err, "Generating agent ID")
 	}
 
-	cid, err := m.createAndRecordVm(agentID, stemcell, resourcePool, networkInterfaces)
+	cid, networks, err := m.createAndRecordVm(agentID, stemcell, resourcePool, networkInterfaces)
 	if err != nil {
 		return nil, err
 	}
 
 	metadata := bicloud.VMMetadata{
 		Deployment: deploymentManifest.Name,
-		Job:        deploymentManifest.JobName(),
-		Index:      "0",
+		Job:        jobName,
+		Index:      strconv.Itoa(index),
 		Director:   "bosh-init",
 	}
 	err = m.cloud.SetVMMetadata(cid, metadata)
@@ -120,6 +121,7 @@ func (m *manager) Create(stemcell bistemcell.CloudStemcell, deploymentManifest b
 
 	vm := NewVM(
 		cid,
+		networks,
 		m.vmRepo,
 		m.stemcellRepo,
 		m.diskDeployer,
@@ -132,17 +134,17 @@ func (m *manager) Create(stemcell bistemcell.CloudStemcell, deploymentManifest b
 	return vm, nil
 }
 
-func (m *manager) createAndRecordVm(agentID string, stemcell bistemcell.CloudStemcell, resourcePool bideplmanifest.ResourcePool, networkInterfaces map[string]biproperty.Map) (string, error) {
-	cid, err := m.cloud.CreateVM(agentID, stemcell.CID(), resourcePool.CloudProperties, networkInterfaces, resourcePool.Env)
+func (m *manager) createAndRecordVm(agentID string, stemcell bistemcell.CloudStemcell, resourcePool bideplmanifest.ResourcePool, networkInterfaces map[string]biproperty.Map) (string, map[string]biproperty.Map, error) {
+	cid, networks, err := m.cloud.CreateVM(agentID, stemcell.CID(), resourcePool.CloudProperties, networkInterfaces, resourcePool.Env)
 	if err != nil {
-		return "", bosherr.WrapErrorf(err, "Creating vm with stemcell cid '%s'", stemcell.CID())
+		return "", nil, bosherr.WrapErrorf(err, "Creating vm with stemcell cid '%s'", stemcell.CID())
 	}
 
 	// Record vm info immediately so we don't leak it
 	err = m.vmRepo.UpdateCurrent(cid)
 	if err != nil {
-		return "", bosherr.WrapError(err, "Updating current vm record")
+		return "", nil, bosherr.WrapError(err, "Updating current vm record")
 	}
 
-	return cid, nil
+	return cid, networks, nil
 }
@@ -3,6 +3,7 @@ package vm_test
 import (
 	"errors"
 
+	fakebiagentclient "github.com/cloudfoundry/bosh-init/agentclient/fakes"
 	"github.com/cloudfoundry/bosh-init/cloud"
 	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
@@ -10,7 +11,6 @@ import (
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	. "github.com/cloudfoundry/bosh-init/deployment/vm"
 	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
-	fakebiagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/fakes"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
@@ -114,10 +114,11 @@ var _ = Describe("Manager", func() {
 
 	Describe("Create", func() {
 		It("creates a VM", func() {
-			vm, err := manager.Create(stemcell, deploymentManifest)
+			vm, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 			Expect(err).ToNot(HaveOccurred())
 			expectedVM := NewVM(
 				"fake-vm-cid",
+				nil,
 				fakeVMRepo,
 				stemcellRepo,
 				fakeDiskDeployer,
@@ -139,8 +140,20 @@ var _ = Describe("Manager", func() {
 			))
 		})
 
+		It("keeps the networks reported by the cloud on the VM", func() {
+			fakeCloud.CreateVMNetworks = map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{"ip": "10.0.0.5"},
+			}
+
+			vm, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vm.Networks()).To(Equal(map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{"ip": "10.0.0.5"},
+			}))
+		})
+
 		It("sets the vm metadata", func() {
-			_, err := manager.Create(stemcell, deploymentManifest)
+			_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeCloud.SetVMMetadataCid).To(Equal("fake-vm-cid"))
@@ -152,8 +165,18 @@ var _ = Describe("Manager", func() {
 			}))
 		})
 
+		It("sets the index of the instance in the vm metadata", func() {
+			deploymentManifest.Jobs[0].Networks[0].StaticIPs = []string{"fake-ip", "fake-other-ip"}
+
+			_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 1)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fakeCloud.CreateVMInput.NetworksInterfaces["fake-network-name"]["ip"]).To(Equal("fake-other-ip"))
+			Expect(fakeCloud.SetVMMetadataMetadata.Index).To(Equal("1"))
+		})
+
 		It("updates the current vm record", func() {
-			_, err := manager.Create(stemcell, deploymentManifest)
+			_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeVMRepo.UpdateCurrentCID).To(Equal("fake-vm-cid"))
@@ -165,13 +188,13 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := manager.Create(stemcell, deploymentManifest)
+				_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-set-metadata-error"))
 			})
 
 			It("still updates the current vm record", func() {
-				_, err := manager.Create(stemcell, deploymentManifest)
+				_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 				Expect(err).To(HaveOccurred())
 				Expect(fakeVMRepo.UpdateCurrentCID).To(Equal("fake-vm-cid"))
 			})
@@ -184,7 +207,7 @@ var _ = Describe("Manager", func() {
 				})
 				fakeCloud.SetVMMetadataError = notImplementedCloudError
 
-				_, err := manager.Create(stemcell, deploymentManifest)
+				_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 				Expect(err).ToNot(HaveOccurred())
 			})
 		})
@@ -195,7 +218,7 @@ var _ = Describe("Manager", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := manager.Create(stemcell, deploymentManifest)
+				_, err := manager.Create(stemcell, deploymentManifest, "fake-job", 0)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-create-error"))
 			})
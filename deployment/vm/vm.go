@@ -3,14 +3,16 @@ package vm
 import (
 	"time"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	boshretry "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/retrystrategy"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/pivotal-golang/clock"
@@ -19,8 +21,9 @@ import (
 
 type VM interface {
 	CID() string
+	Networks() map[string]biproperty.Map
 	Exists() (bool, error)
-	AgentClient() biagentclient.AgentClient
+	AgentClient() boshagentclient.AgentClient
 	WaitUntilReady(timeout time.Duration, delay time.Duration) error
 	Start() error
 	Stop() error
@@ -32,11 +35,13 @@ type VM interface {
 	Disks() ([]bidisk.Disk, error)
 	UnmountDisk(bidisk.Disk) error
 	MigrateDisk() error
+	RunErrand() (biagentclient.ErrandResult, error)
 	Delete() error
 }
 
 type vm struct {
 	cid          string
+	networks     map[string]biproperty.Map
 	vmRepo       biconfig.VMRepo
 	stemcellRepo biconfig.StemcellRepo
 	diskDeployer DiskDeployer
@@ -47,8 +52,11 @@ type vm struct {
 	logTag       string
 }
 
+// NewVM returns the VM with the given cid.
+// networks are the network settings the CPI reported when it created the VM, or nil.
 func NewVM(
 	cid string,
+	networks map[string]biproperty.Map,
 	vmRepo biconfig.VMRepo,
 	stemcellRepo biconfig.StemcellRepo,
 	diskDeployer DiskDeployer,
@@ -59,6 +67,7 @@ func NewVM(
 ) VM {
 	return &vm{
 		cid:          cid,
+		networks:     networks,
 		vmRepo:       vmRepo,
 		stemcellRepo: stemcellRepo,
 		diskDeployer: diskDeployer,
@@ -74,6 +83,12 @@ func (vm *vm) CID() string {
 	return vm.cid
 }
 
+// Networks returns the network settings the CPI reported when it created the VM, e.g. the IP of a dynamic network.
+// They are nil for CPIs with API version 1, and for VMs created by a previous deploy.
+func (vm *vm) Networks() map[string]biproperty.Map {
+	return vm.networks
+}
+
 func (vm *vm) Exists() (bool, error) {
 	exists, err := vm.cloud.HasVM(vm.cid)
 	if err != nil {
@@ -82,12 +97,12 @@ func (vm *vm) Exists() (bool, error) {
 	return exists, nil
 }
 
-func (vm *vm) AgentClient() biagentclient.AgentClient {
+func (vm *vm) AgentClient() boshagentclient.AgentClient {
 	return vm.agentClient
 }
 
 func (vm *vm) WaitUntilReady(timeout time.Duration, delay time.Duration) error {
-	agentPingRetryable := biagentclient.NewPingRetryable(vm.agentClient)
+	agentPingRetryable := boshagentclient.NewPingRetryable(vm.agentClient)
 	timeService := clock.NewClock() //TODO: inject timeService
 	agentPingRetryStrategy := boshretry.NewTimeoutRetryStrategy(timeout, delay, agentPingRetryable, timeService, vm.logger)
 	return agentPingRetryStrategy.Try()
@@ -132,17 +147,28 @@ func (vm *vm) UpdateDisks(diskPool bideplmanifest.DiskPool, eventLoggerStage biu
 }
 
 func (vm *vm) WaitToBeRunning(maxAttempts int, delay time.Duration) error {
-	agentGetStateRetryable := biagentclient.NewGetStateRetryable(vm.agentClient)
+	agentGetStateRetryable := boshagentclient.NewGetStateRetryable(vm.agentClient)
 	agentGetStateRetryStrategy := boshretry.NewAttemptRetryStrategy(maxAttempts, delay, agentGetStateRetryable, vm.logger)
 	return agentGetStateRetryStrategy.Try()
 }
 
 func (vm *vm) AttachDisk(disk bidisk.Disk) error {
-	err := vm.cloud.AttachDisk(vm.cid, disk.CID())
+	diskHint, err := vm.cloud.AttachDisk(vm.cid, disk.CID())
 	if err != nil {
 		return bosherr.WrapError(err, "Attaching disk in the cloud")
 	}
 
+	// With CPI API version 2, the agent is told where the disk is attached instead of reading it from the registry,
+	// unless the agent is too old to support it
+	if diskHint != nil && vm.cloud.Info().NegotiatedAPIVersion() >= 2 {
+		err = vm.agentClient.AddPersistentDisk(disk.CID(), diskHint)
+		if _, unsupported := err.(biagentclient.UnsupportedMessageError); unsupported {
+			vm.logger.Warn(vm.logTag, "Not sending the disk hint of disk '%s' to the agent: %s", disk.CID(), err.Error())
+		} else if err != nil {
+			return bosherr.WrapError(err, "Adding persistent disk to the agent")
+		}
+	}
+
 	err = vm.agentClient.MountDisk(disk.CID())
 	if err != nil {
 		return bosherr.WrapError(err, "Mounting disk")
@@ -184,6 +210,16 @@ func (vm *vm) MigrateDisk() error {
 	return vm.agentClient.MigrateDisk()
 }
 
+func (vm *vm) RunErrand() (biagentclient.ErrandResult, error) {
+	vm.logger.Debug(vm.logTag, "Running errand")
+	result, err := vm.agentClient.RunErrand()
+	if err != nil {
+		return result, bosherr.WrapError(err, "Running errand")
+	}
+
+	return result, nil
+}
+
 func (vm *vm) Delete() error {
 	deleteErr := vm.cloud.DeleteVM(vm.cid)
 	if deleteErr != nil {
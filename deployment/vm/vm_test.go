@@ -7,21 +7,22 @@ import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 
+	biagentclient "github.com/cloudfoundry/bosh-init/agentclient"
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bidisk "github.com/cloudfoundry/bosh-init/deployment/disk"
 	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
-	biagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
+	boshagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
 	bias "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/applyspec"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
 
+	fakebiagentclient "github.com/cloudfoundry/bosh-init/agentclient/fakes"
 	fakebicloud "github.com/cloudfoundry/bosh-init/cloud/fakes"
 	fakebiconfig "github.com/cloudfoundry/bosh-init/config/fakes"
 	fakebidisk "github.com/cloudfoundry/bosh-init/deployment/disk/fakes"
 	fakebivm "github.com/cloudfoundry/bosh-init/deployment/vm/fakes"
-	fakebiagentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/fakes"
 	fakebiui "github.com/cloudfoundry/bosh-init/ui/fakes"
 )
 
@@ -63,6 +64,7 @@ var _ = Describe("VM", func() {
 		fakeDiskDeployer = fakebivm.NewFakeDiskDeployer()
 		vm = NewVM(
 			"fake-vm-cid",
+			nil,
 			fakeVMRepo,
 			fakeStemcellRepo,
 			fakeDiskDeployer,
@@ -188,9 +190,9 @@ var _ = Describe("VM", func() {
 
 	Describe("WaitToBeRunning", func() {
 		BeforeEach(func() {
-			fakeAgentClient.SetGetStateBehavior(biagentclient.AgentState{JobState: "pending"}, nil)
-			fakeAgentClient.SetGetStateBehavior(biagentclient.AgentState{JobState: "pending"}, nil)
-			fakeAgentClient.SetGetStateBehavior(biagentclient.AgentState{JobState: "running"}, nil)
+			fakeAgentClient.SetGetStateBehavior(boshagentclient.AgentState{JobState: "pending"}, nil)
+			fakeAgentClient.SetGetStateBehavior(boshagentclient.AgentState{JobState: "pending"}, nil)
+			fakeAgentClient.SetGetStateBehavior(boshagentclient.AgentState{JobState: "running"}, nil)
 		})
 
 		It("waits until agent reports state as running", func() {
@@ -222,6 +224,65 @@ var _ = Describe("VM", func() {
 			Expect(fakeAgentClient.MountDiskCID).To(Equal("fake-disk-cid"))
 		})
 
+		It("does not send add persistent disk to the agent when the cloud returns no disk hint", func() {
+			err := vm.AttachDisk(disk)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fakeAgentClient.AddPersistentDiskInputs).To(BeEmpty())
+		})
+
+		Context("when the cloud returns a disk hint", func() {
+			BeforeEach(func() {
+				fakeCloud.CPIInfo = bicloud.CPIInfo{APIVersion: 2}
+				fakeCloud.AttachDiskDiskHint = map[string]interface{}{"path": "/dev/sdc"}
+			})
+
+			It("sends add persistent disk with the disk hint to the agent", func() {
+				err := vm.AttachDisk(disk)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeAgentClient.AddPersistentDiskInputs).To(Equal([]fakebiagentclient.AddPersistentDiskInput{
+					{
+						DiskCID:  "fake-disk-cid",
+						DiskHint: map[string]interface{}{"path": "/dev/sdc"},
+					},
+				}))
+				Expect(fakeAgentClient.MountDiskCID).To(Equal("fake-disk-cid"))
+			})
+
+			It("does not send add persistent disk to the agent when the cloud is called with API version 1", func() {
+				fakeCloud.CPIInfo = bicloud.CPIInfo{APIVersion: 1}
+
+				err := vm.AttachDisk(disk)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(fakeAgentClient.AddPersistentDiskInputs).To(BeEmpty())
+				Expect(fakeAgentClient.MountDiskCID).To(Equal("fake-disk-cid"))
+			})
+
+			Context("when the agent does not support add persistent disk", func() {
+				BeforeEach(func() {
+					fakeAgentClient.SetAddPersistentDiskBehavior(biagentclient.NewUnsupportedMessageError("add_persistent_disk"))
+				})
+
+				It("still sends mount disk to the agent", func() {
+					err := vm.AttachDisk(disk)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(fakeAgentClient.AddPersistentDiskInputs).To(HaveLen(1))
+					Expect(fakeAgentClient.MountDiskCID).To(Equal("fake-disk-cid"))
+				})
+			})
+
+			Context("when adding the persistent disk fails", func() {
+				BeforeEach(func() {
+					fakeAgentClient.SetAddPersistentDiskBehavior(errors.New("fake-add-persistent-disk-error"))
+				})
+
+				It("returns an error", func() {
+					err := vm.AttachDisk(disk)
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("fake-add-persistent-disk-error"))
+				})
+			})
+		})
+
 		Context("when attaching disk to cloud fails", func() {
 			BeforeEach(func() {
 				fakeCloud.AttachDiskErr = errors.New("fake-attach-error")
@@ -415,4 +476,27 @@ var _ = Describe("VM", func() {
 			})
 		})
 	})
+
+	Describe("RunErrand", func() {
+		It("sends run_errand to the agent and returns its result", func() {
+			fakeAgentClient.SetRunErrandBehavior(biagentclient.ErrandResult{ExitStatus: 1, Stdout: "fake-stdout"}, nil)
+
+			result, err := vm.RunErrand()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(biagentclient.ErrandResult{ExitStatus: 1, Stdout: "fake-stdout"}))
+			Expect(fakeAgentClient.RunErrandCalledTimes).To(Equal(1))
+		})
+
+		Context("when running the errand fails", func() {
+			BeforeEach(func() {
+				fakeAgentClient.SetRunErrandBehavior(biagentclient.ErrandResult{}, errors.New("fake-run-errand-error"))
+			})
+
+			It("returns an error", func() {
+				_, err := vm.RunErrand()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("fake-run-errand-error"))
+			})
+		})
+	})
 })
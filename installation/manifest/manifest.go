@@ -1,6 +1,8 @@
 package manifest
 
 import (
+	"time"
+
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 )
 
@@ -10,6 +12,20 @@ type Manifest struct {
 	Properties biproperty.Map
 	Mbus       string
 	Registry   Registry
+
+	// Retries are the retry policies of the CPI methods, by method name or 'default'
+	Retries map[string]RetryPolicy
+
+	// Timeouts are how long calls to the CPI methods may take, by method name or 'default'
+	Timeouts map[string]time.Duration
+}
+
+// RetryPolicy configures how calls to a CPI method that fail with a retryable error are retried.
+// Zero values are inherited from the 'default' policy.
+type RetryPolicy struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
 }
 
 type ReleaseJobRef struct {
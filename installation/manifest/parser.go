@@ -1,6 +1,11 @@
 package manifest
 
 import (
+	"sort"
+	"time"
+
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	biposition "github.com/cloudfoundry/bosh-init/common/position"
 	biutil "github.com/cloudfoundry/bosh-init/common/util"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
@@ -17,6 +22,7 @@ type Parser interface {
 
 type parser struct {
 	fs            boshsys.FileSystem
+	interpolator  biinterpolation.Interpolator
 	uuidGenerator boshuuid.Generator
 	logger        boshlog.Logger
 	logTag        string
@@ -33,20 +39,29 @@ type installation struct {
 	Properties map[interface{}]interface{}
 	SSHTunnel  SSHTunnel `yaml:"ssh_tunnel"`
 	Mbus       string
+	Retries    map[string]retryPolicy
+	Timeouts   map[string]string
 }
 
 func (i installation) HasSSHTunnel() bool {
 	return i.SSHTunnel != SSHTunnel{}
 }
 
+type retryPolicy struct {
+	MaxAttempts  *int   `yaml:"max_attempts"`
+	InitialDelay string `yaml:"initial_delay"`
+	MaxDelay     string `yaml:"max_delay"`
+}
+
 type template struct {
 	Name    string
 	Release string
 }
 
-func NewParser(fs boshsys.FileSystem, uuidGenerator boshuuid.Generator, logger boshlog.Logger, validator Validator) Parser {
+func NewParser(fs boshsys.FileSystem, interpolator biinterpolation.Interpolator, uuidGenerator boshuuid.Generator, logger boshlog.Logger, validator Validator) Parser {
 	return &parser{
 		fs:            fs,
+		interpolator:  interpolator,
 		uuidGenerator: uuidGenerator,
 		logger:        logger,
 		logTag:        "deploymentParser",
@@ -59,13 +74,22 @@ func (p *parser) Parse(path string, releaseSetManifest birelsetmanifest.Manifest
 	if err != nil {
 		return Manifest{}, bosherr.WrapErrorf(err, "Reading file %s", path)
 	}
+	positions := biposition.NewIndex(path, contents)
+
+	contents, err = p.interpolator.Interpolate(contents)
+	if err != nil {
+		return Manifest{}, bosherr.WrapErrorf(err, "Interpolating variables in %s", path)
+	}
+	if p.interpolator.AppliesOps() {
+		positions = positions.Map(contents)
+	}
 
 	comboManifest := manifest{}
 	err = yaml.Unmarshal(contents, &comboManifest)
 	if err != nil {
 		return Manifest{}, bosherr.WrapError(err, "Unmarshalling installation manifest")
 	}
-	p.logger.Debug(p.logTag, "Parsed installation manifest: %#v", comboManifest)
+	p.logger.Debug(p.logTag, "Parsed installation manifest '%s'", path)
 
 	if comboManifest.CloudProvider.SSHTunnel.PrivateKey != "" {
 		comboManifest.CloudProvider.SSHTunnel.PrivateKey, err = biutil.AbsolutifyPath(path, comboManifest.CloudProvider.SSHTunnel.PrivateKey, p.fs)
@@ -83,6 +107,16 @@ func (p *parser) Parse(path string, releaseSetManifest birelsetmanifest.Manifest
 		Mbus: comboManifest.CloudProvider.Mbus,
 	}
 
+	installationManifest.Retries, err = p.parseRetries(comboManifest.CloudProvider.Retries)
+	if err != nil {
+		return Manifest{}, bosherr.WrapError(positions.Annotate(err), "Parsing cloud_provider retries")
+	}
+
+	installationManifest.Timeouts, err = p.parseTimeouts(comboManifest.CloudProvider.Timeouts)
+	if err != nil {
+		return Manifest{}, bosherr.WrapError(positions.Annotate(err), "Parsing cloud_provider timeouts")
+	}
+
 	properties, err := biproperty.BuildMap(comboManifest.CloudProvider.Properties)
 	if err != nil {
 		return Manifest{}, bosherr.WrapErrorf(err, "Parsing cloud_provider manifest properties: %#v", comboManifest.CloudProvider.Properties)
@@ -99,8 +133,90 @@ func (p *parser) Parse(path string, releaseSetManifest birelsetmanifest.Manifest
 
 	err = p.validator.Validate(installationManifest, releaseSetManifest)
 	if err != nil {
-		return Manifest{}, bosherr.WrapError(err, "Validating installation manifest")
+		return Manifest{}, bosherr.WrapError(positions.Annotate(err), "Validating installation manifest")
 	}
 
 	return installationManifest, nil
 }
+
+func (p *parser) parseRetries(rawRetries map[string]retryPolicy) (map[string]RetryPolicy, error) {
+	if len(rawRetries) == 0 {
+		return nil, nil
+	}
+
+	methods := []string{}
+	for method := range rawRetries {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	errs := []error{}
+	retries := map[string]RetryPolicy{}
+	for _, method := range methods {
+		rawPolicy := rawRetries[method]
+		policy := RetryPolicy{}
+
+		if rawPolicy.MaxAttempts != nil {
+			// an explicit 0 would be taken as unset and inherit max_attempts from 'default'
+			if *rawPolicy.MaxAttempts < 1 {
+				errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.max_attempts must be greater than 0", method))
+			}
+			policy.MaxAttempts = *rawPolicy.MaxAttempts
+		}
+
+		var err error
+		policy.InitialDelay, err = p.parseDuration(rawPolicy.InitialDelay)
+		if err != nil {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.initial_delay must be a duration, e.g. '2s'", method))
+		}
+
+		policy.MaxDelay, err = p.parseDuration(rawPolicy.MaxDelay)
+		if err != nil {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.max_delay must be a duration, e.g. '1m'", method))
+		}
+
+		retries[method] = policy
+	}
+
+	if len(errs) > 0 {
+		return nil, bosherr.NewMultiError(errs...)
+	}
+
+	return retries, nil
+}
+
+func (p *parser) parseTimeouts(rawTimeouts map[string]string) (map[string]time.Duration, error) {
+	if len(rawTimeouts) == 0 {
+		return nil, nil
+	}
+
+	methods := []string{}
+	for method := range rawTimeouts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	errs := []error{}
+	timeouts := map[string]time.Duration{}
+	for _, method := range methods {
+		timeout, err := p.parseDuration(rawTimeouts[method])
+		if err != nil {
+			errs = append(errs, bosherr.Errorf("cloud_provider.timeouts.%s must be a duration, e.g. '30m'", method))
+		}
+
+		timeouts[method] = timeout
+	}
+
+	if len(errs) > 0 {
+		return nil, bosherr.NewMultiError(errs...)
+	}
+
+	return timeouts, nil
+}
+
+func (p *parser) parseDuration(duration string) (time.Duration, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(duration)
+}
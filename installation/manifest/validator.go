@@ -1,13 +1,29 @@
 package manifest
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	birelsetmanifest "github.com/cloudfoundry/bosh-init/release/set/manifest"
 )
 
+// cpiMethods are the CPI methods called by bosh-init, which may be given a retry policy or a timeout
+var cpiMethods = []string{
+	"create_stemcell",
+	"delete_stemcell",
+	"create_vm",
+	"delete_vm",
+	"has_vm",
+	"set_vm_metadata",
+	"create_disk",
+	"attach_disk",
+	"detach_disk",
+	"delete_disk",
+}
+
 type Validator interface {
 	Validate(Manifest, birelsetmanifest.Manifest) error
 }
@@ -40,6 +56,9 @@ func (v *validator) Validate(manifest Manifest, releaseSetManifest birelsetmanif
 		errs = append(errs, bosherr.Errorf("cloud_provider.template.release '%s' must refer to a release in releases", cpiReleaseName))
 	}
 
+	errs = append(errs, v.validateRetries(manifest.Retries)...)
+	errs = append(errs, v.validateTimeouts(manifest.Timeouts)...)
+
 	if len(errs) > 0 {
 		return bosherr.NewMultiError(errs...)
 	}
@@ -47,6 +66,71 @@ func (v *validator) Validate(manifest Manifest, releaseSetManifest birelsetmanif
 	return nil
 }
 
+func (v *validator) validateRetries(retries map[string]RetryPolicy) []error {
+	methods := []string{}
+	for method := range retries {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	errs := []error{}
+	for _, method := range methods {
+		policy := retries[method]
+
+		if method != "default" && !v.isCPIMethod(method) {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s must be 'default' or a CPI method: %s", method, strings.Join(cpiMethods, ", ")))
+		}
+
+		if policy.MaxAttempts < 0 {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.max_attempts must not be negative", method))
+		}
+
+		if policy.InitialDelay < 0 {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.initial_delay must not be negative", method))
+		}
+
+		if policy.MaxDelay < 0 {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.max_delay must not be negative", method))
+		}
+
+		if policy.MaxDelay > 0 && policy.InitialDelay > policy.MaxDelay {
+			errs = append(errs, bosherr.Errorf("cloud_provider.retries.%s.initial_delay must not be greater than max_delay", method))
+		}
+	}
+
+	return errs
+}
+
+func (v *validator) validateTimeouts(timeouts map[string]time.Duration) []error {
+	methods := []string{}
+	for method := range timeouts {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	errs := []error{}
+	for _, method := range methods {
+		if method != "default" && method != "info" && !v.isCPIMethod(method) {
+			errs = append(errs, bosherr.Errorf("cloud_provider.timeouts.%s must be 'default', 'info' or a CPI method: %s", method, strings.Join(cpiMethods, ", ")))
+		}
+
+		if timeouts[method] <= 0 {
+			errs = append(errs, bosherr.Errorf("cloud_provider.timeouts.%s must be greater than 0", method))
+		}
+	}
+
+	return errs
+}
+
+func (v *validator) isCPIMethod(method string) bool {
+	for _, cpiMethod := range cpiMethods {
+		if cpiMethod == method {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *validator) isBlank(str string) bool {
 	return str == "" || strings.TrimSpace(str) == ""
 }
@@ -1,6 +1,8 @@
 package manifest_test
 
 import (
+	"time"
+
 	. "github.com/cloudfoundry/bosh-init/installation/manifest"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
@@ -101,5 +103,58 @@ var _ = Describe("Validator", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("cloud_provider.template.release 'not-provided-valid-release-name' must refer to a release in releases"))
 		})
+
+		It("does not error if the retry policies are valid", func() {
+			manifest := validManifest
+			manifest.Retries = map[string]RetryPolicy{
+				"default":   {MaxAttempts: 5},
+				"create_vm": {MaxAttempts: 2, InitialDelay: time.Second, MaxDelay: time.Minute},
+			}
+
+			err := validator.Validate(manifest, releaseSetManifest)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("validates the retry policies", func() {
+			manifest := validManifest
+			manifest.Retries = map[string]RetryPolicy{
+				"create_vm":      {MaxAttempts: -1, InitialDelay: time.Minute, MaxDelay: time.Second},
+				"delete_disk":    {InitialDelay: -time.Second, MaxDelay: -time.Second},
+				"unknown_method": {},
+			}
+
+			err := validator.Validate(manifest, releaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.retries.create_vm.max_attempts must not be negative"))
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.retries.create_vm.initial_delay must not be greater than max_delay"))
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.retries.delete_disk.initial_delay must not be negative"))
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.retries.delete_disk.max_delay must not be negative"))
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.retries.unknown_method must be 'default' or a CPI method: create_stemcell, delete_stemcell, create_vm"))
+		})
+
+		It("does not error if the timeouts are valid", func() {
+			manifest := validManifest
+			manifest.Timeouts = map[string]time.Duration{
+				"default":   5 * time.Minute,
+				"info":      time.Minute,
+				"create_vm": time.Hour,
+			}
+
+			err := validator.Validate(manifest, releaseSetManifest)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("validates the timeouts", func() {
+			manifest := validManifest
+			manifest.Timeouts = map[string]time.Duration{
+				"create_vm":      0,
+				"unknown_method": time.Minute,
+			}
+
+			err := validator.Validate(manifest, releaseSetManifest)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.timeouts.create_vm must be greater than 0"))
+			Expect(err.Error()).To(ContainSubstring("cloud_provider.timeouts.unknown_method must be 'default', 'info' or a CPI method: create_stemcell, delete_stemcell, create_vm"))
+		})
 	})
 })
@@ -43,6 +43,16 @@ func (_mr *_MockInstallationRecorder) Job() *gomock.Call {
 	return _mr.mock.ctrl.RecordCall(_mr.mock, "Job")
 }
 
+func (_m *MockInstallation) Manifest() manifest.Manifest {
+	ret := _m.ctrl.Call(_m, "Manifest")
+	ret0, _ := ret[0].(manifest.Manifest)
+	return ret0
+}
+
+func (_mr *_MockInstallationRecorder) Manifest() *gomock.Call {
+	return _mr.mock.ctrl.RecordCall(_mr.mock, "Manifest")
+}
+
 func (_m *MockInstallation) StartRegistry() error {
 	ret := _m.ctrl.Call(_m, "StartRegistry")
 	ret0, _ := ret[0].(error)
@@ -17,16 +17,16 @@ import (
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega/gbytes"
 
+	mock_agentclient "github.com/cloudfoundry/bosh-init/agentclient/mocks"
 	mock_blobstore "github.com/cloudfoundry/bosh-init/blobstore/mocks"
 	mock_cloud "github.com/cloudfoundry/bosh-init/cloud/mocks"
 	mock_instance_state "github.com/cloudfoundry/bosh-init/deployment/instance/state/mocks"
 	mock_install "github.com/cloudfoundry/bosh-init/installation/mocks"
-	mock_httpagent "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/http/mocks"
-	mock_agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient/mocks"
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
 	mock_release "github.com/cloudfoundry/bosh-init/release/mocks"
 
 	bicloud "github.com/cloudfoundry/bosh-init/cloud"
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
 	biconfig "github.com/cloudfoundry/bosh-init/config"
 	bicpirel "github.com/cloudfoundry/bosh-init/cpi/release"
 	bidepl "github.com/cloudfoundry/bosh-init/deployment"
@@ -46,6 +46,7 @@ import (
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
 	fakeuuid "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/uuid/fakes"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 	biregistry "github.com/cloudfoundry/bosh-init/registry"
 	birel "github.com/cloudfoundry/bosh-init/release"
 	bireljob "github.com/cloudfoundry/bosh-init/release/job"
@@ -84,7 +85,7 @@ var _ = Describe("bosh-init", func() {
 			mockCloudFactory       *mock_cloud.MockFactory
 			mockCloud              *mock_cloud.MockCloud
 			mockAgentClient        *mock_agentclient.MockAgentClient
-			mockAgentClientFactory *mock_httpagent.MockAgentClientFactory
+			mockAgentClientFactory *mock_agentclient.MockAgentClientFactory
 			mockReleaseExtractor   *mock_release.MockExtractor
 
 			mockStateBuilderFactory *mock_instance_state.MockBuilderFactory
@@ -216,8 +217,10 @@ cloud_provider:
 			}
 			updateManifest(context)
 
-			fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-				deploymentManifestPath: {Sha1: "fake-deployment-sha1-1"},
+			manifestContents, err := fs.ReadFileString(deploymentManifestPath)
+			Expect(err).ToNot(HaveOccurred())
+			fakeSHA1Calculator.SetCalculateContentsBehavior(map[string]string{
+				manifestContents: "fake-deployment-sha1-1",
 			})
 		}
 
@@ -229,8 +232,10 @@ cloud_provider:
 			}
 			updateManifest(context)
 
-			fakeSHA1Calculator.SetCalculateBehavior(map[string]fakebicrypto.CalculateInput{
-				deploymentManifestPath: {Sha1: "fake-deployment-sha1-2"},
+			manifestContents, err := fs.ReadFileString(deploymentManifestPath)
+			Expect(err).ToNot(HaveOccurred())
+			fakeSHA1Calculator.SetCalculateContentsBehavior(map[string]string{
+				manifestContents: "fake-deployment-sha1-2",
 			})
 		}
 
@@ -370,18 +375,15 @@ cloud_provider:
 			//TODO: use a real state builder
 
 			mockStateBuilderFactory.EXPECT().NewBuilder(mockBlobstore, mockAgentClient).Return(mockStateBuilder).AnyTimes()
-			mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), gomock.Any()).Return(mockState, nil).AnyTimes()
+			mockStateBuilder.EXPECT().Build(jobName, jobIndex, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockState, nil).AnyTimes()
 			mockState.EXPECT().ToApplySpec().Return(applySpec).AnyTimes()
 		}
 
 		var newDeployCmd = func() Cmd {
-			deploymentParser := bideplmanifest.NewParser(fs, logger)
 			releaseSetValidator := birelsetmanifest.NewValidator(logger)
-			releaseSetParser := birelsetmanifest.NewParser(fs, logger, releaseSetValidator)
 			fakeRegistryUUIDGenerator = fakeuuid.NewFakeGenerator()
 			fakeRegistryUUIDGenerator.GeneratedUUID = "registry-password"
 			installationValidator := biinstallmanifest.NewValidator(logger)
-			installationParser := biinstallmanifest.NewParser(fs, fakeRegistryUUIDGenerator, logger, installationValidator)
 
 			deploymentValidator := bideplmanifest.NewValidator(logger)
 
@@ -393,21 +395,33 @@ cloud_provider:
 			deploymentFactory := bidepl.NewFactory(pingTimeout, pingDelay)
 
 			ui := biui.NewWriterUI(stdOut, stdErr, logger)
-			doGet := func(deploymentManifestPath string) (DeploymentPreparer, error) {
+			doGet := func(deploymentManifestPath string, manifestInterpolator biinterpolation.Interpolator) (DeploymentPreparer, error) {
 				// todo: figure this out?
 				deploymentStateService = biconfig.NewFileSystemDeploymentStateService(fs, fakeUUIDGenerator, logger, biconfig.DeploymentStatePath(deploymentManifestPath))
-				vmRepo = biconfig.NewVMRepo(deploymentStateService)
-				diskRepo = biconfig.NewDiskRepo(deploymentStateService, fakeRepoUUIDGenerator)
+				vmRepo = biconfig.NewVMRepo(deploymentStateService, biconfig.BootstrapInstanceKey())
+				diskRepo = biconfig.NewDiskRepo(deploymentStateService, biconfig.BootstrapInstanceKey(), fakeRepoUUIDGenerator)
 				stemcellRepo = biconfig.NewStemcellRepo(deploymentStateService, fakeRepoUUIDGenerator)
 				deploymentRepo = biconfig.NewDeploymentRepo(deploymentStateService)
 				releaseRepo = biconfig.NewReleaseRepo(deploymentStateService, fakeRepoUUIDGenerator)
 
 				legacyDeploymentStateMigrator = biconfig.NewLegacyDeploymentStateMigrator(deploymentStateService, fs, fakeUUIDGenerator, logger)
 				deploymentRecord := bidepl.NewRecord(deploymentRepo, releaseRepo, stemcellRepo, fakeSHA1Calculator)
+				deploymentPlanner := bidepl.NewPlanner(deploymentRepo, releaseRepo, stemcellRepo, biconfig.NewInstanceRepo(deploymentStateService), diskRepo, fakeSHA1Calculator)
 				stemcellManagerFactory = bistemcell.NewManagerFactory(stemcellRepo)
 				diskManagerFactory = bidisk.NewManagerFactory(diskRepo, logger)
 				diskDeployer = bivm.NewDiskDeployer(diskManagerFactory, diskRepo, logger)
 				vmManagerFactory = bivm.NewManagerFactory(vmRepo, stemcellRepo, diskDeployer, fakeAgentIDGenerator, fs, logger)
+				instanceManagerProviderFactory := biinstance.NewManagerProviderFactory(
+					deploymentStateService,
+					vmManagerFactory,
+					instanceManagerFactory,
+					mockAgentClientFactory,
+					mockBlobstoreFactory,
+					stemcellRepo,
+					fakeAgentIDGenerator,
+					fs,
+					logger,
+				)
 				deployer := bidepl.NewDeployer(
 					vmManagerFactory,
 					instanceManagerFactory,
@@ -429,6 +443,10 @@ cloud_provider:
 					StemcellExtractor: fakeStemcellExtractor,
 				}
 
+				deploymentParser := bideplmanifest.NewParser(fs, manifestInterpolator, logger)
+				releaseSetParser := birelsetmanifest.NewParser(fs, manifestInterpolator, logger, releaseSetValidator)
+				installationParser := biinstallmanifest.NewParser(fs, manifestInterpolator, fakeRegistryUUIDGenerator, logger, installationValidator)
+
 				releaseSetAndInstallationManifestParser := ReleaseSetAndInstallationManifestParser{
 					ReleaseSetParser:   releaseSetParser,
 					InstallationParser: installationParser,
@@ -437,6 +455,7 @@ cloud_provider:
 					DeploymentParser:    deploymentParser,
 					DeploymentValidator: deploymentValidator,
 					ReleaseManager:      releaseManager,
+					UI:                  ui,
 				}
 
 				installationUuidGenerator := fakeuuid.NewFakeGenerator()
@@ -459,11 +478,11 @@ cloud_provider:
 					deploymentRecord,
 					mockCloudFactory,
 					stemcellManagerFactory,
-					mockAgentClientFactory,
-					vmManagerFactory,
-					mockBlobstoreFactory,
+					instanceManagerProviderFactory,
 					deployer,
 					deploymentManifestPath,
+					manifestInterpolator,
+					fs,
 					cpiInstaller,
 					releaseFetcher,
 					stemcellFetcher,
@@ -471,6 +490,8 @@ cloud_provider:
 					deploymentManifestParser,
 					tempRootConfigurator,
 					targetProvider,
+					deploymentPlanner,
+					bidepl.NewIPAllocator(biconfig.NewIPAllocationRepo(deploymentStateService), logger),
 				), nil
 			}
 
@@ -492,7 +513,7 @@ cloud_provider:
 
 			gomock.InOrder(
 				mockCloud.EXPECT().CreateStemcell(stemcellImagePath, stemcellCloudProperties).Return(stemcellCID, nil),
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(vmCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(vmCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(vmCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
@@ -528,7 +549,7 @@ cloud_provider:
 				mockCloud.EXPECT().DeleteVM(oldVMCID),
 
 				// create new vm
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(newVMCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
@@ -567,7 +588,7 @@ cloud_provider:
 				expectDeleteVM1,
 
 				// create new vm
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(newVMCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
@@ -606,12 +627,13 @@ cloud_provider:
 				mockCloud.EXPECT().DeleteVM(oldVMCID),
 
 				// create new vm
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(newVMCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
 				// attaching a missing disk will fail
 				mockCloud.EXPECT().AttachDisk(newVMCID, oldDiskCID).Return(
+					nil,
 					bicloud.NewCPIError("attach_disk", bicloud.CmdError{
 						Type:    bicloud.DiskNotFoundError,
 						Message: "fake-disk-not-found-message",
@@ -639,7 +661,7 @@ cloud_provider:
 				mockCloud.EXPECT().DeleteVM(oldVMCID),
 
 				// create new vm
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(newVMCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
@@ -674,7 +696,7 @@ cloud_provider:
 				mockCloud.EXPECT().DeleteVM(oldVMCID),
 
 				// create new vm
-				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil),
+				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Return(newVMCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(newVMCID, gomock.Any()).Return(nil),
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
 
@@ -730,7 +752,7 @@ cloud_provider:
 				).Return(stemcellCID, nil),
 				mockCloud.EXPECT().CreateVM(agentID, stemcellCID, vmCloudProperties, networkInterfaces, vmEnv).Do(
 					func(_, _, _, _, _ interface{}) { expectRegistryToWork() },
-				).Return(vmCID, nil),
+				).Return(vmCID, nil, nil),
 				mockCloud.EXPECT().SetVMMetadata(vmCID, gomock.Any()).Return(nil),
 
 				mockAgentClient.EXPECT().Ping().Return("any-state", nil),
@@ -781,7 +803,9 @@ cloud_provider:
 
 			mockCloud = mock_cloud.NewMockCloud(mockCtrl)
 
-			registryServerManager = biregistry.NewServerManager(logger)
+			redactor, err := bilog.NewRedactor(nil)
+			Expect(err).ToNot(HaveOccurred())
+			registryServerManager = biregistry.NewServerManager(redactor, logger)
 
 			mockReleaseExtractor = mock_release.NewMockExtractor(mockCtrl)
 			releaseManager = birel.NewManager(logger)
@@ -800,7 +824,7 @@ cloud_provider:
 			stdErr = gbytes.NewBuffer()
 			fakeStage = fakebiui.NewFakeStage()
 
-			mockAgentClientFactory = mock_httpagent.NewMockAgentClientFactory(mockCtrl)
+			mockAgentClientFactory = mock_agentclient.NewMockAgentClientFactory(mockCtrl)
 			mockAgentClient = mock_agentclient.NewMockAgentClient(mockCtrl)
 
 			mockAgentClientFactory.EXPECT().NewAgentClient(directorID, mbusURL).Return(mockAgentClient).AnyTimes()
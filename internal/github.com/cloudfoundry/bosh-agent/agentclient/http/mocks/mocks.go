@@ -1,40 +0,0 @@
-// Automatically generated by MockGen. DO NOT EDIT!
-// Source: agent_client_factory.go
-
-package mocks
-
-import (
-	agentclient "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-agent/agentclient"
-	gomock "github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
-)
-
-// Mock of AgentClientFactory interface
-type MockAgentClientFactory struct {
-	ctrl     *gomock.Controller
-	recorder *_MockAgentClientFactoryRecorder
-}
-
-// Recorder for MockAgentClientFactory (not exported)
-type _MockAgentClientFactoryRecorder struct {
-	mock *MockAgentClientFactory
-}
-
-func NewMockAgentClientFactory(ctrl *gomock.Controller) *MockAgentClientFactory {
-	mock := &MockAgentClientFactory{ctrl: ctrl}
-	mock.recorder = &_MockAgentClientFactoryRecorder{mock}
-	return mock
-}
-
-func (_m *MockAgentClientFactory) EXPECT() *_MockAgentClientFactoryRecorder {
-	return _m.recorder
-}
-
-func (_m *MockAgentClientFactory) NewAgentClient(directorID string, mbusURL string) agentclient.AgentClient {
-	ret := _m.ctrl.Call(_m, "NewAgentClient", directorID, mbusURL)
-	ret0, _ := ret[0].(agentclient.AgentClient)
-	return ret0
-}
-
-func (_mr *_MockAgentClientFactoryRecorder) NewAgentClient(arg0, arg1 interface{}) *gomock.Call {
-	return _mr.mock.ctrl.RecordCall(_mr.mock, "NewAgentClient", arg0, arg1)
-}
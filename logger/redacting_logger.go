@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"fmt"
+
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+)
+
+type redactingLogger struct {
+	logger   boshlog.Logger
+	redactor Redactor
+}
+
+// NewRedactingLogger returns a Logger that masks the secrets of its messages with the redactor before logging them with logger.
+// It is meant for the loggers given to components that log payloads they do not know, e.g. HTTP clients.
+func NewRedactingLogger(logger boshlog.Logger, redactor Redactor) boshlog.Logger {
+	return redactingLogger{
+		logger:   logger,
+		redactor: redactor,
+	}
+}
+
+func (l redactingLogger) Debug(tag, msg string, args ...interface{}) {
+	l.logger.Debug(tag, "%s", l.redact(msg, args))
+}
+
+func (l redactingLogger) DebugWithDetails(tag, msg string, args ...interface{}) {
+	if len(args) == 0 {
+		l.Debug(tag, msg)
+		return
+	}
+	msg, details := l.redactWithDetails(msg, args)
+	l.logger.DebugWithDetails(tag, "%s", msg, details)
+}
+
+func (l redactingLogger) Info(tag, msg string, args ...interface{}) {
+	l.logger.Info(tag, "%s", l.redact(msg, args))
+}
+
+func (l redactingLogger) Warn(tag, msg string, args ...interface{}) {
+	l.logger.Warn(tag, "%s", l.redact(msg, args))
+}
+
+func (l redactingLogger) Error(tag, msg string, args ...interface{}) {
+	l.logger.Error(tag, "%s", l.redact(msg, args))
+}
+
+func (l redactingLogger) ErrorWithDetails(tag, msg string, args ...interface{}) {
+	if len(args) == 0 {
+		l.Error(tag, msg)
+		return
+	}
+	msg, details := l.redactWithDetails(msg, args)
+	l.logger.ErrorWithDetails(tag, "%s", msg, details)
+}
+
+// HandlePanic only recovers when called on the wrapped logger, since recover only works in the deferred function itself
+func (l redactingLogger) HandlePanic(tag string) {
+	l.logger.HandlePanic(tag)
+}
+
+func (l redactingLogger) ToggleForcedDebug() {
+	l.logger.ToggleForcedDebug()
+}
+
+func (l redactingLogger) redact(msg string, args []interface{}) string {
+	return l.redactor.RedactText(fmt.Sprintf(msg, args...))
+}
+
+// redactWithDetails redacts the message and its details, which are the last argument
+func (l redactingLogger) redactWithDetails(msg string, args []interface{}) (string, string) {
+	details := fmt.Sprintf("%s", args[len(args)-1])
+	return l.redact(msg, args[:len(args)-1]), l.redactor.RedactText(details)
+}
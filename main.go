@@ -32,6 +32,10 @@ func main() {
 
 	timeService := clock.NewClock()
 
+	redactor := newRedactor(ui, logger)
+
+	cpiLogPath, args := bicmd.ExtractCPILogPath(os.Args[1:])
+
 	cmdFactory := bicmd.NewFactory(
 		fileSystem,
 		ui,
@@ -39,16 +43,18 @@ func main() {
 		logger,
 		boshuuid.NewGenerator(),
 		workspaceRootPath,
+		redactor,
+		cpiLogPath,
 	)
 
 	cmdRunner := bicmd.NewRunner(cmdFactory)
 	stage := biui.NewStage(ui, timeService, logger)
-	err := cmdRunner.Run(stage, os.Args[1:]...)
+	err := cmdRunner.Run(stage, args...)
 	if err != nil {
 		displayHelpFunc := func() {
 			if strings.Contains(err.Error(), "Invalid usage") {
 				ui.ErrorLinef("")
-				helpErr := cmdRunner.Run(stage, append([]string{"help"}, os.Args[1:]...)...)
+				helpErr := cmdRunner.Run(stage, append([]string{"help"}, args...)...)
 				if helpErr != nil {
 					logger.Error(mainLogTag, "Couldn't print help: %s", helpErr.Error())
 				}
@@ -80,6 +86,21 @@ func newLogger() boshlog.Logger {
 	return newSignalableLogger(boshlog.NewLogger(level))
 }
 
+func newRedactor(ui biui.UI, logger boshlog.Logger) bilog.Redactor {
+	var keyPatterns []string
+	keysString := os.Getenv("BOSH_INIT_LOG_REDACT_KEYS")
+	if keysString != "" {
+		keyPatterns = strings.Split(keysString, ",")
+	}
+
+	redactor, err := bilog.NewRedactor(keyPatterns)
+	if err != nil {
+		err = bosherr.WrapError(err, "Invalid BOSH_INIT_LOG_REDACT_KEYS value")
+		fail(err, ui, logger, nil)
+	}
+	return redactor
+}
+
 func newSignalableLogger(logger boshlog.Logger) boshlog.Logger {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGHUP)
@@ -8,12 +8,14 @@ import (
 	"regexp"
 
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 )
 
 type instanceHandler struct {
 	username string
 	password string
 	registry Registry
+	redactor bilog.Redactor
 	logger   boshlog.Logger
 	logTag   string
 }
@@ -22,12 +24,14 @@ func newInstanceHandler(
 	username string,
 	password string,
 	registry Registry,
+	redactor bilog.Redactor,
 	logger boshlog.Logger,
 ) *instanceHandler {
 	return &instanceHandler{
 		username: username,
 		password: password,
 		registry: registry,
+		redactor: redactor,
 		logger:   logger,
 		logTag:   "registryInstanceHandler",
 	}
@@ -73,7 +77,7 @@ func (h *instanceHandler) HandleGet(instanceID string, w http.ResponseWriter, re
 		return
 	}
 
-	h.logger.Debug(h.logTag, "Found settings for instance %s: %s", instanceID, string(settingsJSON))
+	h.logger.Debug(h.logTag, "Found settings for instance %s: %s", instanceID, h.redactor.RedactJSON(settingsJSON))
 
 	response := SettingsResponse{
 		Settings: string(settingsJSON),
@@ -106,7 +110,7 @@ func (h *instanceHandler) HandlePut(instanceID string, w http.ResponseWriter, re
 		return
 	}
 
-	h.logger.Debug(h.logTag, "Saving settings to registry for instance %s: %s", instanceID, string(reqBody))
+	h.logger.Debug(h.logTag, "Saving settings to registry for instance %s: %s", instanceID, h.redactor.RedactJSON(reqBody))
 
 	isUpdated := h.registry.Save(instanceID, reqBody)
 	if isUpdated {
@@ -1,12 +1,13 @@
 package registry
 
 import (
-	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 )
 
 type ServerManager interface {
@@ -14,14 +15,16 @@ type ServerManager interface {
 }
 
 type serverManager struct {
-	logger boshlog.Logger
-	logTag string
+	redactor bilog.Redactor
+	logger   boshlog.Logger
+	logTag   string
 }
 
-func NewServerManager(logger boshlog.Logger) ServerManager {
+func NewServerManager(redactor bilog.Redactor, logger boshlog.Logger) ServerManager {
 	return &serverManager{
-		logger: logger,
-		logTag: "registryServer",
+		redactor: redactor,
+		logger:   logger,
+		logTag:   "registryServer",
 	}
 }
 
@@ -30,8 +33,9 @@ func NewServerManager(logger boshlog.Logger) ServerManager {
 func (s *serverManager) Start(username string, password string, host string, port int) (Server, error) {
 	startedCh := make(chan error)
 	server := &server{
-		logger: s.logger,
-		logTag: "registryServer",
+		redactor: s.redactor,
+		logger:   s.logger,
+		logTag:   "registryServer",
 	}
 	go func() {
 		err := server.start(username, password, host, port, startedCh)
@@ -56,21 +60,23 @@ type Server interface {
 
 type server struct {
 	listener net.Listener
+	redactor bilog.Redactor
 	logger   boshlog.Logger
 	logTag   string
 }
 
-func NewServer(logger boshlog.Logger) Server {
+func NewServer(redactor bilog.Redactor, logger boshlog.Logger) Server {
 	return &server{
-		logger: logger,
-		logTag: "registryServer",
+		redactor: redactor,
+		logger:   logger,
+		logTag:   "registryServer",
 	}
 }
 
 func (s *server) start(username string, password string, host string, port int, readyErrCh chan error) error {
 	s.logger.Debug(s.logTag, "Starting registry server at %s:%d", host, port)
 	var err error
-	s.listener, err = net.Listen("tcp", fmt.Sprintf("%s:%d", host, port))
+	s.listener, err = net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
 	if err != nil {
 		readyErrCh <- bosherr.WrapError(err, "Starting registry listener")
 		return nil
@@ -83,7 +89,7 @@ func (s *server) start(username string, password string, host string, port int,
 	httpServer.Handler = mux
 
 	registry := NewRegistry()
-	instanceHandler := newInstanceHandler(username, password, registry, s.logger)
+	instanceHandler := newInstanceHandler(username, password, registry, s.redactor, s.logger)
 	mux.HandleFunc("/instances/", instanceHandler.HandleFunc)
 
 	return httpServer.Serve(s.listener)
@@ -11,6 +11,8 @@ import (
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+	"github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega/gbytes"
+	bilog "github.com/cloudfoundry/bosh-init/logger"
 	. "github.com/cloudfoundry/bosh-init/registry"
 )
 
@@ -20,13 +22,16 @@ var _ = Describe("Server", func() {
 		registryURL              string
 		incorrectAuthRegistryURL string
 		client                   helperClient
+		logBuffer                *gbytes.Buffer
 	)
 
 	retryStartingServer := func() (Server, error) {
 		var err error
 		var server Server
-		logger := boshlog.NewLogger(boshlog.LevelNone)
-		serverFactory := NewServerManager(logger)
+		logger := boshlog.NewWriterLogger(boshlog.LevelDebug, logBuffer, logBuffer)
+		redactor, err := bilog.NewRedactor(nil)
+		Expect(err).ToNot(HaveOccurred())
+		serverFactory := NewServerManager(redactor, logger)
 
 		attempts := 0
 		for attempts < 3 {
@@ -47,6 +52,8 @@ var _ = Describe("Server", func() {
 		registryURL = fmt.Sprintf("http://fake-user:fake-password@%s", registryHost)
 		incorrectAuthRegistryURL = fmt.Sprintf("http://incorrect-user:incorrect-password@%s", registryHost)
 
+		logBuffer = gbytes.NewBuffer()
+
 		var err error
 		server, err = retryStartingServer() // wait for previous test to close socket if still open
 		Expect(err).ToNot(HaveOccurred())
@@ -92,6 +99,23 @@ var _ = Describe("Server", func() {
 			})
 		})
 
+		It("masks the secrets of the settings when logging them", func() {
+			settings := `{"env":{"bosh":{"password":"fake-bosh-password"}}}`
+			_, _, statusCode := client.DoPut(registryURL+"/instances/1/settings", settings)
+			Expect(statusCode).To(Equal(201))
+
+			httpBody, statusCode := client.DoGet(registryURL + "/instances/1/settings")
+			Expect(statusCode).To(Equal(200))
+			var response SettingsResponse
+			err := json.Unmarshal(httpBody, &response)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Settings).To(Equal(settings))
+
+			Expect(string(logBuffer.Contents())).To(ContainSubstring(`Saving settings to registry for instance 1: {"env":{"bosh":{"password":"<redacted>"}}}`))
+			Expect(string(logBuffer.Contents())).To(ContainSubstring(`Found settings for instance 1: {"env":{"bosh":{"password":"<redacted>"}}}`))
+			Expect(string(logBuffer.Contents())).ToNot(ContainSubstring("fake-bosh-password"))
+		})
+
 		Context("when the settings already exist", func() {
 			It("updates the settings", func() {
 				_, _, statusCode := client.DoPut(registryURL+"/instances/1/settings", "fake-agent-settings")
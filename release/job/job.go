@@ -14,11 +14,26 @@ type Job struct {
 	PackageNames  []string
 	Packages      []*birelpkg.Package
 	Properties    map[string]PropertyDefinition
+	Provides      []LinkDefinition
+	Consumes      []LinkDefinition
 }
 
 type PropertyDefinition struct {
 	Description string
 	Default     biproperty.Property
+	// Example and Type are optional; when given, they define the kind of value the property must have
+	Example biproperty.Property
+	Type    string
+}
+
+// LinkDefinition is a link provided or consumed by a job.
+// The properties of a provided link are the names of the job properties shared with the jobs consuming it.
+// A consumed link may be optional, in which case the job is rendered without it when no job provides it.
+type LinkDefinition struct {
+	Name       string
+	Type       string
+	Optional   bool
+	Properties []string
 }
 
 func (j Job) FindTemplateByValue(value string) (string, bool) {
@@ -61,6 +61,8 @@ func (r *reader) Read() (Job, error) {
 		Templates:     jobManifest.Templates,
 		PackageNames:  jobManifest.Packages,
 		ExtractedPath: r.extractedJobPath,
+		Provides:      r.linkDefinitions(jobManifest.Provides),
+		Consumes:      r.linkDefinitions(jobManifest.Consumes),
 	}
 
 	jobProperties := make(map[string]PropertyDefinition, len(jobManifest.Properties))
@@ -69,12 +71,30 @@ func (r *reader) Read() (Job, error) {
 		if err != nil {
 			return Job{}, bosherr.WrapErrorf(err, "Parsing job '%s' property '%s' default: %#v", job.Name, propertyName, rawPropertyDef.Default)
 		}
+		exampleValue, err := biproperty.Build(rawPropertyDef.Example)
+		if err != nil {
+			return Job{}, bosherr.WrapErrorf(err, "Parsing job '%s' property '%s' example: %#v", job.Name, propertyName, rawPropertyDef.Example)
+		}
 		jobProperties[propertyName] = PropertyDefinition{
 			Description: rawPropertyDef.Description,
 			Default:     defaultValue,
+			Example:     exampleValue,
+			Type:        rawPropertyDef.Type,
 		}
 	}
 	job.Properties = jobProperties
 
 	return job, nil
 }
+
+func (r *reader) linkDefinitions(rawLinkDefs []bireljobmanifest.LinkDefinition) []LinkDefinition {
+	if len(rawLinkDefs) == 0 {
+		return nil
+	}
+
+	linkDefs := make([]LinkDefinition, len(rawLinkDefs), len(rawLinkDefs))
+	for i, rawLinkDef := range rawLinkDefs {
+		linkDefs[i] = LinkDefinition(rawLinkDef)
+	}
+	return linkDefs
+}
@@ -38,6 +38,18 @@ properties:
   fake-property:
     description: "Fake description"
     default: "fake-default"
+  fake-typed-property:
+    description: "Fake typed description"
+    example: [1, 2]
+    type: array
+provides:
+- name: fake-provided-link
+  type: fake-link-type
+  properties: [fake-property]
+consumes:
+- name: fake-consumed-link
+  type: fake-other-link-type
+  optional: true
 `,
 				)
 			})
@@ -56,6 +68,17 @@ properties:
 								Description: "Fake description",
 								Default:     biproperty.Property("fake-default"),
 							},
+							"fake-typed-property": PropertyDefinition{
+								Description: "Fake typed description",
+								Example:     biproperty.List{1, 2},
+								Type:        "array",
+							},
+						},
+						Provides: []LinkDefinition{
+							{Name: "fake-provided-link", Type: "fake-link-type", Properties: []string{"fake-property"}},
+						},
+						Consumes: []LinkDefinition{
+							{Name: "fake-consumed-link", Type: "fake-other-link-type", Optional: true},
 						},
 					},
 				))
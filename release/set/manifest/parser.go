@@ -1,6 +1,8 @@
 package manifest
 
 import (
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	biposition "github.com/cloudfoundry/bosh-init/common/position"
 	biutil "github.com/cloudfoundry/bosh-init/common/util"
 	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
@@ -14,22 +16,24 @@ type Parser interface {
 }
 
 type parser struct {
-	fs        boshsys.FileSystem
-	logger    boshlog.Logger
-	logTag    string
-	validator Validator
+	fs           boshsys.FileSystem
+	interpolator biinterpolation.Interpolator
+	logger       boshlog.Logger
+	logTag       string
+	validator    Validator
 }
 
 type manifest struct {
 	Releases []birelmanifest.ReleaseRef
 }
 
-func NewParser(fs boshsys.FileSystem, logger boshlog.Logger, validator Validator) Parser {
+func NewParser(fs boshsys.FileSystem, interpolator biinterpolation.Interpolator, logger boshlog.Logger, validator Validator) Parser {
 	return &parser{
-		fs:        fs,
-		logger:    logger,
-		logTag:    "releaseSetParser",
-		validator: validator,
+		fs:           fs,
+		interpolator: interpolator,
+		logger:       logger,
+		logTag:       "releaseSetParser",
+		validator:    validator,
 	}
 }
 
@@ -38,6 +42,15 @@ func (p *parser) Parse(path string) (Manifest, error) {
 	if err != nil {
 		return Manifest{}, bosherr.WrapErrorf(err, "Reading file %s", path)
 	}
+	positions := biposition.NewIndex(path, contents)
+
+	contents, err = p.interpolator.Interpolate(contents)
+	if err != nil {
+		return Manifest{}, bosherr.WrapErrorf(err, "Interpolating variables in %s", path)
+	}
+	if p.interpolator.AppliesOps() {
+		positions = positions.Map(contents)
+	}
 
 	comboManifest := manifest{}
 	err = yaml.Unmarshal(contents, &comboManifest)
@@ -59,7 +72,7 @@ func (p *parser) Parse(path string) (Manifest, error) {
 
 	err = p.validator.Validate(releaseSetManifest)
 	if err != nil {
-		return Manifest{}, bosherr.WrapError(err, "Validating release set manifest")
+		return Manifest{}, bosherr.WrapError(positions.Annotate(err), "Validating release set manifest")
 	}
 
 	return releaseSetManifest, nil
@@ -3,6 +3,8 @@ package manifest_test
 import (
 	"errors"
 
+	biinterpolation "github.com/cloudfoundry/bosh-init/common/interpolation"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	fakesys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system/fakes"
 	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
@@ -28,7 +30,10 @@ var _ = Describe("Parser", func() {
 		fakeValidator.SetValidateBehavior([]fakes.ValidateOutput{
 			{Err: nil},
 		})
-		parser = manifest.NewParser(fakeFs, logger, fakeValidator)
+		interpolator := biinterpolation.NewInterpolator(biinterpolation.Variables{
+			"release_sha1": "fake-interpolated-sha1",
+		}, biinterpolation.Ops{})
+		parser = manifest.NewParser(fakeFs, interpolator, logger, fakeValidator)
 		fakeFs.WriteFileString(comboManifestPath, `
 ---
 releases:
@@ -71,6 +76,24 @@ name: unknown-keys-are-ignored
 		})
 	})
 
+	Context("when the manifest contains variables", func() {
+		BeforeEach(func() {
+			fakeFs.WriteFileString(comboManifestPath, `
+---
+releases:
+- name: fake-release-name
+  url: http://fake-url/fake-release.tgz
+  sha1: ((release_sha1))
+`)
+		})
+
+		It("interpolates them before parsing", func() {
+			releaseSetManifest, err := parser.Parse(comboManifestPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(releaseSetManifest.Releases[0].SHA1).To(Equal("fake-interpolated-sha1"))
+		})
+	})
+
 	Context("when release url points to a local file", func() {
 		Context("when release file path begins with 'file://~' or 'file:///'", func() {
 			BeforeEach(func() {
@@ -200,4 +223,31 @@ releases:
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal("Validating release set manifest: couldn't validate that"))
 	})
+
+	It("adds the position in the manifest of the nodes with validation errors", func() {
+		fakeValidator.SetValidateBehavior([]fakes.ValidateOutput{
+			{Err: bosherr.NewMultiError(errors.New("releases[2].url must be a valid URL"), errors.New("fake-other-error"))},
+		})
+
+		_, err := parser.Parse(comboManifestPath)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`Validating release set manifest: /path/to/manifest/fake-deployment-manifest:11:3: releases[2].url must be a valid URL
+  11 |   url: file://relative-path/fake-release-3.tgz
+fake-other-error`))
+	})
+
+	It("adds the position in the manifest file of the nodes with validation errors when ops files change the paths of the manifest", func() {
+		interpolator := biinterpolation.NewInterpolator(biinterpolation.Variables{}, biinterpolation.Ops{
+			{Type: "remove", Path: "/releases/0"},
+		})
+		parser = manifest.NewParser(fakeFs, interpolator, boshlog.NewLogger(boshlog.LevelNone), fakeValidator)
+		fakeValidator.SetValidateBehavior([]fakes.ValidateOutput{
+			{Err: bosherr.NewMultiError(errors.New("releases[1].url must be a valid URL"))},
+		})
+
+		_, err := parser.Parse(comboManifestPath)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(`Validating release set manifest: /path/to/manifest/fake-deployment-manifest:11:3: releases[1].url must be a valid URL
+  11 |   url: file://relative-path/fake-release-3.tgz`))
+	})
 })
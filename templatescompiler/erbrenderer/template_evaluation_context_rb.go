@@ -38,6 +38,7 @@ class TemplateEvaluationContext
 
     @properties = openstruct(properties)
     @raw_properties = properties
+    @links = spec['links'] || {}
     @spec = openstruct(spec)
   end
 
@@ -68,8 +69,29 @@ class TemplateEvaluationContext
     InactiveElseBlock.new
   end
 
+  def link(name)
+    link_spec = @links[name]
+    raise UnknownLink.new(name) if link_spec.nil?
+    create_evaluation_link(link_spec)
+  end
+
+  def if_link(name)
+    link_spec = @links[name]
+    return ActiveElseBlock.new(self) if link_spec.nil?
+
+    yield create_evaluation_link(link_spec)
+    InactiveElseBlock.new
+  end
+
   private
 
+  def create_evaluation_link(link_spec)
+    instances = (link_spec['instances'] || []).map do |instance|
+      EvaluationLinkInstance.new(instance['name'], instance['index'], instance['address'], instance['bootstrap'])
+    end
+    EvaluationLink.new(self, instances, link_spec['properties'] || {})
+  end
+
   def copy_property(dst, src, name, default = nil)
     keys = name.split(".")
     src_ref = src
@@ -106,6 +128,7 @@ class TemplateEvaluationContext
     ref = collection
 
     keys.each do |key|
+      return nil unless ref.is_a?(Hash)
       ref = ref[key]
       return nil if ref.nil?
     end
@@ -122,9 +145,75 @@ class TemplateEvaluationContext
     end
   end
 
+  class UnknownLink < StandardError
+    def initialize(name)
+      super("Can't find link '#{name}'")
+    end
+  end
+
+  class EvaluationLinkInstance
+    attr_reader :name, :index, :address, :bootstrap
+
+    def initialize(name, index, address, bootstrap)
+      @name = name
+      @index = index
+      @address = address
+      @bootstrap = bootstrap
+    end
+  end
+
+  class EvaluationLink
+    attr_reader :instances, :properties
+
+    def initialize(context, instances, properties)
+      @context = context
+      @instances = instances
+      @properties = properties
+    end
+
+    def p(*args)
+      names = Array(args[0])
+
+      names.each do |name|
+        result = lookup_property(@properties, name)
+        return result unless result.nil?
+      end
+
+      return args[1] if args.length == 2
+      raise UnknownProperty.new(names)
+    end
+
+    def if_p(*names)
+      values = names.map do |name|
+        value = lookup_property(@properties, name)
+        return ActiveElseBlock.new(@context, self) if value.nil?
+        value
+      end
+
+      yield *values
+      InactiveElseBlock.new
+    end
+
+    private
+
+    def lookup_property(collection, name)
+      keys = name.split(".")
+      ref = collection
+
+      keys.each do |key|
+        return nil unless ref.is_a?(Hash)
+        ref = ref[key]
+        return nil if ref.nil?
+      end
+
+      ref
+    end
+  end
+
   class ActiveElseBlock
-    def initialize(template)
+    def initialize(template, link = nil)
       @context = template
+      @link = link
     end
 
     def else
@@ -132,7 +221,11 @@ class TemplateEvaluationContext
     end
 
     def else_if_p(*names, &block)
-      @context.if_p(*names, &block)
+      (@link || @context).if_p(*names, &block)
+    end
+
+    def else_if_link(name, &block)
+      @context.if_link(name, &block)
     end
   end
 
@@ -142,6 +235,10 @@ class TemplateEvaluationContext
     def else_if_p(*names)
       InactiveElseBlock.new
     end
+
+    def else_if_link(name)
+      InactiveElseBlock.new
+    end
   end
 end
 
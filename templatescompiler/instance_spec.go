@@ -0,0 +1,156 @@
+package templatescompiler
+
+import (
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	bosherr "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/errors"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+)
+
+// InstanceSpec describes the instance whose job templates are rendered.
+// It is exposed as 'spec' in ERB templates, along with the properties.
+type InstanceSpec struct {
+	ID             string
+	AZ             string
+	Name           string
+	Index          int
+	Bootstrap      bool
+	Address        string
+	IP             string
+	PersistentDisk int
+
+	// Networks are the network interfaces of the instance, by network name
+	Networks map[string]biproperty.Map
+
+	// Links are the links consumed by the release jobs, by release job name and link name
+	Links map[string]map[string]bideplmanifest.Link
+
+	// Releases are the releases of the release jobs, by release job name
+	Releases map[string]ReleaseSpec
+}
+
+type ReleaseSpec struct {
+	Name    string
+	Version string
+}
+
+// NewInstanceSpec describes the instance with the given index of a deployment job.
+// id is the ID recorded for the instance in the deployment state.
+// releaseJobs are the release jobs of the templates of the deployment job, in the same order.
+func NewInstanceSpec(
+	deploymentManifest bideplmanifest.Manifest,
+	jobName string,
+	index int,
+	id string,
+	releaseJobs []bireljob.Job,
+	releaseManager birel.Manager,
+) (InstanceSpec, error) {
+	job, found := deploymentManifest.FindJobByName(jobName)
+	if !found {
+		return InstanceSpec{}, bosherr.Errorf("Could not find job with name: %s", jobName)
+	}
+
+	networks, err := deploymentManifest.NetworkInterfaces(jobName, index)
+	if err != nil {
+		return InstanceSpec{}, bosherr.WrapErrorf(err, "Finding networks for job '%s'", jobName)
+	}
+
+	links, err := deploymentManifest.Links(jobName, releaseJobs)
+	if err != nil {
+		return InstanceSpec{}, bosherr.WrapErrorf(err, "Resolving links for job '%s'", jobName)
+	}
+
+	diskPool, err := deploymentManifest.DiskPool(jobName)
+	if err != nil {
+		return InstanceSpec{}, bosherr.WrapErrorf(err, "Finding persistent disk for job '%s'", jobName)
+	}
+
+	releases := map[string]ReleaseSpec{}
+	for i, releaseJob := range releaseJobs {
+		releaseSpec := ReleaseSpec{Name: job.Templates[i].Release}
+		if release, found := releaseManager.Find(releaseSpec.Name); found {
+			releaseSpec.Version = release.Version()
+		}
+		releases[releaseJob.Name] = releaseSpec
+	}
+
+	ip := instanceIP(job, networks)
+
+	return InstanceSpec{
+		ID:             id,
+		AZ:             availabilityZone(deploymentManifest, jobName),
+		Name:           jobName,
+		Index:          index,
+		Bootstrap:      index == 0,
+		Address:        ip,
+		IP:             ip,
+		PersistentDisk: diskPool.DiskSize,
+		Networks:       networks,
+		Links:          links,
+		Releases:       releases,
+	}, nil
+}
+
+// WithVMNetworks returns the spec with the network settings the CPI reported for the VM of the instance,
+// e.g. the IP of a dynamic network, merged into the networks of the instance.
+// Networks of the VM that the job is not on are ignored.
+func (s InstanceSpec) WithVMNetworks(job bideplmanifest.Job, vmNetworks map[string]biproperty.Map) InstanceSpec {
+	if len(vmNetworks) == 0 {
+		return s
+	}
+
+	networks := map[string]biproperty.Map{}
+	for networkName, networkInterface := range s.Networks {
+		mergedInterface := biproperty.Map{}
+		for key, value := range networkInterface {
+			mergedInterface[key] = value
+		}
+		for key, value := range vmNetworks[networkName] {
+			mergedInterface[key] = value
+		}
+		networks[networkName] = mergedInterface
+	}
+
+	ip := instanceIP(job, networks)
+
+	s.Networks = networks
+	s.Address = ip
+	s.IP = ip
+	return s
+}
+
+// instanceIP is the IP of the instance on its default gateway network, or else on the first network where its IP is known
+func instanceIP(job bideplmanifest.Job, networks map[string]biproperty.Map) string {
+	for _, jobNetwork := range job.Networks {
+		isDefaultGateway := len(job.Networks) == 1
+		for _, networkDefault := range jobNetwork.Defaults {
+			if networkDefault == bideplmanifest.NetworkDefaultGateway {
+				isDefaultGateway = true
+			}
+		}
+
+		if ip, ok := networks[jobNetwork.Name]["ip"].(string); ok && isDefaultGateway {
+			return ip
+		}
+	}
+
+	for _, jobNetwork := range job.Networks {
+		if ip, ok := networks[jobNetwork.Name]["ip"].(string); ok {
+			return ip
+		}
+	}
+
+	return ""
+}
+
+// availabilityZone is the 'availability_zone' of the cloud_properties of the resource pool of the job, as used by most CPIs
+func availabilityZone(deploymentManifest bideplmanifest.Manifest, jobName string) string {
+	resourcePool, err := deploymentManifest.ResourcePool(jobName)
+	if err != nil {
+		return ""
+	}
+
+	az, _ := resourcePool.CloudProperties["availability_zone"].(string)
+	return az
+}
@@ -0,0 +1,147 @@
+package templatescompiler_test
+
+import (
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/ginkgo"
+	. "github.com/cloudfoundry/bosh-init/internal/github.com/onsi/gomega"
+	. "github.com/cloudfoundry/bosh-init/templatescompiler"
+
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
+	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
+	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
+	birel "github.com/cloudfoundry/bosh-init/release"
+	fakebirel "github.com/cloudfoundry/bosh-init/release/fakes"
+	bireljob "github.com/cloudfoundry/bosh-init/release/job"
+)
+
+var _ = Describe("InstanceSpec", func() {
+	var (
+		deploymentManifest bideplmanifest.Manifest
+		releaseJobs        []bireljob.Job
+		releaseManager     birel.Manager
+	)
+
+	BeforeEach(func() {
+		deploymentManifest = bideplmanifest.Manifest{
+			Name: "fake-deployment-name",
+			Networks: []bideplmanifest.Network{
+				{
+					Name: "fake-manual-network",
+					Type: bideplmanifest.Manual,
+					Subnets: []bideplmanifest.Subnet{
+						{Range: "10.0.0.0/24", Gateway: "10.0.0.1"},
+					},
+				},
+				{
+					Name: "fake-other-manual-network",
+					Type: bideplmanifest.Manual,
+					Subnets: []bideplmanifest.Subnet{
+						{Range: "10.0.1.0/24", Gateway: "10.0.1.1"},
+					},
+				},
+			},
+			ResourcePools: []bideplmanifest.ResourcePool{
+				{
+					Name:            "fake-resource-pool-name",
+					CloudProperties: biproperty.Map{"availability_zone": "fake-az"},
+				},
+			},
+			Jobs: []bideplmanifest.Job{
+				{
+					Name:           "fake-job-name",
+					Instances:      2,
+					ResourcePool:   "fake-resource-pool-name",
+					PersistentDisk: 1024,
+					Templates: []bideplmanifest.ReleaseJobRef{
+						{Name: "fake-release-job-name", Release: "fake-release-name"},
+					},
+					Networks: []bideplmanifest.JobNetwork{
+						{
+							Name:      "fake-other-manual-network",
+							StaticIPs: []string{"10.0.1.5", "10.0.1.6"},
+						},
+						{
+							Name:      "fake-manual-network",
+							StaticIPs: []string{"10.0.0.5", "10.0.0.6"},
+							Defaults:  []bideplmanifest.NetworkDefault{bideplmanifest.NetworkDefaultDNS, bideplmanifest.NetworkDefaultGateway},
+						},
+					},
+				},
+			},
+		}
+
+		releaseJobs = []bireljob.Job{{Name: "fake-release-job-name"}}
+
+		releaseManager = birel.NewManager(boshlog.NewLogger(boshlog.LevelNone))
+		releaseManager.Add(fakebirel.New("fake-release-name", "fake-release-version"))
+	})
+
+	It("describes the instance of the job with the given index", func() {
+		instanceSpec, err := NewInstanceSpec(deploymentManifest, "fake-job-name", 1, "fake-instance-id", releaseJobs, releaseManager)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(instanceSpec.ID).To(Equal("fake-instance-id"))
+		Expect(instanceSpec.Name).To(Equal("fake-job-name"))
+		Expect(instanceSpec.Index).To(Equal(1))
+		Expect(instanceSpec.Bootstrap).To(BeFalse())
+		Expect(instanceSpec.AZ).To(Equal("fake-az"))
+		Expect(instanceSpec.PersistentDisk).To(Equal(1024))
+		Expect(instanceSpec.Networks).To(HaveLen(2))
+		Expect(instanceSpec.Networks["fake-manual-network"]["ip"]).To(Equal("10.0.0.6"))
+		Expect(instanceSpec.Links).To(Equal(map[string]map[string]bideplmanifest.Link{
+			"fake-release-job-name": {},
+		}))
+		Expect(instanceSpec.Releases).To(Equal(map[string]ReleaseSpec{
+			"fake-release-job-name": {Name: "fake-release-name", Version: "fake-release-version"},
+		}))
+	})
+
+	It("uses the IP of the default gateway network as address", func() {
+		instanceSpec, err := NewInstanceSpec(deploymentManifest, "fake-job-name", 0, "fake-instance-id", releaseJobs, releaseManager)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(instanceSpec.Bootstrap).To(BeTrue())
+		Expect(instanceSpec.IP).To(Equal("10.0.0.5"))
+		Expect(instanceSpec.Address).To(Equal("10.0.0.5"))
+	})
+
+	It("has no availability zone when the resource pool has none", func() {
+		deploymentManifest.ResourcePools[0].CloudProperties = biproperty.Map{}
+
+		instanceSpec, err := NewInstanceSpec(deploymentManifest, "fake-job-name", 0, "fake-instance-id", releaseJobs, releaseManager)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instanceSpec.AZ).To(BeEmpty())
+	})
+
+	Describe("WithVMNetworks", func() {
+		It("merges the network settings reported for the VM into the networks of the instance", func() {
+			instanceSpec, err := NewInstanceSpec(deploymentManifest, "fake-job-name", 0, "fake-instance-id", releaseJobs, releaseManager)
+			Expect(err).ToNot(HaveOccurred())
+
+			instanceSpec = instanceSpec.WithVMNetworks(deploymentManifest.Jobs[0], map[string]biproperty.Map{
+				"fake-manual-network":  biproperty.Map{"ip": "10.0.0.7", "mac": "fake-mac"},
+				"fake-unknown-network": biproperty.Map{"ip": "10.0.2.5"},
+			})
+
+			Expect(instanceSpec.Networks).To(HaveLen(2))
+			Expect(instanceSpec.Networks["fake-manual-network"]["ip"]).To(Equal("10.0.0.7"))
+			Expect(instanceSpec.Networks["fake-manual-network"]["mac"]).To(Equal("fake-mac"))
+			Expect(instanceSpec.Networks["fake-manual-network"]["gateway"]).To(Equal("10.0.0.1"))
+			Expect(instanceSpec.Networks["fake-other-manual-network"]["ip"]).To(Equal("10.0.1.5"))
+			Expect(instanceSpec.IP).To(Equal("10.0.0.7"))
+			Expect(instanceSpec.Address).To(Equal("10.0.0.7"))
+		})
+
+		It("keeps the networks of the manifest when no networks are reported for the VM", func() {
+			instanceSpec, err := NewInstanceSpec(deploymentManifest, "fake-job-name", 0, "fake-instance-id", releaseJobs, releaseManager)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(instanceSpec.WithVMNetworks(deploymentManifest.Jobs[0], nil)).To(Equal(instanceSpec))
+		})
+	})
+
+	It("returns an error when the job does not exist", func() {
+		_, err := NewInstanceSpec(deploymentManifest, "fake-unknown-job-name", 0, "fake-instance-id", releaseJobs, releaseManager)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("Could not find job with name: fake-unknown-job-name"))
+	})
+})
@@ -15,6 +15,7 @@ type jobEvaluationContext struct {
 	jobProperties    biproperty.Map
 	globalProperties biproperty.Map
 	deploymentName   string
+	instance         InstanceSpec
 	logger           boshlog.Logger
 	logTag           string
 }
@@ -22,13 +23,24 @@ type jobEvaluationContext struct {
 // RootContext is exposed as an open struct in ERB templates.
 // It must stay same to provide backwards compatible API.
 type RootContext struct {
-	Index      int        `json:"index"`
-	JobContext jobContext `json:"job"`
-	Deployment string     `json:"deployment"`
-
-	// Usually is accessed with <%= spec.networks.default.ip %>
+	ID             string         `json:"id"`
+	AZ             *string        `json:"az"` // null when the resource pool has no availability zone
+	Name           string         `json:"name"`
+	Index          int            `json:"index"`
+	Bootstrap      bool           `json:"bootstrap"`
+	Address        string         `json:"address"`
+	IP             string         `json:"ip"`
+	PersistentDisk int            `json:"persistent_disk"`
+	JobContext     jobContext     `json:"job"`
+	Release        releaseContext `json:"release"`
+	Deployment     string         `json:"deployment"`
+
+	// Usually is accessed with <%= spec.networks.<network_name>.ip %>
 	NetworkContexts map[string]networkContext `json:"networks"`
 
+	// Links consumed by the job, by link name. Usually accessed with <%= link("<link_name>").p("<property>") %>
+	LinkContexts map[string]linkContext `json:"links"`
+
 	//TODO: this should be a map[string]interface{}
 	GlobalProperties  biproperty.Map `json:"global_properties"`  // values from manifest's top-level properties
 	ClusterProperties biproperty.Map `json:"cluster_properties"` // values from manifest's jobs[].properties
@@ -39,17 +51,39 @@ type jobContext struct {
 	Name string `json:"name"`
 }
 
+// releaseContext holds the release of the job
+type releaseContext struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// networkContext holds the addresses of an instance on one of its networks.
+// The netmask is in the notation of the address family of the network (e.g. "ffff:ffff:ffff:ffff::" for IPv6).
 type networkContext struct {
 	IP      string `json:"ip"`
 	Netmask string `json:"netmask"`
 	Gateway string `json:"gateway"`
 }
 
+// linkContext holds the instances and the properties of a link consumed by the job
+type linkContext struct {
+	Instances  []linkInstanceContext `json:"instances"`
+	Properties biproperty.Map        `json:"properties"`
+}
+
+type linkInstanceContext struct {
+	Name      string `json:"name"`
+	Index     int    `json:"index"`
+	Address   string `json:"address"`
+	Bootstrap bool   `json:"bootstrap"`
+}
+
 func NewJobEvaluationContext(
 	releaseJob bireljob.Job,
 	jobProperties biproperty.Map,
 	globalProperties biproperty.Map,
 	deploymentName string,
+	instance InstanceSpec,
 	logger boshlog.Logger,
 ) bierbrenderer.TemplateEvaluationContext {
 	return jobEvaluationContext{
@@ -57,6 +91,7 @@ func NewJobEvaluationContext(
 		jobProperties:    jobProperties,
 		globalProperties: globalProperties,
 		deploymentName:   deploymentName,
+		instance:         instance,
 		logger:           logger,
 		logTag:           "jobEvaluationContext",
 	}
@@ -65,21 +100,37 @@ func NewJobEvaluationContext(
 func (ec jobEvaluationContext) MarshalJSON() ([]byte, error) {
 	defaultProperties := ec.propertyDefaults(ec.releaseJob.Properties)
 
+	releaseSpec := ec.instance.Releases[ec.releaseJob.Name]
+
+	var az *string
+	if ec.instance.AZ != "" {
+		az = &ec.instance.AZ
+	}
+
 	context := RootContext{
-		Index:             0,
+		ID:                ec.instance.ID,
+		AZ:                az,
+		Name:              ec.instance.Name,
+		Index:             ec.instance.Index,
+		Bootstrap:         ec.instance.Bootstrap,
+		Address:           ec.instance.Address,
+		IP:                ec.instance.IP,
+		PersistentDisk:    ec.instance.PersistentDisk,
 		JobContext:        jobContext{Name: ec.releaseJob.Name},
+		Release:           releaseContext{Name: releaseSpec.Name, Version: releaseSpec.Version},
 		Deployment:        ec.deploymentName,
 		NetworkContexts:   ec.buildNetworkContexts(),
+		LinkContexts:      ec.buildLinkContexts(),
 		GlobalProperties:  ec.globalProperties,
 		ClusterProperties: ec.jobProperties,
 		DefaultProperties: defaultProperties,
 	}
 
-	ec.logger.Debug(ec.logTag, "Marshalling context %#v", context)
+	ec.logger.Debug(ec.logTag, "Marshalling context of job '%s'", ec.releaseJob.Name)
 
 	jsonBytes, err := json.Marshal(context)
 	if err != nil {
-		return []byte{}, bosherr.WrapErrorf(err, "Marshalling job eval context: %#v", context)
+		return []byte{}, bosherr.WrapErrorf(err, "Marshalling job eval context of job '%s'", ec.releaseJob.Name)
 	}
 
 	return jsonBytes, nil
@@ -93,11 +144,55 @@ func (ec jobEvaluationContext) propertyDefaults(properties map[string]bireljob.P
 	return result
 }
 
+// buildNetworkContexts returns the addresses of the instance on each of its networks, as given by the network interfaces of the manifest.
+// A 'default' network without IP is kept for backwards compatibility, since the IP of a dynamic network is only known to the agent.
 func (ec jobEvaluationContext) buildNetworkContexts() map[string]networkContext {
-	// IP is being returned by agent
-	return map[string]networkContext{
+	networkContexts := map[string]networkContext{
 		"default": networkContext{
 			IP: "",
 		},
 	}
+
+	for networkName, networkInterface := range ec.instance.Networks {
+		networkContexts[networkName] = networkContext{
+			IP:      ec.stringValue(networkInterface, "ip"),
+			Netmask: ec.stringValue(networkInterface, "netmask"),
+			Gateway: ec.stringValue(networkInterface, "gateway"),
+		}
+	}
+
+	return networkContexts
+}
+
+func (ec jobEvaluationContext) buildLinkContexts() map[string]linkContext {
+	linkContexts := map[string]linkContext{}
+
+	for linkName, link := range ec.instance.Links[ec.releaseJob.Name] {
+		instances := make([]linkInstanceContext, len(link.Instances), len(link.Instances))
+		for i, instance := range link.Instances {
+			instances[i] = linkInstanceContext{
+				Name:      instance.Name,
+				Index:     instance.Index,
+				Address:   instance.Address,
+				Bootstrap: instance.Bootstrap,
+			}
+		}
+
+		properties := link.Properties
+		if properties == nil {
+			properties = biproperty.Map{}
+		}
+
+		linkContexts[linkName] = linkContext{
+			Instances:  instances,
+			Properties: properties,
+		}
+	}
+
+	return linkContexts
+}
+
+func (ec jobEvaluationContext) stringValue(networkInterface biproperty.Map, key string) string {
+	value, _ := networkInterface[key].(string)
+	return value
 }
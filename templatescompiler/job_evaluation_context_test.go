@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 
+	bideplmanifest "github.com/cloudfoundry/bosh-init/deployment/manifest"
 	boshlog "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/logger"
 	biproperty "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/property"
 	boshsys "github.com/cloudfoundry/bosh-init/internal/github.com/cloudfoundry/bosh-utils/system"
@@ -23,9 +24,11 @@ var _ = Describe("JobEvaluationContext", func() {
 		releaseJob        bireljob.Job
 		clusterProperties biproperty.Map
 		globalProperties  biproperty.Map
+		instance          InstanceSpec
 	)
 	BeforeEach(func() {
 		generatedContext = RootContext{}
+		instance = InstanceSpec{}
 
 		releaseJob = bireljob.Job{
 			Name: "fake-job-name",
@@ -57,6 +60,7 @@ var _ = Describe("JobEvaluationContext", func() {
 			clusterProperties,
 			globalProperties,
 			"fake-deployment-name",
+			instance,
 			logger,
 		)
 
@@ -67,12 +71,131 @@ var _ = Describe("JobEvaluationContext", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("has the instance of the job", func() {
+		Expect(generatedContext.ID).To(Equal(""))
+		Expect(generatedContext.AZ).To(BeNil())
+		Expect(generatedContext.Index).To(Equal(0))
+		Expect(generatedContext.Bootstrap).To(BeFalse())
+	})
+
+	Context("when the instance is described", func() {
+		BeforeEach(func() {
+			instance = InstanceSpec{
+				ID:             "fake-instance-id",
+				AZ:             "fake-az",
+				Name:           "fake-instance-group-name",
+				Index:          1,
+				Bootstrap:      false,
+				Address:        "10.0.0.6",
+				IP:             "10.0.0.6",
+				PersistentDisk: 1024,
+				Releases: map[string]ReleaseSpec{
+					"fake-job-name": {Name: "fake-release-name", Version: "fake-release-version"},
+				},
+			}
+		})
+
+		It("has the instance spec", func() {
+			Expect(generatedContext.ID).To(Equal("fake-instance-id"))
+			Expect(*generatedContext.AZ).To(Equal("fake-az"))
+			Expect(generatedContext.Name).To(Equal("fake-instance-group-name"))
+			Expect(generatedContext.Index).To(Equal(1))
+			Expect(generatedContext.Bootstrap).To(BeFalse())
+			Expect(generatedContext.Address).To(Equal("10.0.0.6"))
+			Expect(generatedContext.IP).To(Equal("10.0.0.6"))
+			Expect(generatedContext.PersistentDisk).To(Equal(1024))
+			Expect(generatedContext.JobContext.Name).To(Equal("fake-job-name"))
+			Expect(generatedContext.Release.Name).To(Equal("fake-release-name"))
+			Expect(generatedContext.Release.Version).To(Equal("fake-release-version"))
+			Expect(generatedContext.Deployment).To(Equal("fake-deployment-name"))
+		})
+	})
+
 	It("it has a network context section with empty IP", func() {
 		Expect(generatedContext.NetworkContexts["default"].IP).To(Equal(""))
 	})
 
+	Context("when the instance has networks", func() {
+		BeforeEach(func() {
+			instance.Networks = map[string]biproperty.Map{
+				"fake-ipv4-network": biproperty.Map{
+					"type":    "manual",
+					"ip":      "10.0.0.5",
+					"netmask": "255.255.255.0",
+					"gateway": "10.0.0.1",
+				},
+				"fake-ipv6-network": biproperty.Map{
+					"type":    "manual",
+					"ip":      "2001:db8::5",
+					"netmask": "ffff:ffff:ffff:ffff::",
+					"gateway": "2001:db8::1",
+				},
+				"fake-dynamic-network": biproperty.Map{
+					"type": "dynamic",
+				},
+			}
+		})
+
+		It("has a network context section for each network", func() {
+			ipv4Context := generatedContext.NetworkContexts["fake-ipv4-network"]
+			Expect(ipv4Context.IP).To(Equal("10.0.0.5"))
+			Expect(ipv4Context.Netmask).To(Equal("255.255.255.0"))
+			Expect(ipv4Context.Gateway).To(Equal("10.0.0.1"))
+
+			ipv6Context := generatedContext.NetworkContexts["fake-ipv6-network"]
+			Expect(ipv6Context.IP).To(Equal("2001:db8::5"))
+			Expect(ipv6Context.Netmask).To(Equal("ffff:ffff:ffff:ffff::"))
+			Expect(ipv6Context.Gateway).To(Equal("2001:db8::1"))
+
+			Expect(generatedContext.NetworkContexts).To(HaveKey("fake-dynamic-network"))
+			Expect(generatedContext.NetworkContexts["fake-dynamic-network"].IP).To(Equal(""))
+			Expect(generatedContext.NetworkContexts["default"].IP).To(Equal(""))
+		})
+	})
+
+	It("has an empty links section", func() {
+		Expect(generatedContext.LinkContexts).To(BeEmpty())
+	})
+
+	Context("when the job consumes links", func() {
+		BeforeEach(func() {
+			instance.Links = map[string]map[string]bideplmanifest.Link{
+				"fake-job-name": {
+					"fake-link-name": {
+						Instances: []bideplmanifest.LinkInstance{
+							{Name: "fake-provider-job-name", Index: 0, Address: "10.0.0.5", Bootstrap: true},
+							{Name: "fake-provider-job-name", Index: 1, Address: "10.0.0.6"},
+						},
+						Properties: biproperty.Map{
+							"fake-link-property": biproperty.Map{"nested": "fake-link-value"},
+						},
+					},
+					"fake-empty-link-name": {},
+				},
+				"fake-other-job-name": {
+					"fake-other-link-name": {},
+				},
+			}
+		})
+
+		It("has a link context section for each link", func() {
+			generatedJSON, err := json.Marshal(generatedContext.LinkContexts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(generatedJSON).To(MatchJSON(`{
+				"fake-link-name": {
+					"instances": [
+						{"name": "fake-provider-job-name", "index": 0, "address": "10.0.0.5", "bootstrap": true},
+						{"name": "fake-provider-job-name", "index": 1, "address": "10.0.0.6", "bootstrap": false}
+					],
+					"properties": {"fake-link-property": {"nested": "fake-link-value"}}
+				},
+				"fake-empty-link-name": {"instances": [], "properties": {}}
+			}`))
+		})
+	})
+
 	var erbRenderer erbrenderer.ERBRenderer
-	getValueFor := func(key string) string {
+	render := func(erbContents string) string {
 		logger := boshlog.NewLogger(boshlog.LevelNone)
 		fs := boshsys.NewOsFileSystem(logger)
 		commandRunner := boshsys.NewExecCmdRunner(logger)
@@ -82,7 +205,6 @@ var _ = Describe("JobEvaluationContext", func() {
 		Expect(err).ToNot(HaveOccurred())
 		defer os.Remove(srcFile.Name())
 
-		erbContents := fmt.Sprintf("<%%= p('%s') %%>", key)
 		_, err = srcFile.WriteString(erbContents)
 		Expect(err).ToNot(HaveOccurred())
 
@@ -97,6 +219,7 @@ var _ = Describe("JobEvaluationContext", func() {
 			clusterProperties,
 			globalProperties,
 			"fake-deployment-name",
+			instance,
 			logger,
 		)
 
@@ -106,6 +229,9 @@ var _ = Describe("JobEvaluationContext", func() {
 		Expect(err).ToNot(HaveOccurred())
 		return (string)(contents)
 	}
+	getValueFor := func(key string) string {
+		return render(fmt.Sprintf("<%%= p('%s') %%>", key))
+	}
 
 	Context("when a cluster property overrides a global property or default value", func() {
 		BeforeEach(func() {
@@ -208,4 +334,51 @@ var _ = Describe("JobEvaluationContext", func() {
 				To(Equal("value-from-job-defaults"))
 		})
 	})
+
+	Context("when a property is a string", func() {
+		BeforeEach(func() {
+			releaseJob = bireljob.Job{
+				Name: "fake-job-name",
+				Properties: map[string]bireljob.PropertyDefinition{
+					"fake-string-property": bireljob.PropertyDefinition{
+						Default: "abc",
+					},
+				},
+			}
+		})
+
+		It("does not look up nested properties in the string", func() {
+			Expect(render("<%= p('fake-string-property.b', 'fake-default') %>")).To(Equal("fake-default"))
+			Expect(render("<% if_p('fake-string-property.b') do |value| %>found <%= value %><% end.else do %>not found<% end %>")).
+				To(Equal("not found"))
+		})
+	})
+
+	Context("when the job consumes links with properties", func() {
+		BeforeEach(func() {
+			instance.Links = map[string]map[string]bideplmanifest.Link{
+				"fake-job-name": {
+					"fake-link-name": {
+						Properties: biproperty.Map{
+							"fake-link-property": "abc",
+						},
+					},
+					"fake-other-link-name": {},
+				},
+			}
+		})
+
+		It("does not look up nested link properties in strings", func() {
+			Expect(render("<%= link('fake-link-name').p('fake-link-property.b', 'fake-default') %>")).To(Equal("fake-default"))
+		})
+
+		It("continues the else block of a link property with the template links and the link properties", func() {
+			Expect(render("<% link('fake-link-name').if_p('fake-missing-property') do %>found<% end.else_if_link('fake-other-link-name') do %>other link<% end %>")).
+				To(Equal("other link"))
+			Expect(render("<% link('fake-link-name').if_p('fake-missing-property') do %>found<% end.else_if_link('fake-missing-link-name') do %>other link<% end.else do %>no link<% end %>")).
+				To(Equal("no link"))
+			Expect(render("<% link('fake-link-name').if_p('fake-missing-property') do %>found<% end.else_if_p('fake-link-property') do |value| %><%= value %><% end %>")).
+				To(Equal("abc"))
+		})
+	})
 })
@@ -13,6 +13,7 @@ type JobListRenderer interface {
 		jobProperties biproperty.Map,
 		globalProperties biproperty.Map,
 		deploymentName string,
+		instance InstanceSpec,
 	) (RenderedJobList, error)
 }
 
@@ -38,13 +39,15 @@ func (r *jobListRenderer) Render(
 	jobProperties biproperty.Map,
 	globalProperties biproperty.Map,
 	deploymentName string,
+	instance InstanceSpec,
 ) (RenderedJobList, error) {
-	r.logger.Debug(r.logTag, "Rendering job list: deploymentName='%s' jobProperties=%#v globalProperties=%#v", deploymentName, jobProperties, globalProperties)
+	// properties are not logged, since they contain the passwords, keys and certificates of the manifest
+	r.logger.Debug(r.logTag, "Rendering job list: deploymentName='%s' instance='%s/%d'", deploymentName, instance.Name, instance.Index)
 	renderedJobList := NewRenderedJobList()
 
 	// render all the jobs' templates
 	for _, releaseJob := range releaseJobs {
-		renderedJob, err := r.jobRenderer.Render(releaseJob, jobProperties, globalProperties, deploymentName)
+		renderedJob, err := r.jobRenderer.Render(releaseJob, jobProperties, globalProperties, deploymentName, instance)
 		if err != nil {
 			defer renderedJobList.DeleteSilently()
 			return renderedJobList, bosherr.WrapErrorf(err, "Rendering templates for job '%s/%s'", releaseJob.Name, releaseJob.Fingerprint)
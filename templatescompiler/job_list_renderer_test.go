@@ -1,6 +1,8 @@
 package templatescompiler_test
 
 import (
+	"bytes"
+
 	. "github.com/cloudfoundry/bosh-init/templatescompiler"
 
 	"github.com/cloudfoundry/bosh-init/internal/github.com/golang/mock/gomock"
@@ -26,7 +28,8 @@ var _ = Describe("JobListRenderer", func() {
 	})
 
 	var (
-		logger boshlog.Logger
+		logger    boshlog.Logger
+		logBuffer *bytes.Buffer
 
 		mockJobRenderer *mock_template.MockJobRenderer
 
@@ -34,6 +37,7 @@ var _ = Describe("JobListRenderer", func() {
 		jobProperties    biproperty.Map
 		globalProperties biproperty.Map
 		deploymentName   string
+		instanceSpec     InstanceSpec
 
 		renderedJobs []*mock_template.MockRenderedJob
 
@@ -44,7 +48,8 @@ var _ = Describe("JobListRenderer", func() {
 	)
 
 	BeforeEach(func() {
-		logger = boshlog.NewLogger(boshlog.LevelNone)
+		logBuffer = bytes.NewBufferString("")
+		logger = boshlog.NewWriterLogger(boshlog.LevelDebug, logBuffer, logBuffer)
 		mockJobRenderer = mock_template.NewMockJobRenderer(mockCtrl)
 
 		// release jobs are just passed through to JobRenderer.Render, so they do not need real contents
@@ -54,7 +59,8 @@ var _ = Describe("JobListRenderer", func() {
 		}
 
 		jobProperties = biproperty.Map{
-			"fake-key": "fake-job-value",
+			"fake-key":      "fake-job-value",
+			"fake-password": "fake-job-password-value",
 		}
 
 		globalProperties = biproperty.Map{
@@ -63,6 +69,14 @@ var _ = Describe("JobListRenderer", func() {
 
 		deploymentName = "fake-deployment-name"
 
+		instanceSpec = InstanceSpec{
+			Name:  "fake-job-name",
+			Index: 1,
+			Networks: map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{"ip": "10.0.0.5"},
+			},
+		}
+
 		renderedJobs = []*mock_template.MockRenderedJob{
 			mock_template.NewMockRenderedJob(mockCtrl),
 			mock_template.NewMockRenderedJob(mockCtrl),
@@ -72,13 +86,13 @@ var _ = Describe("JobListRenderer", func() {
 	})
 
 	JustBeforeEach(func() {
-		mockJobRenderer.EXPECT().Render(releaseJobs[0], jobProperties, globalProperties, deploymentName).Return(renderedJobs[0], nil)
-		expectRender1 = mockJobRenderer.EXPECT().Render(releaseJobs[1], jobProperties, globalProperties, deploymentName).Return(renderedJobs[1], nil)
+		mockJobRenderer.EXPECT().Render(releaseJobs[0], jobProperties, globalProperties, deploymentName, instanceSpec).Return(renderedJobs[0], nil)
+		expectRender1 = mockJobRenderer.EXPECT().Render(releaseJobs[1], jobProperties, globalProperties, deploymentName, instanceSpec).Return(renderedJobs[1], nil)
 	})
 
 	Describe("Render", func() {
 		It("returns a new RenderedJobList with all the RenderedJobs", func() {
-			renderedJobList, err := jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName)
+			renderedJobList, err := jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName, instanceSpec)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(renderedJobList.All()).To(Equal([]RenderedJob{
 				renderedJobs[0],
@@ -86,6 +100,14 @@ var _ = Describe("JobListRenderer", func() {
 			}))
 		})
 
+		It("does not log the properties, which may contain secrets", func() {
+			_, err := jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName, instanceSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(logBuffer.String()).To(ContainSubstring("Rendering job list: deploymentName='fake-deployment-name' instance='fake-job-name/1'"))
+			Expect(logBuffer.String()).ToNot(ContainSubstring("fake-job-password-value"))
+			Expect(logBuffer.String()).ToNot(ContainSubstring("fake-global-value"))
+		})
+
 		Context("when rendering a job fails", func() {
 			JustBeforeEach(func() {
 				expectRender1.Return(nil, bosherr.Error("fake-render-error"))
@@ -94,7 +116,7 @@ var _ = Describe("JobListRenderer", func() {
 			It("returns an error and cleans up any sucessfully rendered jobs", func() {
 				renderedJobs[0].EXPECT().DeleteSilently()
 
-				_, err := jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName)
+				_, err := jobListRenderer.Render(releaseJobs, jobProperties, globalProperties, deploymentName, instanceSpec)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-render-error"))
 			})
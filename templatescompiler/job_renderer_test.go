@@ -26,6 +26,7 @@ var _ = Describe("JobRenderer", func() {
 		fs               *fakesys.FakeFileSystem
 		jobProperties    biproperty.Map
 		globalProperties biproperty.Map
+		instanceSpec     InstanceSpec
 		srcPath          string
 		dstPath          string
 	)
@@ -41,6 +42,13 @@ var _ = Describe("JobRenderer", func() {
 			"fake-property-key": "fake-global-property-value",
 		}
 
+		instanceSpec = InstanceSpec{
+			Name: "fake-job-name",
+			Networks: map[string]biproperty.Map{
+				"fake-network-name": biproperty.Map{"ip": "10.0.0.5"},
+			},
+		}
+
 		job = bireljob.Job{
 			Templates: map[string]string{
 				"director.yml.erb": "config/director.yml",
@@ -50,7 +58,7 @@ var _ = Describe("JobRenderer", func() {
 
 		logger := boshlog.NewLogger(boshlog.LevelNone)
 
-		context = NewJobEvaluationContext(job, jobProperties, globalProperties, "fake-deployment-name", logger)
+		context = NewJobEvaluationContext(job, jobProperties, globalProperties, "fake-deployment-name", instanceSpec, logger)
 
 		fakeERBRenderer = fakebirender.NewFakeERBRender()
 
@@ -81,7 +89,7 @@ var _ = Describe("JobRenderer", func() {
 
 	Describe("Render", func() {
 		It("renders job templates", func() {
-			renderedjob, err := jobRenderer.Render(job, jobProperties, globalProperties, "fake-deployment-name")
+			renderedjob, err := jobRenderer.Render(job, jobProperties, globalProperties, "fake-deployment-name", instanceSpec)
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(fakeERBRenderer.RenderInputs).To(Equal([]fakebirender.RenderInput{
@@ -109,7 +117,7 @@ var _ = Describe("JobRenderer", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := jobRenderer.Render(job, jobProperties, globalProperties, "fake-deployment-name")
+				_, err := jobRenderer.Render(job, jobProperties, globalProperties, "fake-deployment-name", instanceSpec)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("fake-template-render-error"))
 			})